@@ -0,0 +1,9 @@
+// Package api встраивает спецификацию сервиса в бинарник
+package api
+
+import _ "embed"
+
+// Spec - api/swagger.yaml, его отдают /openapi.yaml и /openapi.json
+//
+//go:embed swagger.yaml
+var Spec []byte
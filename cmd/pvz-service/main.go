@@ -2,15 +2,36 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"pvz-service/internal/app"
+	"pvz-service/internal/config"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tenant"
 )
 
+const configFlagUsage = "путь к файлу конфигурации (по умолчанию $PVZ_CONFIG или " + config.DefaultConfigPath + ")"
+
 func main() {
+	configPath := flag.String("config", "", configFlagUsage)
+	flag.Parse()
+
+	if args := flag.Args(); len(args) > 0 {
+		if err := runCommand(args, *configPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	ctx := context.Background()
 
-	a, err := app.NewApp(ctx)
+	a, err := app.NewApp(ctx, config.ResolvePath(*configPath))
 	if err != nil {
 		slog.Error("failed to initialize app", "error", err)
 		panic(err)
@@ -22,3 +43,61 @@ func main() {
 		panic(err)
 	}
 }
+
+// runCommand выполняет служебные команды, например `pvz-service config print`
+func runCommand(args []string, configPath string) error {
+	if len(args) < 2 {
+		return unknownCommand(args)
+	}
+
+	switch args[0] + " " + args[1] {
+	case "config print":
+		return configPrint(args[2:], configPath)
+	case "admin token":
+		return adminToken(args[2:], configPath)
+	}
+	return unknownCommand(args)
+}
+
+func unknownCommand(args []string) error {
+	return fmt.Errorf("unknown command %q, available: config print, admin token", strings.Join(args, " "))
+}
+
+func configPrint(args []string, configPath string) error {
+	// --config можно указать и после команды
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	path := fs.String("config", configPath, configFlagUsage)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.ResolvePath(*path))
+	if err != nil {
+		return err
+	}
+
+	return cfg.Print(os.Stdout)
+}
+
+// adminToken печатает токен администратора, которым заводят операторов через /admin/tenants
+func adminToken(args []string, configPath string) error {
+	fs := flag.NewFlagSet("admin token", flag.ContinueOnError)
+	path := fs.String("config", configPath, configFlagUsage)
+	ttl := fs.Duration("ttl", time.Hour, "время жизни токена")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(config.ResolvePath(*path))
+	if err != nil {
+		return err
+	}
+
+	token, err := service.GenerateToken(cfg.JWT.GetSecret(), "cli:admin", service.AdminRole, tenant.DefaultID, *ttl)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, token)
+	return err
+}
@@ -8,63 +8,223 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"pvz-service/api"
 	"pvz-service/internal/handler"
+	"pvz-service/internal/mailer"
+	"pvz-service/internal/middleware"
+	"pvz-service/internal/notifier"
 	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/cache"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/repository/pgdb"
+	"pvz-service/internal/scheduler"
 	"pvz-service/internal/service"
+	"pvz-service/internal/service/pkg/hash"
+	"pvz-service/internal/tenant"
+	"pvz-service/pkg/buildinfo"
 	"pvz-service/pkg/logger"
 	"pvz-service/pkg/postgres"
+	"pvz-service/pkg/tlsutil"
 
 	"github.com/go-chi/chi/v5"
 	"pvz-service/internal/config"
 )
 
+// schedulerLockKey - ключ advisory lock, которым экземпляры выбирают лидера планировщика
+const schedulerLockKey int64 = 0x70767a // "pvz"
+
 type App struct {
-	httpCfg config.HTTPConfig
-	router  *chi.Mux
+	httpCfg      config.HTTPConfig
+	router       *chi.Mux
+	shuttingDown *atomic.Bool
+	configPath   string
+	cfg          *config.Config
+	runtime      *config.Runtime
+	scheduler    *scheduler.Scheduler
+	deprecations *middleware.DeprecationCounter
+	cache        *cache.Repository
+	auth         *service.AuthService
 }
 
-func NewApp(ctx context.Context) (*App, error) {
-	logger := logger.InitLogger()
-
-	pgCfg, err := config.PGConfigLoad()
+func NewApp(ctx context.Context, configPath string) (*App, error) {
+	cfg, err := config.Load(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("error loading postgres config: %w", err)
+		return nil, fmt.Errorf("error loading config: %w", err)
 	}
 
-	htppCfg, err := config.HTTPConfigLoad()
-	if err != nil {
-		return nil, fmt.Errorf("error loading http config: %w", err)
-	}
+	runtime := config.NewRuntime(cfg.Runtime)
+	logger := logger.InitLogger(runtime.LogLevel())
 
-	jwtCfg, err := config.JWTConfigLoad()
+	//init repo
+	repo, healthChecker, leader, err := initStorage(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("error loading jwt config: %w", err)
+		return nil, err
 	}
 
-	dbPool, err := postgres.InitDBPool(ctx, pgCfg)
-	if err != nil {
-		return nil, fmt.Errorf("error initializing DB pool: %w", err)
+	var cached *cache.Repository
+	if cfg.Cache.IsEnabled() {
+		log.Info("Using in-process cache", "size", cfg.Cache.GetSize(), "ttl", cfg.Cache.GetTTL().String())
+		cached = cache.New(repo, cache.NewLRU(cfg.Cache.GetSize()), cfg.Cache.GetTTL())
+		repo = cached
 	}
 
-	//init repo
-	repo := repository.NewRepository(dbPool)
-
 	// init service
-	serv := service.NewService(repo, jwtCfg.Jwt)
+	serv := service.NewService(repo, cfg.JWT.GetSecret(), &cfg.Webhooks)
+	if err = initAccounts(serv.AuthService, &cfg.Auth, &cfg.Mail, logger); err != nil {
+		return nil, err
+	}
+	serv.AuthService.MarkDummyTokens = cfg.GetEnv() == config.EnvDev
 
 	//init router
-	r := handler.NewRouter(serv, jwtCfg.Jwt, logger)
+	deprecations := middleware.NewDeprecationCounter()
+	routerOpts := []handler.RouterOption{handler.WithDeprecationCounter(deprecations)}
+	if cfg.DummyLoginEnabled() {
+		log.Warn("Dummy login is enabled", "env", cfg.GetEnv())
+		routerOpts = append(routerOpts, handler.WithDummyLogin())
+	}
+	if cfg.Auth.RejectDummyWrites() {
+		routerOpts = append(routerOpts, handler.WithDummyWritesRejected())
+	}
+	r := handler.NewRouter(serv, cfg.JWT.GetSecret(), logger, runtime, routerOpts...)
+
+	shuttingDown := &atomic.Bool{}
+	handler.MountHealth(r, handler.NewHealthHandler(healthChecker, shuttingDown, buildinfo.Get()))
+
+	if cfg.HTTP.DocsEnabled() {
+		docs, err := handler.NewDocsHandler(api.Spec)
+		if err != nil {
+			return nil, err
+		}
+		handler.MountDocs(r, docs)
+	}
+
+	var jobs *scheduler.Scheduler
+	if cfg.Scheduler.IsEnabled() {
+		timeouts := service.NewReceptionTimeoutService(repo, runtime, notifier.NewLog(logger))
+		timeouts.Events = serv.WebhookService
+		jobs = scheduler.New(cfg.Scheduler.GetInterval(), leader,
+			perTenant(serv.TenantService, receptionTimeoutJob(timeouts)),
+			perTenant(serv.TenantService, scheduler.Job{Name: "webhook_delivery", Run: serv.DeliverWebhooks}),
+		)
+	}
 
 	return &App{
-			router:  r,
-			httpCfg: htppCfg,
+			router:       r,
+			httpCfg:      &cfg.HTTP,
+			shuttingDown: shuttingDown,
+			configPath:   configPath,
+			cfg:          cfg,
+			runtime:      runtime,
+			scheduler:    jobs,
+			deprecations: deprecations,
+			cache:        cached,
+			auth:         serv.AuthService,
 		},
 		nil
 }
 
+// receptionTimeoutJob закрывает или помечает приемки, которые долго не закрывают
+func receptionTimeoutJob(timeouts *service.ReceptionTimeoutService) scheduler.Job {
+	return scheduler.Job{
+		Name: "reception_timeout",
+		Run: func(ctx context.Context) error {
+			result, err := timeouts.SweepReceptions(ctx)
+			if result != (service.SweepResult{}) {
+				tenantID, _ := tenant.FromContext(ctx)
+				log.Info("Stale receptions processed", "tenant_id", tenantID.String(), "closed", result.Closed, "flagged", result.Flagged, "reminded", result.Reminded)
+			}
+			return err
+		},
+	}
+}
+
+// perTenant запускает задачу по очереди для каждого оператора: хранилище отдает данные
+// только оператора из контекста
+func perTenant(tenants *service.TenantService, job scheduler.Job) scheduler.Job {
+	return scheduler.Job{
+		Name: job.Name,
+		Run: func(ctx context.Context) error {
+			return tenants.ForEachTenant(ctx, job.Run)
+		},
+	}
+}
+
+// initAccounts настраивает политику и хеширование паролей, письма для подтверждения почты и сброса пароля
+func initAccounts(auth *service.AuthService, authCfg config.AuthConfig, mailCfg config.MailConfig, logger *log.Logger) error {
+	minLength, minClasses, breachListFile := authCfg.GetPasswordPolicy()
+	policy := service.PasswordPolicy{MinLength: minLength, MinClasses: minClasses}
+	if breachListFile != "" {
+		breached, err := service.ReadBreachList(breachListFile)
+		if err != nil {
+			return err
+		}
+		log.Info("Password breach list loaded", "file", breachListFile, "passwords", len(breached))
+		policy.Breached = breached
+	}
+	auth.Policy = policy
+
+	switch authCfg.GetPasswordHasher() {
+	case hash.AlgorithmArgon2id:
+		memory, iterations, parallelism := authCfg.GetArgon2idParams()
+		auth.Hasher = hash.NewPasswords(hash.NewArgon2id(hash.Argon2idParams{
+			Memory:      memory,
+			Iterations:  iterations,
+			Parallelism: parallelism,
+		}))
+	default:
+		auth.Hasher = hash.NewPasswords(hash.NewBcrypt(authCfg.GetBcryptCost()))
+	}
+
+	switch mailCfg.GetDriver() {
+	case config.MailDriverSMTP:
+		host, port, username, password := mailCfg.GetSMTP()
+		auth.Mailer = mailer.NewSMTP(host, port, username, password, mailCfg.GetFrom())
+	default:
+		log.Warn("Mail driver is log, mails are written to the log instead of being sent")
+		auth.Mailer = mailer.NewLog(logger)
+	}
+	auth.Accounts = authCfg
+
+	return nil
+}
+
+// initStorage выбирает реализацию хранилища по конфигу.
+// Вместе с хранилищем возвращается блокировка лидера для планировщика
+func initStorage(ctx context.Context, cfg *config.Config) (service.Repository, handler.ReadinessChecker, scheduler.Leader, error) {
+	if cfg.GetStorage() == config.StorageMemory {
+		log.Warn("Using in-memory storage, data will be lost on restart")
+		storage := memdb.NewStorage()
+		return repository.NewMemoryRepository(storage), storage, scheduler.Single{}, nil
+	}
+
+	pgCfg := &cfg.Database
+
+	dbPool, err := postgres.InitDBPool(ctx, pgCfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error initializing DB pool: %w", err)
+	}
+
+	replicaPool, err := postgres.InitReplicaPool(ctx, pgCfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error initializing replica pool: %w", err)
+	}
+
+	var readDB pgdb.DB = pgdb.NewTenantDB(dbPool)
+	if replicaPool != nil {
+		log.Info("Using read replica for list queries")
+		readDB = postgres.NewReplicaDB(readDB, pgdb.NewTenantDB(replicaPool), postgres.DefaultReplicaRetryInterval)
+	}
+
+	return repository.NewRepository(dbPool, readDB),
+		postgres.NewHealthChecker(dbPool, repository.SchemaVersion),
+		postgres.NewAdvisoryLock(postgres.PoolConnFactory(dbPool), schedulerLockKey),
+		nil
+}
+
 func (a *App) Run() error {
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%s", a.httpCfg.GetPort()),
@@ -74,11 +234,66 @@ func (a *App) Run() error {
 		IdleTimeout:  a.httpCfg.GetIdleTimeout(),
 	}
 
+	var redirectServer *http.Server
+
+	if a.httpCfg.TLSEnabled() {
+		reloader, err := tlsutil.NewReloader(
+			a.httpCfg.GetTLSCertFile(),
+			a.httpCfg.GetTLSKeyFile(),
+			a.httpCfg.GetTLSClientCAFile(),
+			tlsutil.DefaultCheckInterval,
+		)
+		if err != nil {
+			return fmt.Errorf("error loading tls certificates: %w", err)
+		}
+		server.TLSConfig = reloader.TLSConfig()
+
+		if port := a.httpCfg.GetTLSRedirectPort(); port != "" {
+			redirectServer = &http.Server{
+				Addr:         fmt.Sprintf(":%s", port),
+				Handler:      tlsutil.RedirectHandler(a.httpCfg.GetPort()),
+				ReadTimeout:  a.httpCfg.GetTimeout(),
+				WriteTimeout: a.httpCfg.GetTimeout(),
+			}
+		}
+	}
+
 	// Запуск сервера
 	go func() {
-		log.Info("Starting HTTP server", "addr", server.Addr)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Error("HTTP server ListenAndServe failed", log.Any("err", err))
+		if server.TLSConfig == nil {
+			log.Info("Starting HTTP server", "addr", server.Addr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("HTTP server ListenAndServe failed", log.Any("err", err))
+			}
+			return
+		}
+
+		log.Info("Starting HTTPS server", "addr", server.Addr, "mtls", a.httpCfg.GetTLSClientCAFile() != "")
+		if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("HTTPS server ListenAndServeTLS failed", log.Any("err", err))
+		}
+	}()
+
+	if redirectServer != nil {
+		go func() {
+			log.Info("Starting HTTP to HTTPS redirect", "addr", redirectServer.Addr)
+			if err := redirectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("Redirect server ListenAndServe failed", log.Any("err", err))
+			}
+		}()
+	}
+
+	if a.scheduler != nil {
+		log.Info("Starting scheduler", "interval", a.cfg.Scheduler.GetInterval().String())
+		a.scheduler.Start(context.Background())
+	}
+
+	// SIGHUP перечитывает конфиг без остановки сервера
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			a.reloadConfig()
 		}
 	}()
 
@@ -89,15 +304,46 @@ func (a *App) Run() error {
 	<-quit
 	log.Info("Shutdown signal received")
 
+	// Сначала /readyz начинает отвечать 503, и только после задержки закрываем сервер,
+	// чтобы балансировщик успел вывести инстанс из ротации
+	a.shuttingDown.Store(true)
+	time.Sleep(a.httpCfg.GetShutdownDelay())
+
 	// Контекст с таймаутом на graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if redirectServer != nil {
+		if err := redirectServer.Shutdown(ctx); err != nil {
+			log.Error("Redirect server shutdown failed", log.Any("err", err))
+		}
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		log.Error("Server shutdown failed", log.Any("err", err))
 		return err
 	}
 
+	// Планировщик останавливаем после сервера и отдаем лидерство другому экземпляру
+	if a.scheduler != nil {
+		if err := a.scheduler.Stop(ctx); err != nil {
+			log.Error("Scheduler shutdown failed", log.Any("err", err))
+		}
+	}
+
+	if err := a.auth.WaitMails(ctx); err != nil {
+		log.Error("Account emails were not sent before shutdown", log.Any("err", err))
+	}
+
+	// По этим счетчикам решаем, можно ли убирать корневые алиасы API
+	if calls := a.deprecations.Snapshot(); len(calls) > 0 {
+		log.Warn("Deprecated API routes were called", "calls", calls)
+	}
+
+	if a.cache != nil {
+		log.Info("Cache stats", "stats", a.cache.Stats())
+	}
+
 	select {
 	case <-ctx.Done():
 		log.Warn("Shutdown timeout exceeded")
@@ -107,3 +353,20 @@ func (a *App) Run() error {
 
 	return nil
 }
+
+// reloadConfig применяет новую секцию runtime. При ошибке остаются прежние настройки
+func (a *App) reloadConfig() {
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		log.Error("Config reload failed, keeping current settings", log.Any("err", err))
+		return
+	}
+
+	a.runtime.Update(cfg.Runtime)
+
+	if a.cfg.RequiresRestart(cfg) {
+		log.Warn("Config changes outside the runtime section require a restart")
+	}
+
+	log.Info("Config reloaded", "log_level", cfg.Runtime.LogLevel)
+}
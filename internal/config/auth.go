@@ -0,0 +1,70 @@
+package config
+
+import "time"
+
+// authConfig - пароли и ссылки из писем: подтверждение почты и сброс пароля
+type authConfig struct {
+	Password        passwordConfig `yaml:"password"`
+	VerificationTTL time.Duration  `yaml:"verification_ttl" env:"AUTH_VERIFICATION_TTL" env-default:"48h" validate:"gt=0"`
+	ResetTTL        time.Duration  `yaml:"reset_ttl" env:"AUTH_RESET_TTL" env-default:"1h" validate:"gt=0"`
+	// LinkBaseURL - адрес клиента, на который ведут ссылки из писем
+	LinkBaseURL string `yaml:"link_base_url" env:"AUTH_LINK_BASE_URL" env-default:"http://localhost:8080" validate:"url"`
+	// RequireVerified запрещает вход, пока почта не подтверждена
+	RequireVerified bool `yaml:"require_verified_email" env:"AUTH_REQUIRE_VERIFIED_EMAIL" env-default:"false"`
+	// RejectDummy запрещает токенам /dummyLogin изменяющие запросы, они остаются только для чтения
+	RejectDummy bool `yaml:"reject_dummy_writes" env:"AUTH_REJECT_DUMMY_WRITES" env-default:"false"`
+}
+
+// passwordConfig - политика паролей и хеширование. Классы символов: строчные, заглавные, цифры, остальные.
+// Смена hasher или его параметров не требует миграции: старые хеши пересчитываются при входе
+type passwordConfig struct {
+	MinLength      int          `yaml:"min_length" env:"PASSWORD_MIN_LENGTH" env-default:"8" validate:"gte=1,lte=72"`
+	MinClasses     int          `yaml:"min_classes" env:"PASSWORD_MIN_CLASSES" env-default:"2" validate:"gte=1,lte=4"`
+	BreachListFile string       `yaml:"breach_list_file" env:"PASSWORD_BREACH_LIST_FILE"`
+	Hasher         string       `yaml:"hasher" env:"PASSWORD_HASHER" env-default:"bcrypt" validate:"oneof=bcrypt argon2id"`
+	BcryptCost     int          `yaml:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" env-default:"10" validate:"gte=4,lte=31"`
+	Argon2id       argon2Config `yaml:"argon2id"`
+}
+
+// argon2Config - стоимость argon2id, по умолчанию минимальные параметры из рекомендаций OWASP
+type argon2Config struct {
+	Memory      uint32 `yaml:"memory_kib" env:"PASSWORD_ARGON2_MEMORY_KIB" env-default:"19456" validate:"gte=8"`
+	Iterations  uint32 `yaml:"iterations" env:"PASSWORD_ARGON2_ITERATIONS" env-default:"2" validate:"gte=1"`
+	Parallelism uint8  `yaml:"parallelism" env:"PASSWORD_ARGON2_PARALLELISM" env-default:"1" validate:"gte=1"`
+}
+
+func (a *authConfig) GetVerificationTTL() time.Duration {
+	return a.VerificationTTL
+}
+
+func (a *authConfig) GetResetTTL() time.Duration {
+	return a.ResetTTL
+}
+
+func (a *authConfig) GetLinkBaseURL() string {
+	return a.LinkBaseURL
+}
+
+func (a *authConfig) RequireVerifiedEmail() bool {
+	return a.RequireVerified
+}
+
+func (a *authConfig) RejectDummyWrites() bool {
+	return a.RejectDummy
+}
+
+func (a *authConfig) GetPasswordPolicy() (minLength, minClasses int, breachListFile string) {
+	return a.Password.MinLength, a.Password.MinClasses, a.Password.BreachListFile
+}
+
+func (a *authConfig) GetPasswordHasher() string {
+	return a.Password.Hasher
+}
+
+func (a *authConfig) GetBcryptCost() int {
+	return a.Password.BcryptCost
+}
+
+func (a *authConfig) GetArgon2idParams() (memoryKiB, iterations uint32, parallelism uint8) {
+	return a.Password.Argon2id.Memory, a.Password.Argon2id.Iterations, a.Password.Argon2id.Parallelism
+}
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// cacheConfig - кеш выборок ПВЗ, приемок и товаров в памяти процесса.
+// Инвалидация точная, но только внутри экземпляра: при нескольких экземплярах
+// другой экземпляр увидит изменение не раньше, чем через ttl
+type cacheConfig struct {
+	Enabled bool          `yaml:"enabled" env:"CACHE_ENABLED" env-default:"false"`
+	Size    int           `yaml:"size" env:"CACHE_SIZE" env-default:"10000" validate:"gt=0"`
+	TTL     time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"30s" validate:"gt=0"`
+}
+
+func (c *cacheConfig) IsEnabled() bool {
+	return c.Enabled
+}
+
+func (c *cacheConfig) GetSize() int {
+	return c.Size
+}
+
+func (c *cacheConfig) GetTTL() time.Duration {
+	return c.TTL
+}
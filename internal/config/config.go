@@ -1,19 +1,27 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"reflect"
 	"time"
 
+	"github.com/ilyakaznacheev/cleanenv"
 	"github.com/joho/godotenv"
 )
 
 const (
-	configPath = "./configs/config.yaml"
+	DefaultConfigPath = "./configs/config.yaml"
+	ConfigPathEnv     = "PVZ_CONFIG"
 )
 
 type PGConfig interface {
 	GetDSN() string
+	GetMaxConns() int32
+	GetReplicaDSN() string
+	GetReplicaMaxConns() int32
 }
 
 type HTTPConfig interface {
@@ -21,28 +29,133 @@ type HTTPConfig interface {
 	GetHost() string
 	GetTimeout() time.Duration
 	GetIdleTimeout() time.Duration
+	GetShutdownDelay() time.Duration
+	DocsEnabled() bool
+	TLSEnabled() bool
+	GetTLSCertFile() string
+	GetTLSKeyFile() string
+	GetTLSClientCAFile() string
+	GetTLSRedirectPort() string
+}
+
+type StorageConfig interface {
+	GetStorage() string
+}
+
+type SchedulerConfig interface {
+	IsEnabled() bool
+	GetInterval() time.Duration
+}
+
+type CacheConfig interface {
+	IsEnabled() bool
+	GetSize() int
+	GetTTL() time.Duration
+}
+
+type WebhookConfig interface {
+	GetDeliveryTimeout() time.Duration
+	GetDeliveryWorkers() int
+	GetDeliveryPassTimeout() time.Duration
+	GetMaxAttempts() int
+	GetRetryBackoff() (base time.Duration, max time.Duration)
+	GetAllowPrivateTargets() bool
+}
+
+type AuthConfig interface {
+	GetVerificationTTL() time.Duration
+	GetResetTTL() time.Duration
+	GetLinkBaseURL() string
+	RequireVerifiedEmail() bool
+	GetPasswordPolicy() (minLength, minClasses int, breachListFile string)
+	GetPasswordHasher() string
+	GetBcryptCost() int
+	GetArgon2idParams() (memoryKiB, iterations uint32, parallelism uint8)
+	RejectDummyWrites() bool
+}
+
+type MailConfig interface {
+	GetDriver() string
+	GetFrom() string
+	GetSMTP() (host, port, username, password string)
 }
 
 type JWTConfig interface {
 	GetSecret() string
 }
 
-func LoadConfig() (string, error) {
+// Config - вся конфигурация сервиса.
+// Значения берутся слоями: env-default, затем файл, затем переменные окружения
+type Config struct {
+	Env       string          `yaml:"env" env:"APP_ENV" env-default:"prod" validate:"oneof=dev test prod"`
+	Storage   string          `yaml:"storage" env:"STORAGE" env-default:"postgres" validate:"oneof=postgres memory"`
+	HTTP      httpConfig      `yaml:"http"`
+	Database  pgConfig        `yaml:"database"`
+	JWT       jwtConfig       `yaml:"jwt"`
+	Auth      authConfig      `yaml:"auth"`
+	Mail      mailConfig      `yaml:"mail"`
+	Scheduler schedulerConfig `yaml:"scheduler"`
+	Webhooks  webhookConfig   `yaml:"webhooks"`
+	Cache     cacheConfig     `yaml:"cache"`
+	Runtime   RuntimeConfig   `yaml:"runtime"`
+}
+
+func (c *Config) GetStorage() string {
+	return c.Storage
+}
+
+// ResolvePath выбирает файл конфигурации: флаг --config, затем PVZ_CONFIG, затем путь по умолчанию
+func ResolvePath(flagPath string) string {
+	if flagPath != "" {
+		return flagPath
+	}
+	if envPath := os.Getenv(ConfigPathEnv); envPath != "" {
+		return envPath
+	}
+	return DefaultConfigPath
+}
+
+// Load читает и проверяет конфигурацию.
+// Файл по умолчанию необязателен, явно указанный файл должен существовать
+func Load(path string) (*Config, error) {
 	if err := LoadEnv(); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	cfgPath := configPath
+	var cfg Config
+
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+	case errors.Is(err, fs.ErrNotExist) && path == DefaultConfigPath:
+		if err := cleanenv.ReadEnv(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to read config from environment: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
 
-	if _, err := os.Stat(cfgPath); err != nil {
-		return "", fmt.Errorf("%s file not found", cfgPath)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	return cfgPath, nil
+	return &cfg, nil
+}
+
+// RequiresRestart сообщает, изменилось ли что-то кроме секции runtime
+func (c *Config) RequiresRestart(next *Config) bool {
+	current, updated := *c, *next
+	current.Runtime, updated.Runtime = RuntimeConfig{}, RuntimeConfig{}
+
+	return !reflect.DeepEqual(current, updated)
 }
 
+// LoadEnv подгружает .env, если он есть. Без файла используются переменные окружения процесса
 func LoadEnv() error {
-	if err := godotenv.Load(); err != nil {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("error loading .env file: %w", err)
 	}
 	return nil
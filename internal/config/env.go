@@ -0,0 +1,17 @@
+package config
+
+// Окружения сервиса. В prod нет ручки /dummyLogin, в dev ее токены помечаются claim dummy
+const (
+	EnvDev  = "dev"
+	EnvTest = "test"
+	EnvProd = "prod"
+)
+
+func (c *Config) GetEnv() string {
+	return c.Env
+}
+
+// DummyLoginEnabled сообщает, регистрируется ли ручка тестовых токенов
+func (c *Config) DummyLoginEnabled() bool {
+	return c.Env != EnvProd
+}
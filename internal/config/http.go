@@ -1,32 +1,31 @@
 package config
 
 import (
-	"fmt"
 	"time"
-
-	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type httpConfig struct {
-	Port        string        `yaml:"port"  env-default:"8080"`
-	Host        string        `yaml:"host"  env-default:"localhost"`
-	Timeout     time.Duration `yaml:"timeout" env-default:"5s"`
-	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
-}
-
-func HTTPConfigLoad() (*httpConfig, error) {
-	path, err := LoadConfig()
-	if err != nil {
-		return nil, err
-	}
+	Port        string        `yaml:"port" env:"HTTP_PORT" env-default:"8080" validate:"required,numeric"`
+	Host        string        `yaml:"host" env:"HTTP_HOST" env-default:"localhost"`
+	Timeout     time.Duration `yaml:"timeout" env:"HTTP_TIMEOUT" env-default:"5s" validate:"gt=0"`
+	IdleTimeout time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"60s" validate:"gt=0"`
+	// Сколько ждать после сигнала остановки, чтобы балансировщик успел увидеть 503 на /readyz
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"HTTP_SHUTDOWN_DELAY" env-default:"5s" validate:"gte=0"`
+	// Выключает спецификацию (/openapi.yaml, /openapi.json) и Swagger UI на /docs.
+	// Флаг обратный: false из файла cleanenv заменил бы значением по умолчанию
+	DisableDocs bool `yaml:"disable_docs" env:"HTTP_DISABLE_DOCS"`
 
-	var httpCfg httpConfig
-
-	if err := cleanenv.ReadConfig(path, &httpCfg); err != nil {
-		return nil, fmt.Errorf("%s", err)
-	}
+	TLS tlsConfig `yaml:"tls"`
+}
 
-	return &httpCfg, nil
+// tlsConfig - без cert_file и key_file сервер работает по HTTP
+type tlsConfig struct {
+	CertFile string `yaml:"cert_file" env:"HTTP_TLS_CERT_FILE" validate:"required_with=KeyFile ClientCAFile RedirectPort"`
+	KeyFile  string `yaml:"key_file" env:"HTTP_TLS_KEY_FILE" validate:"required_with=CertFile"`
+	// Бандл CA для сертификатов устройств, включает mTLS
+	ClientCAFile string `yaml:"client_ca_file" env:"HTTP_TLS_CLIENT_CA_FILE"`
+	// Порт для редиректа с HTTP на HTTPS, пустой - редирект выключен
+	RedirectPort string `yaml:"redirect_port" env:"HTTP_TLS_REDIRECT_PORT" validate:"omitempty,numeric"`
 }
 
 func (cfg *httpConfig) GetPort() string {
@@ -44,3 +43,31 @@ func (cfg *httpConfig) GetTimeout() time.Duration {
 func (cfg *httpConfig) GetIdleTimeout() time.Duration {
 	return cfg.IdleTimeout
 }
+
+func (cfg *httpConfig) GetShutdownDelay() time.Duration {
+	return cfg.ShutdownDelay
+}
+
+func (cfg *httpConfig) DocsEnabled() bool {
+	return !cfg.DisableDocs
+}
+
+func (cfg *httpConfig) TLSEnabled() bool {
+	return cfg.TLS.CertFile != ""
+}
+
+func (cfg *httpConfig) GetTLSCertFile() string {
+	return cfg.TLS.CertFile
+}
+
+func (cfg *httpConfig) GetTLSKeyFile() string {
+	return cfg.TLS.KeyFile
+}
+
+func (cfg *httpConfig) GetTLSClientCAFile() string {
+	return cfg.TLS.ClientCAFile
+}
+
+func (cfg *httpConfig) GetTLSRedirectPort() string {
+	return cfg.TLS.RedirectPort
+}
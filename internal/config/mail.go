@@ -0,0 +1,32 @@
+package config
+
+const (
+	MailDriverLog  = "log"
+	MailDriverSMTP = "smtp"
+)
+
+// mailConfig - отправка писем. Драйвер log только пишет письма в лог, для разработки
+type mailConfig struct {
+	Driver string     `yaml:"driver" env:"MAIL_DRIVER" env-default:"log" validate:"oneof=log smtp"`
+	From   string     `yaml:"from" env:"MAIL_FROM" env-default:"no-reply@pvz.local" validate:"email"`
+	SMTP   smtpConfig `yaml:"smtp"`
+}
+
+type smtpConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST" validate:"required"`
+	Port     string `yaml:"port" env:"SMTP_PORT" env-default:"587" validate:"numeric"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+func (m *mailConfig) GetDriver() string {
+	return m.Driver
+}
+
+func (m *mailConfig) GetFrom() string {
+	return m.From
+}
+
+func (m *mailConfig) GetSMTP() (host, port, username, password string) {
+	return m.SMTP.Host, m.SMTP.Port, m.SMTP.Username, m.SMTP.Password
+}
@@ -2,38 +2,20 @@ package config
 
 import (
 	"fmt"
-	"strconv"
-
-	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type pgConfig struct {
-	Name     string `yaml:"database_name" env-required:"true"`
-	Host     string `yaml:"database_host" env-required:"true"`
-	Port     string `yaml:"database_port" env-required:"true"`
-	User     string `yaml:"database_user" env-required:"true"`
-	SSLMode  string `yaml:"database_ssl_mode" env-required:"true"`
-	Password string `env:"DATABASE_PASSWORD" env-required:"true"`
-}
-
-func PGConfigLoad() (*pgConfig, error) {
-	path, err := LoadConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	var pgCfg pgConfig
-
-	// Читаем конфиг-файл и заполняем нашу структуру
-	if err := cleanenv.ReadConfig(path, &pgCfg); err != nil {
-		return nil, fmt.Errorf("%s", err)
-	}
-
-	if _, err := strconv.Atoi(pgCfg.Port); err != nil {
-		return nil, fmt.Errorf("invalid database port: %s", err)
-	}
-
-	return &pgCfg, nil
+	Name     string `yaml:"name" env:"DATABASE_NAME" validate:"required"`
+	Host     string `yaml:"host" env:"DATABASE_HOST" validate:"required"`
+	Port     string `yaml:"port" env:"DATABASE_PORT" env-default:"5432" validate:"required,numeric"`
+	User     string `yaml:"user" env:"DATABASE_USER" validate:"required"`
+	SSLMode  string `yaml:"ssl_mode" env:"DATABASE_SSL_MODE" env-default:"disable" validate:"required"`
+	Password string `yaml:"password" env:"DATABASE_PASSWORD" validate:"required"`
+	MaxConns int32  `yaml:"max_conns" env:"DATABASE_MAX_CONNS" env-default:"10" validate:"gt=0"`
+
+	// Реплика для чтения необязательна, без нее все запросы идут на основную базу
+	ReplicaDSN      string `yaml:"replica_dsn" env:"DATABASE_REPLICA_DSN"`
+	ReplicaMaxConns int32  `yaml:"replica_max_conns" env:"DATABASE_REPLICA_MAX_CONNS" env-default:"10" validate:"gt=0"`
 }
 
 func (cfg *pgConfig) GetDSN() string {
@@ -47,3 +29,15 @@ func (cfg *pgConfig) GetDSN() string {
 		cfg.SSLMode,
 	)
 }
+
+func (cfg *pgConfig) GetMaxConns() int32 {
+	return cfg.MaxConns
+}
+
+func (cfg *pgConfig) GetReplicaDSN() string {
+	return cfg.ReplicaDSN
+}
+
+func (cfg *pgConfig) GetReplicaMaxConns() int32 {
+	return cfg.ReplicaMaxConns
+}
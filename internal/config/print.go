@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "******"
+
+// Redacted возвращает копию конфига со скрытыми секретами
+func (c *Config) Redacted() Config {
+	cfg := *c
+
+	cfg.Database.Password = redact(cfg.Database.Password)
+	cfg.Database.ReplicaDSN = redact(cfg.Database.ReplicaDSN)
+	cfg.JWT.Secret = redact(cfg.JWT.Secret)
+	cfg.Mail.SMTP.Password = redact(cfg.Mail.SMTP.Password)
+
+	return cfg
+}
+
+// Print выводит итоговый конфиг в yaml без секретов
+func (c *Config) Print(w io.Writer) error {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
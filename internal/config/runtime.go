@@ -0,0 +1,81 @@
+package config
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// RuntimeConfig - настройки, которые перечитываются по SIGHUP без перезапуска
+type RuntimeConfig struct {
+	LogLevel   string           `yaml:"log_level" env:"LOG_LEVEL" env-default:"info" validate:"oneof=debug info warn error"`
+	Pagination PaginationConfig `yaml:"pagination"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Receptions ReceptionsConfig `yaml:"receptions"`
+}
+
+type PaginationConfig struct {
+	DefaultLimit int `yaml:"default_limit" env:"PAGINATION_DEFAULT_LIMIT" env-default:"10" validate:"gt=0"`
+	MaxLimit     int `yaml:"max_limit" env:"PAGINATION_MAX_LIMIT" env-default:"30" validate:"gtefield=DefaultLimit"`
+}
+
+// RateLimitConfig - ограничение запросов с одного адреса, RPS = 0 выключает лимит
+type RateLimitConfig struct {
+	RPS   float64 `yaml:"rps" env:"RATE_LIMIT_RPS" env-default:"0" validate:"gte=0"`
+	Burst int     `yaml:"burst" env:"RATE_LIMIT_BURST" env-default:"20" validate:"gte=1"`
+}
+
+// ReceptionsConfig - что делать с приемками, которые долго не закрывают.
+// Нулевой порог выключает действие, по умолчанию оба порога выключены
+type ReceptionsConfig struct {
+	MaxOpen     time.Duration `yaml:"max_open" env:"RECEPTION_MAX_OPEN" env-default:"0s" validate:"gte=0"`
+	OnTimeout   string        `yaml:"on_timeout" env:"RECEPTION_ON_TIMEOUT" env-default:"close" validate:"oneof=close flag"`
+	RemindAfter time.Duration `yaml:"remind_after" env:"RECEPTION_REMIND_AFTER" env-default:"0s" validate:"gte=0"`
+}
+
+// Runtime дает потокобезопасный доступ к текущим RuntimeConfig
+type Runtime struct {
+	cfg   atomic.Pointer[RuntimeConfig]
+	level *slog.LevelVar
+}
+
+func NewRuntime(cfg RuntimeConfig) *Runtime {
+	r := &Runtime{level: new(slog.LevelVar)}
+	r.Update(cfg)
+
+	return r
+}
+
+func (r *Runtime) Update(cfg RuntimeConfig) {
+	r.cfg.Store(&cfg)
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+	r.level.Set(level)
+}
+
+// LogLevel возвращает уровень логирования, который меняется вместе с конфигом
+func (r *Runtime) LogLevel() slog.Leveler {
+	return r.level
+}
+
+func (r *Runtime) GetDefaultLimit() int {
+	return r.cfg.Load().Pagination.DefaultLimit
+}
+
+func (r *Runtime) GetMaxLimit() int {
+	return r.cfg.Load().Pagination.MaxLimit
+}
+
+func (r *Runtime) GetRateLimit() (float64, int) {
+	cfg := r.cfg.Load()
+	return cfg.RateLimit.RPS, cfg.RateLimit.Burst
+}
+
+// GetReceptionTimeout возвращает пороги для зависших приемок и действие по истечении max_open
+func (r *Runtime) GetReceptionTimeout() (time.Duration, time.Duration, string) {
+	cfg := r.cfg.Load().Receptions
+	return cfg.MaxOpen, cfg.RemindAfter, cfg.OnTimeout
+}
@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+// schedulerConfig - фоновые задачи. При нескольких экземплярах задачи выполняет один,
+// лидер выбирается через advisory lock в Postgres
+type schedulerConfig struct {
+	Enabled  bool          `yaml:"enabled" env:"SCHEDULER_ENABLED" env-default:"true"`
+	Interval time.Duration `yaml:"interval" env:"SCHEDULER_INTERVAL" env-default:"1m" validate:"gt=0"`
+}
+
+func (s *schedulerConfig) IsEnabled() bool {
+	return s.Enabled
+}
+
+func (s *schedulerConfig) GetInterval() time.Duration {
+	return s.Interval
+}
@@ -0,0 +1,6 @@
+package config
+
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
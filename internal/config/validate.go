@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validate проверяет конфиг целиком и перечисляет все ошибки по ключам yaml
+func (c *Config) Validate() error {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("yaml"), ",")[0]
+	})
+
+	var fieldErrs validator.ValidationErrors
+	if err := v.Struct(c); err != nil && !errors.As(err, &fieldErrs) {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	problems := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		key := strings.TrimPrefix(fe.Namespace(), "Config.")
+
+		// Без Postgres секция database не нужна
+		if c.Storage == StorageMemory && strings.HasPrefix(key, "database.") {
+			continue
+		}
+		// SMTP настраивается, только если письма через него и отправляются
+		if c.Mail.Driver != MailDriverSMTP && strings.HasPrefix(key, "mail.smtp.") {
+			continue
+		}
+
+		problems = append(problems, key+": "+describe(fe))
+	}
+
+	// Драйвер log пишет письма в лог, а ссылки из них дают войти в чужой аккаунт
+	if c.Env == EnvProd && c.Mail.Driver == MailDriverLog {
+		problems = append(problems, "mail.driver: must not be log in prod")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("invalid config: %s", strings.Join(problems, "; "))
+}
+
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		fields := strings.Fields(fe.Param())
+		for i, field := range fields {
+			fields[i] = snakeCase(field)
+		}
+		return "is required when any of " + strings.Join(fields, ", ") + " is set"
+	case "numeric":
+		return "must be a number"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "url":
+		return "must be a URL"
+	case "email":
+		return "must be an email address"
+	case "gtefield":
+		return "must not be less than " + snakeCase(fe.Param())
+	default:
+		return fmt.Sprintf("failed %q check", fe.Tag())
+	}
+}
+
+// snakeCase переводит имя поля Go в ключ yaml: DefaultLimit -> default_limit, ClientCAFile -> client_ca_file
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
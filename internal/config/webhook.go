@@ -0,0 +1,42 @@
+package config
+
+import "time"
+
+// webhookConfig - исходящие вебхуки. Неудачная доставка повторяется с экспоненциальной
+// задержкой от retry_backoff до retry_backoff_max, после max_attempts попыток доставка
+// переходит в dead. За проход планировщика доставки отправляются параллельно, не больше workers
+// за раз, новые попытки после pass_timeout не начинаются. allow_private_targets разрешает адреса
+// во внутренней сети - только для локальной разработки и тестов
+type webhookConfig struct {
+	Timeout         time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT" env-default:"5s" validate:"gt=0"`
+	Workers         int           `yaml:"workers" env:"WEBHOOK_WORKERS" env-default:"8" validate:"gte=1"`
+	PassTimeout     time.Duration `yaml:"pass_timeout" env:"WEBHOOK_PASS_TIMEOUT" env-default:"20s" validate:"gt=0"`
+	MaxAttempts     int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS" env-default:"8" validate:"gte=1"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff" env:"WEBHOOK_RETRY_BACKOFF" env-default:"30s" validate:"gt=0"`
+	RetryBackoffMax time.Duration `yaml:"retry_backoff_max" env:"WEBHOOK_RETRY_BACKOFF_MAX" env-default:"1h" validate:"gtefield=RetryBackoff"`
+	AllowPrivate    bool          `yaml:"allow_private_targets" env:"WEBHOOK_ALLOW_PRIVATE_TARGETS" env-default:"false"`
+}
+
+func (w *webhookConfig) GetDeliveryTimeout() time.Duration {
+	return w.Timeout
+}
+
+func (w *webhookConfig) GetDeliveryWorkers() int {
+	return w.Workers
+}
+
+func (w *webhookConfig) GetDeliveryPassTimeout() time.Duration {
+	return w.PassTimeout
+}
+
+func (w *webhookConfig) GetMaxAttempts() int {
+	return w.MaxAttempts
+}
+
+func (w *webhookConfig) GetRetryBackoff() (time.Duration, time.Duration) {
+	return w.RetryBackoff, w.RetryBackoffMax
+}
+
+func (w *webhookConfig) GetAllowPrivateTargets() bool {
+	return w.AllowPrivate
+}
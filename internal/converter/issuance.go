@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/model"
+)
+
+func ToIssuanceFromIssueProductRequest(request *dto.IssueProductJSONRequestBody, employeeID string) *model.Issuance {
+	return &model.Issuance{
+		ProductID:        request.ProductId,
+		PvzID:            request.PvzId,
+		EmployeeID:       employeeID,
+		ConfirmationCode: request.ConfirmationCode,
+	}
+}
+
+func ToIssuanceResponseFromIssuance(issuance *model.Issuance) *dto.Issuance {
+	return &dto.Issuance{
+		Id:               ptr(issuance.ID),
+		ProductId:        ptr(issuance.ProductID),
+		PvzId:            ptr(issuance.PvzID),
+		IssuedAt:         ptr(issuance.IssuedAt),
+		EmployeeId:       ptr(issuance.EmployeeID),
+		ConfirmationCode: ptr(issuance.ConfirmationCode),
+	}
+}
+
+func ToProductReturnFromReturnProductRequest(request *dto.ReturnProductJSONRequestBody, employeeID string) *model.ProductReturn {
+	return &model.ProductReturn{
+		ProductID:  request.ProductId,
+		PvzID:      request.PvzId,
+		EmployeeID: employeeID,
+		Reason:     request.Reason,
+	}
+}
+
+func ToReturnResponseFromProductReturn(ret *model.ProductReturn) *dto.ProductReturn {
+	return &dto.ProductReturn{
+		Id:         ptr(ret.ID),
+		ProductId:  ptr(ret.ProductID),
+		PvzId:      ptr(ret.PvzID),
+		ReturnedAt: ptr(ret.ReturnedAt),
+		EmployeeId: ptr(ret.EmployeeID),
+		Reason:     ptr(ret.Reason),
+	}
+}
+
+func ToStockResponseFromProducts(pvz *model.Pvz, products []model.Product) *dto.Stock {
+	resp := &dto.Stock{
+		PvzId:    pvz.ID,
+		Count:    len(products),
+		Products: make([]dto.Product, 0, len(products)),
+	}
+
+	for i := range products {
+		resp.Products = append(resp.Products, *ToProductResponseFromProduct(&products[i]))
+	}
+
+	return resp
+}
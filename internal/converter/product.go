@@ -1,21 +1,78 @@
 package converter
 
 import (
+	"github.com/google/uuid"
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/model"
 )
 
-func ToProductResponseFromProduct(product *model.Product) *dto.ProductResponse {
-	return &dto.ProductResponse{
-		ID:          product.ID.String(),
-		DateTime:    product.DateTime,
-		TypeProduct: product.TypeProduct,
-		ReceptionID: product.ReceptionID.String(),
+func ToProductResponseFromProduct(product *model.Product) *dto.Product {
+	resp := &dto.Product{
+		Id:          ptr(product.ID),
+		DateTime:    ptr(product.DateTime),
+		Type:        dto.ProductType(product.TypeProduct),
+		ReceptionId: product.ReceptionID,
+	}
+
+	if product.Barcode != "" {
+		resp.Barcode = ptr(product.Barcode)
+	}
+	if product.SKU != "" {
+		resp.Sku = ptr(product.SKU)
+	}
+	if product.WeightGrams != 0 {
+		resp.WeightGrams = ptr(product.WeightGrams)
+	}
+
+	// Нулевые габариты означают, что посылку не измеряли
+	if product.Dimensions != (model.Dimensions{}) {
+		resp.Dimensions = &dto.Dimensions{
+			LengthMm: product.Dimensions.LengthMM,
+			WidthMm:  product.Dimensions.WidthMM,
+			HeightMm: product.Dimensions.HeightMM,
+		}
+	}
+
+	if product.CellID != uuid.Nil {
+		resp.CellId = ptr(product.CellID)
+	}
+
+	return resp
+}
+
+func ToProductFromCreateProductRequest(request *dto.CreateProductJSONRequestBody) *model.Product {
+	product := &model.Product{
+		TypeProduct: string(request.Type),
+		Barcode:     deref(request.Barcode),
+		SKU:         deref(request.Sku),
+		WeightGrams: deref(request.WeightGrams),
+		Dimensions:  toDimensions(request.Dimensions),
+	}
+
+	if request.CellId != nil {
+		product.CellID = *request.CellId
+	}
+
+	return product
+}
+
+func toDimensions(dimensions *dto.Dimensions) model.Dimensions {
+	if dimensions == nil {
+		return model.Dimensions{}
+	}
+
+	return model.Dimensions{
+		LengthMM: dimensions.LengthMm,
+		WidthMM:  dimensions.WidthMm,
+		HeightMM: dimensions.HeightMm,
 	}
 }
 
-func ToProductFromCreateProductRequest(request *dto.CreateProductRequest) *model.Product {
-	return &model.Product{
-		TypeProduct: request.TypeProduct,
+// deref возвращает нулевое значение для отсутствующего поля запроса
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
 	}
+	return *v
 }
@@ -1,27 +1,222 @@
 package converter
 
 import (
+	"fmt"
+	"math"
+	"time"
+
 	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/model"
 )
 
-func ToCreatePvzResponseFromPvz(pvz *model.Pvz) *dto.PvzResponse {
-	return &dto.PvzResponse{
-		ID:               pvz.ID.String(),
-		RegistrationDate: pvz.RegistrationDate,
-		City:             pvz.City,
+func ToCreatePvzResponseFromPvz(pvz *model.Pvz) *dto.PVZ {
+	return &dto.PVZ{
+		Id:               ptr(pvz.ID),
+		RegistrationDate: ptr(pvz.RegistrationDate),
+		City:             dto.PVZCity(pvz.City),
+		Status:           ptr(dto.PVZStatus(pvz.Status)),
 	}
 }
 
-func ToPvzFromCreatePvzRequest(dto *dto.CreatePvzRequest) *model.Pvz {
+func ToPvzFromCreatePvzRequest(req *dto.CreatePvzJSONRequestBody) *model.Pvz {
 	return &model.Pvz{
 		ID:   uuid.Nil,
-		City: dto.City,
+		City: string(req.City),
 	}
 }
 
-func ToPvzFromIDRequest(IDString string) (*model.Pvz, error) {
-	id, err := uuid.Parse(IDString)
-	return &model.Pvz{ID: id}, err
+var weekdayKeys = map[string]time.Weekday{
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"sun": time.Sunday,
+}
+
+func ToPvzPatchFromUpdatePvzRequest(req *dto.UpdatePvzJSONRequestBody, pvzID uuid.UUID) (*model.PvzPatch, error) {
+	patch := &model.PvzPatch{
+		ID:                     pvzID,
+		Address:                req.Address,
+		Timezone:               req.Timezone,
+		MaxDailyReceptions:     req.MaxDailyReceptions,
+		ReceptionOverrideUntil: req.ReceptionOverrideUntil,
+	}
+
+	if req.Coordinates != nil {
+		patch.Coordinates = &model.GeoPoint{Lat: req.Coordinates.Lat, Lng: req.Coordinates.Lng}
+	}
+
+	if req.WorkingHours != nil {
+		hours, err := toWorkingHours(req.WorkingHours)
+		if err != nil {
+			return nil, err
+		}
+		patch.WorkingHours = hours
+	}
+
+	return patch, nil
+}
+
+func toWorkingHours(req *dto.WorkingHours) (*model.WorkingHours, error) {
+	hours := &model.WorkingHours{}
+
+	if len(req.Weekly) > 0 {
+		hours.Weekly = make(map[time.Weekday]model.DayHours, len(req.Weekly))
+	}
+	for key, day := range req.Weekly {
+		weekday, ok := weekdayKeys[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday: %s", key)
+		}
+
+		dayHours, err := toDayHours(day.Open, day.Close)
+		if err != nil {
+			return nil, err
+		}
+		hours.Weekly[weekday] = dayHours
+	}
+
+	for _, exception := range req.Exceptions {
+		item := model.HoursException{Date: exception.Date.Time, Closed: exception.Closed}
+		if !exception.Closed {
+			hours, err := toDayHours(exception.Open, exception.Close)
+			if err != nil {
+				return nil, err
+			}
+			item.Hours = hours
+		}
+		hours.Exceptions = append(hours.Exceptions, item)
+	}
+
+	return hours, nil
+}
+
+func toDayHours(open, close string) (model.DayHours, error) {
+	openMinute, err := parseClock(open)
+	if err != nil {
+		return model.DayHours{}, err
+	}
+
+	closeMinute, err := parseClock(close)
+	if err != nil {
+		return model.DayHours{}, err
+	}
+
+	return model.DayHours{Open: openMinute, Close: closeMinute}, nil
+}
+
+// parseClock переводит HH:MM в минуты от полуночи, 24:00 допустимо как время закрытия
+func parseClock(clock string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%02d:%02d", &hour, &minute); err != nil || len(clock) != 5 {
+		return 0, fmt.Errorf("invalid time of day: %q", clock)
+	}
+
+	if hour < 0 || minute < 0 || minute > 59 || hour*60+minute > model.MinutesInDay {
+		return 0, fmt.Errorf("invalid time of day: %q", clock)
+	}
+
+	return hour*60 + minute, nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func ToPvzProfileResponseFromPvz(pvz *model.Pvz) *dto.PvzProfile {
+	resp := &dto.PvzProfile{
+		Id:                 ptr(pvz.ID),
+		RegistrationDate:   ptr(pvz.RegistrationDate),
+		City:               ptr(pvz.City),
+		Status:             ptr(dto.PvzProfileStatus(pvz.Status)),
+		Address:            ptr(pvz.Address),
+		Timezone:           ptr(pvz.Timezone),
+		MaxDailyReceptions: ptr(pvz.MaxDailyReceptions),
+	}
+
+	if pvz.Coordinates != nil {
+		resp.Coordinates = &dto.Coordinates{Lat: pvz.Coordinates.Lat, Lng: pvz.Coordinates.Lng}
+	}
+
+	if !pvz.ReceptionOverrideUntil.IsZero() {
+		resp.ReceptionOverrideUntil = ptr(pvz.ReceptionOverrideUntil)
+	}
+
+	if pvz.WorkingHours.IsSet() {
+		resp.WorkingHours = toWorkingHoursResponse(pvz.WorkingHours)
+	}
+
+	return resp
+}
+
+func toWorkingHoursResponse(hours model.WorkingHours) *dto.WorkingHours {
+	resp := &dto.WorkingHours{}
+
+	if len(hours.Weekly) > 0 {
+		resp.Weekly = make(map[string]dto.DayHours, len(hours.Weekly))
+	}
+	for key, weekday := range weekdayKeys {
+		if day, ok := hours.Weekly[weekday]; ok {
+			resp.Weekly[key] = dto.DayHours{Open: formatClock(day.Open), Close: formatClock(day.Close)}
+		}
+	}
+
+	for _, exception := range hours.Exceptions {
+		item := dto.HoursException{Date: openapi_types.Date{Time: exception.Date}, Closed: exception.Closed}
+		if !exception.Closed {
+			item.Open = formatClock(exception.Hours.Open)
+			item.Close = formatClock(exception.Hours.Close)
+		}
+		resp.Exceptions = append(resp.Exceptions, item)
+	}
+
+	return resp
+}
+
+const (
+	// DefaultNearbyRadius - радиус поиска ПВЗ в метрах, если он не задан
+	DefaultNearbyRadius = 5000
+	DefaultNearbyLimit  = 20
+)
+
+func ToNearbyQueryFromNearbyPvzParams(params *dto.GetNearbyPvzParams) *model.NearbyQuery {
+	query := &model.NearbyQuery{
+		Point:        model.GeoPoint{Lat: params.Lat, Lng: params.Lon},
+		RadiusMeters: DefaultNearbyRadius,
+		Limit:        DefaultNearbyLimit,
+	}
+
+	if params.Radius != nil {
+		query.RadiusMeters = *params.Radius
+	}
+	if params.Limit != nil {
+		query.Limit = *params.Limit
+	}
+
+	return query
+}
+
+func ToNearbyPvzResponseList(list []model.NearbyPvz) []dto.NearbyPvz {
+	resp := make([]dto.NearbyPvz, 0, len(list))
+
+	for _, nearby := range list {
+		pvz := ToPvzProfileResponseFromPvz(&nearby.Pvz)
+
+		resp = append(resp, dto.NearbyPvz{
+			Id:             pvz.Id,
+			City:           pvz.City,
+			Address:        pvz.Address,
+			Coordinates:    pvz.Coordinates,
+			Timezone:       pvz.Timezone,
+			WorkingHours:   pvz.WorkingHours,
+			DistanceMeters: ptr(math.Round(nearby.DistanceMeters)),
+			IsOpen:         ptr(nearby.IsOpen),
+		})
+	}
+
+	return resp
 }
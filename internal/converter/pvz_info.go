@@ -1,73 +1,77 @@
 package converter
 
 import (
-	"time"
-
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/model"
 )
 
-func ToPvzInfoQueryFromPvzInfoResponse(req *dto.PvzInfoRequest) (*model.PvzInfoQuery, error) {
-	const layout = time.RFC3339
-	start, err := time.Parse(layout, req.StartDate)
-	if err != nil && req.StartDate != "" {
-		return nil, err
-	}
+func ToPvzInfoQueryFromPvzListParams(params *dto.GetPvzListParams, defaultLimit, maxLimit int) *model.PvzInfoQuery {
+	ans := &model.PvzInfoQuery{}
 
-	end, err := time.Parse(layout, req.EndDate)
-	if err != nil && req.EndDate != "" {
-		return nil, err
+	if params.StartDate != nil {
+		ans.StartDate = *params.StartDate
 	}
-
-	ans := &model.PvzInfoQuery{
-		StartDate: start,
-		EndDate:   end,
-		Page:      req.Page,
-		Limit:     req.Limit,
+	if params.EndDate != nil {
+		ans.EndDate = *params.EndDate
+	}
+	if params.Page != nil {
+		ans.Page = *params.Page
+	}
+	if params.Limit != nil {
+		ans.Limit = *params.Limit
+	}
+	if params.IncludeInactive != nil {
+		ans.IncludeInactive = *params.IncludeInactive
 	}
 
-	setDefaultsPagination(ans)
-
-	return ans, nil
+	setDefaultsPagination(&ans.Page, &ans.Limit, defaultLimit, maxLimit)
 
+	return ans
 }
 
-func setDefaultsPagination(q *model.PvzInfoQuery) {
-	if q.Page < 1 {
-		q.Page = 1
+func setDefaultsPagination(page, limit *int, defaultLimit, maxLimit int) {
+	if *page < 1 {
+		*page = 1
 	}
-	if q.Limit < 1 || q.Limit > 30 {
-		q.Limit = 10
+	if *limit < 1 || *limit > maxLimit {
+		*limit = defaultLimit
 	}
 }
 
-func ToPvzInfoResponseList(pvzList []*model.Pvz) []dto.SinglePvzInfoResponse {
-	responseList := make([]dto.SinglePvzInfoResponse, 0, len(pvzList))
+// ptr нужен для необязательных полей сгенерированных моделей
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func ToPvzInfoResponseList(pvzList []*model.Pvz) []dto.PvzInfo {
+	responseList := make([]dto.PvzInfo, 0, len(pvzList))
 
 	for _, pvz := range pvzList {
 		pvzResp := ToCreatePvzResponseFromPvz(pvz)
 
 		receptions := make([]dto.ReceptionInfo, 0, len(pvz.Receptions))
 		for _, rec := range pvz.Receptions {
-			recResp := ToReceptionResponseFromReception(&rec)
-
-			products := make([]dto.ProductResponse, 0, len(rec.Products))
-			for _, prod := range rec.Products {
-				prodResp := ToProductResponseFromProduct(&prod)
-				products = append(products, *prodResp)
-			}
-
-			receptions = append(receptions, dto.ReceptionInfo{
-				ReceptionData: *recResp,
-				Products:      products,
-			})
+			receptions = append(receptions, *ToReceptionInfoFromReception(&rec))
 		}
 
-		responseList = append(responseList, dto.SinglePvzInfoResponse{
-			PvzData:    *pvzResp,
+		responseList = append(responseList, dto.PvzInfo{
+			Pvz:        *pvzResp,
 			Receptions: receptions,
 		})
 	}
 
 	return responseList
 }
+
+// ToReceptionInfoFromReception - приемка вместе с ее товарами
+func ToReceptionInfoFromReception(rec *model.Reception) *dto.ReceptionInfo {
+	products := make([]dto.Product, 0, len(rec.Products))
+	for i := range rec.Products {
+		products = append(products, *ToProductResponseFromProduct(&rec.Products[i]))
+	}
+
+	return &dto.ReceptionInfo{
+		Reception: *ToReceptionResponseFromReception(rec),
+		Products:  products,
+	}
+}
@@ -1,31 +1,33 @@
 package converter
 
 import (
-	"github.com/google/uuid"
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/model"
 )
 
-func ToReceptionResponseFromReception(r *model.Reception) *dto.ReceptionResponse {
-	return &dto.ReceptionResponse{
-		ID:       r.ID.String(),
+func ToReceptionResponseFromReception(r *model.Reception) *dto.Reception {
+	result := &dto.Reception{
+		Id:       ptr(r.ID),
 		DateTime: r.DateTime,
-		PvzID:    r.PvzID.String(),
-		Status:   r.Status(),
+		PvzId:    r.PvzID,
+		Status:   dto.ReceptionStatus(r.Status()),
 	}
-}
 
-func ToReceptionFromReceptionRequest(dto *dto.ReceptionRequest) (*model.Reception, error) {
-	pvzID, err := uuid.Parse(dto.PvzID)
-	return &model.Reception{
-		ID:    uuid.Nil,
-		PvzID: pvzID,
-	}, err
+	if r.CloseReason != "" {
+		result.CloseReason = ptr(dto.ReceptionCloseReason(r.CloseReason))
+	}
+	if !r.ClosedAt.IsZero() {
+		result.ClosedAt = ptr(r.ClosedAt)
+	}
+	if !r.FlaggedAt.IsZero() {
+		result.FlaggedAt = ptr(r.FlaggedAt)
+	}
+
+	return result
 }
 
-func ToReceptionFromPvzIDRequest(IDString string) (*model.Reception, error) {
-	id, err := uuid.Parse(IDString)
+func ToReceptionFromReceptionRequest(req *dto.CreateReceptionJSONRequestBody) *model.Reception {
 	return &model.Reception{
-		PvzID: id,
-	}, err
+		PvzID: req.PvzId,
+	}
 }
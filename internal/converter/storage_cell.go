@@ -0,0 +1,41 @@
+package converter
+
+import (
+	"github.com/google/uuid"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/model"
+)
+
+func ToStorageCellFromCreateStorageCellRequest(request *dto.CreateStorageCellJSONRequestBody, pvzID uuid.UUID) *model.StorageCell {
+	return &model.StorageCell{
+		PvzID:     pvzID,
+		Code:      request.Code,
+		Capacity:  request.Capacity,
+		SizeClass: string(request.SizeClass),
+	}
+}
+
+func ToStorageCellResponseFromStorageCell(cell *model.StorageCell) *dto.StorageCell {
+	return &dto.StorageCell{
+		Id:        ptr(cell.ID),
+		PvzId:     ptr(cell.PvzID),
+		Code:      ptr(cell.Code),
+		Capacity:  ptr(cell.Capacity),
+		SizeClass: ptr(dto.StorageCellSizeClass(cell.SizeClass)),
+		Occupied:  ptr(cell.Occupied),
+		Free:      ptr(cell.Free()),
+	}
+}
+
+func ToStorageCellsResponseFromStorageCells(pvz *model.Pvz, cells []model.StorageCell) *dto.StorageCells {
+	resp := &dto.StorageCells{
+		PvzId: pvz.ID,
+		Cells: make([]dto.StorageCell, 0, len(cells)),
+	}
+
+	for i := range cells {
+		resp.Cells = append(resp.Cells, *ToStorageCellResponseFromStorageCell(&cells[i]))
+	}
+
+	return resp
+}
@@ -0,0 +1,56 @@
+package converter
+
+import (
+	"github.com/google/uuid"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/model"
+)
+
+func ToSyncOperationsFromSyncRequest(request *dto.SyncOperationsJSONRequestBody) (*model.Pvz, []model.SyncOperation) {
+	ops := make([]model.SyncOperation, 0, len(request.Operations))
+	for _, opReq := range request.Operations {
+		op := model.SyncOperation{
+			ID:          opReq.Id,
+			PvzID:       request.PvzId,
+			Type:        string(opReq.Type),
+			DateTime:    opReq.DateTime,
+			TypeProduct: string(deref(opReq.ProductType)),
+			Barcode:     deref(opReq.Barcode),
+			SKU:         deref(opReq.Sku),
+			WeightGrams: deref(opReq.WeightGrams),
+			Dimensions:  toDimensions(opReq.Dimensions),
+		}
+
+		if opReq.CellId != nil {
+			op.CellID = *opReq.CellId
+		}
+
+		ops = append(ops, op)
+	}
+
+	return &model.Pvz{ID: request.PvzId}, ops
+}
+
+func ToSyncResponseFromSyncResult(result *model.SyncResult) *dto.SyncResult {
+	resp := &dto.SyncResult{
+		PvzId: result.PvzID,
+		// Пустые списки отдаются как [], а не null
+		Applied:  append(make([]uuid.UUID, 0, len(result.Applied)), result.Applied...),
+		Rejected: make([]dto.SyncRejected, 0, len(result.Rejected)),
+	}
+
+	for _, op := range result.Rejected {
+		resp.Rejected = append(resp.Rejected, dto.SyncRejected{
+			Id:      op.ID,
+			Type:    op.Type,
+			Code:    op.Code,
+			Message: op.Message,
+		})
+	}
+
+	if result.Reception != nil {
+		resp.State = ToReceptionInfoFromReception(result.Reception)
+	}
+
+	return resp
+}
@@ -0,0 +1,29 @@
+package converter
+
+import (
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/model"
+)
+
+func ToTenantFromTenantRequest(req *dto.CreateTenantJSONRequestBody) *model.Tenant {
+	return &model.Tenant{
+		Name: req.Name,
+	}
+}
+
+func ToTenantResponseFromTenant(t *model.Tenant) *dto.Tenant {
+	return &dto.Tenant{
+		Id:               t.ID,
+		Name:             t.Name,
+		CreatedAt:        t.CreatedAt,
+		OpenRegistration: t.OpenRegistration,
+	}
+}
+
+func ToTenantResponseList(tenants []model.Tenant) []dto.Tenant {
+	resp := make([]dto.Tenant, 0, len(tenants))
+	for i := range tenants {
+		resp = append(resp, *ToTenantResponseFromTenant(&tenants[i]))
+	}
+	return resp
+}
@@ -6,24 +6,28 @@ import (
 	"pvz-service/internal/model"
 )
 
-func ToUserFromCreateUserRequest(user *dto.CreateUserRequest) *model.User {
+func ToUserFromCreateUserRequest(user *dto.RegisterJSONRequestBody) *model.User {
 	return &model.User{
 		ID:       uuid.Nil,
 		Email:    user.Email,
 		Password: user.Password,
-		Role:     user.Role,
+		Role:     string(user.Role),
 	}
 }
 
-func ToCreateUserResponseFromUser(user *model.User) *dto.CreateUserResponse {
-	return &dto.CreateUserResponse{
-		ID:    user.ID.String(),
+func ToCreateUserResponseFromUser(user *model.User) *dto.User {
+	resp := &dto.User{
+		Id:    ptr(user.ID),
 		Email: user.Email,
-		Role:  user.Role,
+		Role:  dto.UserRole(user.Role),
 	}
+	if user.TenantID != uuid.Nil {
+		resp.TenantId = ptr(user.TenantID)
+	}
+	return resp
 }
 
-func ToUserFromLoginUserRequest(user *dto.LoginUserRequest) *model.User {
+func ToUserFromLoginUserRequest(user *dto.LoginJSONRequestBody) *model.User {
 	return &model.User{
 		ID:       uuid.Nil,
 		Email:    user.Email,
@@ -32,9 +36,9 @@ func ToUserFromLoginUserRequest(user *dto.LoginUserRequest) *model.User {
 	}
 }
 
-func ToUserFromDummyLoginRequest(user *dto.TestUserRequest) *model.User {
+func ToUserFromDummyLoginRequest(user *dto.DummyLoginJSONRequestBody) *model.User {
 	return &model.User{
 		ID:   uuid.Nil,
-		Role: user.Role,
+		Role: string(user.Role),
 	}
 }
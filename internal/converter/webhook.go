@@ -0,0 +1,105 @@
+package converter
+
+import (
+	"github.com/google/uuid"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/model"
+)
+
+func ToWebhookFromWebhookRequest(req *dto.CreateWebhookJSONRequestBody) *model.WebhookSubscription {
+	sub := &model.WebhookSubscription{
+		URL:        req.Url,
+		EventTypes: make([]string, 0, len(req.EventTypes)),
+		PvzIDs:     make([]uuid.UUID, 0),
+		Secret:     deref(req.Secret),
+	}
+
+	for _, eventType := range req.EventTypes {
+		sub.EventTypes = append(sub.EventTypes, string(eventType))
+	}
+	if req.PvzIds != nil {
+		sub.PvzIDs = append(sub.PvzIDs, *req.PvzIds...)
+	}
+
+	return sub
+}
+
+// ToWebhookResponseFromWebhook переводит подписку в ответ. Секрет попадает в ответ только при withSecret
+func ToWebhookResponseFromWebhook(sub *model.WebhookSubscription, withSecret bool) *dto.Webhook {
+	resp := &dto.Webhook{
+		Id:         sub.ID,
+		Url:        sub.URL,
+		EventTypes: make([]dto.WebhookEventTypes, 0, len(sub.EventTypes)),
+		PvzIds:     append(make([]uuid.UUID, 0, len(sub.PvzIDs)), sub.PvzIDs...),
+		CreatedAt:  sub.CreatedAt,
+	}
+
+	for _, eventType := range sub.EventTypes {
+		resp.EventTypes = append(resp.EventTypes, dto.WebhookEventTypes(eventType))
+	}
+
+	if withSecret && sub.Secret != "" {
+		resp.Secret = ptr(sub.Secret)
+	}
+
+	return resp
+}
+
+func ToWebhookResponseList(subs []model.WebhookSubscription) []dto.Webhook {
+	resp := make([]dto.Webhook, 0, len(subs))
+	for i := range subs {
+		resp = append(resp, *ToWebhookResponseFromWebhook(&subs[i], false))
+	}
+	return resp
+}
+
+func ToWebhookDeliveryQuery(params *dto.GetWebhookDeliveriesParams, webhookID uuid.UUID, defaultLimit, maxLimit int) *model.WebhookDeliveryQuery {
+	query := &model.WebhookDeliveryQuery{
+		SubscriptionID: webhookID,
+		Status:         string(deref(params.Status)),
+		Page:           deref(params.Page),
+		Limit:          deref(params.Limit),
+	}
+
+	setDefaultsPagination(&query.Page, &query.Limit, defaultLimit, maxLimit)
+
+	return query
+}
+
+func ToWebhookDeliveryResponse(delivery *model.WebhookDelivery) *dto.WebhookDelivery {
+	resp := &dto.WebhookDelivery{
+		Id:        delivery.ID,
+		WebhookId: delivery.SubscriptionID,
+		EventId:   delivery.EventID,
+		EventType: delivery.EventType,
+		Status:    dto.WebhookDeliveryStatus(delivery.Status),
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt,
+		Payload:   delivery.Payload,
+	}
+
+	if delivery.LastError != "" {
+		resp.LastError = ptr(delivery.LastError)
+	}
+	if delivery.ResponseStatus != 0 {
+		resp.ResponseStatus = ptr(delivery.ResponseStatus)
+	}
+
+	// Время следующей попытки имеет смысл только для доставки в очереди
+	if delivery.Status == model.WebhookDeliveryPending {
+		resp.NextAttemptAt = ptr(delivery.NextAttemptAt)
+	}
+	if !delivery.DeliveredAt.IsZero() {
+		resp.DeliveredAt = ptr(delivery.DeliveredAt)
+	}
+
+	return resp
+}
+
+func ToWebhookDeliveryResponseList(deliveries []model.WebhookDelivery) []dto.WebhookDelivery {
+	resp := make([]dto.WebhookDelivery, 0, len(deliveries))
+	for i := range deliveries {
+		resp = append(resp, *ToWebhookDeliveryResponse(&deliveries[i]))
+	}
+	return resp
+}
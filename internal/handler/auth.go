@@ -17,6 +17,10 @@ type AuthService interface {
 	Registration(ctx context.Context, user model.User) (*model.User, error)
 	Authenticate(ctx context.Context, user model.User) (string, error)
 	DummyAuth(ctx context.Context, user model.User) (string, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
 }
 
 type AuthHandlers struct {
@@ -30,33 +34,33 @@ func NewAuthHandler(service AuthService) *AuthHandlers {
 }
 
 func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
-	var req dto.CreateUserRequest
+	var req dto.RegisterJSONRequestBody
 	logger := getLogger(r)
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, ErrBodyRequest, http.StatusBadRequest)
-		logger.Info(ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	v := getValidator(r)
 	if err := v.Struct(req); err != nil {
-		response.WriteError(w, ErrRequestFields, http.StatusBadRequest)
-		logger.Info(ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	userModel := *converter.ToUserFromCreateUserRequest(&req)
 	if err := validateRole(userModel.Role); err != nil {
-		response.WriteError(w, ErrInvalidRole, http.StatusBadRequest)
-		logger.Info(ErrInvalidRole, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidRole, ErrInvalidRole)
+		logger.InfoContext(r.Context(), ErrInvalidRole, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	user, err := h.Service.Registration(r.Context(), userModel)
 	if err != nil {
-		response.WriteError(w, err.Error(), http.StatusBadRequest)
-		logger.Info("error to register user", slog.String(ErrorKey, err.Error()))
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), "error to register user", slog.String(ErrorKey, err.Error()))
 		return
 	}
 
@@ -67,26 +71,26 @@ func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
-	var req dto.LoginUserRequest
+	var req dto.LoginJSONRequestBody
 	logger := getLogger(r)
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, ErrBodyRequest, http.StatusUnauthorized)
-		logger.Info(ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusUnauthorized, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	v := getValidator(r)
 	if err := v.Struct(req); err != nil {
-		response.WriteError(w, ErrRequestFields, http.StatusUnauthorized)
-		logger.Info(ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusUnauthorized, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	token, err := h.Service.Authenticate(r.Context(), *converter.ToUserFromLoginUserRequest(&req))
 	if err != nil {
-		response.WriteError(w, err.Error(), http.StatusUnauthorized)
-		logger.Info("error to login user", slog.String(ErrorKey, err.Error()))
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), "error to login user", slog.String(ErrorKey, err.Error()))
 		return
 	}
 
@@ -96,41 +100,124 @@ func (h *AuthHandlers) Login(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandlers) DummyLogin(w http.ResponseWriter, r *http.Request) {
-	var req dto.TestUserRequest
+	var req dto.DummyLoginJSONRequestBody
 	logger := getLogger(r)
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, ErrBodyRequest, http.StatusBadRequest)
-		logger.Info(ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	v := getValidator(r)
 	if err := v.Struct(req); err != nil {
-		response.WriteError(w, ErrRequestFields, http.StatusBadRequest)
-		logger.Info(ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	userModel := *converter.ToUserFromDummyLoginRequest(&req)
 	if err := validateRole(userModel.Role); err != nil {
-		response.WriteError(w, ErrInvalidRole, http.StatusBadRequest)
-		logger.Info(ErrInvalidRole, slog.String(ErrorKey, err.Error()))
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidRole, ErrInvalidRole)
+		logger.InfoContext(r.Context(), ErrInvalidRole, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	token, err := h.Service.DummyAuth(r.Context(), userModel)
 	if err != nil {
-		response.WriteError(w, err.Error(), http.StatusBadRequest)
-		logger.Info("error to login testUser", slog.String(ErrorKey, err.Error()))
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), "error to login testUser", slog.String(ErrorKey, err.Error()))
 		return
 	}
 
-	logger.InfoContext(r.Context(), "successful dummyLogin", slog.String("role", req.Role))
+	logger.InfoContext(r.Context(), "successful dummyLogin", slog.String("role", userModel.Role))
 
 	response.SuccessText(w, token, http.StatusOK)
 }
 
+func (h *AuthHandlers) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyEmailJSONRequestBody
+	if !decodeAccountRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.VerifyEmail(r.Context(), req.Token); err != nil {
+		response.WriteServiceError(w, err)
+		getLogger(r).InfoContext(r.Context(), "error to verify email", slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.Success(w, http.StatusOK)
+}
+
+// ResendVerification и ForgotPassword отвечают 202 и для неизвестной почты
+func (h *AuthHandlers) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResendVerificationJSONRequestBody
+	if !decodeAccountRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.ResendVerification(r.Context(), req.Email); err != nil {
+		response.WriteServiceError(w, err)
+		getLogger(r).ErrorContext(r.Context(), "error to resend verification", slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.Success(w, http.StatusAccepted)
+}
+
+func (h *AuthHandlers) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordJSONRequestBody
+	if !decodeAccountRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.ForgotPassword(r.Context(), req.Email); err != nil {
+		response.WriteServiceError(w, err)
+		getLogger(r).ErrorContext(r.Context(), "error to send password reset", slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.Success(w, http.StatusAccepted)
+}
+
+func (h *AuthHandlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordJSONRequestBody
+	if !decodeAccountRequest(w, r, &req) {
+		return
+	}
+
+	if err := h.Service.ResetPassword(r.Context(), req.Token, req.Password); err != nil {
+		response.WriteServiceError(w, err)
+		getLogger(r).InfoContext(r.Context(), "error to reset password", slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	getLogger(r).InfoContext(r.Context(), "successful password reset")
+
+	response.Success(w, http.StatusOK)
+}
+
+// decodeAccountRequest разбирает и проверяет тело запроса, при ошибке сам пишет ответ 400
+func decodeAccountRequest(w http.ResponseWriter, r *http.Request, req any) bool {
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return false
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return false
+	}
+
+	return true
+}
+
 func validateRole(role string) error {
 	switch role {
 	case EmployeeRole, ModeratorRole:
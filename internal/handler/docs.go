@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/swaggest/swgui/v5emb"
+)
+
+const (
+	docsTitle    = "PVZ service API"
+	docsBasePath = "/docs/"
+	specJSONPath = "/openapi.json"
+)
+
+// DocsHandlers отдает встроенную спецификацию и Swagger UI.
+// Статика UI тоже встроена в бинарник, CDN не нужен
+type DocsHandlers struct {
+	specYAML []byte
+	specJSON []byte
+	ui       http.Handler
+}
+
+func NewDocsHandler(spec []byte) (*DocsHandlers, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load openapi spec: %w", err)
+	}
+
+	specJSON, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert openapi spec to json: %w", err)
+	}
+
+	return &DocsHandlers{
+		specYAML: spec,
+		specJSON: specJSON,
+		ui:       v5emb.New(docsTitle, specJSONPath, docsBasePath),
+	}, nil
+}
+
+func (h *DocsHandlers) SpecYAML(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(h.specYAML)
+}
+
+func (h *DocsHandlers) SpecJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(h.specJSON)
+}
+
+// UI отдает страницу Swagger UI и ее статику
+func (h *DocsHandlers) UI(w http.ResponseWriter, r *http.Request) {
+	h.ui.ServeHTTP(w, r)
+}
+
+// MountDocs регистрирует документацию API, доступную без авторизации
+func MountDocs(r chi.Router, h *DocsHandlers) {
+	r.Get("/openapi.yaml", h.SpecYAML)
+	r.Get(specJSONPath, h.SpecJSON)
+	r.Get("/docs", h.UI)
+	r.Get(docsBasePath+"*", h.UI)
+}
@@ -1,5 +0,0 @@
-package dto
-
-type TestUserRequest struct {
-	Role string `json:"role" validate:"required"`
-}
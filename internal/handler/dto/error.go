@@ -1,6 +0,0 @@
-package dto
-
-type ErrorResponse struct {
-	// Сообщение об ошибке, описывающее проблему.
-	Message string `json:"message,omitempty"`
-}
@@ -1,6 +0,0 @@
-package dto
-
-type LoginUserRequest struct {
-	Email    string `json:"email" validate:"required"`
-	Password string `json:"password" validate:"required"`
-}
@@ -0,0 +1,724 @@
+// Package dto provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for PVZCity.
+const (
+	Казань         PVZCity = "Казань"
+	Москва         PVZCity = "Москва"
+	СанктПетербург PVZCity = "Санкт-Петербург"
+)
+
+// Defines values for PVZStatus.
+const (
+	PVZStatusActive         PVZStatus = "active"
+	PVZStatusDecommissioned PVZStatus = "decommissioned"
+	PVZStatusSuspended      PVZStatus = "suspended"
+)
+
+// Defines values for ProductType.
+const (
+	ProductTypeОбувь       ProductType = "обувь"
+	ProductTypeОдежда      ProductType = "одежда"
+	ProductTypeЭлектроника ProductType = "электроника"
+)
+
+// Defines values for PvzProfileStatus.
+const (
+	PvzProfileStatusActive         PvzProfileStatus = "active"
+	PvzProfileStatusDecommissioned PvzProfileStatus = "decommissioned"
+	PvzProfileStatusSuspended      PvzProfileStatus = "suspended"
+)
+
+// Defines values for ReceptionCloseReason.
+const (
+	Manual  ReceptionCloseReason = "manual"
+	Sync    ReceptionCloseReason = "sync"
+	Timeout ReceptionCloseReason = "timeout"
+)
+
+// Defines values for ReceptionStatus.
+const (
+	Close      ReceptionStatus = "close"
+	InProgress ReceptionStatus = "in_progress"
+)
+
+// Defines values for StorageCellSizeClass.
+const (
+	StorageCellSizeClassLarge  StorageCellSizeClass = "large"
+	StorageCellSizeClassMedium StorageCellSizeClass = "medium"
+	StorageCellSizeClassSmall  StorageCellSizeClass = "small"
+)
+
+// Defines values for SyncOperationProductType.
+const (
+	SyncOperationProductTypeОбувь       SyncOperationProductType = "обувь"
+	SyncOperationProductTypeОдежда      SyncOperationProductType = "одежда"
+	SyncOperationProductTypeЭлектроника SyncOperationProductType = "электроника"
+)
+
+// Defines values for SyncOperationType.
+const (
+	AddProduct        SyncOperationType = "add_product"
+	CloseReception    SyncOperationType = "close_reception"
+	DeleteLastProduct SyncOperationType = "delete_last_product"
+	OpenReception     SyncOperationType = "open_reception"
+)
+
+// Defines values for UserRole.
+const (
+	UserRoleEmployee  UserRole = "employee"
+	UserRoleModerator UserRole = "moderator"
+)
+
+// Defines values for WebhookEventTypes.
+const (
+	WebhookEventTypesProductReceived  WebhookEventTypes = "product.received"
+	WebhookEventTypesReceptionClosed  WebhookEventTypes = "reception.closed"
+	WebhookEventTypesReceptionCreated WebhookEventTypes = "reception.created"
+)
+
+// Defines values for WebhookDeliveryStatus.
+const (
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+)
+
+// Defines values for DummyLoginJSONBodyRole.
+const (
+	DummyLoginJSONBodyRoleEmployee  DummyLoginJSONBodyRole = "employee"
+	DummyLoginJSONBodyRoleModerator DummyLoginJSONBodyRole = "moderator"
+)
+
+// Defines values for CreateProductJSONBodyType.
+const (
+	Обувь       CreateProductJSONBodyType = "обувь"
+	Одежда      CreateProductJSONBodyType = "одежда"
+	Электроника CreateProductJSONBodyType = "электроника"
+)
+
+// Defines values for CreateStorageCellJSONBodySizeClass.
+const (
+	CreateStorageCellJSONBodySizeClassLarge  CreateStorageCellJSONBodySizeClass = "large"
+	CreateStorageCellJSONBodySizeClassMedium CreateStorageCellJSONBodySizeClass = "medium"
+	CreateStorageCellJSONBodySizeClassSmall  CreateStorageCellJSONBodySizeClass = "small"
+)
+
+// Defines values for RegisterJSONBodyRole.
+const (
+	Employee  RegisterJSONBodyRole = "employee"
+	Moderator RegisterJSONBodyRole = "moderator"
+)
+
+// Defines values for CreateWebhookJSONBodyEventTypes.
+const (
+	CreateWebhookJSONBodyEventTypesProductReceived  CreateWebhookJSONBodyEventTypes = "product.received"
+	CreateWebhookJSONBodyEventTypesReceptionClosed  CreateWebhookJSONBodyEventTypes = "reception.closed"
+	CreateWebhookJSONBodyEventTypesReceptionCreated CreateWebhookJSONBodyEventTypes = "reception.created"
+)
+
+// Defines values for GetWebhookDeliveriesParamsStatus.
+const (
+	GetWebhookDeliveriesParamsStatusDead      GetWebhookDeliveriesParamsStatus = "dead"
+	GetWebhookDeliveriesParamsStatusDelivered GetWebhookDeliveriesParamsStatus = "delivered"
+	GetWebhookDeliveriesParamsStatusPending   GetWebhookDeliveriesParamsStatus = "pending"
+)
+
+// Coordinates defines model for Coordinates.
+type Coordinates struct {
+	Lat float64 `json:"lat" validate:"gte=-90,lte=90"`
+	Lng float64 `json:"lng" validate:"gte=-180,lte=180"`
+}
+
+// DayHours defines model for DayHours.
+type DayHours struct {
+	// Close Время закрытия, допускается 24:00
+	Close string `json:"close" validate:"required"`
+	Open  string `json:"open" validate:"required"`
+}
+
+// Dimensions Габариты посылки в миллиметрах
+type Dimensions struct {
+	HeightMm int `json:"heightMm" validate:"gt=0"`
+	LengthMm int `json:"lengthMm" validate:"gt=0"`
+	WidthMm  int `json:"widthMm" validate:"gt=0"`
+}
+
+// Error Ошибка в формате RFC 7807 (application/problem+json)
+type Error struct {
+	// Code Стабильный машиночитаемый код ошибки
+	Code   string  `json:"code"`
+	Detail *string `json:"detail,omitempty"`
+
+	// Message Дублирует detail для старых клиентов
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+}
+
+// Health defines model for Health.
+type Health struct {
+	Status string `json:"status"`
+}
+
+// HoursException Часы работы в отдельную дату, closed - выходной
+type HoursException struct {
+	Close  string             `json:"close,omitempty" validate:"required_without=Closed"`
+	Closed bool               `json:"closed,omitempty"`
+	Date   openapi_types.Date `json:"date"`
+	Open   string             `json:"open,omitempty" validate:"required_without=Closed"`
+}
+
+// Issuance defines model for Issuance.
+type Issuance struct {
+	ConfirmationCode *string             `json:"confirmationCode,omitempty"`
+	EmployeeId       *string             `json:"employeeId,omitempty"`
+	Id               *openapi_types.UUID `json:"id,omitempty"`
+	IssuedAt         *time.Time          `json:"issuedAt,omitempty"`
+	ProductId        *openapi_types.UUID `json:"productId,omitempty"`
+	PvzId            *openapi_types.UUID `json:"pvzId,omitempty"`
+}
+
+// NearbyPvz defines model for NearbyPvz.
+type NearbyPvz struct {
+	Address     *string      `json:"address,omitempty"`
+	City        *string      `json:"city,omitempty"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+
+	// DistanceMeters Расстояние от точки поиска, округленное до метра
+	DistanceMeters *float64            `json:"distanceMeters,omitempty"`
+	Id             *openapi_types.UUID `json:"id,omitempty"`
+
+	// IsOpen Работает ли ПВЗ сейчас по своему графику
+	IsOpen   *bool   `json:"isOpen,omitempty"`
+	Timezone *string `json:"timezone,omitempty"`
+
+	// WorkingHours Пустой график - ПВЗ работает круглосуточно. Дня нет в weekly - выходной
+	WorkingHours *WorkingHours `json:"workingHours,omitempty"`
+}
+
+// PVZ defines model for PVZ.
+type PVZ struct {
+	City             PVZCity             `json:"city" validate:"required"`
+	Id               *openapi_types.UUID `json:"id,omitempty"`
+	RegistrationDate *time.Time          `json:"registrationDate,omitempty"`
+	Status           *PVZStatus          `json:"status,omitempty"`
+}
+
+// PVZCity defines model for PVZ.City.
+type PVZCity string
+
+// PVZStatus defines model for PVZ.Status.
+type PVZStatus string
+
+// Product defines model for Product.
+type Product struct {
+	// Barcode EAN-13 или Code128 с контрольным символом
+	Barcode *string `json:"barcode,omitempty"`
+
+	// CellId Ячейка хранения, отсутствует если товар не размещен
+	CellId   *openapi_types.UUID `json:"cellId,omitempty"`
+	DateTime *time.Time          `json:"dateTime,omitempty"`
+
+	// Dimensions Габариты посылки в миллиметрах
+	Dimensions  *Dimensions         `json:"dimensions,omitempty"`
+	Id          *openapi_types.UUID `json:"id,omitempty"`
+	ReceptionId openapi_types.UUID  `json:"receptionId"`
+	Sku         *string             `json:"sku,omitempty"`
+	Type        ProductType         `json:"type"`
+	WeightGrams *int                `json:"weightGrams,omitempty"`
+}
+
+// ProductType defines model for Product.Type.
+type ProductType string
+
+// ProductReturn defines model for ProductReturn.
+type ProductReturn struct {
+	EmployeeId *string             `json:"employeeId,omitempty"`
+	Id         *openapi_types.UUID `json:"id,omitempty"`
+	ProductId  *openapi_types.UUID `json:"productId,omitempty"`
+	PvzId      *openapi_types.UUID `json:"pvzId,omitempty"`
+	Reason     *string             `json:"reason,omitempty"`
+	ReturnedAt *time.Time          `json:"returnedAt,omitempty"`
+}
+
+// PvzInfo defines model for PvzInfo.
+type PvzInfo struct {
+	Pvz        PVZ             `json:"pvz"`
+	Receptions []ReceptionInfo `json:"receptions"`
+}
+
+// PvzProfile defines model for PvzProfile.
+type PvzProfile struct {
+	Address     *string             `json:"address,omitempty"`
+	City        *string             `json:"city,omitempty"`
+	Coordinates *Coordinates        `json:"coordinates,omitempty"`
+	Id          *openapi_types.UUID `json:"id,omitempty"`
+
+	// MaxDailyReceptions Лимит приемок за местные сутки ПВЗ, 0 - без ограничения
+	MaxDailyReceptions *int `json:"maxDailyReceptions,omitempty"`
+
+	// ReceptionOverrideUntil До этого момента приемки открываются без проверки графика и лимита
+	ReceptionOverrideUntil *time.Time        `json:"receptionOverrideUntil,omitempty"`
+	RegistrationDate       *time.Time        `json:"registrationDate,omitempty"`
+	Status                 *PvzProfileStatus `json:"status,omitempty"`
+	Timezone               *string           `json:"timezone,omitempty"`
+
+	// WorkingHours Пустой график - ПВЗ работает круглосуточно. Дня нет в weekly - выходной
+	WorkingHours *WorkingHours `json:"workingHours,omitempty"`
+}
+
+// PvzProfileStatus defines model for PvzProfile.Status.
+type PvzProfileStatus string
+
+// Reception defines model for Reception.
+type Reception struct {
+	// CloseReason Причина закрытия, timeout - закрыта планировщиком
+	CloseReason *ReceptionCloseReason `json:"closeReason,omitempty"`
+	ClosedAt    *time.Time            `json:"closedAt,omitempty"`
+	DateTime    time.Time             `json:"dateTime"`
+
+	// FlaggedAt Когда приемка помечена как зависшая
+	FlaggedAt *time.Time          `json:"flaggedAt,omitempty"`
+	Id        *openapi_types.UUID `json:"id,omitempty"`
+	PvzId     openapi_types.UUID  `json:"pvzId"`
+	Status    ReceptionStatus     `json:"status"`
+}
+
+// ReceptionCloseReason Причина закрытия, timeout - закрыта планировщиком
+type ReceptionCloseReason string
+
+// ReceptionStatus defines model for Reception.Status.
+type ReceptionStatus string
+
+// ReceptionInfo defines model for ReceptionInfo.
+type ReceptionInfo struct {
+	Products  []Product `json:"products"`
+	Reception Reception `json:"reception"`
+}
+
+// Stock defines model for Stock.
+type Stock struct {
+	Count    int                `json:"count"`
+	Products []Product          `json:"products"`
+	PvzId    openapi_types.UUID `json:"pvzId"`
+}
+
+// StorageCell defines model for StorageCell.
+type StorageCell struct {
+	Capacity *int                `json:"capacity,omitempty"`
+	Code     *string             `json:"code,omitempty"`
+	Free     *int                `json:"free,omitempty"`
+	Id       *openapi_types.UUID `json:"id,omitempty"`
+
+	// Occupied Число невыданных товаров в ячейке
+	Occupied *int                `json:"occupied,omitempty"`
+	PvzId    *openapi_types.UUID `json:"pvzId,omitempty"`
+
+	// SizeClass small - самая длинная сторона до 350 мм, medium - до 600 мм, large - больше
+	SizeClass *StorageCellSizeClass `json:"sizeClass,omitempty"`
+}
+
+// StorageCellSizeClass small - самая длинная сторона до 350 мм, medium - до 600 мм, large - больше
+type StorageCellSizeClass string
+
+// StorageCells defines model for StorageCells.
+type StorageCells struct {
+	Cells []StorageCell      `json:"cells"`
+	PvzId openapi_types.UUID `json:"pvzId"`
+}
+
+// SyncOperation defines model for SyncOperation.
+type SyncOperation struct {
+	// Barcode Для add_product, проверяется как в POST /products
+	Barcode *string `json:"barcode,omitempty" validate:"omitempty,max=64"`
+
+	// CellId Ячейка для товара. Если не указана, сервис выбирает ее сам
+	CellId *openapi_types.UUID `json:"cellId,omitempty"`
+
+	// DateTime Время операции на устройстве
+	DateTime time.Time `json:"dateTime" validate:"required"`
+
+	// Dimensions Габариты посылки в миллиметрах
+	Dimensions *Dimensions `json:"dimensions,omitempty"`
+
+	// Id UUID операции, он же ID создаваемой приемки или товара
+	Id openapi_types.UUID `json:"id" validate:"required"`
+
+	// ProductType Обязателен для add_product
+	ProductType *SyncOperationProductType `json:"productType,omitempty" validate:"required_if=Type add_product"`
+	Sku         *string                   `json:"sku,omitempty" validate:"omitempty,max=64"`
+	Type        SyncOperationType         `json:"type" validate:"required,oneof=open_reception add_product delete_last_product close_reception"`
+	WeightGrams *int                      `json:"weightGrams,omitempty" validate:"omitempty,gte=0"`
+}
+
+// SyncOperationProductType Обязателен для add_product
+type SyncOperationProductType string
+
+// SyncOperationType defines model for SyncOperation.Type.
+type SyncOperationType string
+
+// SyncRejected defines model for SyncRejected.
+type SyncRejected struct {
+	Code    string             `json:"code"`
+	Id      openapi_types.UUID `json:"id"`
+	Message string             `json:"message"`
+	Type    string             `json:"type"`
+}
+
+// SyncResult defines model for SyncResult.
+type SyncResult struct {
+	Applied  []openapi_types.UUID `json:"applied"`
+	PvzId    openapi_types.UUID   `json:"pvzId"`
+	Rejected []SyncRejected       `json:"rejected"`
+
+	// State Последняя приемка ПВЗ после синхронизации
+	State *ReceptionInfo `json:"state"`
+}
+
+// Tenant Оператор маркетплейса. Его ПВЗ, приемки, товары и подписки не видны другим операторам
+type Tenant struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	Id        openapi_types.UUID `json:"id"`
+	Name      string             `json:"name"`
+
+	// OpenRegistration Пользователи могут сами регистрироваться у оператора через POST /register
+	OpenRegistration bool `json:"openRegistration"`
+}
+
+// Token defines model for Token.
+type Token = string
+
+// User defines model for User.
+type User struct {
+	Email string              `json:"email"`
+	Id    *openapi_types.UUID `json:"id,omitempty"`
+	Role  UserRole            `json:"role"`
+
+	// TenantId Оператор маркетплейса, к данным которого у пользователя есть доступ
+	TenantId *openapi_types.UUID `json:"tenantId,omitempty"`
+}
+
+// UserRole defines model for User.Role.
+type UserRole string
+
+// Version defines model for Version.
+type Version struct {
+	BuildTime string `json:"buildTime"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	CreatedAt  time.Time           `json:"createdAt"`
+	EventTypes []WebhookEventTypes `json:"eventTypes"`
+	Id         openapi_types.UUID  `json:"id"`
+
+	// PvzIds Фильтр по ПВЗ, пустой - события всех ПВЗ
+	PvzIds []openapi_types.UUID `json:"pvzIds"`
+
+	// Secret Ключ подписи HMAC-SHA256, возвращается только при создании
+	Secret *string `json:"secret,omitempty"`
+	Url    string  `json:"url"`
+}
+
+// WebhookEventTypes defines model for Webhook.EventTypes.
+type WebhookEventTypes string
+
+// WebhookDelivery defines model for WebhookDelivery.
+type WebhookDelivery struct {
+	Attempts    int        `json:"attempts"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+
+	// EventId Совпадает с заголовком X-Webhook-Id, по нему получатель отсекает повторы
+	EventId   openapi_types.UUID `json:"eventId"`
+	EventType string             `json:"eventType"`
+	Id        openapi_types.UUID `json:"id"`
+	LastError *string            `json:"lastError,omitempty"`
+
+	// NextAttemptAt Только для pending
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+
+	// Payload Тело запроса к получателю
+	Payload        json.RawMessage       `json:"payload"`
+	ResponseStatus *int                  `json:"responseStatus,omitempty"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	WebhookId      openapi_types.UUID    `json:"webhookId"`
+}
+
+// WebhookDeliveryStatus defines model for WebhookDelivery.Status.
+type WebhookDeliveryStatus string
+
+// WorkingHours Пустой график - ПВЗ работает круглосуточно. Дня нет в weekly - выходной
+type WorkingHours struct {
+	Exceptions []HoursException `json:"exceptions,omitempty" validate:"dive"`
+
+	// Weekly Ключи mon, tue, wed, thu, fri, sat, sun
+	Weekly map[string]DayHours `json:"weekly,omitempty" validate:"dive,keys,oneof=mon tue wed thu fri sat sun,endkeys"`
+}
+
+// Conflict Ошибка в формате RFC 7807 (application/problem+json)
+type Conflict = Error
+
+// InternalError Ошибка в формате RFC 7807 (application/problem+json)
+type InternalError = Error
+
+// NotFound Ошибка в формате RFC 7807 (application/problem+json)
+type NotFound = Error
+
+// TooManyRequests Ошибка в формате RFC 7807 (application/problem+json)
+type TooManyRequests = Error
+
+// CreateTenantJSONBody defines parameters for CreateTenant.
+type CreateTenantJSONBody struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// UpdateTenantJSONBody defines parameters for UpdateTenant.
+type UpdateTenantJSONBody struct {
+	OpenRegistration *bool `json:"openRegistration" validate:"required"`
+}
+
+// DummyLoginJSONBody defines parameters for DummyLogin.
+type DummyLoginJSONBody struct {
+	Role DummyLoginJSONBodyRole `json:"role" validate:"required"`
+}
+
+// DummyLoginJSONBodyRole defines parameters for DummyLogin.
+type DummyLoginJSONBodyRole string
+
+// ForgotPasswordJSONBody defines parameters for ForgotPassword.
+type ForgotPasswordJSONBody struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// IssueProductJSONBody defines parameters for IssueProduct.
+type IssueProductJSONBody struct {
+	ConfirmationCode string             `json:"confirmationCode" validate:"required,alphanum,min=4,max=16"`
+	ProductId        openapi_types.UUID `json:"productId" validate:"required"`
+	PvzId            openapi_types.UUID `json:"pvzId" validate:"required"`
+}
+
+// LoginJSONBody defines parameters for Login.
+type LoginJSONBody struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// CreateProductJSONBody defines parameters for CreateProduct.
+type CreateProductJSONBody struct {
+	// Barcode EAN-13 или Code128 с контрольным символом
+	Barcode *string `json:"barcode,omitempty" validate:"omitempty,max=64"`
+
+	// CellId Ячейка для размещения. Если не указана, сервис выбирает наименее заполненную ячейку наименьшего подходящего размера
+	CellId *openapi_types.UUID `json:"cellId,omitempty"`
+
+	// Dimensions Габариты посылки в миллиметрах
+	Dimensions  *Dimensions               `json:"dimensions,omitempty"`
+	PvzId       openapi_types.UUID        `json:"pvzId" validate:"required"`
+	Sku         *string                   `json:"sku,omitempty" validate:"omitempty,max=64"`
+	Type        CreateProductJSONBodyType `json:"type" validate:"required"`
+	WeightGrams *int                      `json:"weightGrams,omitempty" validate:"omitempty,gte=0"`
+}
+
+// CreateProductJSONBodyType defines parameters for CreateProduct.
+type CreateProductJSONBodyType string
+
+// GetPvzListParams defines parameters for GetPvzList.
+type GetPvzListParams struct {
+	// StartDate Начальная дата диапазона
+	StartDate *time.Time `form:"startDate,omitempty" json:"startDate,omitempty"`
+
+	// EndDate Конечная дата диапазона
+	EndDate *time.Time `form:"endDate,omitempty" json:"endDate,omitempty"`
+
+	// Page Номер страницы
+	Page *int `form:"page,omitempty" json:"page,omitempty"`
+
+	// Limit Количество элементов на странице
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// IncludeInactive Показывать и выведенные из эксплуатации ПВЗ
+	IncludeInactive *bool `form:"includeInactive,omitempty" json:"includeInactive,omitempty"`
+}
+
+// GetNearbyPvzParams defines parameters for GetNearbyPvz.
+type GetNearbyPvzParams struct {
+	Lat float64 `form:"lat" json:"lat" validate:"gte=-90,lte=90"`
+	Lon float64 `form:"lon" json:"lon" validate:"gte=-180,lte=180"`
+
+	// Radius Радиус поиска в метрах
+	Radius *float64 `form:"radius,omitempty" json:"radius,omitempty" validate:"omitempty,gt=0,lte=50000"`
+	Limit  *int     `form:"limit,omitempty" json:"limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+
+	// XTenantID Оператор маркетплейса, чьи ПВЗ ищем. Без него - оператор по умолчанию
+	XTenantID *openapi_types.UUID `json:"X-Tenant-ID,omitempty"`
+}
+
+// UpdatePvzJSONBody defines parameters for UpdatePvz.
+type UpdatePvzJSONBody struct {
+	Address                *string      `json:"address,omitempty" validate:"omitempty,max=255"`
+	Coordinates            *Coordinates `json:"coordinates,omitempty"`
+	MaxDailyReceptions     *int         `json:"maxDailyReceptions,omitempty" validate:"omitempty,gte=0"`
+	ReceptionOverrideUntil *time.Time   `json:"receptionOverrideUntil,omitempty"`
+	Timezone               *string      `json:"timezone,omitempty" validate:"omitempty,timezone"`
+
+	// WorkingHours Пустой график - ПВЗ работает круглосуточно. Дня нет в weekly - выходной
+	WorkingHours *WorkingHours `json:"workingHours,omitempty"`
+}
+
+// CreateStorageCellJSONBody defines parameters for CreateStorageCell.
+type CreateStorageCellJSONBody struct {
+	Capacity  int                                `json:"capacity" validate:"required,gt=0,lte=10000"`
+	Code      string                             `json:"code" validate:"required,max=32"`
+	SizeClass CreateStorageCellJSONBodySizeClass `json:"sizeClass" validate:"required,oneof=small medium large"`
+}
+
+// CreateStorageCellJSONBodySizeClass defines parameters for CreateStorageCell.
+type CreateStorageCellJSONBodySizeClass string
+
+// CreateReceptionJSONBody defines parameters for CreateReception.
+type CreateReceptionJSONBody struct {
+	PvzId openapi_types.UUID `json:"pvzId" validate:"required"`
+}
+
+// RegisterJSONBody defines parameters for Register.
+type RegisterJSONBody struct {
+	Email    string               `json:"email" validate:"required,email"`
+	Password string               `json:"password" validate:"required"`
+	Role     RegisterJSONBodyRole `json:"role" validate:"required"`
+}
+
+// RegisterParams defines parameters for Register.
+type RegisterParams struct {
+	// XTenantID Оператор маркетплейса. Без него - оператор по умолчанию
+	XTenantID *openapi_types.UUID `json:"X-Tenant-ID,omitempty"`
+}
+
+// RegisterJSONBodyRole defines parameters for Register.
+type RegisterJSONBodyRole string
+
+// ResetPasswordJSONBody defines parameters for ResetPassword.
+type ResetPasswordJSONBody struct {
+	Password string `json:"password" validate:"required"`
+
+	// Token Токен из ссылки в письме
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+// ReturnProductJSONBody defines parameters for ReturnProduct.
+type ReturnProductJSONBody struct {
+	ProductId openapi_types.UUID `json:"productId" validate:"required"`
+	PvzId     openapi_types.UUID `json:"pvzId" validate:"required"`
+	Reason    string             `json:"reason" validate:"required,max=500"`
+}
+
+// SyncOperationsJSONBody defines parameters for SyncOperations.
+type SyncOperationsJSONBody struct {
+	Operations []SyncOperation    `json:"operations" validate:"required,min=1,max=1000,dive"`
+	PvzId      openapi_types.UUID `json:"pvzId" validate:"required"`
+}
+
+// VerifyEmailJSONBody defines parameters for VerifyEmail.
+type VerifyEmailJSONBody struct {
+	// Token Токен из ссылки в письме
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+// ResendVerificationJSONBody defines parameters for ResendVerification.
+type ResendVerificationJSONBody struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// CreateWebhookJSONBody defines parameters for CreateWebhook.
+type CreateWebhookJSONBody struct {
+	EventTypes []CreateWebhookJSONBodyEventTypes `json:"eventTypes" validate:"required,min=1,dive,required"`
+	PvzIds     *[]openapi_types.UUID             `json:"pvzIds,omitempty"`
+
+	// Secret Без него сервис сгенерирует случайный
+	Secret *string `json:"secret,omitempty" validate:"omitempty,min=16,max=256"`
+	Url    string  `json:"url" validate:"required,url,max=2048"`
+}
+
+// CreateWebhookJSONBodyEventTypes defines parameters for CreateWebhook.
+type CreateWebhookJSONBodyEventTypes string
+
+// GetWebhookDeliveriesParams defines parameters for GetWebhookDeliveries.
+type GetWebhookDeliveriesParams struct {
+	Status *GetWebhookDeliveriesParamsStatus `form:"status,omitempty" json:"status,omitempty" validate:"omitempty,oneof=pending delivered dead"`
+	Page   *int                              `form:"page,omitempty" json:"page,omitempty"`
+	Limit  *int                              `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetWebhookDeliveriesParamsStatus defines parameters for GetWebhookDeliveries.
+type GetWebhookDeliveriesParamsStatus string
+
+// CreateTenantJSONRequestBody defines body for CreateTenant for application/json ContentType.
+type CreateTenantJSONRequestBody CreateTenantJSONBody
+
+// UpdateTenantJSONRequestBody defines body for UpdateTenant for application/json ContentType.
+type UpdateTenantJSONRequestBody UpdateTenantJSONBody
+
+// DummyLoginJSONRequestBody defines body for DummyLogin for application/json ContentType.
+type DummyLoginJSONRequestBody DummyLoginJSONBody
+
+// ForgotPasswordJSONRequestBody defines body for ForgotPassword for application/json ContentType.
+type ForgotPasswordJSONRequestBody ForgotPasswordJSONBody
+
+// IssueProductJSONRequestBody defines body for IssueProduct for application/json ContentType.
+type IssueProductJSONRequestBody IssueProductJSONBody
+
+// LoginJSONRequestBody defines body for Login for application/json ContentType.
+type LoginJSONRequestBody LoginJSONBody
+
+// CreateProductJSONRequestBody defines body for CreateProduct for application/json ContentType.
+type CreateProductJSONRequestBody CreateProductJSONBody
+
+// CreatePvzJSONRequestBody defines body for CreatePvz for application/json ContentType.
+type CreatePvzJSONRequestBody = PVZ
+
+// UpdatePvzJSONRequestBody defines body for UpdatePvz for application/json ContentType.
+type UpdatePvzJSONRequestBody UpdatePvzJSONBody
+
+// CreateStorageCellJSONRequestBody defines body for CreateStorageCell for application/json ContentType.
+type CreateStorageCellJSONRequestBody CreateStorageCellJSONBody
+
+// CreateReceptionJSONRequestBody defines body for CreateReception for application/json ContentType.
+type CreateReceptionJSONRequestBody CreateReceptionJSONBody
+
+// RegisterJSONRequestBody defines body for Register for application/json ContentType.
+type RegisterJSONRequestBody RegisterJSONBody
+
+// ResetPasswordJSONRequestBody defines body for ResetPassword for application/json ContentType.
+type ResetPasswordJSONRequestBody ResetPasswordJSONBody
+
+// ReturnProductJSONRequestBody defines body for ReturnProduct for application/json ContentType.
+type ReturnProductJSONRequestBody ReturnProductJSONBody
+
+// SyncOperationsJSONRequestBody defines body for SyncOperations for application/json ContentType.
+type SyncOperationsJSONRequestBody SyncOperationsJSONBody
+
+// VerifyEmailJSONRequestBody defines body for VerifyEmail for application/json ContentType.
+type VerifyEmailJSONRequestBody VerifyEmailJSONBody
+
+// ResendVerificationJSONRequestBody defines body for ResendVerification for application/json ContentType.
+type ResendVerificationJSONRequestBody ResendVerificationJSONBody
+
+// CreateWebhookJSONRequestBody defines body for CreateWebhook for application/json ContentType.
+type CreateWebhookJSONRequestBody CreateWebhookJSONBody
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"pvz-service/internal/model"
+)
+
+// weakETag строит слабый ETag из отметки изменения данных. variant различает
+// ответы, собранные из тех же данных: ресурс ПВЗ, параметры списка
+func weakETag(stamp model.ChangeStamp, variant string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d|%d|%s", stamp.ChangedAt.UnixNano(), stamp.Count, variant)))
+	return `W/"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// notModified ставит ETag ответа и отвечает 304, если клиент прислал его в If-None-Match.
+// Отметку берем до сборки ответа: если данные изменятся между ними, ETag окажется
+// старее ответа и следующий запрос просто получит 200
+func notModified(w http.ResponseWriter, r *http.Request, stamp model.ChangeStamp, variant string) bool {
+	etag := weakETag(stamp, variant)
+	w.Header().Set("ETag", etag)
+
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	getLogger(r).DebugContext(r.Context(), "not modified", slog.String("etag", etag))
+	return true
+}
+
+// etagMatches сравнивает If-None-Match с ETag слабым сравнением (RFC 9110, 13.1.2)
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// pvzNotModified - notModified по отметке изменения ПВЗ. Если отметку получить не удалось,
+// ответ собирается как обычно и ошибку (например, ПВЗ не найден) вернет основной запрос
+func pvzNotModified(w http.ResponseWriter, r *http.Request, service PvzChangeStamps, pvz model.Pvz, variant string) bool {
+	stamp, err := service.GetPvzChangeStamp(r.Context(), pvz)
+	if err != nil {
+		return false
+	}
+	return notModified(w, r, stamp, variant)
+}
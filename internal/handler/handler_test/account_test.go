@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+)
+
+type testAccountConfig struct{}
+
+func (testAccountConfig) GetVerificationTTL() time.Duration { return time.Hour }
+func (testAccountConfig) GetResetTTL() time.Duration        { return time.Hour }
+func (testAccountConfig) GetLinkBaseURL() string            { return "https://pvz.example" }
+func (testAccountConfig) RequireVerifiedEmail() bool        { return true }
+
+// lastMail хранит последнее письмо, тесты читают из него ссылку
+type lastMail struct {
+	mail model.Mail
+}
+
+func (m *lastMail) Send(_ context.Context, mail model.Mail) error {
+	m.mail = mail
+	return nil
+}
+
+var mailToken = regexp.MustCompile(`\?token=([A-Za-z0-9_-]+)`)
+
+func (m *lastMail) token(t *testing.T) string {
+	t.Helper()
+	match := mailToken.FindStringSubmatch(m.mail.Body)
+	require.NotNil(t, match, m.mail.Body)
+	return match[1]
+}
+
+// Регистрация, подтверждение почты и сброс пароля через HTTP
+func TestRouter_AccountFlow(t *testing.T) {
+	const secret = "test-secret"
+
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	serv := service.NewService(repo, secret, testWebhookConfig{})
+	mails := &lastMail{}
+	serv.AuthService.Mailer = mails
+	serv.AuthService.Accounts = testAccountConfig{}
+	server := httptest.NewServer(handler.NewRouter(serv, secret, logger, testSettings{},
+		handler.WithSpecValidation(specValidator(t))))
+	defer server.Close()
+
+	call := func(path, body string, want int) string {
+		t.Helper()
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, want, resp.StatusCode, "%s: %s", path, data)
+		return string(data)
+	}
+
+	require.Contains(t, call("/register", `{"email":"not-an-email","password":"Sklad-2026","role":"employee"}`,
+		http.StatusBadRequest), handler.CodeInvalidFields)
+	require.Contains(t, call("/register", `{"email":"emp@example.com","password":"qwerty","role":"employee"}`,
+		http.StatusBadRequest), service.CodeWeakPassword)
+	call("/register", `{"email":"emp@example.com","password":"Sklad-2026","role":"employee"}`, http.StatusCreated)
+
+	require.Contains(t, call("/login", `{"email":"emp@example.com","password":"Sklad-2026"}`, http.StatusForbidden),
+		service.CodeEmailNotVerified)
+
+	require.Contains(t, call("/verify-email", `{"token":"forged"}`, http.StatusBadRequest), service.CodeInvalidToken)
+	call("/verify-email", fmt.Sprintf(`{"token":%q}`, mails.token(t)), http.StatusOK)
+	call("/login", `{"email":"emp@example.com","password":"Sklad-2026"}`, http.StatusOK)
+
+	// Ответ не выдает, есть ли такая почта
+	call("/verify-email/resend", `{"email":"emp@example.com"}`, http.StatusAccepted)
+	call("/forgot-password", `{"email":"missing@example.com"}`, http.StatusAccepted)
+	call("/forgot-password", `{"email":"emp@example.com"}`, http.StatusAccepted)
+	// Письмо сброса уходит в фоне
+	require.NoError(t, serv.AuthService.WaitMails(context.Background()))
+	reset := mails.token(t)
+
+	require.Contains(t, call("/reset-password", fmt.Sprintf(`{"token":%q,"password":"short"}`, reset),
+		http.StatusBadRequest), service.CodeWeakPassword)
+	call("/reset-password", fmt.Sprintf(`{"token":%q,"password":"Novyi-Parol-1"}`, reset), http.StatusOK)
+	call("/reset-password", fmt.Sprintf(`{"token":%q,"password":"Novyi-Parol-1"}`, reset), http.StatusBadRequest)
+
+	call("/login", `{"email":"emp@example.com","password":"Sklad-2026"}`, http.StatusUnauthorized)
+	call("/api/v1/login", `{"email":"emp@example.com","password":"Novyi-Parol-1"}`, http.StatusOK)
+}
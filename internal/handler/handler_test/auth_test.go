@@ -13,7 +13,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"pvz-service/internal/handler"
 	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/model"
+	"pvz-service/internal/service"
 )
 
 func TestAuthHandler_Register(t *testing.T) {
@@ -60,7 +62,7 @@ func TestAuthHandler_Register(t *testing.T) {
 					}).Return(nil, errors.New(""))
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrInvalidRole),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidRole, handler.ErrInvalidRole),
 		},
 		{
 			name:    "ошибка регистрации - неверные поля запроса",
@@ -74,7 +76,7 @@ func TestAuthHandler_Register(t *testing.T) {
 					}).Return(nil, errors.New("registration failed"))
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrRequestFields),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
 		},
 		{
 			name:    "ошибка регистрации - неверное тело запроса",
@@ -88,7 +90,7 @@ func TestAuthHandler_Register(t *testing.T) {
 					}).Return(nil, errors.New(""))
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrBodyRequest),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:    "ошибка регистрация - ошибка сервера",
@@ -99,10 +101,10 @@ func TestAuthHandler_Register(t *testing.T) {
 						Email:    "test2@example.com",
 						Password: "password123",
 						Role:     "employee",
-					}).Return(nil, fmt.Errorf("server error"))
+					}).Return(nil, service.NewConflictError(service.CodeUserAlreadyExists, "user already exist"))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":"server error"}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeUserAlreadyExists, "user already exist"),
 		},
 	}
 
@@ -166,7 +168,7 @@ func TestAuthHandler_Authenticate(t *testing.T) {
 					}).Return(mock.Anything, nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrRequestFields),
+			expectedBody:   problemBody(http.StatusUnauthorized, handler.CodeInvalidFields, handler.ErrRequestFields),
 		},
 		{
 			name:    "ошибка авторизации - неверное тело запроса",
@@ -179,7 +181,7 @@ func TestAuthHandler_Authenticate(t *testing.T) {
 					}).Return(mock.Anything, nil)
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrBodyRequest),
+			expectedBody:   problemBody(http.StatusUnauthorized, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:    "ошибка авторизациии - ошибка сервера",
@@ -191,8 +193,8 @@ func TestAuthHandler_Authenticate(t *testing.T) {
 						Password: "password123",
 					}).Return("", fmt.Errorf("server error"))
 			},
-			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   fmt.Sprintf(`{"message":"server error"}`),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, service.CodeInternal, response.ErrInternalServer),
 		},
 	}
 
@@ -251,21 +253,21 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 			reqBody:        `{"role": "admin"}`,
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrInvalidRole),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidRole, handler.ErrInvalidRole),
 		},
 		{
 			name:           "ошибка тестовой авторизации - неверные поля запроса",
 			reqBody:        `{}`,
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrRequestFields),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
 		},
 		{
 			name:           "ошибка тестовой авторизации - неверное тело запроса",
 			reqBody:        `{"email": "test@example.com", :'inv'\rr\t "role": "employee"}`,
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrBodyRequest),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:    "ошибочная тестовая авторизация - server errpr",
@@ -275,8 +277,8 @@ func TestAuthHandler_DummyLogin(t *testing.T) {
 					mock.Anything, model.User{Role: handler.ModeratorRole}).
 					Return("", fmt.Errorf("server error"))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"server error"}`),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, service.CodeInternal, response.ErrInternalServer),
 		},
 	}
 
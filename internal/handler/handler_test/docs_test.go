@@ -0,0 +1,65 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/api"
+	"pvz-service/internal/handler"
+)
+
+func TestDocsHandlers(t *testing.T) {
+	docs, err := handler.NewDocsHandler(api.Spec)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	handler.MountDocs(r, docs)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("yaml как в репозитории", func(t *testing.T) {
+		w := get("/openapi.yaml")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+		assert.Equal(t, api.Spec, w.Body.Bytes())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		w := get("/openapi.json")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var spec struct {
+			OpenAPI string         `json:"openapi"`
+			Paths   map[string]any `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+		assert.Equal(t, "3.0.0", spec.OpenAPI)
+		assert.Contains(t, spec.Paths, "/pvz")
+	})
+
+	t.Run("swagger ui со встроенной статикой", func(t *testing.T) {
+		w := get("/docs")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "/openapi.json")
+		assert.Contains(t, w.Body.String(), "/docs/swagger-ui-bundle.js")
+		assert.NotContains(t, w.Body.String(), "cdn")
+
+		w = get("/docs/swagger-ui-bundle.js")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Body.Bytes())
+	})
+
+	t.Run("невалидная спецификация", func(t *testing.T) {
+		_, err := handler.NewDocsHandler([]byte("openapi: ["))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/mocks"
+	"pvz-service/pkg/buildinfo"
+)
+
+func TestHealthHandler(t *testing.T) {
+	info := buildinfo.Info{Commit: "abc123", BuildTime: "2024-01-01T00:00:00Z", GoVersion: "go1.23"}
+
+	tests := []struct {
+		name           string
+		path           string
+		shuttingDown   bool
+		mockSetup      func(c *mocks.ReadinessChecker)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "liveness",
+			path:           "/healthz",
+			mockSetup:      func(c *mocks.ReadinessChecker) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok"}`,
+		},
+		{
+			name: "readiness ok",
+			path: "/readyz",
+			mockSetup: func(c *mocks.ReadinessChecker) {
+				c.On("CheckReadiness", mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"status":"ok"}`,
+		},
+		{
+			name: "readiness db down",
+			path: "/readyz",
+			mockSetup: func(c *mocks.ReadinessChecker) {
+				c.On("CheckReadiness", mock.Anything).Return(errors.New("connection refused"))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   problemBody(http.StatusServiceUnavailable, handler.CodeNotReady, handler.ErrNotReady),
+		},
+		{
+			name:           "readiness while shutting down",
+			path:           "/readyz",
+			shuttingDown:   true,
+			mockSetup:      func(c *mocks.ReadinessChecker) {},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   problemBody(http.StatusServiceUnavailable, handler.CodeShuttingDown, handler.ErrShuttingDown),
+		},
+		{
+			name:           "version",
+			path:           "/version",
+			mockSetup:      func(c *mocks.ReadinessChecker) {},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"commit":"abc123","buildTime":"2024-01-01T00:00:00Z","goVersion":"go1.23"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := mocks.NewReadinessChecker(t)
+			tt.mockSetup(checker)
+
+			shuttingDown := &atomic.Bool{}
+			shuttingDown.Store(tt.shuttingDown)
+
+			r := chi.NewRouter()
+			handler.MountHealth(r, handler.NewHealthHandler(checker, shuttingDown, info))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"pvz-service/internal/handler"
 	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/model"
 )
 
@@ -106,7 +107,7 @@ func TestInfoHandler_GetInfo(t *testing.T) {
 			queryParams:    invalidDateQuery,
 			mockSetup:      func(s *mocks.InfoService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   handler.ErrConvertParams,
+			expectedBody:   handler.ErrQueryParameters,
 		},
 
 		{
@@ -114,7 +115,7 @@ func TestInfoHandler_GetInfo(t *testing.T) {
 			queryParams:    invalidDateQuery2,
 			mockSetup:      func(s *mocks.InfoService) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   handler.ErrConvertParams,
+			expectedBody:   handler.ErrQueryParameters,
 		},
 		{
 			name:           "невалидная страница",
@@ -135,8 +136,8 @@ func TestInfoHandler_GetInfo(t *testing.T) {
 						Limit:     10, // по умолчанию
 					}).Return(nil, errors.New("some error"))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   "Failed to get PVZ: some error",
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   response.ErrInternalServer,
 		},
 		{
 			name:        "успешный запрос c несколькими пвз",
@@ -152,19 +153,35 @@ func TestInfoHandler_GetInfo(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:        "с выведенными из эксплуатации ПВЗ",
+			queryParams: "/info?includeInactive=true",
+			mockSetup: func(s *mocks.InfoService) {
+				s.On("GetInfoPvz", mock.Anything, &model.PvzInfoQuery{
+					Page:            1,
+					Limit:           10,
+					IncludeInactive: true,
+				}).Return([]*model.Pvz{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockInfoService := new(mocks.InfoService)
+			mockInfoService.On("GetInfoChangeStamp", mock.Anything).Return(model.ChangeStamp{}, nil).Maybe()
 			if tt.mockSetup != nil {
 				tt.mockSetup(mockInfoService)
 			}
 
-			infoHandler := handler.NewInfoHandler(mockInfoService)
+			infoHandler := handler.NewInfoHandler(mockInfoService, testSettings{})
 
 			r := chi.NewRouter()
-			r.Get("/info", infoHandler.GetInfo)
+			r.Get("/info", operations(struct {
+				unimplemented
+				*handler.InfoHandlers
+			}{InfoHandlers: infoHandler}).GetPvzList)
 
 			req := httptest.NewRequest(http.MethodGet, tt.queryParams, nil)
 			rec := httptest.NewRecorder()
@@ -174,7 +191,7 @@ func TestInfoHandler_GetInfo(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, rec.Code)
 			if tt.expectedBody != "" {
 				assert.Contains(t, rec.Body.String(),
-					fmt.Sprintf(`{"message":"%s"}`, tt.expectedBody))
+					fmt.Sprintf(`"detail":"%s"`, tt.expectedBody))
 			}
 
 			mockInfoService.AssertExpectations(t)
@@ -182,6 +199,54 @@ func TestInfoHandler_GetInfo(t *testing.T) {
 	}
 }
 
+func TestInfoHandler_GetInfo_ETag(t *testing.T) {
+	stamp := model.ChangeStamp{ChangedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), Count: 2}
+	query := &model.PvzInfoQuery{Page: 1, Limit: 10}
+
+	mockInfoService := new(mocks.InfoService)
+	mockInfoService.On("GetInfoChangeStamp", mock.Anything).Return(stamp, nil).Times(3)
+	mockInfoService.On("GetInfoChangeStamp", mock.Anything).Return(model.ChangeStamp{}, errors.New("db down")).Once()
+	// Ответ собирается только для первого запроса, смены параметров и запроса без отметки
+	mockInfoService.On("GetInfoPvz", mock.Anything, query).Return([]*model.Pvz{}, nil).Twice()
+	mockInfoService.On("GetInfoPvz", mock.Anything, &model.PvzInfoQuery{Page: 2, Limit: 10}).Return([]*model.Pvz{}, nil).Once()
+
+	r := chi.NewRouter()
+	r.Get("/info", operations(struct {
+		unimplemented
+		*handler.InfoHandlers
+	}{InfoHandlers: handler.NewInfoHandler(mockInfoService, testSettings{})}).GetPvzList)
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/info", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]+"$`, etag)
+
+	cached := get("/info", `"other", `+etag)
+	assert.Equal(t, http.StatusNotModified, cached.Code)
+	assert.Equal(t, etag, cached.Header().Get("ETag"))
+	assert.Empty(t, cached.Body.String())
+
+	otherPage := get("/info?page=2", etag)
+	assert.Equal(t, http.StatusOK, otherPage.Code)
+	assert.NotEqual(t, etag, otherPage.Header().Get("ETag"))
+
+	noStamp := get("/info", etag)
+	assert.Equal(t, http.StatusOK, noStamp.Code)
+	assert.Empty(t, noStamp.Header().Get("ETag"))
+
+	mockInfoService.AssertExpectations(t)
+}
+
 func parseRFC3339(dateStr string) time.Time {
 	t, _ := time.Parse(time.RFC3339, dateStr)
 	return t
@@ -0,0 +1,244 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/middleware"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+)
+
+const testEmployeeID = "employee-1"
+
+// withEmployee имитирует JWT-аутентификацию сотрудника
+func withEmployee(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, testEmployeeID)))
+	}
+}
+
+func TestIssuanceHandlers_IssueProduct(t *testing.T) {
+	mockService := new(mocks.IssuanceService)
+	h := handler.NewIssuanceHandler(mockService)
+
+	router := chi.NewRouter()
+	router.Post("/issuances", withEmployee(h.IssueProduct))
+
+	productID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	issuedProductID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	issuanceID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	issuedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "успешная выдача",
+			body: fmt.Sprintf(`{"productId":"%s","pvzId":"%s","confirmationCode":"4821"}`, productID, pvzID),
+			mockSetup: func() {
+				mockService.On("IssueProduct", mock.Anything, model.Issuance{
+					ProductID: productID, PvzID: pvzID, EmployeeID: testEmployeeID, ConfirmationCode: "4821",
+				}).Return(&model.Issuance{
+					ID: issuanceID, ProductID: productID, PvzID: pvzID, IssuedAt: issuedAt,
+					EmployeeID: testEmployeeID, ConfirmationCode: "4821",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: fmt.Sprintf(`{"id":"%s","productId":"%s","pvzId":"%s","issuedAt":"2024-03-01T12:00:00Z",
+				"employeeId":"%s","confirmationCode":"4821"}`, issuanceID, productID, pvzID, testEmployeeID),
+		},
+		{
+			name:           "код подтверждения не указан",
+			body:           fmt.Sprintf(`{"productId":"%s","pvzId":"%s"}`, productID, pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "невалидный UUID товара",
+			body:           fmt.Sprintf(`{"productId":"42","pvzId":"%s","confirmationCode":"4821"}`, pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
+		},
+		{
+			name: "товар уже выдан",
+			body: fmt.Sprintf(`{"productId":"%s","pvzId":"%s","confirmationCode":"4821"}`, issuedProductID, pvzID),
+			mockSetup: func() {
+				mockService.On("IssueProduct", mock.Anything, mock.MatchedBy(func(i model.Issuance) bool {
+					return i.ProductID == issuedProductID
+				})).Return(nil, service.NewConflictError(service.CodeProductAlreadyIssued, service.ProductAlreadyIssued))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeProductAlreadyIssued, service.ProductAlreadyIssued),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/issuances", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssuanceHandlers_ReturnProduct(t *testing.T) {
+	mockService := new(mocks.IssuanceService)
+	h := handler.NewIssuanceHandler(mockService)
+
+	router := chi.NewRouter()
+	router.Post("/returns", withEmployee(h.ReturnProduct))
+
+	productID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	returnID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+	returnedAt := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "успешный возврат",
+			body: fmt.Sprintf(`{"productId":"%s","pvzId":"%s","reason":"брак"}`, productID, pvzID),
+			mockSetup: func() {
+				mockService.On("ReturnProduct", mock.Anything, model.ProductReturn{
+					ProductID: productID, PvzID: pvzID, EmployeeID: testEmployeeID, Reason: "брак",
+				}).Return(&model.ProductReturn{
+					ID: returnID, ProductID: productID, PvzID: pvzID, ReturnedAt: returnedAt,
+					EmployeeID: testEmployeeID, Reason: "брак",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: fmt.Sprintf(`{"id":"%s","productId":"%s","pvzId":"%s","returnedAt":"2024-03-02T12:00:00Z",
+				"employeeId":"%s","reason":"брак"}`, returnID, productID, pvzID, testEmployeeID),
+		},
+		{
+			name:           "причина не указана",
+			body:           fmt.Sprintf(`{"productId":"%s","pvzId":"%s"}`, productID, pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/returns", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIssuanceHandlers_GetPvzStock(t *testing.T) {
+	mockService := new(mocks.IssuanceService)
+	h := handler.NewIssuanceHandler(mockService)
+
+	router := chi.NewRouter()
+	router.Get("/pvz/{pvzId}/stock", operations(struct {
+		unimplemented
+		*handler.IssuanceHandlers
+	}{IssuanceHandlers: h}).GetPvzStock)
+
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	missingPvzID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	product := model.Product{
+		ID:          uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		DateTime:    time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		TypeProduct: handler.ShoesType,
+		ReceptionID: uuid.MustParse("77777777-7777-7777-7777-777777777777"),
+	}
+
+	tests := []struct {
+		name           string
+		pvzID          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "остатки ПВЗ",
+			pvzID: pvzID.String(),
+			mockSetup: func() {
+				mockService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: pvzID}).Return(model.ChangeStamp{ChangedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), Count: 1}, nil)
+				mockService.On("GetStock", mock.Anything, model.Pvz{ID: pvzID}).Return([]model.Product{product}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"pvzId":"%s","count":1,"products":[
+				{"id":"%s","dateTime":"2024-03-01T09:00:00Z","type":"обувь","receptionId":"%s"}]}`,
+				pvzID, product.ID, product.ReceptionID),
+		},
+		{
+			name:           "невалидный UUID",
+			pvzID:          "123",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
+		},
+		{
+			name:  "ПВЗ не найден",
+			pvzID: missingPvzID.String(),
+			mockSetup: func() {
+				mockService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: missingPvzID}).
+					Return(model.ChangeStamp{}, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+				mockService.On("GetStock", mock.Anything, model.Pvz{ID: missingPvzID}).
+					Return(nil, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, service.CodePvzNotFound, service.PvzNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/"+tt.pvzID+"/stock", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
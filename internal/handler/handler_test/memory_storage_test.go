@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/api"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/middleware"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/pkg/webhooksig"
+)
+
+const webhookSecret = "partner-secret-0123"
+
+// testWebhookConfig - вебхуки без повторов по времени: один запрос на доставку
+type testWebhookConfig struct{}
+
+func (testWebhookConfig) GetDeliveryTimeout() time.Duration { return time.Second }
+
+func (testWebhookConfig) GetDeliveryWorkers() int { return 1 }
+
+func (testWebhookConfig) GetDeliveryPassTimeout() time.Duration { return time.Minute }
+
+func (testWebhookConfig) GetMaxAttempts() int { return 1 }
+
+func (testWebhookConfig) GetRetryBackoff() (time.Duration, time.Duration) {
+	return time.Minute, time.Minute
+}
+
+// GetAllowPrivateTargets - получатели в сценариях на httptest
+func (testWebhookConfig) GetAllowPrivateTargets() bool { return true }
+
+// specValidator сверяет запросы и ответы сценария с api/swagger.yaml
+func specValidator(t *testing.T) *middleware.OpenAPIValidator {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	v, err := middleware.NewOpenAPIValidator(doc)
+	require.NoError(t, err)
+	return v
+}
+
+// Полный сценарий приемки через роутер поверх хранилища в памяти, без базы данных
+func TestRouter_MemoryStorageFlow(t *testing.T) {
+	const secret = "test-secret"
+
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	serv := service.NewService(repo, secret, testWebhookConfig{})
+	server := httptest.NewServer(handler.NewRouter(serv, secret, logger, testSettings{},
+		handler.WithSpecValidation(specValidator(t)), handler.WithDummyLogin()))
+	defer server.Close()
+
+	// Партнер, подписанный на закрытие приемок
+	var hooks []string
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := webhooksig.Verify(webhookSecret, r.Header.Get(webhooksig.SignatureHeader), r.Header.Get(webhooksig.TimestampHeader),
+			body, time.Now(), webhooksig.DefaultTolerance)
+		assert.NoError(t, err)
+		hooks = append(hooks, r.Header.Get(webhooksig.EventHeader))
+	}))
+	defer partner.Close()
+
+	do := func(method, path, token, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	login := func(role string) string {
+		resp := do(http.MethodPost, "/dummyLogin", "", fmt.Sprintf(`{"role":"%s"}`, role))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		token, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return strings.TrimSpace(string(token))
+	}
+
+	moderator := login(handler.ModeratorRole)
+	employee := login(handler.EmployeeRole)
+
+	resp := do(http.MethodPost, "/pvz", moderator, `{"city":"Москва"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var pvz dto.PVZ
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&pvz))
+	require.NotNil(t, pvz.Id)
+	pvzID := pvz.Id.String()
+
+	resp = do(http.MethodPost, "/webhooks", moderator, fmt.Sprintf(`{"url":"%s","eventTypes":["reception.closed"],"secret":"%s"}`,
+		partner.URL, webhookSecret))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var webhook dto.Webhook
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&webhook))
+
+	resp = do(http.MethodPost, "/receptions", employee, fmt.Sprintf(`{"pvzId":"%s"}`, pvzID))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	resp = do(http.MethodPost, "/receptions", employee, fmt.Sprintf(`{"pvzId":"%s"}`, pvzID))
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	for _, typeProduct := range []string{handler.ShoesType, handler.ElectrType} {
+		resp = do(http.MethodPost, "/products", employee,
+			fmt.Sprintf(`{"type":"%s","pvzId":"%s"}`, typeProduct, pvzID))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	resp = do(http.MethodPost, "/pvz/"+pvzID+"/delete_last_product", employee, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = do(http.MethodPost, "/pvz/"+pvzID+"/close_last_reception", employee, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var reception dto.Reception
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reception))
+	assert.Equal(t, dto.Close, reception.Status)
+
+	// Доставку делает планировщик, здесь вызываем его задачу напрямую
+	require.NoError(t, serv.ForEachTenant(context.Background(), serv.DeliverWebhooks))
+	assert.Equal(t, []string{model.WebhookEventReceptionClosed}, hooks)
+
+	resp = do(http.MethodGet, "/webhooks/"+webhook.Id.String()+"/deliveries", moderator, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var deliveries []dto.WebhookDelivery
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&deliveries))
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, dto.WebhookDeliveryStatusDelivered, deliveries[0].Status)
+
+	resp = do(http.MethodGet, "/pvz", moderator, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var info []dto.PvzInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Len(t, info, 1)
+	assert.Equal(t, pvz.Id, info[0].Pvz.Id)
+	require.Len(t, info[0].Receptions, 1)
+	require.Len(t, info[0].Receptions[0].Products, 1)
+	assert.Equal(t, dto.ProductType(handler.ShoesType), info[0].Receptions[0].Products[0].Type)
+}
@@ -15,7 +15,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"pvz-service/internal/handler"
 	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/model"
+	"pvz-service/internal/service"
 )
 
 func TestProductHandlers_CreateNewProduct(t *testing.T) {
@@ -27,6 +29,7 @@ func TestProductHandlers_CreateNewProduct(t *testing.T) {
 
 	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	testRecepID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	cellID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
 
 	product := &model.Product{
 		ID:          uuid.New(),
@@ -60,14 +63,14 @@ func TestProductHandlers_CreateNewProduct(t *testing.T) {
 			body:           fmt.Sprintf(`{"pvzId":"%s"}`, pvzID),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrRequestFields),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
 		},
 		{
 			name:           "невалидное тело запроса",
 			body:           `{typeProduct:"electronic"}`, // неправильно оформлен JSON
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrBodyRequest),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name: "невалидный UUID",
@@ -75,14 +78,91 @@ func TestProductHandlers_CreateNewProduct(t *testing.T) {
 			mockSetup: func() {
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrUUIDParsing),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:           "неподдерживаемый тип продукта",
 			body:           fmt.Sprintf(`{"type":"weird","pvzId":"%s"}`, pvzID),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrProductType),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidProductType, handler.ErrProductType),
+		},
+		{
+			name: "создание со штрихкодом и габаритами",
+			body: fmt.Sprintf(`{"type":"%s","pvzId":"%s","barcode":"4006381333931","sku":"SKU-1","weightGrams":1200,
+				"dimensions":{"lengthMm":300,"widthMm":200,"heightMm":100}}`, handler.ShoesType, pvzID),
+			mockSetup: func() {
+				mockService.On("AddProduct", mock.Anything, model.Product{
+					TypeProduct: handler.ShoesType,
+					Barcode:     "4006381333931",
+					SKU:         "SKU-1",
+					WeightGrams: 1200,
+					Dimensions:  model.Dimensions{LengthMM: 300, WidthMM: 200, HeightMM: 100},
+				}, model.Pvz{ID: pvzID}).Return(&model.Product{
+					ID:          product.ID,
+					ReceptionID: testRecepID,
+					TypeProduct: handler.ShoesType,
+					DateTime:    product.DateTime,
+					Barcode:     "4006381333931",
+					SKU:         "SKU-1",
+					WeightGrams: 1200,
+					Dimensions:  model.Dimensions{LengthMM: 300, WidthMM: 200, HeightMM: 100},
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: fmt.Sprintf(`{"id":"%s","receptionId":"%s","type":"%s","dateTime":"%s",
+				"barcode":"4006381333931","sku":"SKU-1","weightGrams":1200,
+				"dimensions":{"lengthMm":300,"widthMm":200,"heightMm":100}}`,
+				product.ID, testRecepID, handler.ShoesType, product.DateTime.Format(time.RFC3339)),
+		},
+		{
+			name:           "неверная контрольная цифра штрихкода",
+			body:           fmt.Sprintf(`{"type":"%s","pvzId":"%s","barcode":"4006381333932"}`, handler.ShoesType, pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBarcode, handler.ErrBarcode),
+		},
+		{
+			name: "нулевые габариты",
+			body: fmt.Sprintf(`{"type":"%s","pvzId":"%s","dimensions":{"lengthMm":0,"widthMm":200,"heightMm":100}}`,
+				handler.ShoesType, pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name: "размещение в указанной ячейке",
+			body: fmt.Sprintf(`{"type":"%s","pvzId":"%s","cellId":"%s"}`, handler.ShoesType, pvzID, cellID),
+			mockSetup: func() {
+				mockService.On("AddProduct", mock.Anything, model.Product{TypeProduct: handler.ShoesType, CellID: cellID},
+					model.Pvz{ID: pvzID}).Return(&model.Product{
+					ID:          product.ID,
+					ReceptionID: testRecepID,
+					TypeProduct: handler.ShoesType,
+					DateTime:    product.DateTime,
+					CellID:      cellID,
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: fmt.Sprintf(`{"id":"%s","receptionId":"%s","type":"%s","dateTime":"%s","cellId":"%s"}`,
+				product.ID, testRecepID, handler.ShoesType, product.DateTime.Format(time.RFC3339), cellID),
+		},
+		{
+			name:           "невалидный UUID ячейки",
+			body:           fmt.Sprintf(`{"type":"%s","pvzId":"%s","cellId":"A-01"}`, handler.ShoesType, pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
+		},
+		{
+			name: "ячейка заполнена",
+			body: fmt.Sprintf(`{"type":"%s","pvzId":"%s","cellId":"%s"}`, handler.ElectrType, pvzID, cellID),
+			mockSetup: func() {
+				mockService.On("AddProduct", mock.Anything, model.Product{TypeProduct: handler.ElectrType, CellID: cellID},
+					model.Pvz{ID: pvzID}).Return(nil, service.NewConflictError(service.CodeStorageCellFull, service.StorageCellFull))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeStorageCellFull, service.StorageCellFull),
 		},
 		{
 			name: "ошибка при создании продукта",
@@ -91,8 +171,8 @@ func TestProductHandlers_CreateNewProduct(t *testing.T) {
 				mockService.On("AddProduct", mock.Anything, model.Product{TypeProduct: handler.ClothesType},
 					model.Pvz{ID: pvzID}).Return(nil, errors.New("DB error"))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":"Failed add Product: DB error"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, service.CodeInternal, response.ErrInternalServer),
 		},
 	}
 
@@ -113,12 +193,15 @@ func TestProductHandlers_CreateNewProduct(t *testing.T) {
 	}
 }
 
-func TestProductHandlers_RemoveLastProduct(t *testing.T) {
+func TestProductHandlers_DeleteLastProduct(t *testing.T) {
 	mockService := new(mocks.ProductService)
 	handl := handler.NewProductHandler(mockService)
 
 	router := chi.NewRouter()
-	router.Delete("/product/{pvzId}", handl.RemoveLastProduct)
+	router.Delete("/product/{pvzId}", operations(struct {
+		unimplemented
+		*handler.ProductHandlers
+	}{ProductHandlers: handl}).DeleteLastProduct)
 
 	validPvzID := uuid.New()
 	validPvzID2 := uuid.New()
@@ -146,7 +229,7 @@ func TestProductHandlers_RemoveLastProduct(t *testing.T) {
 			pvzIdPath:      "invalid-uuid",
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrUUIDParsing),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
 		},
 		{
 			name:      "ошибка при удалении",
@@ -154,10 +237,10 @@ func TestProductHandlers_RemoveLastProduct(t *testing.T) {
 			mockSetup: func() {
 				mockService.On("DeleteProduct",
 					mock.Anything, model.Pvz{ID: validPvzID2}).
-					Return(errors.New("delete error"))
+					Return(service.NewNotFoundError(service.CodeProductNotFound, service.ProductNotFound))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":"failed to delete product:  delete error"}`,
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, service.CodeProductNotFound, service.ProductNotFound),
 		},
 	}
 
@@ -180,3 +263,72 @@ func TestProductHandlers_RemoveLastProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestProductHandlers_GetProductByBarcode(t *testing.T) {
+	mockService := new(mocks.ProductService)
+	handl := handler.NewProductHandler(mockService)
+
+	router := chi.NewRouter()
+	router.Get("/products/by-barcode/{code}", operations(struct {
+		unimplemented
+		*handler.ProductHandlers
+	}{ProductHandlers: handl}).GetProductByBarcode)
+
+	product := &model.Product{
+		ID:          uuid.New(),
+		ReceptionID: uuid.New(),
+		TypeProduct: handler.ClothesType,
+		DateTime:    time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		Barcode:     "4006381333931",
+	}
+
+	tests := []struct {
+		name           string
+		code           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "товар найден",
+			code: product.Barcode,
+			mockSetup: func() {
+				mockService.On("GetProductByBarcode", mock.Anything, product.Barcode).Return(product, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","receptionId":"%s","type":"%s","dateTime":"%s","barcode":"%s"}`,
+				product.ID, product.ReceptionID, handler.ClothesType, product.DateTime.Format(time.RFC3339), product.Barcode),
+		},
+		{
+			name:           "невалидный штрихкод",
+			code:           "4006381333932",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBarcode, handler.ErrBarcode),
+		},
+		{
+			name: "товар не найден",
+			code: "5901234123457",
+			mockSetup: func() {
+				mockService.On("GetProductByBarcode", mock.Anything, "5901234123457").
+					Return(nil, service.NewNotFoundError(service.CodeProductNotFound, service.ProductNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, service.CodeProductNotFound, service.ProductNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/products/by-barcode/"+tt.code, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
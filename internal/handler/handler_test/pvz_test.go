@@ -12,9 +12,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"pvz-service/internal/handler"
 	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/model"
+	"pvz-service/internal/service"
 )
 
 func TestPvzHandler_Create(t *testing.T) {
@@ -43,10 +46,11 @@ func TestPvzHandler_Create(t *testing.T) {
 						ID:               testPvzID,
 						RegistrationDate: fixedTime,
 						City:             handler.KazanRU,
+						Status:           model.PvzStatusActive,
 					}, nil)
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate" :"%s","city":"%s"}`,
+			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate" :"%s","city":"%s","status":"active"}`,
 				testPvzID.String(), fixedTime.Format(time.RFC3339), handler.KazanRU),
 		},
 		{
@@ -54,21 +58,21 @@ func TestPvzHandler_Create(t *testing.T) {
 			reqBody:        fmt.Sprintf(`{city: "%s"}`, handler.KazanRU),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrBodyRequest),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:           "ошибка создания - invalidFields",
 			reqBody:        fmt.Sprintf(`{"role":"employee"}`),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrRequestFields),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
 		},
 		{
 			name:           "ошибка создания - InvalidCity",
 			reqBody:        fmt.Sprintf(`{"city": "%s"}`, "Nizhnekamsk"),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrInvalidCity),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidCity, handler.ErrInvalidCity),
 		},
 		{
 			name:    "ошибка создания - ошибка сервера",
@@ -78,8 +82,8 @@ func TestPvzHandler_Create(t *testing.T) {
 					mock.Anything, model.Pvz{City: handler.SpbRU}).
 					Return(nil, fmt.Errorf("server error"))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"failed to create PVZ: server error"}`),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, service.CodeInternal, response.ErrInternalServer),
 		},
 	}
 	for _, tt := range tests {
@@ -102,3 +106,447 @@ func TestPvzHandler_Create(t *testing.T) {
 		})
 	}
 }
+
+func TestPvzHandler_GetPvz(t *testing.T) {
+	mockPvzService := new(mocks.PvzService)
+	pvzHandler := handler.NewPvzHandler(mockPvzService)
+	r := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.PVZHandlers
+	}{PVZHandlers: pvzHandler})
+	r.Get("/pvz/{pvzId}", ops.GetPvz)
+
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	missingID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	registered := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	overrideUntil := time.Date(2024, time.March, 1, 20, 0, 0, 0, time.UTC)
+	stamp := model.ChangeStamp{ChangedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), Count: 1}
+
+	tests := []struct {
+		name           string
+		pvzID          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "полный профиль",
+			pvzID: pvzID.String(),
+			mockSetup: func() {
+				mockPvzService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: pvzID}).Return(stamp, nil)
+				mockPvzService.On("GetPvz", mock.Anything, model.Pvz{ID: pvzID}).Return(&model.Pvz{
+					ID:               pvzID,
+					RegistrationDate: registered,
+					City:             handler.MoscowRU,
+					Status:           model.PvzStatusActive,
+					Address:          "ул. Тверская, 1",
+					Coordinates:      &model.GeoPoint{Lat: 55.7558, Lng: 37.6173},
+					Timezone:         model.DefaultTimezone,
+					WorkingHours: model.WorkingHours{
+						Weekly: map[time.Weekday]model.DayHours{time.Monday: {Open: 9 * 60, Close: 21 * 60}},
+						Exceptions: []model.HoursException{
+							{Date: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), Closed: true},
+							{Date: time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC), Hours: model.DayHours{Open: 9 * 60, Close: 18*60 + 30}},
+						},
+					},
+					MaxDailyReceptions:     3,
+					ReceptionOverrideUntil: overrideUntil,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate":"2024-01-01T12:00:00Z","city":"Москва","status":"active",
+				"address":"ул. Тверская, 1","coordinates":{"lat":55.7558,"lng":37.6173},"timezone":"Europe/Moscow",
+				"workingHours":{"weekly":{"mon":{"open":"09:00","close":"21:00"}},"exceptions":[
+					{"date":"2024-03-08","closed":true},{"date":"2024-03-07","open":"09:00","close":"18:30"}]},
+				"maxDailyReceptions":3,"receptionOverrideUntil":"2024-03-01T20:00:00Z"}`, pvzID),
+		},
+		{
+			name:           "невалидный UUID",
+			pvzID:          "123",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
+		},
+		{
+			name:  "ПВЗ не найден",
+			pvzID: missingID.String(),
+			mockSetup: func() {
+				mockPvzService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: missingID}).
+					Return(model.ChangeStamp{}, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+				mockPvzService.On("GetPvz", mock.Anything, model.Pvz{ID: missingID}).
+					Return(nil, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, service.CodePvzNotFound, service.PvzNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/"+tt.pvzID, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			// ETag есть только у успешного ответа
+			assert.Equal(t, tt.expectedStatus == http.StatusOK, w.Header().Get("ETag") != "")
+			mockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+// Повторный запрос с тем же ETag получает 304, профиль при этом не собирается
+func TestPvzHandler_GetPvz_NotModified(t *testing.T) {
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	stamp := model.ChangeStamp{ChangedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), Count: 1}
+
+	mockPvzService := new(mocks.PvzService)
+	mockPvzService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: pvzID}).Return(stamp, nil).Twice()
+	mockPvzService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: pvzID}).
+		Return(model.ChangeStamp{ChangedAt: stamp.ChangedAt.Add(time.Second), Count: 1}, nil).Once()
+	mockPvzService.On("GetPvz", mock.Anything, model.Pvz{ID: pvzID}).
+		Return(&model.Pvz{ID: pvzID, City: handler.KazanRU, Status: model.PvzStatusActive}, nil).Twice()
+
+	r := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.PVZHandlers
+	}{PVZHandlers: handler.NewPvzHandler(mockPvzService)})
+	r.Get("/pvz/{pvzId}", ops.GetPvz)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/pvz/"+pvzID.String(), nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+
+	cached := get(etag)
+	assert.Equal(t, http.StatusNotModified, cached.Code)
+	assert.Empty(t, cached.Body.String())
+
+	// ПВЗ изменился - ответ собирается заново с новым ETag
+	changed := get(etag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.NotEqual(t, etag, changed.Header().Get("ETag"))
+
+	mockPvzService.AssertExpectations(t)
+}
+
+func TestPvzHandler_UpdatePvz(t *testing.T) {
+	mockPvzService := new(mocks.PvzService)
+	pvzHandler := handler.NewPvzHandler(mockPvzService)
+	r := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.PVZHandlers
+	}{PVZHandlers: pvzHandler})
+	r.Patch("/pvz/{pvzId}", ops.UpdatePvz)
+
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	registered := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	address := "Невский пр., 28"
+	limit := 2
+	hours := model.WorkingHours{
+		Weekly: map[time.Weekday]model.DayHours{
+			time.Saturday: {Open: 10 * 60, Close: model.MinutesInDay},
+		},
+		Exceptions: []model.HoursException{{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Closed: true}},
+	}
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "успешное обновление",
+			reqBody: `{"address":"Невский пр., 28","maxDailyReceptions":2,
+				"workingHours":{"weekly":{"sat":{"open":"10:00","close":"24:00"}},"exceptions":[{"date":"2024-01-01","closed":true}]}}`,
+			mockSetup: func() {
+				mockPvzService.On("UpdatePvz", mock.Anything, model.PvzPatch{
+					ID: pvzID, Address: &address, MaxDailyReceptions: &limit, WorkingHours: &hours,
+				}).Return(&model.Pvz{
+					ID:                 pvzID,
+					RegistrationDate:   registered,
+					City:               handler.SpbRU,
+					Status:             model.PvzStatusActive,
+					Address:            address,
+					Timezone:           model.DefaultTimezone,
+					WorkingHours:       hours,
+					MaxDailyReceptions: limit,
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate":"2024-01-01T12:00:00Z","city":"Санкт-Петербург","status":"active",
+				"address":"Невский пр., 28","timezone":"Europe/Moscow","maxDailyReceptions":2,
+				"workingHours":{"weekly":{"sat":{"open":"10:00","close":"24:00"}},"exceptions":[{"date":"2024-01-01","closed":true}]}}`,
+				pvzID),
+		},
+		{
+			name:           "неизвестный день недели",
+			reqBody:        `{"workingHours":{"weekly":{"monday":{"open":"09:00","close":"21:00"}}}}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "неверный формат времени",
+			reqBody:        `{"workingHours":{"weekly":{"mon":{"open":"9:00","close":"21:00"}}}}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "исключение без часов и без закрытия",
+			reqBody:        `{"workingHours":{"exceptions":[{"date":"2024-01-01"}]}}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "широта вне диапазона",
+			reqBody:        `{"coordinates":{"lat":91,"lng":37.6}}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "неизвестный часовой пояс",
+			reqBody:        `{"timezone":"Mars/Olympus"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:    "закрытие раньше открытия",
+			reqBody: `{"workingHours":{"weekly":{"mon":{"open":"21:00","close":"09:00"}}}}`,
+			mockSetup: func() {
+				mockPvzService.On("UpdatePvz", mock.Anything, mock.Anything).
+					Return(nil, service.NewValidationError(service.CodeInvalidPvzProfile, service.InvalidWorkingHours)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, service.CodeInvalidPvzProfile, service.InvalidWorkingHours),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPatch, "/pvz/"+pvzID.String(), strings.NewReader(tt.reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzHandler_GetNearbyPvz(t *testing.T) {
+	mockPvzService := new(mocks.PvzService)
+	pvzHandler := handler.NewPvzHandler(mockPvzService)
+	r := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.PVZHandlers
+	}{PVZHandlers: pvzHandler})
+	r.Get("/pvz/nearby", ops.GetNearbyPvz)
+
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	point := model.GeoPoint{Lat: 55.75, Lng: 37.62}
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "ПВЗ с расстоянием и статусом",
+			query: "lat=55.75&lon=37.62&radius=2000&limit=5",
+			mockSetup: func() {
+				mockPvzService.On("GetNearbyPvz", mock.Anything,
+					model.NearbyQuery{Point: point, RadiusMeters: 2000, Limit: 5}).
+					Return([]model.NearbyPvz{{
+						Pvz: model.Pvz{
+							ID:          pvzID,
+							City:        handler.MoscowRU,
+							Address:     "ул. Тверская, 1",
+							Coordinates: &model.GeoPoint{Lat: 55.7558, Lng: 37.6173},
+							Timezone:    model.DefaultTimezone,
+						},
+						DistanceMeters: 645.7,
+						IsOpen:         true,
+					}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`[{"id":"%s","city":"Москва","address":"ул. Тверская, 1",
+				"coordinates":{"lat":55.7558,"lng":37.6173},"timezone":"Europe/Moscow",
+				"distanceMeters":646,"isOpen":true}]`, pvzID),
+		},
+		{
+			name:  "радиус и лимит по умолчанию",
+			query: "lat=55.75&lon=37.62",
+			mockSetup: func() {
+				mockPvzService.On("GetNearbyPvz", mock.Anything,
+					model.NearbyQuery{Point: point, RadiusMeters: 5000, Limit: 20}).
+					Return([]model.NearbyPvz{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			name:           "нет долготы",
+			query:          "lat=55.75",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidQuery, handler.ErrQueryParameters),
+		},
+		{
+			name:           "широта вне диапазона",
+			query:          "lat=91&lon=37.62",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidQuery, handler.ErrQueryParameters),
+		},
+		{
+			name:           "слишком большой радиус",
+			query:          "lat=55.75&lon=37.62&radius=100000",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidQuery, handler.ErrQueryParameters),
+		},
+		{
+			name:           "координаты не числом",
+			query:          "lat=abc&lon=37.62",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidQuery, handler.ErrQueryParameters),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/nearby?"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockPvzService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPvzHandler_ChangeStatus(t *testing.T) {
+	mockPvzService := new(mocks.PvzService)
+	pvzHandler := handler.NewPvzHandler(mockPvzService)
+	r := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.PVZHandlers
+	}{PVZHandlers: pvzHandler})
+	r.Post("/pvz/{pvzId}/suspend", ops.SuspendPvz)
+	r.Post("/pvz/{pvzId}/reopen", ops.ReopenPvz)
+	r.Post("/pvz/{pvzId}/decommission", ops.DecommissionPvz)
+
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	closedID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	registered := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	changed := func(status string) *model.Pvz {
+		return &model.Pvz{ID: pvzID, RegistrationDate: registered, City: handler.KazanRU, Status: status,
+			Timezone: model.DefaultTimezone}
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "приостановка",
+			path: "/pvz/" + pvzID.String() + "/suspend",
+			mockSetup: func() {
+				mockPvzService.On("ChangePvzStatus", mock.Anything, model.Pvz{ID: pvzID}, model.PvzStatusSuspended).
+					Return(changed(model.PvzStatusSuspended), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate":"2024-01-01T12:00:00Z","city":"Казань",
+				"status":"suspended","address":"","timezone":"Europe/Moscow","maxDailyReceptions":0}`, pvzID),
+		},
+		{
+			name: "возобновление",
+			path: "/pvz/" + pvzID.String() + "/reopen",
+			mockSetup: func() {
+				mockPvzService.On("ChangePvzStatus", mock.Anything, model.Pvz{ID: pvzID}, model.PvzStatusActive).
+					Return(changed(model.PvzStatusActive), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate":"2024-01-01T12:00:00Z","city":"Казань",
+				"status":"active","address":"","timezone":"Europe/Moscow","maxDailyReceptions":0}`, pvzID),
+		},
+		{
+			name: "выведенный ПВЗ нельзя вернуть",
+			path: "/pvz/" + closedID.String() + "/reopen",
+			mockSetup: func() {
+				mockPvzService.On("ChangePvzStatus", mock.Anything, model.Pvz{ID: closedID}, model.PvzStatusActive).
+					Return(nil, service.NewConflictError(service.CodeInvalidPvzStatus, service.InvalidPvzStatus)).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeInvalidPvzStatus, service.InvalidPvzStatus),
+		},
+		{
+			name: "вывод из эксплуатации",
+			path: "/pvz/" + pvzID.String() + "/decommission",
+			mockSetup: func() {
+				mockPvzService.On("ChangePvzStatus", mock.Anything, model.Pvz{ID: pvzID}, model.PvzStatusDecommissioned).
+					Return(changed(model.PvzStatusDecommissioned), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"id":"%s","registrationDate":"2024-01-01T12:00:00Z","city":"Казань",
+				"status":"decommissioned","address":"","timezone":"Europe/Moscow","maxDailyReceptions":0}`, pvzID),
+		},
+		{
+			name:           "невалидный UUID",
+			path:           "/pvz/123/suspend",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockPvzService.AssertExpectations(t)
+		})
+	}
+}
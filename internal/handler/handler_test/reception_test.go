@@ -14,7 +14,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"pvz-service/internal/handler"
 	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/model"
+	"pvz-service/internal/service"
 )
 
 func TestReceptionHandlers_OpenNewReception(t *testing.T) {
@@ -26,6 +28,7 @@ func TestReceptionHandlers_OpenNewReception(t *testing.T) {
 	fixedTime := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
 	testPvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
 	testPvzID2 := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	testPvzID3 := uuid.MustParse("44444444-4444-4444-4444-444444444444")
 	testRecepID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
 
 	tests := []struct {
@@ -60,21 +63,21 @@ func TestReceptionHandlers_OpenNewReception(t *testing.T) {
 			reqBody:        fmt.Sprintf(`{pvzID: "%s"}`, testPvzID), // неверный формат JSON
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrBodyRequest),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:           "ошибка при проверке полей запроса - неверные данные",
 			reqBody:        fmt.Sprintf(`{"role":"employee"}`),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrRequestFields),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
 		},
 		{
 			name:           "ошибка при проверке полей запроса - неверные uuid",
 			reqBody:        fmt.Sprintf(`{"pvzID": "%s"}`, "55"),
 			mockSetup:      func() {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrUUIDParsing),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
 		},
 		{
 			name:    "ошибка при создании - сервис не смог создать",
@@ -82,10 +85,21 @@ func TestReceptionHandlers_OpenNewReception(t *testing.T) {
 			mockSetup: func() {
 				mockReceptionService.On("CreateReception",
 					mock.Anything, model.Reception{PvzID: testPvzID2}).
-					Return(nil, fmt.Errorf("failed to create reception"))
+					Return(nil, service.NewConflictError(service.CodeReceptionNotClosed, service.ReceptionWasNotClosed))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":"failed to create Reception: failed to create reception"}`,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeReceptionNotClosed, service.ReceptionWasNotClosed),
+		},
+		{
+			name:    "ПВЗ закрыт по графику",
+			reqBody: fmt.Sprintf(`{"pvzID": "%s"}`, testPvzID3),
+			mockSetup: func() {
+				mockReceptionService.On("CreateReception",
+					mock.Anything, model.Reception{PvzID: testPvzID3}).
+					Return(nil, service.NewConflictError(service.CodePvzClosed, service.PvzClosed))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodePvzClosed, service.PvzClosed),
 		},
 	}
 
@@ -115,7 +129,11 @@ func TestReceptionHandlers_CloseLastReception(t *testing.T) {
 	receptionHandler := handler.NewReceptionHandler(mockReceptionService)
 	r := chi.NewRouter()
 
-	r.Post("/receptions/{pvzId}/close", receptionHandler.CloseLastReception)
+	ops := operations(struct {
+		unimplemented
+		*handler.ReceptionHandlers
+	}{ReceptionHandlers: receptionHandler})
+	r.Post("/receptions/{pvzId}/close", ops.CloseLastReception)
 
 	fixedTime := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
 	testPvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
@@ -134,14 +152,15 @@ func TestReceptionHandlers_CloseLastReception(t *testing.T) {
 			pvzIdParam: testPvzID.String(),
 			mockSetup: func() {
 				mockReceptionService.On("CloseReception", mock.Anything, model.Reception{PvzID: testPvzID}).Return(&model.Reception{
-					ID:       testRecepID,
-					DateTime: fixedTime,
-					IsClosed: true,
-					PvzID:    testPvzID,
+					ID:          testRecepID,
+					DateTime:    fixedTime,
+					IsClosed:    true,
+					PvzID:       testPvzID,
+					CloseReason: model.CloseReasonManual,
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody: fmt.Sprintf(`{"id":"%s","dateTime":"%s",  "pvzId":"%s", "status":"%s"}`,
+			expectedBody: fmt.Sprintf(`{"id":"%s","dateTime":"%s",  "pvzId":"%s", "status":"%s", "closeReason":"manual"}`,
 				testRecepID.String(), fixedTime.Format(time.RFC3339), testPvzID.String(), "close"),
 		},
 		{
@@ -151,7 +170,7 @@ func TestReceptionHandlers_CloseLastReception(t *testing.T) {
 				mockReceptionService.On("CloseReception", mock.Anything, model.Reception{PvzID: testPvzID}).Return(nil, nil)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   fmt.Sprintf(`{"message":"%s"}`, handler.ErrUUIDParsing),
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
 		},
 		{
 			name:       "ошибка при закрытии приёма - сервис не смог закрыть",
@@ -159,8 +178,8 @@ func TestReceptionHandlers_CloseLastReception(t *testing.T) {
 			mockSetup: func() {
 				mockReceptionService.On("CloseReception", mock.Anything, model.Reception{PvzID: testPvzID2}).Return(nil, fmt.Errorf("failed to close reception"))
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":"failed to close reception: failed to close reception"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   problemBody(http.StatusInternalServerError, service.CodeInternal, response.ErrInternalServer),
 		},
 	}
 
@@ -1,13 +1,18 @@
 package handler_test
 
 import (
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/middleware"
 	"pvz-service/pkg/jwtutils"
 	"pvz-service/pkg/logger"
 
@@ -18,9 +23,9 @@ import (
 func TestAccessControl_AllRoutes(t *testing.T) {
 	mockService := new(mocks.Service)
 	secret := "test-secret"
-	logger := logger.InitLogger()
+	logger := logger.InitLogger(slog.LevelDebug)
 
-	r := handler.NewRouter(mockService, secret, logger)
+	r := handler.NewRouter(mockService, secret, logger, testSettings{})
 
 	type testCase struct {
 		name           string
@@ -38,6 +43,17 @@ func TestAccessControl_AllRoutes(t *testing.T) {
 		{"NoToken /products POST", http.MethodPost, "/products", "", http.StatusForbidden},
 		{"NoToken /pvz/{id}/close_last_reception", http.MethodPost, "/pvz/123/close_last_reception", "", http.StatusForbidden},
 		{"NoToken /pvz/{id}/delete_last_product", http.MethodPost, "/pvz/123/delete_last_product", "", http.StatusForbidden},
+		{"NoToken /issuances POST", http.MethodPost, "/issuances", "", http.StatusForbidden},
+		{"NoToken /returns POST", http.MethodPost, "/returns", "", http.StatusForbidden},
+		{"NoToken /pvz/{id}/stock", http.MethodGet, "/pvz/123/stock", "", http.StatusForbidden},
+		{"NoToken /pvz/{id}/cells GET", http.MethodGet, "/pvz/123/cells", "", http.StatusForbidden},
+		{"NoToken /pvz/{id} GET", http.MethodGet, "/pvz/123", "", http.StatusForbidden},
+		{"NoToken /pvz/{id}/decommission", http.MethodPost, "/pvz/123/decommission", "", http.StatusForbidden},
+		{"NoToken /webhooks GET", http.MethodGet, "/webhooks", "", http.StatusForbidden},
+		{"NoToken /webhooks/{id}/test", http.MethodPost, "/webhooks/123/test", "", http.StatusForbidden},
+		// Поиск ближайших ПВЗ публичный: без токена запрос доходит до проверки параметров
+		{"NoToken /pvz/nearby GET", http.MethodGet, "/pvz/nearby", "", http.StatusBadRequest},
+		{"NoToken /pvz/nearby GET with point", http.MethodGet, "/pvz/nearby?lat=55.75&lon=37.62", "", http.StatusOK},
 
 		//Wrong Role
 		{"WrongRole-Employee /pvz POST", http.MethodPost, "/pvz", handler.EmployeeRole, http.StatusForbidden},
@@ -46,6 +62,23 @@ func TestAccessControl_AllRoutes(t *testing.T) {
 		{"WrongRole-Moderator /products POST", http.MethodPost, "/products", handler.ModeratorRole, http.StatusForbidden},
 		{"WrongRole-Moderator /pvz/{id}/close_last_reception", http.MethodPost, "/pvz/123/close_last_reception", handler.ModeratorRole, http.StatusForbidden},
 		{"WrongRole-Moderator /pvz/{id}/delete_last_product", http.MethodPost, "/pvz/123/delete_last_product", handler.ModeratorRole, http.StatusForbidden},
+		{"WrongRole-Moderator /issuances POST", http.MethodPost, "/issuances", handler.ModeratorRole, http.StatusForbidden},
+		{"WrongRole-Moderator /returns POST", http.MethodPost, "/returns", handler.ModeratorRole, http.StatusForbidden},
+		{"InvalidRole /pvz/{id}/stock", http.MethodGet, "/pvz/123/stock", "invalid", http.StatusForbidden},
+		{"WrongRole-Employee /pvz/{id} PATCH", http.MethodPatch, "/pvz/123", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /pvz/{id}/cells POST", http.MethodPost, "/pvz/123/cells", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /pvz/{id}/cells/{id} DELETE", http.MethodDelete, "/pvz/123/cells/456", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /pvz/{id}/suspend", http.MethodPost, "/pvz/123/suspend", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /pvz/{id}/reopen", http.MethodPost, "/pvz/123/reopen", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /pvz/{id}/decommission", http.MethodPost, "/pvz/123/decommission", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /webhooks POST", http.MethodPost, "/webhooks", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /webhooks GET", http.MethodGet, "/webhooks", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /webhooks/{id} DELETE", http.MethodDelete, "/webhooks/123", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /webhooks/{id}/deliveries", http.MethodGet, "/webhooks/123/deliveries", handler.EmployeeRole, http.StatusForbidden},
+		{"WrongRole-Employee /webhooks/{id}/deliveries/{id}/retry", http.MethodPost, "/webhooks/123/deliveries/456/retry", handler.EmployeeRole, http.StatusForbidden},
+		{"Moderator /webhooks GET", http.MethodGet, "/webhooks", handler.ModeratorRole, http.StatusOK},
+		{"Moderator /pvz/{id} invalid uuid", http.MethodGet, "/pvz/123", handler.ModeratorRole, http.StatusBadRequest},
+		{"Moderator /pvz invalid limit", http.MethodGet, "/pvz?limit=abc", handler.ModeratorRole, http.StatusBadRequest},
 
 		// Good Role
 		//{"Employee /receptions POST", http.MethodPost, "/receptions", handler.EmployeeRole, http.StatusBadRequest},
@@ -78,3 +111,118 @@ func TestAccessControl_AllRoutes(t *testing.T) {
 		})
 	}
 }
+
+// Корневые пути остались алиасами /api/v1: отвечают так же, но с заголовками устаревания
+func TestRouter_DeprecatedRootAliases(t *testing.T) {
+	logger := logger.InitLogger(slog.LevelDebug)
+	counter := middleware.NewDeprecationCounter()
+	r := handler.NewRouter(new(mocks.Service), "test-secret", logger, testSettings{},
+		handler.WithDeprecationCounter(counter))
+
+	tests := []struct {
+		name       string
+		path       string
+		deprecated bool
+	}{
+		{"root alias", "/pvz", true},
+		{"v1", "/api/v1/pvz", false},
+		{"v2", "/api/v2/pvz", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			if !tt.deprecated {
+				assert.Empty(t, w.Header().Get("Deprecation"))
+				assert.Empty(t, w.Header().Get("Sunset"))
+				return
+			}
+			assert.Equal(t, fmt.Sprintf("@%d", handler.RootDeprecatedSince.Unix()), w.Header().Get("Deprecation"))
+			assert.Equal(t, handler.RootSunset.Format(http.TimeFormat), w.Header().Get("Sunset"))
+			assert.Equal(t, `</api/v1/pvz>; rel="successor-version"`, w.Header().Get("Link"))
+		})
+	}
+
+	assert.Equal(t, int64(1), counter.Count("GET /pvz"))
+}
+
+// Без WithDummyLogin (prod) ручки нет ни под одним префиксом
+func TestRouter_DummyLoginDisabled(t *testing.T) {
+	logger := logger.InitLogger(slog.LevelDebug)
+	r := handler.NewRouter(new(mocks.Service), "test-secret", logger, testSettings{})
+
+	for _, path := range []string{"/dummyLogin", handler.APIv1Prefix + "/dummyLogin", handler.APIv2Prefix + "/dummyLogin"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"role":"moderator"}`)))
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+	}
+}
+
+// Токену с claim dummy остается только чтение, обычный токен той же роли пишет как раньше
+func TestRouter_DummyWritesRejected(t *testing.T) {
+	const secret = "test-secret"
+	logger := logger.InitLogger(slog.LevelDebug)
+	r := handler.NewRouter(new(mocks.Service), secret, logger, testSettings{},
+		handler.WithDummyLogin(), handler.WithDummyWritesRejected())
+
+	token := func(dummy bool) string {
+		claims := map[string]interface{}{"userId": "test-user", "role": handler.ModeratorRole}
+		if dummy {
+			claims[middleware.DummyKey] = true
+		}
+		token, err := jwtutils.Generate(claims, time.Hour, secret)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		dummy          bool
+		expectedStatus int
+	}{
+		{"dummy read", http.MethodGet, true, http.StatusOK},
+		{"dummy write", http.MethodPost, true, http.StatusForbidden},
+		{"regular write", http.MethodPost, false, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/pvz", nil)
+			req.Header.Set("Authorization", "Bearer "+token(tt.dummy))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func problemBody(status int, code, detail string) string {
+	return fmt.Sprintf(`{"type":"about:blank","title":"%s","status":%d,"detail":"%s","code":"%s","message":"%s"}`,
+		http.StatusText(status), status, detail, code, detail)
+}
+
+// testSettings - фиксированные настройки для тестов: лимиты пагинации по умолчанию, без rate limit
+type testSettings struct {
+	rps   float64
+	burst int
+}
+
+func (s testSettings) GetDefaultLimit() int { return 10 }
+
+func (s testSettings) GetMaxLimit() int { return 30 }
+
+func (s testSettings) GetRateLimit() (float64, int) { return s.rps, s.burst }
+
+// unimplemented отвечает 501 на операции, которых нет у ручек под тестом.
+// Лежит глубже ручек, поэтому их методы перекрывают заглушки
+type unimplemented struct{ dto.Unimplemented }
+
+// operations разбирает параметры пути и запроса так же, как handler.NewRouter
+func operations(handlers dto.ServerInterface) *dto.ServerInterfaceWrapper {
+	return &dto.ServerInterfaceWrapper{Handler: handlers, ErrorHandlerFunc: handler.ParamError}
+}
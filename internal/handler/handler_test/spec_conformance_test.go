@@ -0,0 +1,227 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/api"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/pkg/buildinfo"
+)
+
+// Обходит ручки со включенной проверкой по api/swagger.yaml:
+// ответ, расходящийся со спецификацией, приходит как 500 response_spec_violation
+func TestRouter_SpecConformance(t *testing.T) {
+	for _, prefix := range []string{handler.APIv1Prefix, handler.APIv2Prefix, ""} {
+		t.Run("prefix="+prefix, func(t *testing.T) {
+			checkSpecConformance(t, prefix)
+		})
+	}
+}
+
+func checkSpecConformance(t *testing.T, prefix string) {
+	const secret = "test-secret"
+
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	serv := service.NewService(repo, secret, testWebhookConfig{})
+	server := httptest.NewServer(handler.NewRouter(serv, secret, logger, testSettings{},
+		handler.WithSpecValidation(specValidator(t)), handler.WithDummyLogin()))
+	defer server.Close()
+
+	partner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer partner.Close()
+
+	call := func(method, path, token, body string, expectedStatus int) []byte {
+		t.Helper()
+
+		req, err := http.NewRequest(method, server.URL+prefix+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, expectedStatus, resp.StatusCode, "%s %s: %s", method, path, data)
+		return data
+	}
+
+	// Повторный GET с ETag из первого ответа получает 304 без тела
+	revalidate := func(path, token string) {
+		t.Helper()
+
+		get := func(ifNoneMatch string) *http.Response {
+			req, err := http.NewRequest(http.MethodGet, server.URL+prefix+path, nil)
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("If-None-Match", ifNoneMatch)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			return resp
+		}
+
+		resp := get("")
+		require.Equal(t, http.StatusOK, resp.StatusCode, path)
+		etag := resp.Header.Get("ETag")
+		require.NotEmpty(t, etag, path)
+
+		resp = get(etag)
+		require.Equal(t, http.StatusNotModified, resp.StatusCode, path)
+		assert.Equal(t, etag, resp.Header.Get("ETag"))
+	}
+
+	decode := func(data []byte, v any) {
+		t.Helper()
+		require.NoError(t, json.Unmarshal(data, v))
+	}
+
+	call(http.MethodPost, "/register", "", `{"email":"spec@example.com","password":"password1","role":"employee"}`, http.StatusCreated)
+	call(http.MethodPost, "/register", "", `{"email":"spec@example.com","password":"password1","role":"employee"}`, http.StatusConflict)
+	employee := strings.TrimSpace(string(call(http.MethodPost, "/login", "",
+		`{"email":"spec@example.com","password":"password1"}`, http.StatusOK)))
+	moderator := strings.TrimSpace(string(call(http.MethodPost, "/dummyLogin", "", `{"role":"moderator"}`, http.StatusOK)))
+
+	var pvz dto.PVZ
+	decode(call(http.MethodPost, "/pvz", moderator, `{"city":"Казань"}`, http.StatusCreated), &pvz)
+	require.NotNil(t, pvz.Id)
+	pvzID := pvz.Id.String()
+	pvzPath := "/pvz/" + pvzID
+
+	call(http.MethodGet, "/pvz/"+uuid.NewString(), employee, "", http.StatusNotFound)
+	call(http.MethodPatch, pvzPath, moderator, `{"address":"ул. Баумана, 1","coordinates":{"lat":55.79,"lng":49.12},`+
+		`"timezone":"Europe/Moscow","workingHours":{"weekly":{"mon":{"open":"00:00","close":"23:59"}}}}`, http.StatusOK)
+	call(http.MethodGet, pvzPath, employee, "", http.StatusOK)
+	revalidate(pvzPath, employee)
+	call(http.MethodGet, "/pvz/nearby?lat=55.79&lon=49.12&radius=1000", "", "", http.StatusOK)
+
+	call(http.MethodPost, pvzPath+"/suspend", moderator, "", http.StatusOK)
+	call(http.MethodPost, "/receptions", employee, fmt.Sprintf(`{"pvzId":"%s"}`, pvzID), http.StatusConflict)
+	call(http.MethodPost, pvzPath+"/reopen", moderator, "", http.StatusOK)
+
+	var cell dto.StorageCell
+	decode(call(http.MethodPost, pvzPath+"/cells", moderator, `{"code":"A-01","capacity":10,"sizeClass":"small"}`,
+		http.StatusCreated), &cell)
+	call(http.MethodPost, pvzPath+"/cells", moderator, `{"code":"B-01","capacity":1,"sizeClass":"large"}`, http.StatusCreated)
+
+	call(http.MethodPost, "/receptions", employee, fmt.Sprintf(`{"pvzId":"%s"}`, pvzID), http.StatusCreated)
+
+	var product dto.Product
+	decode(call(http.MethodPost, "/products", employee, fmt.Sprintf(`{"type":"электроника","pvzId":"%s",`+
+		`"barcode":"4006381333931","sku":"SKU-1","weightGrams":300,"dimensions":{"lengthMm":100,"widthMm":50,"heightMm":20}}`,
+		pvzID), http.StatusCreated), &product)
+	require.NotNil(t, product.Id)
+	call(http.MethodPost, "/products", employee, fmt.Sprintf(`{"type":"обувь","pvzId":"%s","barcode":"4006381333931"}`, pvzID),
+		http.StatusConflict)
+	call(http.MethodPost, "/products", employee, fmt.Sprintf(`{"type":"одежда","pvzId":"%s"}`, pvzID), http.StatusCreated)
+	call(http.MethodPost, pvzPath+"/delete_last_product", employee, "", http.StatusOK)
+	call(http.MethodGet, "/products/by-barcode/4006381333931", employee, "", http.StatusOK)
+	call(http.MethodGet, pvzPath+"/cells", employee, "", http.StatusOK)
+	revalidate(pvzPath+"/cells", employee)
+	call(http.MethodDelete, pvzPath+"/cells/"+cell.Id.String(), moderator, "", http.StatusConflict)
+
+	call(http.MethodPost, pvzPath+"/close_last_reception", employee, "", http.StatusOK)
+	call(http.MethodPost, pvzPath+"/close_last_reception", employee, "", http.StatusConflict)
+	call(http.MethodGet, pvzPath+"/stock", employee, "", http.StatusOK)
+	revalidate(pvzPath+"/stock", employee)
+
+	call(http.MethodPost, "/issuances", employee, fmt.Sprintf(`{"productId":"%s","pvzId":"%s","confirmationCode":"1234"}`,
+		*product.Id, pvzID), http.StatusCreated)
+	call(http.MethodPost, "/returns", employee, fmt.Sprintf(`{"productId":"%s","pvzId":"%s","reason":"брак"}`,
+		*product.Id, pvzID), http.StatusCreated)
+
+	call(http.MethodPost, "/sync", employee, fmt.Sprintf(`{"pvzId":"%s","operations":[`+
+		`{"id":"%s","type":"open_reception","dateTime":"%s"},`+
+		`{"id":"%s","type":"add_product","dateTime":"%s","productType":"обувь"},`+
+		`{"id":"%s","type":"open_reception","dateTime":"%s"}]}`,
+		pvzID, uuid.NewString(), time.Now().UTC().Format(time.RFC3339), uuid.NewString(), time.Now().UTC().Format(time.RFC3339),
+		uuid.NewString(), time.Now().UTC().Format(time.RFC3339)), http.StatusOK)
+
+	call(http.MethodGet, "/pvz?page=1&limit=5&includeInactive=true", moderator, "", http.StatusOK)
+	revalidate("/pvz?page=1&limit=5&includeInactive=true", moderator)
+	call(http.MethodGet, "/pvz?limit=abc", moderator, "", http.StatusBadRequest)
+
+	var webhook dto.Webhook
+	decode(call(http.MethodPost, "/webhooks", moderator, fmt.Sprintf(`{"url":"%s","eventTypes":["product.received"]}`, partner.URL),
+		http.StatusCreated), &webhook)
+	call(http.MethodGet, "/webhooks", moderator, "", http.StatusOK)
+	call(http.MethodPost, "/webhooks/"+webhook.Id.String()+"/test", moderator, "", http.StatusOK)
+	call(http.MethodGet, "/webhooks/"+webhook.Id.String()+"/deliveries?status=delivered", moderator, "", http.StatusOK)
+	call(http.MethodPost, "/webhooks/"+webhook.Id.String()+"/deliveries/"+uuid.NewString()+"/retry", moderator, "", http.StatusNotFound)
+	call(http.MethodDelete, "/webhooks/"+webhook.Id.String(), moderator, "", http.StatusOK)
+
+	call(http.MethodPost, pvzPath+"/decommission", moderator, "", http.StatusOK)
+	call(http.MethodPost, pvzPath+"/reopen", moderator, "", http.StatusConflict)
+}
+
+// Каждый маршрут роутера описан в api/swagger.yaml, и каждая операция спецификации зарегистрирована.
+// Ручки API доступны под /api/v1, /api/v2 и по устаревшим корневым путям, служебные - только в корне
+func TestRouter_RoutesMatchSpec(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData(api.Spec)
+	require.NoError(t, err)
+
+	docs, err := handler.NewDocsHandler(api.Spec)
+	require.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r := handler.NewRouter(new(mocks.Service), "secret", logger, testSettings{}, handler.WithDummyLogin())
+	handler.MountHealth(r, handler.NewHealthHandler(nil, nil, buildinfo.Info{}))
+	handler.MountDocs(r, docs)
+
+	var registered []string
+	err = chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// Статика Swagger UI
+		if strings.HasSuffix(route, "/*") {
+			return nil
+		}
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		registered = append(registered, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method, op := range item.Operations() {
+			if slices.Contains(op.Tags, "health") || slices.Contains(op.Tags, "docs") {
+				documented = append(documented, method+" "+path)
+				continue
+			}
+			for _, prefix := range []string{"", handler.APIv1Prefix, handler.APIv2Prefix} {
+				documented = append(documented, method+" "+prefix+path)
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, documented, registered)
+}
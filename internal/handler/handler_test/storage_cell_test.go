@@ -0,0 +1,247 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+)
+
+func TestStorageCellHandlers_CreateStorageCell(t *testing.T) {
+	mockService := new(mocks.StorageCellService)
+	h := handler.NewStorageCellHandler(mockService)
+
+	router := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.StorageCellHandlers
+	}{StorageCellHandlers: h})
+	router.Post("/pvz/{pvzId}/cells", ops.CreateStorageCell)
+
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	cellID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	cell := model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 10, SizeClass: model.SizeSmall}
+
+	tests := []struct {
+		name           string
+		pvzID          string
+		body           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "успешное создание",
+			pvzID: pvzID.String(),
+			body:  `{"code":"A-01","capacity":10,"sizeClass":"small"}`,
+			mockSetup: func() {
+				created := cell
+				created.ID = cellID
+				mockService.On("CreateStorageCell", mock.Anything, cell).Return(&created, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: fmt.Sprintf(`{"id":"%s","pvzId":"%s","code":"A-01","capacity":10,"sizeClass":"small","occupied":0,"free":10}`,
+				cellID, pvzID),
+		},
+		{
+			name:           "невалидный UUID",
+			pvzID:          "123",
+			body:           `{"code":"A-01","capacity":10,"sizeClass":"small"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
+		},
+		{
+			name:           "неизвестный размерный класс",
+			pvzID:          pvzID.String(),
+			body:           `{"code":"A-01","capacity":10,"sizeClass":"huge"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "нулевая вместимость",
+			pvzID:          pvzID.String(),
+			body:           `{"code":"A-01","capacity":0,"sizeClass":"small"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:  "код уже занят",
+			pvzID: pvzID.String(),
+			body:  `{"code":"A-01","capacity":10,"sizeClass":"small"}`,
+			mockSetup: func() {
+				mockService.On("CreateStorageCell", mock.Anything, cell).
+					Return(nil, service.NewConflictError(service.CodeStorageCellExists, service.StorageCellExists)).Once()
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeStorageCellExists, service.StorageCellExists),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/pvz/"+tt.pvzID+"/cells", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStorageCellHandlers_DeleteStorageCell(t *testing.T) {
+	mockService := new(mocks.StorageCellService)
+	h := handler.NewStorageCellHandler(mockService)
+
+	router := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.StorageCellHandlers
+	}{StorageCellHandlers: h})
+	router.Delete("/pvz/{pvzId}/cells/{cellId}", ops.DeleteStorageCell)
+
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	cellID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	busyCellID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	tests := []struct {
+		name           string
+		path           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "успешное удаление",
+			path: fmt.Sprintf("/pvz/%s/cells/%s", pvzID, cellID),
+			mockSetup: func() {
+				mockService.On("DeleteStorageCell", mock.Anything, model.Pvz{ID: pvzID}, cellID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "невалидный UUID ячейки",
+			path:           fmt.Sprintf("/pvz/%s/cells/123", pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing),
+		},
+		{
+			name: "в ячейке есть товары",
+			path: fmt.Sprintf("/pvz/%s/cells/%s", pvzID, busyCellID),
+			mockSetup: func() {
+				mockService.On("DeleteStorageCell", mock.Anything, model.Pvz{ID: pvzID}, busyCellID).
+					Return(service.NewConflictError(service.CodeStorageCellNotEmpty, service.StorageCellNotEmpty))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   problemBody(http.StatusConflict, service.CodeStorageCellNotEmpty, service.StorageCellNotEmpty),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodDelete, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStorageCellHandlers_GetStorageCells(t *testing.T) {
+	mockService := new(mocks.StorageCellService)
+	h := handler.NewStorageCellHandler(mockService)
+
+	router := chi.NewRouter()
+	ops := operations(struct {
+		unimplemented
+		*handler.StorageCellHandlers
+	}{StorageCellHandlers: h})
+	router.Get("/pvz/{pvzId}/cells", ops.GetStorageCells)
+
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	missingPvzID := uuid.MustParse("66666666-6666-6666-6666-666666666666")
+	cell := model.StorageCell{
+		ID:        uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		PvzID:     pvzID,
+		Code:      "B-02",
+		Capacity:  4,
+		SizeClass: model.SizeMedium,
+		Occupied:  3,
+	}
+
+	tests := []struct {
+		name           string
+		pvzID          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "ячейки с заполненностью",
+			pvzID: pvzID.String(),
+			mockSetup: func() {
+				mockService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: pvzID}).Return(model.ChangeStamp{ChangedAt: time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC), Count: 1}, nil)
+				mockService.On("GetStorageCells", mock.Anything, model.Pvz{ID: pvzID}).Return([]model.StorageCell{cell}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"pvzId":"%[1]s","cells":[
+				{"id":"%[2]s","pvzId":"%[1]s","code":"B-02","capacity":4,"sizeClass":"medium","occupied":3,"free":1}]}`,
+				pvzID, cell.ID),
+		},
+		{
+			name:  "ПВЗ не найден",
+			pvzID: missingPvzID.String(),
+			mockSetup: func() {
+				mockService.On("GetPvzChangeStamp", mock.Anything, model.Pvz{ID: missingPvzID}).
+					Return(model.ChangeStamp{}, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+				mockService.On("GetStorageCells", mock.Anything, model.Pvz{ID: missingPvzID}).
+					Return(nil, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, service.CodePvzNotFound, service.PvzNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/"+tt.pvzID+"/cells", nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
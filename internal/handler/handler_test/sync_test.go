@@ -0,0 +1,171 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+)
+
+func TestSyncHandlers_Sync(t *testing.T) {
+	mockSyncService := new(mocks.SyncService)
+	syncHandler := handler.NewSyncHandler(mockSyncService)
+	r := chi.NewRouter()
+	r.Post("/sync", syncHandler.Sync)
+
+	clientTime := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	pvzID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	pvzID2 := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	openID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	productID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	closeID := uuid.MustParse("55555555-5555-5555-5555-555555555555")
+
+	body := func(pvz uuid.UUID, ops ...string) string {
+		return fmt.Sprintf(`{"pvzId":"%s","operations":[%s]}`, pvz, strings.Join(ops, ","))
+	}
+	openOp := fmt.Sprintf(`{"id":"%s","type":"open_reception","dateTime":"%s"}`, openID, clientTime.Format(time.RFC3339))
+	addOp := fmt.Sprintf(`{"id":"%s","type":"add_product","dateTime":"%s","productType":"%s"}`,
+		productID, clientTime.Add(time.Minute).Format(time.RFC3339), handler.ShoesType)
+	closeOp := fmt.Sprintf(`{"id":"%s","type":"close_reception","dateTime":"%s"}`,
+		closeID, clientTime.Add(2*time.Minute).Format(time.RFC3339))
+
+	tests := []struct {
+		name           string
+		reqBody        string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "успешная синхронизация",
+			reqBody: body(pvzID, openOp, addOp, closeOp),
+			mockSetup: func() {
+				mockSyncService.On("Sync", mock.Anything, model.Pvz{ID: pvzID}, []model.SyncOperation{
+					{ID: openID, PvzID: pvzID, Type: model.SyncOpenReception, DateTime: clientTime},
+					{ID: productID, PvzID: pvzID, Type: model.SyncAddProduct, DateTime: clientTime.Add(time.Minute), TypeProduct: handler.ShoesType},
+					{ID: closeID, PvzID: pvzID, Type: model.SyncCloseReception, DateTime: clientTime.Add(2 * time.Minute)},
+				}).Return(&model.SyncResult{
+					PvzID: pvzID,
+					Reception: &model.Reception{
+						ID:       openID,
+						DateTime: clientTime,
+						PvzID:    pvzID,
+						Products: []model.Product{{
+							ID:          productID,
+							DateTime:    clientTime.Add(time.Minute),
+							TypeProduct: handler.ShoesType,
+							ReceptionID: openID,
+						}},
+					},
+					Applied: []uuid.UUID{openID, productID},
+					Rejected: []model.SyncOperation{{
+						ID: closeID, Type: model.SyncCloseReception,
+						Code: service.CodeSyncOutOfOrder, Message: service.SyncOutOfOrder,
+					}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{
+				"pvzId":"%[1]s",
+				"state":{
+					"reception":{"id":"%[2]s","dateTime":"2024-03-01T09:00:00Z","pvzId":"%[1]s","status":"in_progress"},
+					"products":[{"id":"%[3]s","dateTime":"2024-03-01T09:01:00Z","type":"обувь","receptionId":"%[2]s"}]
+				},
+				"applied":["%[2]s","%[3]s"],
+				"rejected":[{"id":"%[4]s","type":"close_reception","code":"%[5]s","message":"%[6]s"}]
+			}`, pvzID, openID, productID, closeID, service.CodeSyncOutOfOrder, service.SyncOutOfOrder),
+		},
+		{
+			name:           "ошибка при обработке тела запроса - неверный формат",
+			reqBody:        `{"pvzId": "1", operations}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
+		},
+		{
+			name:           "пустой журнал",
+			reqBody:        body(pvzID),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "неизвестный тип операции",
+			reqBody:        body(pvzID, fmt.Sprintf(`{"id":"%s","type":"reopen","dateTime":"%s"}`, openID, clientTime.Format(time.RFC3339))),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "добавление товара без типа",
+			reqBody:        body(pvzID, fmt.Sprintf(`{"id":"%s","type":"add_product","dateTime":"%s"}`, productID, clientTime.Format(time.RFC3339))),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "неверный uuid операции",
+			reqBody:        body(pvzID, fmt.Sprintf(`{"id":"55","type":"open_reception","dateTime":"%s"}`, clientTime.Format(time.RFC3339))),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
+		},
+		{
+			name: "неподдерживаемый тип товара",
+			reqBody: body(pvzID, fmt.Sprintf(`{"id":"%s","type":"add_product","dateTime":"%s","productType":"weird"}`,
+				productID, clientTime.Format(time.RFC3339))),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidProductType, handler.ErrProductType),
+		},
+		{
+			name: "неверный штрихкод товара",
+			reqBody: body(pvzID, fmt.Sprintf(`{"id":"%s","type":"add_product","dateTime":"%s","productType":"%s","barcode":"4006381333932"}`,
+				productID, clientTime.Format(time.RFC3339), handler.ShoesType)),
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBarcode, handler.ErrBarcode),
+		},
+		{
+			name:    "ПВЗ не найден",
+			reqBody: body(pvzID2, openOp),
+			mockSetup: func() {
+				mockSyncService.On("Sync", mock.Anything, model.Pvz{ID: pvzID2}, mock.Anything).
+					Return(nil, service.NewNotFoundError(service.CodePvzNotFound, service.PvzNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   problemBody(http.StatusNotFound, service.CodePvzNotFound, service.PvzNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req, err := http.NewRequest(http.MethodPost, "/sync", strings.NewReader(tt.reqBody))
+			if err != nil {
+				t.Fatalf("Ошибка при создании запроса: %v", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+
+			mockSyncService.AssertExpectations(t)
+		})
+	}
+}
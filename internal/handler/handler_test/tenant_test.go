@@ -0,0 +1,130 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/middleware"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tenant"
+)
+
+// Два оператора на одном сервисе: данные каждого видны только его пользователям
+func TestRouter_TenantIsolation(t *testing.T) {
+	const secret = "test-secret"
+
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	serv := service.NewService(repo, secret, testWebhookConfig{})
+	server := httptest.NewServer(handler.NewRouter(serv, secret, logger, testSettings{},
+		handler.WithSpecValidation(specValidator(t)), handler.WithDummyLogin()))
+	defer server.Close()
+
+	call := func(method, path, token, body string, header http.Header, want int) []byte {
+		t.Helper()
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, want, resp.StatusCode, "%s %s: %s", method, path, data)
+		return data
+	}
+
+	// Токен администратора выдает команда pvz-service admin token
+	admin, err := service.GenerateToken(secret, "cli:admin", service.AdminRole, tenant.DefaultID, time.Hour)
+	require.NoError(t, err)
+	moderator := strings.TrimSpace(string(call(http.MethodPost, "/dummyLogin", "", `{"role":"moderator"}`, nil, http.StatusOK)))
+
+	call(http.MethodPost, "/admin/tenants", moderator, `{"name":"acme"}`, nil, http.StatusForbidden)
+	var acme dto.Tenant
+	require.NoError(t, json.Unmarshal(call(http.MethodPost, "/admin/tenants", admin, `{"name":"acme"}`, nil, http.StatusCreated), &acme))
+	call(http.MethodPost, "/admin/tenants", admin, `{"name":"acme"}`, nil, http.StatusConflict)
+
+	var tenants []dto.Tenant
+	require.NoError(t, json.Unmarshal(call(http.MethodGet, "/admin/tenants", admin, "", nil, http.StatusOK), &tenants))
+	require.Len(t, tenants, 2)
+	assert.Equal(t, tenant.DefaultID, tenants[0].Id)
+	assert.Equal(t, acme.Id, tenants[1].Id)
+
+	assert.True(t, tenants[0].OpenRegistration)
+	assert.False(t, acme.OpenRegistration)
+
+	// Оператор задает только X-Tenant-ID: регистрация у нового оператора закрыта,
+	// неизвестный оператор отвечает так же
+	acmeID := acme.Id.String()
+	acmeHeader := http.Header{middleware.TenantHeader: {acmeID}}
+	register := `{"email":"mod@acme.example","password":"password1","role":"moderator"}`
+	call(http.MethodPost, "/register", "", register, acmeHeader, http.StatusForbidden)
+	call(http.MethodPost, "/register", "", register, http.Header{middleware.TenantHeader: {uuid.NewString()}}, http.StatusForbidden)
+
+	call(http.MethodPatch, "/admin/tenants/"+acmeID, moderator, `{"openRegistration":true}`, nil, http.StatusForbidden)
+	call(http.MethodPatch, "/admin/tenants/"+uuid.NewString(), admin, `{"openRegistration":true}`, nil, http.StatusNotFound)
+	require.NoError(t, json.Unmarshal(call(http.MethodPatch, "/admin/tenants/"+acmeID, admin, `{"openRegistration":true}`, nil,
+		http.StatusOK), &acme))
+	assert.True(t, acme.OpenRegistration)
+
+	var registered dto.User
+	require.NoError(t, json.Unmarshal(call(http.MethodPost, "/register", "", register, acmeHeader, http.StatusCreated), &registered))
+	require.NotNil(t, registered.TenantId)
+	assert.Equal(t, acme.Id, *registered.TenantId)
+	acmeModerator := strings.TrimSpace(string(call(http.MethodPost, "/login", "",
+		`{"email":"mod@acme.example","password":"password1"}`, nil, http.StatusOK)))
+
+	// tenantId в теле не переносит пользователя к другому оператору: без заголовка он попадает
+	// к оператору по умолчанию и данных acme не видит
+	intruderBody := fmt.Sprintf(`{"email":"intruder@example.com","password":"password1","role":"moderator","tenantId":"%s"}`, acmeID)
+	require.NoError(t, json.Unmarshal(call(http.MethodPost, "/register", "", intruderBody, nil, http.StatusCreated), &registered))
+	require.NotNil(t, registered.TenantId)
+	assert.Equal(t, tenant.DefaultID, *registered.TenantId)
+	intruder := strings.TrimSpace(string(call(http.MethodPost, "/login", "",
+		`{"email":"intruder@example.com","password":"password1"}`, nil, http.StatusOK)))
+
+	var pvz, acmePvz dto.PVZ
+	require.NoError(t, json.Unmarshal(call(http.MethodPost, "/pvz", moderator, `{"city":"Казань"}`, nil, http.StatusCreated), &pvz))
+	require.NoError(t, json.Unmarshal(call(http.MethodPost, "/pvz", acmeModerator, `{"city":"Казань"}`, nil, http.StatusCreated), &acmePvz))
+	require.NotNil(t, pvz.Id)
+	require.NotNil(t, acmePvz.Id)
+	call(http.MethodPatch, "/pvz/"+pvz.Id.String(), moderator, `{"coordinates":{"lat":55.79,"lng":49.12}}`, nil, http.StatusOK)
+
+	call(http.MethodGet, "/pvz/"+pvz.Id.String(), moderator, "", nil, http.StatusOK)
+	call(http.MethodGet, "/pvz/"+pvz.Id.String(), acmeModerator, "", nil, http.StatusNotFound)
+	call(http.MethodPatch, "/pvz/"+pvz.Id.String(), acmeModerator, `{"address":"ул. Баумана, 1"}`, nil, http.StatusNotFound)
+	call(http.MethodGet, "/pvz/"+acmePvz.Id.String(), moderator, "", nil, http.StatusNotFound)
+	call(http.MethodGet, "/pvz/"+acmePvz.Id.String(), intruder, "", nil, http.StatusNotFound)
+
+	// Поиск без авторизации ищет среди ПВЗ оператора из X-Tenant-ID
+	nearby := func(header http.Header) []dto.NearbyPvz {
+		var found []dto.NearbyPvz
+		require.NoError(t, json.Unmarshal(call(http.MethodGet, "/pvz/nearby?lat=55.79&lon=49.12&radius=1000", "", "", header,
+			http.StatusOK), &found))
+		return found
+	}
+	found := nearby(nil)
+	require.Len(t, found, 1)
+	assert.Equal(t, pvz.Id, found[0].Id)
+	assert.Empty(t, nearby(http.Header{middleware.TenantHeader: {acmeID}}))
+}
@@ -0,0 +1,287 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"pvz-service/internal/handler"
+	"pvz-service/internal/handler/mocks"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+)
+
+func newWebhookRouter(mockService *mocks.WebhookService) *chi.Mux {
+	h := handler.NewWebhookHandler(mockService, testSettings{})
+	ops := operations(struct {
+		unimplemented
+		*handler.WebhookHandlers
+	}{WebhookHandlers: h})
+
+	router := chi.NewRouter()
+	router.Post("/webhooks", h.CreateWebhook)
+	router.Get("/webhooks", h.GetWebhooks)
+	router.Delete("/webhooks/{webhookId}", ops.DeleteWebhook)
+	router.Get("/webhooks/{webhookId}/deliveries", ops.GetWebhookDeliveries)
+	router.Post("/webhooks/{webhookId}/test", ops.SendWebhookTestEvent)
+	router.Post("/webhooks/{webhookId}/deliveries/{deliveryId}/retry", ops.RetryWebhookDelivery)
+	return router
+}
+
+func TestWebhookHandlers_CreateWebhook(t *testing.T) {
+	mockService := mocks.NewWebhookService(t)
+	router := newWebhookRouter(mockService)
+
+	webhookID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	pvzID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+	sub := model.WebhookSubscription{
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.WebhookEventReceptionClosed},
+		PvzIDs:     []uuid.UUID{pvzID},
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "секрет возвращается только при создании",
+			body: fmt.Sprintf(`{"url":"https://partner.example/hooks","eventTypes":["reception.closed"],"pvzIds":["%s"]}`, pvzID),
+			mockSetup: func() {
+				created := sub
+				created.ID, created.Secret, created.CreatedAt = webhookID, "generated-secret", createdAt
+				mockService.On("CreateWebhook", mock.Anything, sub).Return(&created, nil).Once()
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: fmt.Sprintf(`{"id":"%s","url":"https://partner.example/hooks","eventTypes":["reception.closed"],`+
+				`"pvzIds":["%s"],"createdAt":"2025-04-01T10:00:00Z","secret":"generated-secret"}`, webhookID, pvzID),
+		},
+		{
+			name:           "без событий",
+			body:           `{"url":"https://partner.example/hooks","eventTypes":[]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "короткий секрет",
+			body:           `{"url":"https://partner.example/hooks","eventTypes":["reception.closed"],"secret":"short"}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidFields, handler.ErrRequestFields),
+		},
+		{
+			name:           "невалидный UUID ПВЗ",
+			body:           `{"url":"https://partner.example/hooks","eventTypes":["reception.closed"],"pvzIds":["123"]}`,
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidBody, handler.ErrBodyRequest),
+		},
+		{
+			name: "неизвестное событие",
+			body: `{"url":"https://partner.example/hooks","eventTypes":["pvz.deleted"]}`,
+			mockSetup: func() {
+				mockService.On("CreateWebhook", mock.Anything, mock.Anything).
+					Return(nil, service.NewValidationError(service.CodeInvalidWebhookEvent, service.InvalidWebhookEventType)).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, service.CodeInvalidWebhookEvent, service.InvalidWebhookEventType),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestWebhookHandlers_GetWebhooks(t *testing.T) {
+	mockService := mocks.NewWebhookService(t)
+	router := newWebhookRouter(mockService)
+
+	webhookID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	mockService.On("GetWebhooks", mock.Anything).Return([]model.WebhookSubscription{{
+		ID:         webhookID,
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.WebhookEventProductReceived},
+		PvzIDs:     []uuid.UUID{},
+		Secret:     "must-not-leak",
+		CreatedAt:  time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+	}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`[{"id":"%s","url":"https://partner.example/hooks","eventTypes":["product.received"],`+
+		`"pvzIds":[],"createdAt":"2025-04-01T10:00:00Z"}]`, webhookID), w.Body.String())
+}
+
+func TestWebhookHandlers_DeleteWebhook(t *testing.T) {
+	mockService := mocks.NewWebhookService(t)
+	router := newWebhookRouter(mockService)
+
+	webhookID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	mockService.On("DeleteWebhook", mock.Anything, webhookID).
+		Return(service.NewNotFoundError(service.CodeWebhookNotFound, service.WebhookNotFound))
+
+	req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+webhookID.String(), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, problemBody(http.StatusNotFound, service.CodeWebhookNotFound, service.WebhookNotFound), w.Body.String())
+}
+
+func TestWebhookHandlers_GetDeliveries(t *testing.T) {
+	mockService := mocks.NewWebhookService(t)
+	router := newWebhookRouter(mockService)
+
+	webhookID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	deliveryID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	eventID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	createdAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func()
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "dead с пагинацией по умолчанию",
+			query: "?status=dead",
+			mockSetup: func() {
+				mockService.On("GetWebhookDeliveries", mock.Anything, model.WebhookDeliveryQuery{
+					SubscriptionID: webhookID, Status: model.WebhookDeliveryDead, Page: 1, Limit: 10,
+				}).Return([]model.WebhookDelivery{{
+					ID:             deliveryID,
+					SubscriptionID: webhookID,
+					EventID:        eventID,
+					EventType:      model.WebhookEventReceptionClosed,
+					Payload:        []byte(`{"type":"reception.closed"}`),
+					Status:         model.WebhookDeliveryDead,
+					Attempts:       8,
+					NextAttemptAt:  createdAt.Add(time.Hour),
+					LastError:      "unexpected response status 500",
+					ResponseStatus: 500,
+					CreatedAt:      createdAt,
+				}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`[{"id":"%s","webhookId":"%s","eventId":"%s","eventType":"reception.closed",`+
+				`"status":"dead","attempts":8,"lastError":"unexpected response status 500","responseStatus":500,`+
+				`"createdAt":"2025-04-01T10:00:00Z","payload":{"type":"reception.closed"}}]`, deliveryID, webhookID, eventID),
+		},
+		{
+			name:           "неизвестный статус",
+			query:          "?status=lost",
+			mockSetup:      func() {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   problemBody(http.StatusBadRequest, handler.CodeInvalidQuery, handler.ErrQueryParameters),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+
+			req := httptest.NewRequest(http.MethodGet, "/webhooks/"+webhookID.String()+"/deliveries"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestWebhookHandlers_SendTestEvent(t *testing.T) {
+	mockService := mocks.NewWebhookService(t)
+	router := newWebhookRouter(mockService)
+
+	webhookID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	deliveryID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+	eventID := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+	sentAt := time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC)
+
+	mockService.On("SendTestEvent", mock.Anything, webhookID).Return(&model.WebhookDelivery{
+		ID:             deliveryID,
+		SubscriptionID: webhookID,
+		EventID:        eventID,
+		EventType:      model.WebhookEventTest,
+		Payload:        []byte(`{"type":"webhook.test"}`),
+		Status:         model.WebhookDeliveryDelivered,
+		Attempts:       1,
+		ResponseStatus: 200,
+		CreatedAt:      sentAt,
+		DeliveredAt:    sentAt,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/"+webhookID.String()+"/test", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, fmt.Sprintf(`{"id":"%s","webhookId":"%s","eventId":"%s","eventType":"webhook.test","status":"delivered",`+
+		`"attempts":1,"responseStatus":200,"createdAt":"2025-04-01T10:00:00Z","deliveredAt":"2025-04-01T10:00:00Z",`+
+		`"payload":{"type":"webhook.test"}}`, deliveryID, webhookID, eventID), w.Body.String())
+}
+
+func TestWebhookHandlers_RetryDelivery(t *testing.T) {
+	mockService := mocks.NewWebhookService(t)
+	router := newWebhookRouter(mockService)
+
+	webhookID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	deliveryID := uuid.MustParse("33333333-3333-3333-3333-333333333333")
+
+	t.Run("доставка еще в очереди", func(t *testing.T) {
+		mockService.On("RetryDelivery", mock.Anything, webhookID, deliveryID).
+			Return(nil, service.NewConflictError(service.CodeWebhookDeliveryNotDead, service.WebhookDeliveryNotDead)).Once()
+
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/webhooks/%s/deliveries/%s/retry", webhookID, deliveryID), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, problemBody(http.StatusConflict, service.CodeWebhookDeliveryNotDead, service.WebhookDeliveryNotDead), w.Body.String())
+	})
+
+	t.Run("невалидный UUID доставки", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/webhooks/%s/deliveries/123/retry", webhookID), nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, problemBody(http.StatusBadRequest, handler.CodeInvalidID, handler.ErrUUIDParsing), w.Body.String())
+	})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/pkg/buildinfo"
+)
+
+const (
+	StatusOK = "ok"
+
+	ErrShuttingDown = "service is shutting down"
+	ErrNotReady     = "service is not ready"
+
+	CodeShuttingDown = "shutting_down"
+	CodeNotReady     = "not_ready"
+)
+
+type ReadinessChecker interface {
+	CheckReadiness(ctx context.Context) error
+}
+
+type HealthHandlers struct {
+	checker      ReadinessChecker
+	shuttingDown *atomic.Bool
+	info         buildinfo.Info
+}
+
+func NewHealthHandler(checker ReadinessChecker, shuttingDown *atomic.Bool, info buildinfo.Info) *HealthHandlers {
+	return &HealthHandlers{
+		checker:      checker,
+		shuttingDown: shuttingDown,
+		info:         info,
+	}
+}
+
+// Liveness отвечает 200, пока процесс жив и обслуживает запросы
+func (h *HealthHandlers) Liveness(w http.ResponseWriter, r *http.Request) {
+	response.SuccessJSON(w, dto.Health{Status: StatusOK}, http.StatusOK)
+}
+
+// Readiness отвечает 503 во время остановки сервиса и когда база недоступна,
+// чтобы балансировщик перестал направлять сюда трафик
+func (h *HealthHandlers) Readiness(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+
+	if h.shuttingDown != nil && h.shuttingDown.Load() {
+		response.WriteError(w, http.StatusServiceUnavailable, CodeShuttingDown, ErrShuttingDown)
+		return
+	}
+
+	if err := h.checker.CheckReadiness(r.Context()); err != nil {
+		response.WriteError(w, http.StatusServiceUnavailable, CodeNotReady, ErrNotReady)
+		logger.WarnContext(r.Context(), ErrNotReady, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, dto.Health{Status: StatusOK}, http.StatusOK)
+}
+
+func (h *HealthHandlers) Version(w http.ResponseWriter, r *http.Request) {
+	response.SuccessJSON(w, dto.Version{
+		Commit:    h.info.Commit,
+		BuildTime: h.info.BuildTime,
+		GoVersion: h.info.GoVersion,
+	}, http.StatusOK)
+}
@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
-	"github.com/gorilla/schema"
 	"pvz-service/internal/handler/pkg/response"
 
 	"pvz-service/internal/converter"
@@ -16,47 +16,51 @@ import (
 
 const (
 	ErrQueryParameters = "invalid query parameters"
-	ErrConvertParams   = "invalid converting query parameters"
+	ErrChangeStamp     = "failed to get change stamp"
 	FailedGetPvz       = "Failed to get PVZ"
+
+	CodeInvalidQuery = "invalid_query"
 )
 
 type InfoService interface {
 	GetInfoPvz(ctx context.Context, query *model.PvzInfoQuery) ([]*model.Pvz, error)
+	GetInfoChangeStamp(ctx context.Context) (model.ChangeStamp, error)
+}
+
+// PaginationLimits - лимиты пагинации, могут меняться без перезапуска
+type PaginationLimits interface {
+	GetDefaultLimit() int
+	GetMaxLimit() int
 }
 
 type InfoHandlers struct {
 	Service InfoService
+	Limits  PaginationLimits
 }
 
-func NewInfoHandler(service InfoService) *InfoHandlers {
+func NewInfoHandler(service InfoService, limits PaginationLimits) *InfoHandlers {
 	return &InfoHandlers{
 		Service: service,
+		Limits:  limits,
 	}
 }
 
-func (h *InfoHandlers) GetInfo(w http.ResponseWriter, r *http.Request) {
-	var req dto.PvzInfoRequest
+func (h *InfoHandlers) GetPvzList(w http.ResponseWriter, r *http.Request, params dto.GetPvzListParams) {
 	logger := getLogger(r)
 
-	decoder := schema.NewDecoder()
-	decoder.IgnoreUnknownKeys(true)
-
-	if err := decoder.Decode(&req, r.URL.Query()); err != nil {
-		response.WriteError(w, ErrQueryParameters, http.StatusBadRequest)
-		logger.InfoContext(r.Context(), ErrQueryParameters, slog.String(ErrorKey, err.Error()))
-		return
-	}
+	pvzInfo := converter.ToPvzInfoQueryFromPvzListParams(&params, h.Limits.GetDefaultLimit(), h.Limits.GetMaxLimit())
 
-	pvzInfo, err := converter.ToPvzInfoQueryFromPvzInfoResponse(&req)
+	// Без отметки изменения ответ собирается как обычно, просто без ETag
+	stamp, err := h.Service.GetInfoChangeStamp(r.Context())
 	if err != nil {
-		response.WriteError(w, ErrConvertParams, http.StatusBadRequest)
-		logger.InfoContext(r.Context(), ErrQueryParameters, slog.String(ErrorKey, err.Error()))
+		logger.InfoContext(r.Context(), ErrChangeStamp, slog.String(ErrorKey, err.Error()))
+	} else if notModified(w, r, stamp, infoVariant(pvzInfo)) {
 		return
 	}
 
 	pvzList, err := h.Service.GetInfoPvz(r.Context(), pvzInfo)
 	if err != nil {
-		response.WriteError(w, fmt.Sprintf("%s: %s", FailedGetPvz, err.Error()), http.StatusBadRequest)
+		response.WriteServiceError(w, err)
 		logger.InfoContext(r.Context(), FailedGetPvz, slog.String(ErrorKey, err.Error()))
 		return
 	}
@@ -66,3 +70,9 @@ func (h *InfoHandlers) GetInfo(w http.ResponseWriter, r *http.Request) {
 
 	response.SuccessJSON(w, resp, http.StatusOK)
 }
+
+// infoVariant - параметры списка, от которых зависит ответ при тех же данных
+func infoVariant(query *model.PvzInfoQuery) string {
+	return fmt.Sprintf("list|%s|%s|%d|%d|%t", query.StartDate.Format(time.RFC3339Nano), query.EndDate.Format(time.RFC3339Nano),
+		query.Page, query.Limit, query.IncludeInactive)
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/converter"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedIssueProduct  = "failed to issue product"
+	FailedReturnProduct = "failed to return product"
+	FailedGetStock      = "failed to get pvz stock"
+)
+
+type IssuanceService interface {
+	PvzChangeStamps
+	IssueProduct(ctx context.Context, issuance model.Issuance) (*model.Issuance, error)
+	ReturnProduct(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error)
+	GetStock(ctx context.Context, pvz model.Pvz) ([]model.Product, error)
+}
+
+type IssuanceHandlers struct {
+	Service IssuanceService
+}
+
+func NewIssuanceHandler(service IssuanceService) *IssuanceHandlers {
+	return &IssuanceHandlers{
+		Service: service,
+	}
+}
+
+// IssueProduct выдает товар покупателю по коду подтверждения
+func (h *IssuanceHandlers) IssueProduct(w http.ResponseWriter, r *http.Request) {
+	var req dto.IssueProductJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	issuanceModel := converter.ToIssuanceFromIssueProductRequest(&req, getUserID(r))
+	issuance, err := h.Service.IssueProduct(r.Context(), *issuanceModel)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedIssueProduct, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful issue product",
+		slog.String(ProductIDKey, issuance.ProductID.String()),
+		slog.String(PvzIDKey, issuance.PvzID.String()),
+	)
+
+	response.SuccessJSON(w, converter.ToIssuanceResponseFromIssuance(issuance), http.StatusCreated)
+}
+
+// ReturnProduct принимает от покупателя ранее выданный товар
+func (h *IssuanceHandlers) ReturnProduct(w http.ResponseWriter, r *http.Request) {
+	var req dto.ReturnProductJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	returnModel := converter.ToProductReturnFromReturnProductRequest(&req, getUserID(r))
+	ret, err := h.Service.ReturnProduct(r.Context(), *returnModel)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedReturnProduct, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful return product",
+		slog.String(ProductIDKey, ret.ProductID.String()),
+		slog.String(PvzIDKey, ret.PvzID.String()),
+	)
+
+	response.SuccessJSON(w, converter.ToReturnResponseFromProductReturn(ret), http.StatusCreated)
+}
+
+// GetPvzStock возвращает товары, которые сейчас лежат на складе ПВЗ
+func (h *IssuanceHandlers) GetPvzStock(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	logger := getLogger(r)
+	pvzModel := model.Pvz{ID: pvzID}
+
+	if pvzNotModified(w, r, h.Service, pvzModel, "stock") {
+		return
+	}
+
+	products, err := h.Service.GetStock(r.Context(), pvzModel)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedGetStock, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToStockResponseFromProducts(&pvzModel, products), http.StatusOK)
+}
@@ -15,6 +15,34 @@ type InfoService struct {
 	mock.Mock
 }
 
+// GetInfoChangeStamp provides a mock function with given fields: ctx
+func (_m *InfoService) GetInfoChangeStamp(ctx context.Context) (model.ChangeStamp, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInfoChangeStamp")
+	}
+
+	var r0 model.ChangeStamp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (model.ChangeStamp, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) model.ChangeStamp); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(model.ChangeStamp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInfoPvz provides a mock function with given fields: ctx, query
 func (_m *InfoService) GetInfoPvz(ctx context.Context, query *model.PvzInfoQuery) ([]*model.Pvz, error) {
 	ret := _m.Called(ctx, query)
@@ -0,0 +1,148 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "pvz-service/internal/model"
+)
+
+// IssuanceService is an autogenerated mock type for the IssuanceService type
+type IssuanceService struct {
+	mock.Mock
+}
+
+// GetPvzChangeStamp provides a mock function with given fields: ctx, pvz
+func (_m *IssuanceService) GetPvzChangeStamp(ctx context.Context, pvz model.Pvz) (model.ChangeStamp, error) {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPvzChangeStamp")
+	}
+
+	var r0 model.ChangeStamp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) (model.ChangeStamp, error)); ok {
+		return rf(ctx, pvz)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) model.ChangeStamp); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		r0 = ret.Get(0).(model.ChangeStamp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz) error); ok {
+		r1 = rf(ctx, pvz)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStock provides a mock function with given fields: ctx, pvz
+func (_m *IssuanceService) GetStock(ctx context.Context, pvz model.Pvz) ([]model.Product, error) {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStock")
+	}
+
+	var r0 []model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) ([]model.Product, error)); ok {
+		return rf(ctx, pvz)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) []model.Product); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz) error); ok {
+		r1 = rf(ctx, pvz)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueProduct provides a mock function with given fields: ctx, issuance
+func (_m *IssuanceService) IssueProduct(ctx context.Context, issuance model.Issuance) (*model.Issuance, error) {
+	ret := _m.Called(ctx, issuance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueProduct")
+	}
+
+	var r0 *model.Issuance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Issuance) (*model.Issuance, error)); ok {
+		return rf(ctx, issuance)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Issuance) *model.Issuance); ok {
+		r0 = rf(ctx, issuance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Issuance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Issuance) error); ok {
+		r1 = rf(ctx, issuance)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReturnProduct provides a mock function with given fields: ctx, ret
+func (_m *IssuanceService) ReturnProduct(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error) {
+	ret_2 := _m.Called(ctx, ret)
+
+	if len(ret_2) == 0 {
+		panic("no return value specified for ReturnProduct")
+	}
+
+	var r0 *model.ProductReturn
+	var r1 error
+	if rf, ok := ret_2.Get(0).(func(context.Context, model.ProductReturn) (*model.ProductReturn, error)); ok {
+		return rf(ctx, ret)
+	}
+	if rf, ok := ret_2.Get(0).(func(context.Context, model.ProductReturn) *model.ProductReturn); ok {
+		r0 = rf(ctx, ret)
+	} else {
+		if ret_2.Get(0) != nil {
+			r0 = ret_2.Get(0).(*model.ProductReturn)
+		}
+	}
+
+	if rf, ok := ret_2.Get(1).(func(context.Context, model.ProductReturn) error); ok {
+		r1 = rf(ctx, ret)
+	} else {
+		r1 = ret_2.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIssuanceService creates a new instance of IssuanceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIssuanceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IssuanceService {
+	mock := &IssuanceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -63,6 +63,36 @@ func (_m *ProductService) DeleteProduct(ctx context.Context, pvz model.Pvz) erro
 	return r0
 }
 
+// GetProductByBarcode provides a mock function with given fields: ctx, barcode
+func (_m *ProductService) GetProductByBarcode(ctx context.Context, barcode string) (*model.Product, error) {
+	ret := _m.Called(ctx, barcode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductByBarcode")
+	}
+
+	var r0 *model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.Product, error)); ok {
+		return rf(ctx, barcode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.Product); ok {
+		r0 = rf(ctx, barcode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, barcode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewProductService creates a new instance of ProductService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewProductService(t interface {
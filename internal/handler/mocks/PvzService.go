@@ -45,6 +45,154 @@ func (_m *PvzService) AddNewPvz(ctx context.Context, pvz model.Pvz) (*model.Pvz,
 	return r0, r1
 }
 
+// ChangePvzStatus provides a mock function with given fields: ctx, pvz, status
+func (_m *PvzService) ChangePvzStatus(ctx context.Context, pvz model.Pvz, status string) (*model.Pvz, error) {
+	ret := _m.Called(ctx, pvz, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePvzStatus")
+	}
+
+	var r0 *model.Pvz
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz, string) (*model.Pvz, error)); ok {
+		return rf(ctx, pvz, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz, string) *model.Pvz); ok {
+		r0 = rf(ctx, pvz, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Pvz)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz, string) error); ok {
+		r1 = rf(ctx, pvz, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNearbyPvz provides a mock function with given fields: ctx, nearby
+func (_m *PvzService) GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error) {
+	ret := _m.Called(ctx, nearby)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNearbyPvz")
+	}
+
+	var r0 []model.NearbyPvz
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.NearbyQuery) ([]model.NearbyPvz, error)); ok {
+		return rf(ctx, nearby)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.NearbyQuery) []model.NearbyPvz); ok {
+		r0 = rf(ctx, nearby)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.NearbyPvz)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.NearbyQuery) error); ok {
+		r1 = rf(ctx, nearby)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPvz provides a mock function with given fields: ctx, pvz
+func (_m *PvzService) GetPvz(ctx context.Context, pvz model.Pvz) (*model.Pvz, error) {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPvz")
+	}
+
+	var r0 *model.Pvz
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) (*model.Pvz, error)); ok {
+		return rf(ctx, pvz)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) *model.Pvz); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Pvz)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz) error); ok {
+		r1 = rf(ctx, pvz)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPvzChangeStamp provides a mock function with given fields: ctx, pvz
+func (_m *PvzService) GetPvzChangeStamp(ctx context.Context, pvz model.Pvz) (model.ChangeStamp, error) {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPvzChangeStamp")
+	}
+
+	var r0 model.ChangeStamp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) (model.ChangeStamp, error)); ok {
+		return rf(ctx, pvz)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) model.ChangeStamp); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		r0 = ret.Get(0).(model.ChangeStamp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz) error); ok {
+		r1 = rf(ctx, pvz)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePvz provides a mock function with given fields: ctx, patch
+func (_m *PvzService) UpdatePvz(ctx context.Context, patch model.PvzPatch) (*model.Pvz, error) {
+	ret := _m.Called(ctx, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePvz")
+	}
+
+	var r0 *model.Pvz
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.PvzPatch) (*model.Pvz, error)); ok {
+		return rf(ctx, patch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.PvzPatch) *model.Pvz); ok {
+		r0 = rf(ctx, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Pvz)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.PvzPatch) error); ok {
+		r1 = rf(ctx, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewPvzService creates a new instance of PvzService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPvzService(t interface {
@@ -3,6 +3,7 @@ package mocks
 import (
 	context "context"
 
+	uuid "github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 
 	model "pvz-service/internal/model"
@@ -45,6 +46,82 @@ func (_m *Service) DeleteProduct(ctx context.Context, pvz model.Pvz) error {
 
 }
 
+// GetProductByBarcode provides a mock function with given fields: ctx, barcode
+func (_m *Service) GetProductByBarcode(ctx context.Context, barcode string) (*model.Product, error) {
+	return nil, nil
+
+}
+
+// IssueProduct provides a mock function with given fields: ctx, issuance
+func (_m *Service) IssueProduct(ctx context.Context, issuance model.Issuance) (*model.Issuance, error) {
+	return nil, nil
+
+}
+
+// ReturnProduct provides a mock function with given fields: ctx, ret
+func (_m *Service) ReturnProduct(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error) {
+	return nil, nil
+
+}
+
+// GetStock provides a mock function with given fields: ctx, pvz
+func (_m *Service) GetStock(ctx context.Context, pvz model.Pvz) ([]model.Product, error) {
+	return nil, nil
+
+}
+
+// CreateStorageCell provides a mock function with given fields: ctx, cell
+func (_m *Service) CreateStorageCell(ctx context.Context, cell model.StorageCell) (*model.StorageCell, error) {
+	return nil, nil
+
+}
+
+// DeleteStorageCell provides a mock function with given fields: ctx, pvz, cellID
+func (_m *Service) DeleteStorageCell(ctx context.Context, pvz model.Pvz, cellID uuid.UUID) error {
+	return nil
+
+}
+
+// GetStorageCells provides a mock function with given fields: ctx, pvz
+func (_m *Service) GetStorageCells(ctx context.Context, pvz model.Pvz) ([]model.StorageCell, error) {
+	return nil, nil
+
+}
+
+// GetPvz provides a mock function with given fields: ctx, pvz
+func (_m *Service) GetPvz(ctx context.Context, pvz model.Pvz) (*model.Pvz, error) {
+	return nil, nil
+
+}
+
+// GetPvzChangeStamp provides a mock function with given fields: ctx, pvz
+func (_m *Service) GetPvzChangeStamp(ctx context.Context, pvz model.Pvz) (model.ChangeStamp, error) {
+	return model.ChangeStamp{}, nil
+}
+
+// GetInfoChangeStamp provides a mock function with given fields: ctx
+func (_m *Service) GetInfoChangeStamp(ctx context.Context) (model.ChangeStamp, error) {
+	return model.ChangeStamp{}, nil
+}
+
+// UpdatePvz provides a mock function with given fields: ctx, patch
+func (_m *Service) UpdatePvz(ctx context.Context, patch model.PvzPatch) (*model.Pvz, error) {
+	return nil, nil
+
+}
+
+// GetNearbyPvz provides a mock function with given fields: ctx, nearby
+func (_m *Service) GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error) {
+	return nil, nil
+
+}
+
+// ChangePvzStatus provides a mock function with given fields: ctx, pvz, status
+func (_m *Service) ChangePvzStatus(ctx context.Context, pvz model.Pvz, status string) (*model.Pvz, error) {
+	return nil, nil
+
+}
+
 // DummyAuth provides a mock function with given fields: ctx, role
 func (_m *Service) DummyAuth(ctx context.Context, user model.User) (string, error) {
 	return "", nil
@@ -62,8 +139,78 @@ func (_m *Service) Registration(ctx context.Context, user model.User) (*model.Us
 
 }
 
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *Service) VerifyEmail(ctx context.Context, token string) error {
+	return nil
+}
+
+// ResendVerification provides a mock function with given fields: ctx, email
+func (_m *Service) ResendVerification(ctx context.Context, email string) error {
+	return nil
+}
+
+// ForgotPassword provides a mock function with given fields: ctx, email
+func (_m *Service) ForgotPassword(ctx context.Context, email string) error {
+	return nil
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *Service) ResetPassword(ctx context.Context, token string, password string) error {
+	return nil
+}
+
+// Sync provides a mock function with given fields: ctx, pvz, ops
+func (_m *Service) Sync(ctx context.Context, pvz model.Pvz, ops []model.SyncOperation) (*model.SyncResult, error) {
+	return nil, nil
+}
+
 // NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
+// CreateWebhook provides a mock function with given fields: ctx, sub
+func (_m *Service) CreateWebhook(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	return nil, nil
+}
+
+// GetWebhooks provides a mock function with given fields: ctx
+func (_m *Service) GetWebhooks(ctx context.Context) ([]model.WebhookSubscription, error) {
+	return nil, nil
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: ctx, query
+func (_m *Service) GetWebhookDeliveries(ctx context.Context, query model.WebhookDeliveryQuery) ([]model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+// SendTestEvent provides a mock function with given fields: ctx, id
+func (_m *Service) SendTestEvent(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+// RetryDelivery provides a mock function with given fields: ctx, webhookID, deliveryID
+func (_m *Service) RetryDelivery(ctx context.Context, webhookID uuid.UUID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	return nil, nil
+}
+
+// CreateTenant provides a mock function with given fields: ctx, t
+func (_m *Service) CreateTenant(ctx context.Context, t model.Tenant) (*model.Tenant, error) {
+	return nil, nil
+}
+
+// GetTenants provides a mock function with given fields: ctx
+func (_m *Service) GetTenants(ctx context.Context) ([]model.Tenant, error) {
+	return nil, nil
+}
+
+// SetRegistration provides a mock function with given fields: ctx, id, open
+func (_m *Service) SetRegistration(ctx context.Context, id uuid.UUID, open bool) (*model.Tenant, error) {
+	return nil, nil
+}
+
 func NewService(t interface {
 	mock.TestingT
 	Cleanup(func())
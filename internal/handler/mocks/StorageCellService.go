@@ -0,0 +1,138 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "pvz-service/internal/model"
+
+	uuid "github.com/google/uuid"
+)
+
+// StorageCellService is an autogenerated mock type for the StorageCellService type
+type StorageCellService struct {
+	mock.Mock
+}
+
+// CreateStorageCell provides a mock function with given fields: ctx, cell
+func (_m *StorageCellService) CreateStorageCell(ctx context.Context, cell model.StorageCell) (*model.StorageCell, error) {
+	ret := _m.Called(ctx, cell)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateStorageCell")
+	}
+
+	var r0 *model.StorageCell
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.StorageCell) (*model.StorageCell, error)); ok {
+		return rf(ctx, cell)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.StorageCell) *model.StorageCell); ok {
+		r0 = rf(ctx, cell)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.StorageCell)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.StorageCell) error); ok {
+		r1 = rf(ctx, cell)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteStorageCell provides a mock function with given fields: ctx, pvz, cellID
+func (_m *StorageCellService) DeleteStorageCell(ctx context.Context, pvz model.Pvz, cellID uuid.UUID) error {
+	ret := _m.Called(ctx, pvz, cellID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStorageCell")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz, uuid.UUID) error); ok {
+		r0 = rf(ctx, pvz, cellID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetPvzChangeStamp provides a mock function with given fields: ctx, pvz
+func (_m *StorageCellService) GetPvzChangeStamp(ctx context.Context, pvz model.Pvz) (model.ChangeStamp, error) {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPvzChangeStamp")
+	}
+
+	var r0 model.ChangeStamp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) (model.ChangeStamp, error)); ok {
+		return rf(ctx, pvz)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) model.ChangeStamp); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		r0 = ret.Get(0).(model.ChangeStamp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz) error); ok {
+		r1 = rf(ctx, pvz)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStorageCells provides a mock function with given fields: ctx, pvz
+func (_m *StorageCellService) GetStorageCells(ctx context.Context, pvz model.Pvz) ([]model.StorageCell, error) {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStorageCells")
+	}
+
+	var r0 []model.StorageCell
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) ([]model.StorageCell, error)); ok {
+		return rf(ctx, pvz)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) []model.StorageCell); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StorageCell)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz) error); ok {
+		r1 = rf(ctx, pvz)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStorageCellService creates a new instance of StorageCellService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStorageCellService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StorageCellService {
+	mock := &StorageCellService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "pvz-service/internal/model"
+)
+
+// SyncService is an autogenerated mock type for the SyncService type
+type SyncService struct {
+	mock.Mock
+}
+
+// Sync provides a mock function with given fields: ctx, pvz, ops
+func (_m *SyncService) Sync(ctx context.Context, pvz model.Pvz, ops []model.SyncOperation) (*model.SyncResult, error) {
+	ret := _m.Called(ctx, pvz, ops)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sync")
+	}
+
+	var r0 *model.SyncResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz, []model.SyncOperation) (*model.SyncResult, error)); ok {
+		return rf(ctx, pvz, ops)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz, []model.SyncOperation) *model.SyncResult); ok {
+		r0 = rf(ctx, pvz, ops)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SyncResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Pvz, []model.SyncOperation) error); ok {
+		r1 = rf(ctx, pvz, ops)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSyncService creates a new instance of SyncService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSyncService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SyncService {
+	mock := &SyncService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,13 +2,69 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/service"
 )
 
-func WriteError(w http.ResponseWriter, message string, status int) {
-	w.Header().Set("Content-Type", "application/json")
+const (
+	ProblemContentType = "application/problem+json"
+	problemTypeBlank   = "about:blank"
+	ErrInternalServer  = "internal server error"
+)
+
+// WriteError пишет ответ об ошибке в формате application/problem+json.
+func WriteError(w http.ResponseWriter, status int, code string, message string) {
+	// ETag, поставленный до ошибки, относится к данным, а не к ответу об ошибке
+	w.Header().Del("ETag")
+	w.Header().Set("Content-Type", ProblemContentType)
 	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(dto.ErrorResponse{Message: message})
+	body := dto.Error{
+		Type:    problemTypeBlank,
+		Title:   http.StatusText(status),
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+	if message != "" {
+		body.Detail = &message
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// WriteServiceError сопоставляет ошибку сервиса со статусом и кодом ответа.
+// Текст исходной причины клиенту не передается.
+func WriteServiceError(w http.ResponseWriter, err error) {
+	var svcErr *service.Error
+	if !errors.As(err, &svcErr) {
+		WriteError(w, http.StatusInternalServerError, service.CodeInternal, ErrInternalServer)
+		return
+	}
+
+	status := StatusFromError(svcErr)
+	if status == http.StatusInternalServerError {
+		WriteError(w, status, service.CodeInternal, ErrInternalServer)
+		return
+	}
+
+	WriteError(w, status, svcErr.Code, svcErr.Message)
+}
+
+func StatusFromError(err error) int {
+	switch {
+	case errors.Is(err, service.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, service.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrConflict):
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
 }
@@ -0,0 +1,85 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/service"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+		expectedDetail string
+	}{
+		{
+			name:           "not found",
+			err:            service.NewNotFoundError(service.CodeReceptionNotFound, service.PvzOrReceptionsNotExist),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   service.CodeReceptionNotFound,
+			expectedDetail: service.PvzOrReceptionsNotExist,
+		},
+		{
+			name:           "conflict wrapped",
+			err:            fmt.Errorf("wrap: %w", service.NewConflictError(service.CodeReceptionNotClosed, service.ReceptionWasNotClosed)),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   service.CodeReceptionNotClosed,
+			expectedDetail: service.ReceptionWasNotClosed,
+		},
+		{
+			name:           "validation",
+			err:            service.NewValidationError(service.CodeInvalidRole, "forbidden role admin"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   service.CodeInvalidRole,
+			expectedDetail: "forbidden role admin",
+		},
+		{
+			name:           "unauthorized",
+			err:            service.NewUnauthorizedError(service.CodeInvalidCredentials, "invalid email or password"),
+			expectedStatus: http.StatusUnauthorized,
+			expectedCode:   service.CodeInvalidCredentials,
+			expectedDetail: "invalid email or password",
+		},
+		{
+			name:           "internal hides cause",
+			err:            service.NewInternalError(service.FailedPvzCreate, errors.New("pq: connection refused")),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   service.CodeInternal,
+			expectedDetail: ErrInternalServer,
+		},
+		{
+			name:           "unknown error",
+			err:            errors.New("secret db host 10.0.0.1"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   service.CodeInternal,
+			expectedDetail: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			WriteServiceError(w, tt.err)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+
+			var body dto.Error
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.expectedStatus, body.Status)
+			assert.Equal(t, tt.expectedCode, body.Code)
+			require.NotNil(t, body.Detail)
+			assert.Equal(t, tt.expectedDetail, *body.Detail)
+		})
+	}
+}
@@ -7,22 +7,29 @@ import (
 	"log/slog"
 	"net/http"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"pvz-service/internal/converter"
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/model"
+	"pvz-service/pkg/barcode"
 )
 
 const (
 	ErrProductType      = "Invalid Type Product"
 	FailedDeleteProduct = "failed to delete product"
 	FailedCreateProduct = "Failed add Product"
+	ErrBarcode          = "Invalid Barcode"
+	FailedFindProduct   = "failed to find product"
+
+	CodeInvalidProductType = "invalid_product_type"
+	CodeInvalidBarcode     = "invalid_barcode"
 )
 
 type ProductService interface {
 	AddProduct(ctx context.Context, product model.Product, pvz model.Pvz) (*model.Product, error)
 	DeleteProduct(ctx context.Context, pvz model.Pvz) error
+	GetProductByBarcode(ctx context.Context, barcode string) (*model.Product, error)
 }
 
 type ProductHandlers struct {
@@ -36,39 +43,40 @@ func NewProductHandler(service ProductService) *ProductHandlers {
 }
 
 func (h *ProductHandlers) CreateNewProduct(w http.ResponseWriter, r *http.Request) {
-	var req dto.CreateProductRequest
+	var req dto.CreateProductJSONRequestBody
 	logger := getLogger(r)
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, ErrBodyRequest, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
 		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	v := getValidator(r)
 	if err := v.Struct(req); err != nil {
-		response.WriteError(w, ErrRequestFields, http.StatusBadRequest)
-		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
-		return
-	}
-
-	pvzModel, err := converter.ToPvzFromIDRequest(req.PvzID)
-	if err != nil {
-		response.WriteError(w, ErrUUIDParsing, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
 		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	productModel := converter.ToProductFromCreateProductRequest(&req)
-	if err = validateType(productModel.TypeProduct); err != nil {
-		response.WriteError(w, ErrProductType, http.StatusBadRequest)
+	if err := validateType(productModel.TypeProduct); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidProductType, ErrProductType)
 		logger.InfoContext(r.Context(), ErrProductType, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
-	product, err := h.Service.AddProduct(r.Context(), *productModel, *pvzModel)
+	if productModel.Barcode != "" {
+		if _, err := barcode.Validate(productModel.Barcode); err != nil {
+			response.WriteError(w, http.StatusBadRequest, CodeInvalidBarcode, ErrBarcode)
+			logger.InfoContext(r.Context(), ErrBarcode, slog.String(ErrorKey, err.Error()))
+			return
+		}
+	}
+
+	product, err := h.Service.AddProduct(r.Context(), *productModel, model.Pvz{ID: req.PvzId})
 	if err != nil {
-		response.WriteError(w, fmt.Sprintf("%s: %s", FailedCreateProduct, err.Error()), http.StatusBadRequest)
+		response.WriteServiceError(w, err)
 		logger.InfoContext(r.Context(), FailedCreateProduct, slog.String(ErrorKey, err.Error()))
 		return
 	}
@@ -82,27 +90,38 @@ func (h *ProductHandlers) CreateNewProduct(w http.ResponseWriter, r *http.Reques
 	response.SuccessJSON(w, resp, http.StatusCreated)
 }
 
-func (h *ProductHandlers) RemoveLastProduct(w http.ResponseWriter, r *http.Request) {
+func (h *ProductHandlers) DeleteLastProduct(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
 	logger := getLogger(r)
-	pvzIdStr := chi.URLParam(r, "pvzId")
 
-	pvzModel, err := converter.ToPvzFromIDRequest(pvzIdStr)
-	if err != nil {
-		response.WriteError(w, ErrUUIDParsing, http.StatusBadRequest)
-		logger.InfoContext(r.Context(), ErrUUIDParsing, slog.String(ErrorKey, err.Error()))
+	if err := h.Service.DeleteProduct(r.Context(), model.Pvz{ID: pvzID}); err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedDeleteProduct, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
-	err = h.Service.DeleteProduct(r.Context(), *pvzModel)
-	if err != nil {
-		response.WriteError(w, fmt.Sprintf("%s:  %s", FailedDeleteProduct, err.Error()), http.StatusBadRequest)
-		logger.InfoContext(r.Context(), FailedDeleteProduct, slog.String(ErrorKey, err.Error()))
+	logger.InfoContext(r.Context(), "successful delete last product", slog.String(PvzIDKey, pvzID.String()))
+
+	response.Success(w, http.StatusOK)
+}
+
+// GetProductByBarcode ищет последний принятый товар по отсканированному штрихкоду
+func (h *ProductHandlers) GetProductByBarcode(w http.ResponseWriter, r *http.Request, code string) {
+	logger := getLogger(r)
+
+	if _, err := barcode.Validate(code); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBarcode, ErrBarcode)
+		logger.InfoContext(r.Context(), ErrBarcode, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
-	logger.InfoContext(r.Context(), "successful delete last product", slog.String(PvzIDKey, pvzModel.ID.String()))
+	product, err := h.Service.GetProductByBarcode(r.Context(), code)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedFindProduct, slog.String(ErrorKey, err.Error()))
+		return
+	}
 
-	response.Success(w, http.StatusOK)
+	response.SuccessJSON(w, converter.ToProductResponseFromProduct(product), http.StatusOK)
 }
 
 func validateType(typeProduct string) error {
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/google/uuid"
 	"pvz-service/internal/converter"
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/handler/pkg/response"
@@ -22,10 +23,26 @@ const (
 const (
 	ErrInvalidCity = "invalid city"
 	ErrCreatePvz   = "failed to create PVZ"
+	ErrGetPvz      = "failed to get PVZ"
+	ErrUpdatePvz   = "failed to update PVZ"
+	ErrNearbyPvz   = "failed to find nearby PVZ"
+	ErrPvzStatus   = "failed to change PVZ status"
+
+	CodeInvalidCity = "invalid_city"
 )
 
+// PvzChangeStamps отдает отметку изменения ПВЗ для ETag ручек чтения
+type PvzChangeStamps interface {
+	GetPvzChangeStamp(ctx context.Context, pvz model.Pvz) (model.ChangeStamp, error)
+}
+
 type PvzService interface {
+	PvzChangeStamps
 	AddNewPvz(ctx context.Context, pvz model.Pvz) (*model.Pvz, error)
+	GetPvz(ctx context.Context, pvz model.Pvz) (*model.Pvz, error)
+	UpdatePvz(ctx context.Context, patch model.PvzPatch) (*model.Pvz, error)
+	GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error)
+	ChangePvzStatus(ctx context.Context, pvz model.Pvz, status string) (*model.Pvz, error)
 }
 
 type PVZHandlers struct {
@@ -39,42 +56,150 @@ func NewPvzHandler(service PvzService) *PVZHandlers {
 }
 
 func (h *PVZHandlers) CreateNewPvz(w http.ResponseWriter, r *http.Request) {
-	var req dto.CreatePvzRequest
+	var req dto.CreatePvzJSONRequestBody
 	logger := getLogger(r)
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, ErrBodyRequest, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
 		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	v := getValidator(r)
 	if err := v.Struct(req); err != nil {
-		response.WriteError(w, ErrRequestFields, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
 		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	pvzModel := converter.ToPvzFromCreatePvzRequest(&req)
 	if err := validateCity(pvzModel.City); err != nil {
-		response.WriteError(w, ErrInvalidCity, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidCity, ErrInvalidCity)
 		logger.InfoContext(r.Context(), ErrInvalidCity, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	pvz, err := h.Service.AddNewPvz(r.Context(), *pvzModel)
 	if err != nil {
-		response.WriteError(w, fmt.Sprintf("%s: %s", ErrCreatePvz, err.Error()), http.StatusBadRequest)
+		response.WriteServiceError(w, err)
 		logger.InfoContext(r.Context(), ErrCreatePvz, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	resp := converter.ToCreatePvzResponseFromPvz(pvz)
-	logger.InfoContext(r.Context(), "successful create pvz", slog.String(PvzIDKey, resp.ID))
+	logger.InfoContext(r.Context(), "successful create pvz", slog.String(PvzIDKey, pvz.ID.String()))
 
 	response.SuccessJSON(w, resp, http.StatusCreated)
 }
 
+// GetPvz возвращает профиль ПВЗ
+func (h *PVZHandlers) GetPvz(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	logger := getLogger(r)
+	pvzModel := model.Pvz{ID: pvzID}
+
+	if pvzNotModified(w, r, h.Service, pvzModel, "profile") {
+		return
+	}
+
+	pvz, err := h.Service.GetPvz(r.Context(), pvzModel)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), ErrGetPvz, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToPvzProfileResponseFromPvz(pvz), http.StatusOK)
+}
+
+// UpdatePvz меняет адрес, координаты, график и лимиты ПВЗ
+func (h *PVZHandlers) UpdatePvz(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	var req dto.UpdatePvzJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	patch, err := converter.ToPvzPatchFromUpdatePvzRequest(&req, pvzID)
+	if err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	pvz, err := h.Service.UpdatePvz(r.Context(), *patch)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), ErrUpdatePvz, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful update pvz", slog.String(PvzIDKey, pvz.ID.String()))
+
+	response.SuccessJSON(w, converter.ToPvzProfileResponseFromPvz(pvz), http.StatusOK)
+}
+
+// SuspendPvz временно останавливает прием товаров в ПВЗ
+func (h *PVZHandlers) SuspendPvz(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	h.changeStatus(w, r, pvzID, model.PvzStatusSuspended)
+}
+
+// ReopenPvz возвращает приостановленный ПВЗ в работу
+func (h *PVZHandlers) ReopenPvz(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	h.changeStatus(w, r, pvzID, model.PvzStatusActive)
+}
+
+// DecommissionPvz выводит ПВЗ из эксплуатации, его данные сохраняются
+func (h *PVZHandlers) DecommissionPvz(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	h.changeStatus(w, r, pvzID, model.PvzStatusDecommissioned)
+}
+
+func (h *PVZHandlers) changeStatus(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID, status string) {
+	logger := getLogger(r)
+
+	pvz, err := h.Service.ChangePvzStatus(r.Context(), model.Pvz{ID: pvzID}, status)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), ErrPvzStatus, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful change pvz status",
+		slog.String(PvzIDKey, pvz.ID.String()), slog.String(PvzStatusKey, pvz.Status))
+
+	response.SuccessJSON(w, converter.ToPvzProfileResponseFromPvz(pvz), http.StatusOK)
+}
+
+// GetNearbyPvz ищет ближайшие к точке ПВЗ с их текущим статусом работы
+func (h *PVZHandlers) GetNearbyPvz(w http.ResponseWriter, r *http.Request, params dto.GetNearbyPvzParams) {
+	logger := getLogger(r)
+
+	v := getValidator(r)
+	if err := v.Struct(params); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidQuery, ErrQueryParameters)
+		logger.InfoContext(r.Context(), ErrQueryParameters, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	list, err := h.Service.GetNearbyPvz(r.Context(), *converter.ToNearbyQueryFromNearbyPvzParams(&params))
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), ErrNearbyPvz, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToNearbyPvzResponseList(list), http.StatusOK)
+}
+
 func validateCity(city string) error {
 	switch city {
 	case MoscowRU, SpbRU, KazanRU:
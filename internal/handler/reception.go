@@ -3,11 +3,10 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log/slog"
 	"net/http"
 
-	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"pvz-service/internal/converter"
 	"pvz-service/internal/handler/dto"
 	"pvz-service/internal/handler/pkg/response"
@@ -34,63 +33,48 @@ func NewReceptionHandler(service ReceptionService) *ReceptionHandlers {
 }
 
 func (h *ReceptionHandlers) OpenNewReception(w http.ResponseWriter, r *http.Request) {
-	var req dto.ReceptionRequest
+	var req dto.CreateReceptionJSONRequestBody
 	logger := getLogger(r)
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.WriteError(w, ErrBodyRequest, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
 		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	v := getValidator(r)
 	if err := v.Struct(req); err != nil {
-		response.WriteError(w, ErrRequestFields, http.StatusBadRequest)
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
 		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
-	receptionModel, err := converter.ToReceptionFromReceptionRequest(&req)
+	recep, err := h.Service.CreateReception(r.Context(), *converter.ToReceptionFromReceptionRequest(&req))
 	if err != nil {
-		response.WriteError(w, ErrUUIDParsing, http.StatusBadRequest)
-		logger.InfoContext(r.Context(), ErrUUIDParsing, slog.String(ErrorKey, err.Error()))
-		return
-	}
-
-	recep, err := h.Service.CreateReception(r.Context(), *receptionModel)
-	if err != nil {
-		response.WriteError(w, fmt.Sprintf("%s: %s", FailedCreateReception, err.Error()), http.StatusBadRequest)
+		response.WriteServiceError(w, err)
 		logger.InfoContext(r.Context(), FailedCreateReception, slog.String(ErrorKey, err.Error()))
 		return
 	}
 
 	resp := converter.ToReceptionResponseFromReception(recep)
-	logger.InfoContext(r.Context(), "successful create reception", slog.String(PvzIDKey, resp.ID))
+	logger.InfoContext(r.Context(), "successful create reception", slog.String(PvzIDKey, recep.PvzID.String()))
 
 	response.SuccessJSON(w, resp, http.StatusCreated)
 }
 
-func (h *ReceptionHandlers) CloseLastReception(w http.ResponseWriter, r *http.Request) {
-	pvzIdStr := chi.URLParam(r, "pvzId")
+func (h *ReceptionHandlers) CloseLastReception(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
 	logger := getLogger(r)
 
-	receptionModel, err := converter.ToReceptionFromPvzIDRequest(pvzIdStr)
-	if err != nil {
-		response.WriteError(w, ErrUUIDParsing, http.StatusBadRequest)
-		logger.InfoContext(r.Context(), ErrUUIDParsing, slog.String(ErrorKey, err.Error()))
-		return
-	}
-
-	recep, err := h.Service.CloseReception(r.Context(), *receptionModel)
+	recep, err := h.Service.CloseReception(r.Context(), model.Reception{PvzID: pvzID})
 	if err != nil {
-		response.WriteError(w, fmt.Sprintf("%s: %s", FailedCloseReception, err.Error()), http.StatusBadRequest)
+		response.WriteServiceError(w, err)
 		logger.InfoContext(r.Context(), FailedCloseReception, slog.String(ErrorKey, err.Error()))
 
 		return
 	}
 
 	resp := converter.ToReceptionResponseFromReception(recep)
-	logger.InfoContext(r.Context(), "successful close reception", slog.String(PvzIDKey, resp.ID))
+	logger.InfoContext(r.Context(), "successful close reception", slog.String(PvzIDKey, recep.PvzID.String()))
 
 	response.SuccessJSON(w, resp, http.StatusOK)
 }
@@ -2,11 +2,17 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
 	"pvz-service/internal/middleware"
 )
 
@@ -17,9 +23,28 @@ const (
 	ErrUUIDParsing   = "invalid ID format"
 )
 
+const (
+	CodeInvalidBody   = "invalid_body"
+	CodeInvalidFields = "invalid_fields"
+	CodeInvalidRole   = "invalid_role"
+	CodeInvalidID     = "invalid_id"
+)
+
+const (
+	APIv1Prefix = "/api/v1"
+	APIv2Prefix = "/api/v2"
+)
+
+// Корневые пути без версии остались от первых прошивок сканеров
+var (
+	RootDeprecatedSince = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	RootSunset          = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
 const (
 	ModeratorRole = "moderator"
 	EmployeeRole  = "employee"
+	AdminRole     = "admin"
 )
 
 const (
@@ -28,6 +53,7 @@ const (
 	UserIDKey      = "userId"
 	ErrorKey       = "error"
 	ReceptionIDKey = "receptionId"
+	PvzStatusKey   = "status"
 )
 const (
 	ElectrType  = "электроника"
@@ -45,40 +71,219 @@ type Service interface {
 	ReceptionService
 	ProductService
 	InfoService
+	SyncService
+	IssuanceService
+	StorageCellService
+	WebhookService
+	TenantService
 }
 
+// Settings - настройки, которые перечитываются без перезапуска
+type Settings interface {
+	PaginationLimits
+	middleware.RateLimits
+}
+
+// Router реализует сгенерированный по api/swagger.yaml dto.ServerInterface
 type Router struct {
-	service Service
+	service  Service
+	settings Settings
+}
+
+var _ dto.ServerInterface = (*Router)(nil)
+
+type RouterOption func(*routerOptions)
+
+type routerOptions struct {
+	specValidator *middleware.OpenAPIValidator
+	deprecations  *middleware.DeprecationCounter
+	dummyLogin    bool
+	rejectDummy   bool
 }
 
-func NewRouter(service Service, jwtSecret string, logger *slog.Logger) *chi.Mux {
+// WithSpecValidation проверяет запросы и ответы ручек по спецификации.
+// Включается в тестах, чтобы расхождение ответа со swagger.yaml роняло тест
+func WithSpecValidation(v *middleware.OpenAPIValidator) RouterOption {
+	return func(o *routerOptions) {
+		o.specValidator = v
+	}
+}
+
+// WithDeprecationCounter передает счетчик обращений к устаревшим маршрутам,
+// по нему приложение видит, остались ли клиенты на корневых путях
+func WithDeprecationCounter(c *middleware.DeprecationCounter) RouterOption {
+	return func(o *routerOptions) {
+		o.deprecations = c
+	}
+}
+
+// WithDummyLogin регистрирует /dummyLogin. Без опции ручки нет, как и положено в prod
+func WithDummyLogin() RouterOption {
+	return func(o *routerOptions) {
+		o.dummyLogin = true
+	}
+}
+
+// WithDummyWritesRejected оставляет токенам /dummyLogin с claim dummy только чтение
+func WithDummyWritesRejected() RouterOption {
+	return func(o *routerOptions) {
+		o.rejectDummy = true
+	}
+}
+
+func NewRouter(service Service, jwtSecret string, logger *slog.Logger, settings Settings, opts ...RouterOption) *chi.Mux {
+	options := routerOptions{deprecations: middleware.NewDeprecationCounter()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	r := chi.NewRouter()
-	router := &Router{service: service}
+	router := &Router{service: service, settings: settings}
 
+	// Обертка разбирает параметры пути и запроса по спецификации,
+	// проверка ролей при этом остается на маршрутах ниже
+	ops := &dto.ServerInterfaceWrapper{Handler: router, ErrorHandlerFunc: ParamError}
+	if options.specValidator != nil {
+		ops.HandlerMiddlewares = append(ops.HandlerMiddlewares, options.specValidator.Middleware)
+	}
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.AccessLog(logger))
+	// Сжатие снаружи Recoverer: ответ 500 после паники пишется уже через него
+	r.Use(middleware.Compress)
+	r.Use(middleware.Recoverer(logger))
 	r.Use(middleware.NewValidator().Middleware)
 	r.Use(middleware.ContextLoggerMiddleware(logger))
-	r.Post("/register", http.HandlerFunc(router.registerHandler))
-	r.Post("/login", http.HandlerFunc(router.loginHandler))
-	r.Post("/dummyLogin", http.HandlerFunc(router.dummyLoginHandler))
+	r.Use(middleware.ClientCert)
+
+	// Служебные ручки (MountHealth) под лимит не попадают.
+	// Лимитер один на все версии, чтобы префикс не давал клиенту лишнюю квоту
+	limit := middleware.RateLimit(settings)
+	jwt := middleware.NewJWT(jwtSecret).Authenticate
+	auth := jwt
+	if options.rejectDummy {
+		auth = func(next http.Handler) http.Handler {
+			return jwt(middleware.RejectDummyWrites(next))
+		}
+	}
+
+	r.Route(APIv1Prefix, func(v1 chi.Router) {
+		v1.Use(limit)
+		mountV1(v1, ops, auth, options.dummyLogin)
+	})
+	r.Route(APIv2Prefix, func(v2 chi.Router) {
+		v2.Use(limit)
+		mountV2(v2, ops, auth, options.dummyLogin)
+	})
+
+	// Старые прошивки сканеров ходят в корень, держим алиасы до RootSunset
+	r.Group(func(root chi.Router) {
+		root.Use(limit)
+		root.Use(middleware.Deprecated(middleware.Deprecation{
+			Since:     RootDeprecatedSince,
+			Sunset:    RootSunset,
+			Successor: APIv1Prefix,
+		}, options.deprecations))
+		mountV1(root, ops, auth, options.dummyLogin)
+	})
+	return r
+}
+
+// mountV1 регистрирует ручки первой версии API
+func mountV1(r chi.Router, ops *dto.ServerInterfaceWrapper, auth func(http.Handler) http.Handler, dummyLogin bool) {
+	// Оператора нового пользователя клиент передает в X-Tenant-ID
+	r.With(middleware.PublicTenant).Post("/register", ops.Register)
+	r.Post("/login", ops.Login)
+	if dummyLogin {
+		r.Post("/dummyLogin", ops.DummyLogin)
+	}
+	r.Post("/verify-email", ops.VerifyEmail)
+	r.Post("/verify-email/resend", ops.ResendVerification)
+	r.Post("/forgot-password", ops.ForgotPassword)
+	r.Post("/reset-password", ops.ResetPassword)
+	// Поиск ПВЗ нужен клиентскому приложению, поэтому без авторизации.
+	// Оператора клиент передает в X-Tenant-ID
+	r.With(middleware.PublicTenant).Get("/pvz/nearby", ops.GetNearbyPvz)
 
 	r.Group(func(protected chi.Router) {
-		protected.Use(middleware.NewJWT(jwtSecret).Authenticate)
+		protected.Use(auth)
+
+		protected.With(middleware.RequireRoles(ModeratorRole)).Post("/pvz", ops.CreatePvz)
+		protected.With(middleware.RequireRoles(ModeratorRole)).Patch("/pvz/{pvzId}", ops.UpdatePvz)
+		protected.With(middleware.RequireRoles(ModeratorRole)).Post("/pvz/{pvzId}/cells", ops.CreateStorageCell)
+		protected.With(middleware.RequireRoles(ModeratorRole)).Post("/pvz/{pvzId}/suspend", ops.SuspendPvz)
+		protected.With(middleware.RequireRoles(ModeratorRole)).Post("/pvz/{pvzId}/reopen", ops.ReopenPvz)
+		protected.With(middleware.RequireRoles(ModeratorRole)).
+			Post("/pvz/{pvzId}/decommission", ops.DecommissionPvz)
+		protected.With(middleware.RequireRoles(ModeratorRole)).
+			Delete("/pvz/{pvzId}/cells/{cellId}", ops.DeleteStorageCell)
+
+		protected.With(middleware.RequireRoles(ModeratorRole, EmployeeRole)).Get("/pvz", ops.GetPvzList)
+		protected.With(middleware.RequireRoles(ModeratorRole, EmployeeRole)).Get("/pvz/{pvzId}", ops.GetPvz)
+		protected.With(middleware.RequireRoles(ModeratorRole, EmployeeRole)).
+			Get("/products/by-barcode/{code}", ops.GetProductByBarcode)
+		protected.With(middleware.RequireRoles(ModeratorRole, EmployeeRole)).
+			Get("/pvz/{pvzId}/stock", ops.GetPvzStock)
+		protected.With(middleware.RequireRoles(ModeratorRole, EmployeeRole)).
+			Get("/pvz/{pvzId}/cells", ops.GetStorageCells)
 
-		protected.With(middleware.RequireRoles(ModeratorRole)).Post("/pvz", http.HandlerFunc(router.newPvz))
+		// Подписки партнеров на события, управляет модератор
+		protected.Route("/webhooks", func(hooks chi.Router) {
+			hooks.Use(middleware.RequireRoles(ModeratorRole))
+			hooks.Post("/", ops.CreateWebhook)
+			hooks.Get("/", ops.GetWebhooks)
+			hooks.Delete("/{webhookId}", ops.DeleteWebhook)
+			hooks.Get("/{webhookId}/deliveries", ops.GetWebhookDeliveries)
+			hooks.Post("/{webhookId}/test", ops.SendWebhookTestEvent)
+			hooks.Post("/{webhookId}/deliveries/{deliveryId}/retry", ops.RetryWebhookDelivery)
+		})
 
-		protected.With(middleware.RequireRoles(ModeratorRole, EmployeeRole)).Get("/pvz", http.HandlerFunc(router.getInfoPvzByParameters))
+		// Операторов маркетплейсов заводит администратор сервиса
+		protected.Route("/admin/tenants", func(admin chi.Router) {
+			admin.Use(middleware.RequireRoles(AdminRole))
+			admin.Post("/", ops.CreateTenant)
+			admin.Get("/", ops.GetTenants)
+			admin.Patch("/{tenantId}", ops.UpdateTenant)
+		})
 
 		// Cоздаём вложенную группу для ручек, требующих роль employee
 		protected.Group(func(emp chi.Router) {
 			emp.Use(middleware.RequireRoles(EmployeeRole))
-			emp.Post("/receptions", http.HandlerFunc(router.newReception))
-			emp.Post("/products", http.HandlerFunc(router.newProduct))
-			emp.Post("/pvz/{pvzId}/close_last_reception", http.HandlerFunc(router.closeReception))
-			emp.Post("/pvz/{pvzId}/delete_last_product", http.HandlerFunc(router.deleteLastProduct))
+			emp.Post("/receptions", ops.CreateReception)
+			emp.Post("/products", ops.CreateProduct)
+			emp.Post("/pvz/{pvzId}/close_last_reception", ops.CloseLastReception)
+			emp.Post("/pvz/{pvzId}/delete_last_product", ops.DeleteLastProduct)
+			emp.Post("/sync", ops.SyncOperations)
+			emp.Post("/issuances", ops.IssueProduct)
+			emp.Post("/returns", ops.ReturnProduct)
 		})
 	})
-	r.Route("/auth", func(r chi.Router) {})
-	return r
+}
+
+// mountV2 - заготовка второй версии. Пока она совпадает с v1; ручку с новым
+// форматом ответа регистрируем здесь после mountV1, chi заменит маршрут v1
+func mountV2(r chi.Router, ops *dto.ServerInterfaceWrapper, auth func(http.Handler) http.Handler, dummyLogin bool) {
+	mountV1(r, ops, auth, dummyLogin)
+}
+
+// MountHealth регистрирует служебные ручки, доступные без авторизации
+func MountHealth(r chi.Router, h *HealthHandlers) {
+	r.Get("/healthz", h.Liveness)
+	r.Get("/readyz", h.Readiness)
+	r.Get("/version", h.Version)
+}
+
+// ParamError отвечает на параметр, не прошедший разбор в dto.ServerInterfaceWrapper
+func ParamError(w http.ResponseWriter, r *http.Request, err error) {
+	code, message := CodeInvalidQuery, ErrQueryParameters
+
+	var formatErr *dto.InvalidParamFormatError
+	if errors.As(err, &formatErr) && strings.HasSuffix(formatErr.ParamName, "Id") {
+		code, message = CodeInvalidID, ErrUUIDParsing
+	}
+
+	response.WriteError(w, http.StatusBadRequest, code, message)
+	getLogger(r).InfoContext(r.Context(), message, slog.String(ErrorKey, err.Error()))
 }
 
 func getValidator(r *http.Request) *validator.Validate {
@@ -95,47 +300,192 @@ func getLogger(r *http.Request) *slog.Logger {
 	return slog.Default() // fallback на глобальный
 }
 
-func (r *Router) registerHandler(w http.ResponseWriter, req *http.Request) {
+// getUserID возвращает userId из JWT или сертификата устройства
+func getUserID(r *http.Request) string {
+	if id, ok := r.Context().Value(middleware.UserIDKey).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// Register: X-Tenant-ID уже разобран middleware.PublicTenant и лежит в контексте
+func (r *Router) Register(w http.ResponseWriter, req *http.Request, _ dto.RegisterParams) {
 	h := NewAuthHandler(r.service)
 	h.Register(w, req)
 }
 
-func (r *Router) loginHandler(w http.ResponseWriter, req *http.Request) {
+func (r *Router) Login(w http.ResponseWriter, req *http.Request) {
 	h := NewAuthHandler(r.service)
 	h.Login(w, req)
 }
 
-func (r *Router) dummyLoginHandler(w http.ResponseWriter, req *http.Request) {
+func (r *Router) DummyLogin(w http.ResponseWriter, req *http.Request) {
 	h := NewAuthHandler(r.service)
 	h.DummyLogin(w, req)
 }
 
-func (r *Router) newPvz(w http.ResponseWriter, req *http.Request) {
+func (r *Router) VerifyEmail(w http.ResponseWriter, req *http.Request) {
+	h := NewAuthHandler(r.service)
+	h.VerifyEmail(w, req)
+}
+
+func (r *Router) ResendVerification(w http.ResponseWriter, req *http.Request) {
+	h := NewAuthHandler(r.service)
+	h.ResendVerification(w, req)
+}
+
+func (r *Router) ForgotPassword(w http.ResponseWriter, req *http.Request) {
+	h := NewAuthHandler(r.service)
+	h.ForgotPassword(w, req)
+}
+
+func (r *Router) ResetPassword(w http.ResponseWriter, req *http.Request) {
+	h := NewAuthHandler(r.service)
+	h.ResetPassword(w, req)
+}
+
+func (r *Router) CreatePvz(w http.ResponseWriter, req *http.Request) {
 	h := NewPvzHandler(r.service)
 	h.CreateNewPvz(w, req)
 }
 
-func (r *Router) newReception(w http.ResponseWriter, req *http.Request) {
+func (r *Router) GetPvz(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewPvzHandler(r.service)
+	h.GetPvz(w, req, pvzID)
+}
+
+func (r *Router) UpdatePvz(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewPvzHandler(r.service)
+	h.UpdatePvz(w, req, pvzID)
+}
+
+func (r *Router) SuspendPvz(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewPvzHandler(r.service)
+	h.SuspendPvz(w, req, pvzID)
+}
+
+func (r *Router) ReopenPvz(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewPvzHandler(r.service)
+	h.ReopenPvz(w, req, pvzID)
+}
+
+func (r *Router) DecommissionPvz(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewPvzHandler(r.service)
+	h.DecommissionPvz(w, req, pvzID)
+}
+
+// GetNearbyPvz: X-Tenant-ID из params уже разобран middleware.PublicTenant
+func (r *Router) GetNearbyPvz(w http.ResponseWriter, req *http.Request, params dto.GetNearbyPvzParams) {
+	h := NewPvzHandler(r.service)
+	h.GetNearbyPvz(w, req, params)
+}
+
+func (r *Router) CreateReception(w http.ResponseWriter, req *http.Request) {
 	h := NewReceptionHandler(r.service)
 	h.OpenNewReception(w, req)
 }
 
-func (r *Router) newProduct(w http.ResponseWriter, req *http.Request) {
+func (r *Router) CreateProduct(w http.ResponseWriter, req *http.Request) {
 	h := NewProductHandler(r.service)
 	h.CreateNewProduct(w, req)
 }
 
-func (r *Router) closeReception(w http.ResponseWriter, req *http.Request) {
+func (r *Router) CloseLastReception(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
 	h := NewReceptionHandler(r.service)
-	h.CloseLastReception(w, req)
+	h.CloseLastReception(w, req, pvzID)
 }
 
-func (r *Router) deleteLastProduct(w http.ResponseWriter, req *http.Request) {
+func (r *Router) DeleteLastProduct(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
 	h := NewProductHandler(r.service)
-	h.RemoveLastProduct(w, req)
+	h.DeleteLastProduct(w, req, pvzID)
+}
+
+func (r *Router) GetProductByBarcode(w http.ResponseWriter, req *http.Request, code string) {
+	h := NewProductHandler(r.service)
+	h.GetProductByBarcode(w, req, code)
+}
+
+func (r *Router) SyncOperations(w http.ResponseWriter, req *http.Request) {
+	h := NewSyncHandler(r.service)
+	h.Sync(w, req)
+}
+
+func (r *Router) IssueProduct(w http.ResponseWriter, req *http.Request) {
+	h := NewIssuanceHandler(r.service)
+	h.IssueProduct(w, req)
+}
+
+func (r *Router) ReturnProduct(w http.ResponseWriter, req *http.Request) {
+	h := NewIssuanceHandler(r.service)
+	h.ReturnProduct(w, req)
+}
+
+func (r *Router) GetPvzStock(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewIssuanceHandler(r.service)
+	h.GetPvzStock(w, req, pvzID)
+}
+
+func (r *Router) CreateStorageCell(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewStorageCellHandler(r.service)
+	h.CreateStorageCell(w, req, pvzID)
+}
+
+func (r *Router) DeleteStorageCell(w http.ResponseWriter, req *http.Request, pvzID, cellID uuid.UUID) {
+	h := NewStorageCellHandler(r.service)
+	h.DeleteStorageCell(w, req, pvzID, cellID)
+}
+
+func (r *Router) GetStorageCells(w http.ResponseWriter, req *http.Request, pvzID uuid.UUID) {
+	h := NewStorageCellHandler(r.service)
+	h.GetStorageCells(w, req, pvzID)
+}
+
+func (r *Router) CreateWebhook(w http.ResponseWriter, req *http.Request) {
+	h := NewWebhookHandler(r.service, r.settings)
+	h.CreateWebhook(w, req)
+}
+
+func (r *Router) GetWebhooks(w http.ResponseWriter, req *http.Request) {
+	h := NewWebhookHandler(r.service, r.settings)
+	h.GetWebhooks(w, req)
+}
+
+func (r *Router) DeleteWebhook(w http.ResponseWriter, req *http.Request, webhookID uuid.UUID) {
+	h := NewWebhookHandler(r.service, r.settings)
+	h.DeleteWebhook(w, req, webhookID)
+}
+
+func (r *Router) GetWebhookDeliveries(w http.ResponseWriter, req *http.Request, webhookID uuid.UUID, params dto.GetWebhookDeliveriesParams) {
+	h := NewWebhookHandler(r.service, r.settings)
+	h.GetWebhookDeliveries(w, req, webhookID, params)
+}
+
+func (r *Router) SendWebhookTestEvent(w http.ResponseWriter, req *http.Request, webhookID uuid.UUID) {
+	h := NewWebhookHandler(r.service, r.settings)
+	h.SendWebhookTestEvent(w, req, webhookID)
+}
+
+func (r *Router) RetryWebhookDelivery(w http.ResponseWriter, req *http.Request, webhookID, deliveryID uuid.UUID) {
+	h := NewWebhookHandler(r.service, r.settings)
+	h.RetryWebhookDelivery(w, req, webhookID, deliveryID)
+}
+
+func (r *Router) GetPvzList(w http.ResponseWriter, req *http.Request, params dto.GetPvzListParams) {
+	h := NewInfoHandler(r.service, r.settings)
+	h.GetPvzList(w, req, params)
+}
+
+func (r *Router) CreateTenant(w http.ResponseWriter, req *http.Request) {
+	h := NewTenantHandler(r.service)
+	h.CreateTenant(w, req)
+}
+
+func (r *Router) GetTenants(w http.ResponseWriter, req *http.Request) {
+	h := NewTenantHandler(r.service)
+	h.GetTenants(w, req)
 }
 
-func (r *Router) getInfoPvzByParameters(w http.ResponseWriter, req *http.Request) {
-	h := NewInfoHandler(r.service)
-	h.GetInfo(w, req)
+func (r *Router) UpdateTenant(w http.ResponseWriter, req *http.Request, tenantID uuid.UUID) {
+	h := NewTenantHandler(r.service)
+	h.UpdateTenant(w, req, tenantID)
 }
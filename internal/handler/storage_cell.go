@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/converter"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateStorageCell = "failed to create storage cell"
+	FailedDeleteStorageCell = "failed to delete storage cell"
+	FailedGetStorageCells   = "failed to get storage cells"
+)
+
+type StorageCellService interface {
+	PvzChangeStamps
+	CreateStorageCell(ctx context.Context, cell model.StorageCell) (*model.StorageCell, error)
+	DeleteStorageCell(ctx context.Context, pvz model.Pvz, cellID uuid.UUID) error
+	GetStorageCells(ctx context.Context, pvz model.Pvz) ([]model.StorageCell, error)
+}
+
+type StorageCellHandlers struct {
+	Service StorageCellService
+}
+
+func NewStorageCellHandler(service StorageCellService) *StorageCellHandlers {
+	return &StorageCellHandlers{
+		Service: service,
+	}
+}
+
+func (h *StorageCellHandlers) CreateStorageCell(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	var req dto.CreateStorageCellJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	cell, err := h.Service.CreateStorageCell(r.Context(), *converter.ToStorageCellFromCreateStorageCellRequest(&req, pvzID))
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedCreateStorageCell, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful create storage cell",
+		slog.String(PvzIDKey, pvzID.String()),
+		slog.String("cellId", cell.ID.String()),
+	)
+
+	response.SuccessJSON(w, converter.ToStorageCellResponseFromStorageCell(cell), http.StatusCreated)
+}
+
+func (h *StorageCellHandlers) DeleteStorageCell(w http.ResponseWriter, r *http.Request, pvzID, cellID uuid.UUID) {
+	logger := getLogger(r)
+
+	if err := h.Service.DeleteStorageCell(r.Context(), model.Pvz{ID: pvzID}, cellID); err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedDeleteStorageCell, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful delete storage cell", slog.String("cellId", cellID.String()))
+
+	response.Success(w, http.StatusOK)
+}
+
+// GetStorageCells возвращает ячейки ПВЗ с заполненностью
+func (h *StorageCellHandlers) GetStorageCells(w http.ResponseWriter, r *http.Request, pvzID uuid.UUID) {
+	logger := getLogger(r)
+	pvzModel := model.Pvz{ID: pvzID}
+
+	if pvzNotModified(w, r, h.Service, pvzModel, "cells") {
+		return
+	}
+
+	cells, err := h.Service.GetStorageCells(r.Context(), pvzModel)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedGetStorageCells, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToStorageCellsResponseFromStorageCells(&pvzModel, cells), http.StatusOK)
+}
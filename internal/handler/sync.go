@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"pvz-service/internal/converter"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/model"
+	"pvz-service/pkg/barcode"
+)
+
+const FailedSync = "failed to sync operations"
+
+type SyncService interface {
+	Sync(ctx context.Context, pvz model.Pvz, ops []model.SyncOperation) (*model.SyncResult, error)
+}
+
+type SyncHandlers struct {
+	Service SyncService
+}
+
+func NewSyncHandler(service SyncService) *SyncHandlers {
+	return &SyncHandlers{
+		Service: service,
+	}
+}
+
+// Sync принимает журнал операций, накопленный сканером без связи
+func (h *SyncHandlers) Sync(w http.ResponseWriter, r *http.Request) {
+	var req dto.SyncOperationsJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	pvzModel, ops := converter.ToSyncOperationsFromSyncRequest(&req)
+
+	for _, op := range ops {
+		if op.Type != model.SyncAddProduct {
+			continue
+		}
+		if err := validateType(op.TypeProduct); err != nil {
+			response.WriteError(w, http.StatusBadRequest, CodeInvalidProductType, ErrProductType)
+			logger.InfoContext(r.Context(), ErrProductType, slog.String(ErrorKey, err.Error()))
+			return
+		}
+		if op.Barcode == "" {
+			continue
+		}
+		if _, err := barcode.Validate(op.Barcode); err != nil {
+			response.WriteError(w, http.StatusBadRequest, CodeInvalidBarcode, ErrBarcode)
+			logger.InfoContext(r.Context(), ErrBarcode, slog.String(ErrorKey, err.Error()))
+			return
+		}
+	}
+
+	result, err := h.Service.Sync(r.Context(), *pvzModel, ops)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedSync, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful sync",
+		slog.String(PvzIDKey, pvzModel.ID.String()),
+		slog.Int("applied", len(result.Applied)),
+		slog.Int("rejected", len(result.Rejected)),
+	)
+
+	response.SuccessJSON(w, converter.ToSyncResponseFromSyncResult(result), http.StatusOK)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/converter"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateTenant = "failed to create tenant"
+	FailedGetTenants   = "failed to get tenants"
+	FailedUpdateTenant = "failed to update tenant"
+)
+
+const TenantIDKey = "tenantId"
+
+type TenantService interface {
+	CreateTenant(ctx context.Context, t model.Tenant) (*model.Tenant, error)
+	GetTenants(ctx context.Context) ([]model.Tenant, error)
+	SetRegistration(ctx context.Context, id uuid.UUID, open bool) (*model.Tenant, error)
+}
+
+type TenantHandlers struct {
+	Service TenantService
+}
+
+func NewTenantHandler(service TenantService) *TenantHandlers {
+	return &TenantHandlers{
+		Service: service,
+	}
+}
+
+func (h *TenantHandlers) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTenantJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	t, err := h.Service.CreateTenant(r.Context(), *converter.ToTenantFromTenantRequest(&req))
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedCreateTenant, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful create tenant", slog.String(TenantIDKey, t.ID.String()))
+
+	response.SuccessJSON(w, converter.ToTenantResponseFromTenant(t), http.StatusCreated)
+}
+
+func (h *TenantHandlers) GetTenants(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+
+	tenants, err := h.Service.GetTenants(r.Context())
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedGetTenants, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToTenantResponseList(tenants), http.StatusOK)
+}
+
+// UpdateTenant открывает или закрывает регистрацию пользователей у оператора
+func (h *TenantHandlers) UpdateTenant(w http.ResponseWriter, r *http.Request, tenantID uuid.UUID) {
+	var req dto.UpdateTenantJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	t, err := h.Service.SetRegistration(r.Context(), tenantID, *req.OpenRegistration)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedUpdateTenant, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful update tenant", slog.String(TenantIDKey, t.ID.String()),
+		slog.Bool("openRegistration", t.OpenRegistration))
+
+	response.SuccessJSON(w, converter.ToTenantResponseFromTenant(t), http.StatusOK)
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/converter"
+	"pvz-service/internal/handler/dto"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateWebhook = "failed to create webhook subscription"
+	FailedGetWebhooks   = "failed to get webhook subscriptions"
+	FailedDeleteWebhook = "failed to delete webhook subscription"
+	FailedGetDeliveries = "failed to get webhook deliveries"
+	FailedSendTestEvent = "failed to send test event"
+	FailedRetryDelivery = "failed to retry webhook delivery"
+)
+
+const (
+	WebhookIDKey             = "webhookId"
+	WebhookDeliveryIDKey     = "deliveryId"
+	WebhookDeliveryStatusKey = "deliveryStatus"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error)
+	GetWebhooks(ctx context.Context) ([]model.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, query model.WebhookDeliveryQuery) ([]model.WebhookDelivery, error)
+	SendTestEvent(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error)
+}
+
+type WebhookHandlers struct {
+	Service WebhookService
+	Limits  PaginationLimits
+}
+
+func NewWebhookHandler(service WebhookService, limits PaginationLimits) *WebhookHandlers {
+	return &WebhookHandlers{
+		Service: service,
+		Limits:  limits,
+	}
+}
+
+func (h *WebhookHandlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookJSONRequestBody
+	logger := getLogger(r)
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidBody, ErrBodyRequest)
+		logger.InfoContext(r.Context(), ErrBodyRequest, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	v := getValidator(r)
+	if err := v.Struct(req); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidFields, ErrRequestFields)
+		logger.InfoContext(r.Context(), ErrRequestFields, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	sub, err := h.Service.CreateWebhook(r.Context(), *converter.ToWebhookFromWebhookRequest(&req))
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedCreateWebhook, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful create webhook subscription", slog.String(WebhookIDKey, sub.ID.String()))
+
+	response.SuccessJSON(w, converter.ToWebhookResponseFromWebhook(sub, true), http.StatusCreated)
+}
+
+// GetWebhooks возвращает подписки без секретов
+func (h *WebhookHandlers) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	logger := getLogger(r)
+
+	subs, err := h.Service.GetWebhooks(r.Context())
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedGetWebhooks, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToWebhookResponseList(subs), http.StatusOK)
+}
+
+func (h *WebhookHandlers) DeleteWebhook(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	logger := getLogger(r)
+
+	if err := h.Service.DeleteWebhook(r.Context(), id); err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedDeleteWebhook, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "successful delete webhook subscription", slog.String(WebhookIDKey, id.String()))
+
+	response.Success(w, http.StatusOK)
+}
+
+// GetWebhookDeliveries возвращает журнал доставок подписки, новые первыми
+func (h *WebhookHandlers) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request, id uuid.UUID, params dto.GetWebhookDeliveriesParams) {
+	logger := getLogger(r)
+
+	v := getValidator(r)
+	if err := v.Struct(params); err != nil {
+		response.WriteError(w, http.StatusBadRequest, CodeInvalidQuery, ErrQueryParameters)
+		logger.InfoContext(r.Context(), ErrQueryParameters, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	query := converter.ToWebhookDeliveryQuery(&params, id, h.Limits.GetDefaultLimit(), h.Limits.GetMaxLimit())
+
+	deliveries, err := h.Service.GetWebhookDeliveries(r.Context(), *query)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedGetDeliveries, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	response.SuccessJSON(w, converter.ToWebhookDeliveryResponseList(deliveries), http.StatusOK)
+}
+
+// SendWebhookTestEvent отправляет подписке webhook.test и возвращает результат первой попытки
+func (h *WebhookHandlers) SendWebhookTestEvent(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	logger := getLogger(r)
+
+	delivery, err := h.Service.SendTestEvent(r.Context(), id)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedSendTestEvent, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "test event sent",
+		slog.String(WebhookIDKey, id.String()),
+		slog.String(WebhookDeliveryStatusKey, delivery.Status),
+	)
+
+	response.SuccessJSON(w, converter.ToWebhookDeliveryResponse(delivery), http.StatusOK)
+}
+
+// RetryWebhookDelivery возвращает доставку из dead в очередь
+func (h *WebhookHandlers) RetryWebhookDelivery(w http.ResponseWriter, r *http.Request, webhookID, deliveryID uuid.UUID) {
+	logger := getLogger(r)
+
+	delivery, err := h.Service.RetryDelivery(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		response.WriteServiceError(w, err)
+		logger.InfoContext(r.Context(), FailedRetryDelivery, slog.String(ErrorKey, err.Error()))
+		return
+	}
+
+	logger.InfoContext(r.Context(), "webhook delivery requeued", slog.String(WebhookDeliveryIDKey, deliveryID.String()))
+
+	response.SuccessJSON(w, converter.ToWebhookDeliveryResponse(delivery), http.StatusOK)
+}
@@ -0,0 +1,25 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+)
+
+func TestLog_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewLog(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	body := "Сбросьте пароль по ссылке https://pvz.example/reset-password?token=secret-token_1&lang=ru"
+	err := m.Send(context.Background(), model.Mail{To: "user@example.com", Subject: "Сброс пароля", Body: body})
+	require.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "user@example.com")
+	assert.Contains(t, buf.String(), "https://pvz.example/reset-password?token=REDACTED&lang=ru")
+	assert.NotContains(t, buf.String(), "secret-token_1")
+}
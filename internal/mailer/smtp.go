@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"pvz-service/internal/model"
+)
+
+// base64LineLength - длина строки тела письма, RFC 2045 ограничивает ее 76 символами
+const base64LineLength = 76
+
+var ErrInvalidRecipient = errors.New("invalid recipient")
+
+// SMTP отправляет письма через SMTP сервер. STARTTLS включается, если сервер его поддерживает,
+// без него net/smtp не передает пароль никуда, кроме localhost
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+
+	// send - отправка письма, в тестах подменяется
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTP(host, port, username, password, from string) *SMTP {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTP{
+		addr: net.JoinHostPort(host, port),
+		from: from,
+		auth: auth,
+		send: smtp.SendMail,
+	}
+}
+
+// Send отправляет письмо. net/smtp не принимает контекст, поэтому отмена запроса
+// не прерывает уже начатую отправку
+func (m *SMTP) Send(ctx context.Context, msg model.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil || strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("%w: %q", ErrInvalidRecipient, msg.To)
+	}
+
+	data := m.message(to.Address, msg.Subject, msg.Body, time.Now())
+	if err = m.send(m.addr, m.auth, m.from, []string{to.Address}, data); err != nil {
+		return fmt.Errorf("smtp %s: %w", m.addr, err)
+	}
+
+	return nil
+}
+
+// message собирает письмо: тема кодируется по RFC 2047, тело - в base64, чтобы кириллица
+// доходила через любые серверы
+func (m *SMTP) message(to, subject, body string, date time.Time) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(body))
+	for len(encoded) > base64LineLength {
+		b.WriteString(encoded[:base64LineLength] + "\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+	b.WriteString(encoded + "\r\n")
+
+	return b.Bytes()
+}
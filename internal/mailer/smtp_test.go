@@ -0,0 +1,60 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+)
+
+func TestSMTP_Send(t *testing.T) {
+	m := NewSMTP("smtp.example.com", "587", "user", "pass", "no-reply@pvz.local")
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	m.send = func(addr string, _ smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		return nil
+	}
+
+	body := strings.Repeat("Подтвердите почту по ссылке ", 5)
+	err := m.Send(context.Background(), model.Mail{To: "user@example.com", Subject: "Подтверждение почты", Body: body})
+	require.NoError(t, err)
+
+	assert.Equal(t, "smtp.example.com:587", gotAddr)
+	assert.Equal(t, "no-reply@pvz.local", gotFrom)
+	assert.Equal(t, []string{"user@example.com"}, gotTo)
+
+	headers, encoded, ok := strings.Cut(string(gotMsg), "\r\n\r\n")
+	require.True(t, ok)
+	assert.Contains(t, headers, "To: user@example.com\r\n")
+	assert.Contains(t, headers, "Subject: =?utf-8?q?")
+	assert.Contains(t, headers, "Content-Transfer-Encoding: base64")
+
+	for _, line := range strings.Split(strings.TrimSuffix(encoded, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), base64LineLength)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decoded))
+}
+
+// Адрес с переводом строки мог бы дописать в письмо свои заголовки
+func TestSMTP_SendInvalidRecipient(t *testing.T) {
+	m := NewSMTP("smtp.example.com", "587", "", "", "no-reply@pvz.local")
+	m.send = func(string, smtp.Auth, string, []string, []byte) error {
+		t.Fatal("письмо не должно отправляться")
+		return nil
+	}
+
+	for _, to := range []string{"", "not-an-email", "user@example.com\r\nBcc: victim@example.com"} {
+		err := m.Send(context.Background(), model.Mail{To: to, Subject: "Тема", Body: "Текст"})
+		assert.ErrorIs(t, err, ErrInvalidRecipient, to)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/tenant"
+)
+
+// DeviceIDPrefix отличает устройства, вошедшие по сертификату, от пользователей в логах
+const DeviceIDPrefix = "device:"
+
+// DeviceRole - единственная роль, которую дает сертификат. Модераторы и администраторы входят по JWT
+const DeviceRole = "employee"
+
+// ClientCert опознает устройство по проверенному клиентскому сертификату (mTLS).
+// CN становится userId с префиксом device:, первый OU - ролью, которую проверяет RequireRoles,
+// O - id оператора (без O - оператор по умолчанию). Сертификат с ролью, отличной от DeviceRole,
+// отклоняется с 403. Запросы без сертификата проходят дальше без изменений и авторизуются по JWT
+func ClientCert(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject := r.TLS.VerifiedChains[0][0].Subject
+		if subject.CommonName == "" || len(subject.OrganizationalUnit) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if subject.OrganizationalUnit[0] != DeviceRole {
+			response.WriteError(w, http.StatusForbidden, CodeForbidden, ErrForbidden)
+			return
+		}
+
+		tenantID := tenant.DefaultID
+		if len(subject.Organization) > 0 {
+			id, err := uuid.Parse(subject.Organization[0])
+			if err != nil || id == uuid.Nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			tenantID = id
+		}
+
+		ctx := context.WithValue(r.Context(), UserIDKey, DeviceIDPrefix+subject.CommonName)
+		ctx = context.WithValue(ctx, RoleKey, subject.OrganizationalUnit[0])
+		ctx = tenant.WithID(ctx, tenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
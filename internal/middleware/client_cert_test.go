@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"pvz-service/internal/tenant"
+)
+
+func TestClientCert(t *testing.T) {
+	verified := func(subject pkix.Name) *tls.ConnectionState {
+		return &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}},
+		}
+	}
+
+	operatorID := uuid.New()
+	validToken := mockGenerateToken(t, map[string]interface{}{UserIDKey: uuid.NewString(), RoleKey: "admin"}, "secret")
+
+	tests := []struct {
+		name           string
+		tls            *tls.ConnectionState
+		authHeader     string
+		expectedStatus int
+		expectedUserID string
+		expectedRole   string
+		expectedTenant uuid.UUID
+	}{
+		{
+			name:           "сертификат устройства заменяет JWT",
+			tls:            verified(pkix.Name{CommonName: "scanner-7", OrganizationalUnit: []string{"employee"}}),
+			expectedStatus: http.StatusOK,
+			expectedUserID: DeviceIDPrefix + "scanner-7",
+			expectedRole:   "employee",
+			expectedTenant: tenant.DefaultID,
+		},
+		{
+			name: "оператор устройства из O",
+			tls: verified(pkix.Name{
+				CommonName:         "scanner-7",
+				OrganizationalUnit: []string{"employee"},
+				Organization:       []string{operatorID.String()},
+			}),
+			expectedStatus: http.StatusOK,
+			expectedUserID: DeviceIDPrefix + "scanner-7",
+			expectedRole:   "employee",
+			expectedTenant: operatorID,
+		},
+		{
+			name: "O не id оператора - сертификат не дает входа",
+			tls: verified(pkix.Name{
+				CommonName:         "scanner-7",
+				OrganizationalUnit: []string{"employee"},
+				Organization:       []string{"ООО Ромашка"},
+			}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "сертификат с ролью модератора отклоняется",
+			tls:            verified(pkix.Name{CommonName: "scanner-7", OrganizationalUnit: []string{"moderator"}}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "сертификат с ролью администратора отклоняется и с токеном",
+			tls:            verified(pkix.Name{CommonName: "scanner-7", OrganizationalUnit: []string{"admin"}}),
+			authHeader:     "Bearer " + validToken,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "сертификат без OU не дает роли",
+			tls:            verified(pkix.Name{CommonName: "scanner-7"}),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "непроверенный сертификат игнорируется",
+			tls:            &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "x", OrganizationalUnit: []string{"moderator"}}}}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "обычный HTTP без токена",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "при наличии заголовка Authorization проверяется токен",
+			tls:            verified(pkix.Name{CommonName: "scanner-7", OrganizationalUnit: []string{"employee"}}),
+			authHeader:     "Bearer invalid.token.string",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userID, role string
+			var tenantID uuid.UUID
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ = r.Context().Value(UserIDKey).(string)
+				role, _ = r.Context().Value(RoleKey).(string)
+				tenantID, _ = tenant.FromContext(r.Context())
+			})
+
+			handler := ClientCert(NewJWT("secret").Authenticate(next))
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+			req.TLS = tt.tls
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedUserID, userID)
+			assert.Equal(t, tt.expectedRole, role)
+			assert.Equal(t, tt.expectedTenant, tenantID)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	// Короткие ответы (ошибки, токены) после сжатия почти не уменьшаются
+	compressMinSize = 1024
+	// Уровень brotli 4 сжимает JSON лучше gzip при сопоставимой скорости
+	brotliLevel = 4
+)
+
+// Сжимаем только текстовые ответы
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/problem+json": true,
+	"application/yaml":         true,
+	"application/javascript":   true,
+	"image/svg+xml":            true,
+}
+
+var (
+	gzipPool = sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	brotliPool = sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(io.Discard, brotliLevel)
+	}}
+)
+
+// Compress сжимает ответ в br или gzip по заголовку Accept-Encoding с учетом q-весов.
+// При равных весах выбирается br
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ответ зависит от Accept-Encoding, даже если конкретно этот не сжат
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, status: http.StatusOK}
+		defer cw.close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding выбирает кодировку из Accept-Encoding, "" - отдавать как есть
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = q
+		}
+		weights[name] = weight
+	}
+
+	best, bestWeight := "", 0.0
+	for _, encoding := range []string{encodingBrotli, encodingGzip} {
+		weight, ok := weights[encoding]
+		if !ok {
+			weight, ok = weights["*"]
+		}
+		if ok && weight > bestWeight {
+			best, bestWeight = encoding, weight
+		}
+	}
+	return best
+}
+
+// compressWriter копит начало ответа и решает, сжимать ли его, когда набралось
+// compressMinSize байт или ответ закончился
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+
+	buf         []byte
+	decided     bool
+	wroteHeader bool
+	encoder     io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	// Промежуточные ответы 1xx уходят сразу, основной статус будет следом
+	if status < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+	cw.status = status
+	cw.wroteHeader = true
+
+	// У ответов без тела сжимать нечего
+	if !bodyAllowed(status) {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.decided {
+		if cw.encoder != nil {
+			return cw.encoder.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= compressMinSize {
+		if err := cw.flushBuffer(cw.compressible()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush отправляет накопленное клиенту, например для потоковых ответов
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		_ = cw.flushBuffer(cw.compressible())
+	}
+	if f, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *compressWriter) compressible() bool {
+	h := cw.Header()
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") || compressibleTypes[mediaType]
+}
+
+// decide пишет заголовки ответа, после этого тело идет сразу в клиент или в encoder
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+
+	if compress {
+		h := cw.Header()
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+
+		switch cw.encoding {
+		case encodingBrotli:
+			br := brotliPool.Get().(*brotli.Writer)
+			br.Reset(cw.ResponseWriter)
+			cw.encoder = br
+		case encodingGzip:
+			gz := gzipPool.Get().(*gzip.Writer)
+			gz.Reset(cw.ResponseWriter)
+			cw.encoder = gz
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+func (cw *compressWriter) flushBuffer(compress bool) error {
+	cw.decide(compress)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+
+	if cw.encoder != nil {
+		_, err := cw.encoder.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// close дописывает короткий ответ как есть или закрывает поток сжатия
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader && len(cw.buf) == 0 {
+			// Обработчик ничего не записал, net/http сам ответит 200
+			return
+		}
+		_ = cw.flushBuffer(false)
+		return
+	}
+
+	if cw.encoder == nil {
+		return
+	}
+	_ = cw.encoder.Close()
+
+	switch enc := cw.encoder.(type) {
+	case *brotli.Writer:
+		brotliPool.Put(enc)
+	case *gzip.Writer:
+		gzipPool.Put(enc)
+	}
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", encodingGzip},
+		{"gzip, deflate, br", encodingBrotli},
+		{"br;q=0.5, gzip", encodingGzip},
+		{"br;q=0, gzip;q=0", ""},
+		{"GZIP;q=0.8", encodingGzip},
+		{"*", encodingBrotli},
+		{"*;q=0.1, br;q=0", encodingGzip},
+		{"gzip;q=abc", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateEncoding(tt.header))
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	bigJSON := `{"items":[` + strings.Repeat(`{"city":"Москва"},`, 200) + `{}]}`
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		method         string
+		contentType    string
+		status         int
+		body           string
+		wantEncoding   string
+	}{
+		{"Big JSON gzip", "gzip", http.MethodGet, "application/json", http.StatusOK, bigJSON, encodingGzip},
+		{"Big JSON br", "gzip, br", http.MethodGet, "application/json", http.StatusOK, bigJSON, encodingBrotli},
+		{"Small body", "gzip, br", http.MethodGet, "application/json", http.StatusOK, `{"message":"ok"}`, ""},
+		{"Not accepted", "", http.MethodGet, "application/json", http.StatusOK, bigJSON, ""},
+		{"Binary type", "gzip", http.MethodGet, "application/octet-stream", http.StatusOK, bigJSON, ""},
+		{"Not modified", "gzip", http.MethodGet, "application/json", http.StatusNotModified, "", ""},
+		{"HEAD", "gzip", http.MethodHead, "application/json", http.StatusOK, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				// Пишем кусками, чтобы решение принималось на середине тела
+				for i := 0; i < len(tt.body); i += 100 {
+					_, _ = io.WriteString(w, tt.body[i:min(i+100, len(tt.body))])
+				}
+			}))
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Equal(t, tt.wantEncoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.body, decodeBody(t, tt.wantEncoding, w.Body.Bytes()))
+		})
+	}
+}
+
+func decodeBody(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+
+	var r io.Reader = bytes.NewReader(body)
+	switch encoding {
+	case encodingGzip:
+		gz, err := gzip.NewReader(r)
+		require.NoError(t, err)
+		r = gz
+	case encodingBrotli:
+		r = brotli.NewReader(r)
+	}
+
+	decoded, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(decoded)
+}
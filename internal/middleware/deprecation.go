@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Deprecation описывает вывод маршрутов из эксплуатации
+type Deprecation struct {
+	// Since - с какого момента маршрут устарел (заголовок Deprecation, RFC 9745)
+	Since time.Time
+	// Sunset - после какого момента маршрут может быть удален (заголовок Sunset, RFC 8594)
+	Sunset time.Time
+	// Successor - префикс, под которым лежит замена маршрута, попадает в Link
+	Successor string
+}
+
+// DeprecationCounter считает обращения к устаревшим маршрутам,
+// чтобы понять, когда старых клиентов не осталось
+type DeprecationCounter struct {
+	mu    sync.Mutex
+	calls map[string]int64
+}
+
+func NewDeprecationCounter() *DeprecationCounter {
+	return &DeprecationCounter{calls: make(map[string]int64)}
+}
+
+func (c *DeprecationCounter) inc(route string) {
+	c.mu.Lock()
+	c.calls[route]++
+	c.mu.Unlock()
+}
+
+// Count возвращает число обращений к маршруту вида "GET /pvz/{pvzId}"
+func (c *DeprecationCounter) Count(route string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[route]
+}
+
+// Snapshot возвращает счетчики, отсортированные по маршруту, в виде "маршрут=число"
+func (c *DeprecationCounter) Snapshot() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make([]string, 0, len(c.calls))
+	for route, n := range c.calls {
+		out = append(out, fmt.Sprintf("%s=%d", route, n))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Deprecated помечает ответы маршрута заголовками Deprecation, Sunset и Link
+// на замену и считает обращения по шаблону маршрута chi
+func Deprecated(d Deprecation, counter *DeprecationCounter) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", d.Since.Unix())
+	sunset := d.Sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Set("Sunset", sunset)
+			if d.Successor != "" {
+				w.Header().Set("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, d.Successor, r.URL.Path))
+			}
+
+			next.ServeHTTP(w, r)
+
+			// Шаблон дописывается по мере спуска по роутеру, поэтому берем его после обработки.
+			// Сырой путь не считаем, иначе число ключей растет от каждого id
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				counter.inc(r.Method + " " + rctx.RoutePattern())
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecated(t *testing.T) {
+	counter := NewDeprecationCounter()
+	deprecation := Deprecation{
+		Since:     time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v1",
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	r := chi.NewRouter()
+	r.Get("/api/v1/pvz/{pvzId}", ok)
+	r.With(Deprecated(deprecation, counter)).Get("/pvz/{pvzId}", ok)
+
+	for _, path := range []string{"/pvz/1", "/pvz/2"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v1`+path+`>; rel="successor-version"`, w.Header().Get("Link"))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/pvz/1", nil))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+
+	assert.Equal(t, int64(2), counter.Count("GET /pvz/{pvzId}"))
+	assert.Equal(t, []string{"GET /pvz/{pvzId}=2"}, counter.Snapshot())
+}
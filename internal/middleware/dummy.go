@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"pvz-service/internal/handler/pkg/response"
+)
+
+const (
+	ErrDummyReadOnly  = "dummy token is read-only"
+	CodeDummyReadOnly = "dummy_read_only"
+)
+
+// RejectDummyWrites пропускает с тестовым токеном /dummyLogin только чтение.
+// Ставится после Authenticate
+func RejectDummyWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dummy, _ := r.Context().Value(DummyKey).(bool)
+		if !dummy {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+		default:
+			response.WriteError(w, http.StatusForbidden, CodeDummyReadOnly, ErrDummyReadOnly)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejectDummyWrites(t *testing.T) {
+	secret := "mysecret"
+	claims := map[string]interface{}{UserIDKey: uuid.NewString(), RoleKey: employeeRole}
+	regular := mockGenerateToken(t, claims, secret)
+	claims[DummyKey] = true
+	dummy := mockGenerateToken(t, claims, secret)
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		expectedStatus int
+	}{
+		{"regular token write", http.MethodPost, regular, http.StatusOK},
+		{"dummy token read", http.MethodGet, dummy, http.StatusOK},
+		{"dummy token write", http.MethodPost, dummy, http.StatusForbidden},
+		{"dummy token delete", http.MethodDelete, dummy, http.StatusForbidden},
+	}
+
+	handler := NewJWT(secret).Authenticate(RejectDummyWrites(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, rr.Body.String(), CodeDummyReadOnly)
+			}
+		})
+	}
+}
@@ -6,14 +6,22 @@ import (
 	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/tenant"
 )
 
-const ErrInvalidToken = "Invalid token"
+const (
+	ErrInvalidToken  = "Invalid token"
+	CodeInvalidToken = "invalid_token"
+)
 
 const (
-	UserIDKey = "userId"
-	RoleKey   = "role"
+	UserIDKey   = "userId"
+	RoleKey     = "role"
+	TenantIDKey = "tenantId"
+	// DummyKey - токен выдан /dummyLogin в окружении dev
+	DummyKey = "dummy"
 )
 
 type JWT struct {
@@ -26,8 +34,15 @@ func NewJWT(secret string) *JWT {
 func (j *JWT) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
+
+		// Устройство уже опознано по клиентскому сертификату
+		if _, ok := r.Context().Value(RoleKey).(string); ok && authHeader == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		if authHeader == "" {
-			response.WriteError(w, ErrForbidden, http.StatusForbidden)
+			response.WriteError(w, http.StatusForbidden, CodeForbidden, ErrForbidden)
 			return
 		}
 
@@ -39,29 +54,58 @@ func (j *JWT) Authenticate(next http.Handler) http.Handler {
 		})
 
 		if err != nil {
-			response.WriteError(w, ErrInvalidToken, http.StatusForbidden)
+			response.WriteError(w, http.StatusForbidden, CodeInvalidToken, ErrInvalidToken)
 			return
 		}
 
 		if !token.Valid {
-			response.WriteError(w, ErrInvalidToken, http.StatusForbidden)
+			response.WriteError(w, http.StatusForbidden, CodeInvalidToken, ErrInvalidToken)
 			return
 		}
 
 		userID, ok := claims[UserIDKey].(string)
 		if !ok {
-			response.WriteError(w, ErrInvalidToken, http.StatusForbidden)
+			response.WriteError(w, http.StatusForbidden, CodeInvalidToken, ErrInvalidToken)
 			return
 		}
 
 		role, ok := claims[RoleKey].(string)
 		if !ok {
-			response.WriteError(w, ErrInvalidToken, http.StatusForbidden)
+			response.WriteError(w, http.StatusForbidden, CodeInvalidToken, ErrInvalidToken)
+			return
+		}
+
+		tenantID, ok := tenantFromClaims(claims)
+		if !ok {
+			response.WriteError(w, http.StatusForbidden, CodeInvalidToken, ErrInvalidToken)
 			return
 		}
 
 		ctx := context.WithValue(r.Context(), UserIDKey, userID)
 		ctx = context.WithValue(ctx, RoleKey, role)
+		ctx = tenant.WithID(ctx, tenantID)
+		if dummy, _ := claims[DummyKey].(bool); dummy {
+			ctx = context.WithValue(ctx, DummyKey, true)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// tenantFromClaims возвращает оператора токена. Токены, выданные до разделения
+// на операторов, его не содержат и относятся к оператору по умолчанию
+func tenantFromClaims(claims jwt.MapClaims) (uuid.UUID, bool) {
+	raw, ok := claims[TenantIDKey]
+	if !ok {
+		return tenant.DefaultID, true
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(str)
+	if err != nil || id == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -9,14 +9,15 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"pvz-service/internal/tenant"
 )
 
 func mockGenerateToken(t *testing.T, claims map[string]interface{}, secret string) string {
-	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		UserIDKey: claims[UserIDKey],
-		RoleKey:   claims[RoleKey],
-		"exp":     time.Now().Add(time.Minute).Unix(),
-	})
+	mapClaims := jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()}
+	for k, v := range claims {
+		mapClaims[k] = v
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
 
 	tokenStr, err := tok.SignedString([]byte(secret))
 	assert.NoError(t, err)
@@ -27,12 +28,14 @@ func TestAuthenticate(t *testing.T) {
 	secret := "mysecret"
 	jwtMiddleware := NewJWT(secret)
 	id := uuid.New()
+	tenantID := uuid.New()
 	tests := []struct {
 		name           string
 		authHeader     string
 		expectedStatus int
 		expectedUserID string
 		expectedRole   string
+		expectedTenant uuid.UUID
 	}{
 		{
 			name:           "missing Authorization header",
@@ -53,6 +56,37 @@ func TestAuthenticate(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedUserID: id.String(),
 			expectedRole:   moderatorRole,
+			expectedTenant: tenant.DefaultID,
+		},
+		{
+			name: "tenant from token",
+			authHeader: "Bearer " + mockGenerateToken(t, map[string]interface{}{
+				UserIDKey:   id.String(),
+				RoleKey:     moderatorRole,
+				TenantIDKey: tenantID.String(),
+			}, secret),
+			expectedStatus: http.StatusOK,
+			expectedUserID: id.String(),
+			expectedRole:   moderatorRole,
+			expectedTenant: tenantID,
+		},
+		{
+			name: "invalid tenantId in token",
+			authHeader: "Bearer " + mockGenerateToken(t, map[string]interface{}{
+				UserIDKey:   id.String(),
+				RoleKey:     moderatorRole,
+				TenantIDKey: "not-a-uuid",
+			}, secret),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "nil tenantId in token",
+			authHeader: "Bearer " + mockGenerateToken(t, map[string]interface{}{
+				UserIDKey:   id.String(),
+				RoleKey:     moderatorRole,
+				TenantIDKey: uuid.Nil.String(),
+			}, secret),
+			expectedStatus: http.StatusForbidden,
 		},
 		{
 			name: "missing userId in token",
@@ -82,6 +116,8 @@ func TestAuthenticate(t *testing.T) {
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				assert.Equal(t, tt.expectedUserID, r.Context().Value(UserIDKey))
 				assert.Equal(t, tt.expectedRole, r.Context().Value(RoleKey))
+				got, _ := tenant.FromContext(r.Context())
+				assert.Equal(t, tt.expectedTenant, got)
 				w.WriteHeader(http.StatusOK)
 			})
 
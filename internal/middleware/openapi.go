@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"pvz-service/internal/handler/pkg/response"
+)
+
+const (
+	ErrRequestSpec  = "request does not match API specification"
+	CodeRequestSpec = "invalid_request"
+
+	CodeResponseSpec = "response_spec_violation"
+)
+
+// OpenAPIValidator проверяет запросы и ответы по api/swagger.yaml.
+// Ответ, не совпадающий со спецификацией, заменяется на 500 с описанием
+// расхождения - так несовпадение сразу роняет тест
+type OpenAPIValidator struct {
+	router routers.Router
+}
+
+func NewOpenAPIValidator(doc *openapi3.T) (*OpenAPIValidator, error) {
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build openapi router: %w", err)
+	}
+
+	return &OpenAPIValidator{router: router}, nil
+}
+
+func (v *OpenAPIValidator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(withoutVersion(r))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Токен уже проверили JWT и RequireRoles
+		options := &openapi3filter.Options{
+			AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+			IncludeResponseStatus: true,
+		}
+		requestInput := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		}
+
+		if err = openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+			response.WriteError(w, http.StatusBadRequest, CodeRequestSpec, fmt.Sprintf("%s: %s", ErrRequestSpec, firstLine(err)))
+			return
+		}
+
+		rec := &bufferedWriter{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestInput,
+			Status:                 rec.status,
+			Header:                 rec.header,
+			Options:                options,
+		}
+		responseInput.SetBodyBytes(rec.body.Bytes())
+
+		if err = openapi3filter.ValidateResponse(r.Context(), responseInput); err != nil {
+			response.WriteError(w, http.StatusInternalServerError, CodeResponseSpec,
+				fmt.Sprintf("%s %s -> %d: %s", r.Method, route.Path, rec.status, firstLine(err)))
+			return
+		}
+
+		for key, values := range rec.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+	})
+}
+
+// Спецификация описывает пути без префикса версии API
+var versionPrefix = regexp.MustCompile(`^/api/v[0-9]+(/|$)`)
+
+// withoutVersion возвращает копию запроса с путем без /api/vN для поиска операции
+func withoutVersion(r *http.Request) *http.Request {
+	path := versionPrefix.ReplaceAllString(r.URL.Path, "/")
+	if path == r.URL.Path {
+		return r
+	}
+
+	u := *r.URL
+	u.Path, u.RawPath = path, ""
+	stripped := *r
+	stripped.URL = &u
+	return &stripped
+}
+
+// firstLine отрезает от ошибки kin-openapi дамп схемы
+func firstLine(err error) string {
+	msg, _, _ := strings.Cut(err.Error(), "\n")
+	return msg
+}
+
+// bufferedWriter придерживает ответ до проверки
+type bufferedWriter struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (b *bufferedWriter) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
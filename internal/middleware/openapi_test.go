@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/handler/dto"
+)
+
+const testSpec = `
+openapi: 3.0.0
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /items:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+              required: [name]
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                required: [id]
+`
+
+func TestOpenAPIValidator(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	require.NoError(t, err)
+	v, err := NewOpenAPIValidator(doc)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		status         int
+		respBody       string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "ответ по спецификации",
+			path:           "/items",
+			body:           `{"name":"box"}`,
+			status:         http.StatusCreated,
+			respBody:       `{"id":1}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "запрос без обязательного поля",
+			path:           "/items",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeRequestSpec,
+		},
+		{
+			name:           "тип поля ответа не совпадает",
+			path:           "/items",
+			body:           `{"name":"box"}`,
+			status:         http.StatusCreated,
+			respBody:       `{"id":"1"}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeResponseSpec,
+		},
+		{
+			name:           "статус не описан",
+			path:           "/items",
+			body:           `{"name":"box"}`,
+			status:         http.StatusOK,
+			respBody:       `{"id":1}`,
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   CodeResponseSpec,
+		},
+		{
+			name:           "путь с префиксом версии",
+			path:           "/api/v1/items",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeRequestSpec,
+		},
+		{
+			name:           "маршрута нет в спецификации",
+			path:           "/other",
+			status:         http.StatusTeapot,
+			respBody:       `{}`,
+			expectedStatus: http.StatusTeapot,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.respBody))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			v.Middleware(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode == "" {
+				assert.JSONEq(t, tt.respBody, w.Body.String())
+				return
+			}
+
+			var body dto.Error
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			assert.Equal(t, tt.expectedCode, body.Code)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"pvz-service/internal/handler/pkg/response"
+)
+
+const (
+	ErrRateLimited  = "too many requests"
+	CodeRateLimited = "rate_limited"
+)
+
+// Как часто чистим корзины клиентов, которые давно не приходили
+const rateLimitSweepInterval = time.Minute
+
+// RateLimits отдает текущие лимиты, они могут меняться без перезапуска
+type RateLimits interface {
+	GetRateLimit() (rps float64, burst int)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	limits RateLimits
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// RateLimit ограничивает число запросов с одного адреса (token bucket).
+// При превышении отвечает 429 с заголовком Retry-After
+func RateLimit(limits RateLimits) func(http.Handler) http.Handler {
+	return newRateLimiter(limits, time.Now).middleware
+}
+
+func newRateLimiter(limits RateLimits, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		limits:  limits,
+		now:     now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := l.allow(clientIP(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			response.WriteError(w, http.StatusTooManyRequests, CodeRateLimited, ErrRateLimited)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	rps, burst := l.limits.GetRateLimit()
+	if rps <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now, rps, burst)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rps)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / rps * float64(time.Second))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// sweep удаляет корзины, которые успели бы наполниться полностью
+func (l *rateLimiter) sweep(now time.Time, rps float64, burst int) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(float64(burst) / rps * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticLimits struct {
+	rps   float64
+	burst int
+}
+
+func (l *staticLimits) GetRateLimit() (float64, int) {
+	return l.rps, l.burst
+}
+
+func TestRateLimit(t *testing.T) {
+	limits := &staticLimits{rps: 1, burst: 2}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(limits, func() time.Time { return now })
+
+	handler := limiter.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/pvz", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// burst запросов проходит сразу
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1000").Code)
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1001").Code)
+
+	w := do("10.0.0.1:1002")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), CodeRateLimited)
+
+	// у другого клиента своя корзина
+	assert.Equal(t, http.StatusOK, do("10.0.0.2:1000").Code)
+
+	// через секунду появляется один токен
+	now = now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1003").Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.1:1004").Code)
+
+	// лимит выключается без перезапуска
+	limits.rps = 0
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1005").Code)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"pvz-service/internal/handler/pkg/response"
+	"pvz-service/internal/service"
+)
+
+// Recoverer перехватывает панику в обработчике, пишет ее в лог со стектрейсом
+// и отвечает клиенту 500 вместо обрыва соединения.
+func Recoverer(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+
+				// http.ErrAbortHandler - штатный способ прервать ответ, его не логируем
+				if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(rec)
+				}
+
+				logger.ErrorContext(r.Context(), "panic recovered",
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				response.WriteError(w, http.StatusInternalServerError, service.CodeInternal, response.ErrInternalServer)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
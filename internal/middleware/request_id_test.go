@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name         string
+		headerValue  string
+		expectReuse  bool
+		expectedUUID bool
+	}{
+		{
+			name:        "reuses client id",
+			headerValue: "scanner-42-abc",
+			expectReuse: true,
+		},
+		{
+			name:         "generates id when header missing",
+			headerValue:  "",
+			expectedUUID: true,
+		},
+		{
+			name:         "replaces id with spaces",
+			headerValue:  "bad id\nwith newline",
+			expectedUUID: true,
+		},
+		{
+			name:         "replaces too long id",
+			headerValue:  strings.Repeat("a", maxRequestIDLength+1),
+			expectedUUID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.headerValue != "" {
+				req.Header.Set(RequestIDHeader, tt.headerValue)
+			}
+			rr := httptest.NewRecorder()
+
+			var ctxID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = GetRequestID(r.Context())
+			})
+
+			RequestID(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, ctxID, rr.Header().Get(RequestIDHeader))
+			if tt.expectReuse {
+				assert.Equal(t, tt.headerValue, ctxID)
+			}
+			if tt.expectedUUID {
+				_, err := uuid.Parse(ctxID)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -6,14 +6,17 @@ import (
 	"pvz-service/internal/handler/pkg/response"
 )
 
-const ErrForbidden = "forbidden"
+const (
+	ErrForbidden  = "forbidden"
+	CodeForbidden = "forbidden"
+)
 
 func RequireRoles(allowedRoles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctxRole, ok := r.Context().Value("role").(string)
 			if !ok {
-				response.WriteError(w, ErrForbidden, http.StatusForbidden)
+				response.WriteError(w, http.StatusForbidden, CodeForbidden, ErrForbidden)
 				return
 			}
 
@@ -24,7 +27,7 @@ func RequireRoles(allowedRoles ...string) func(http.Handler) http.Handler {
 				}
 			}
 
-			response.WriteError(w, ErrForbidden, http.StatusForbidden)
+			response.WriteError(w, http.StatusForbidden, CodeForbidden, ErrForbidden)
 		})
 	}
 }
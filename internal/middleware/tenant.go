@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/tenant"
+)
+
+// TenantHeader выбирает оператора в ручках без авторизации
+const TenantHeader = "X-Tenant-ID"
+
+// PublicTenant задает оператора для ручек без авторизации: из заголовка X-Tenant-ID,
+// без него - оператора по умолчанию. Неверный id отклоняет разбор параметров по спецификации
+func PublicTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := tenant.DefaultID
+		if parsed, err := uuid.Parse(r.Header.Get(TenantHeader)); err == nil && parsed != uuid.Nil {
+			id = parsed
+		}
+
+		next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), id)))
+	})
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"pvz-service/internal/tenant"
+)
+
+func TestPublicTenant(t *testing.T) {
+	other := uuid.New()
+
+	tests := []struct {
+		name           string
+		headerValue    string
+		expectedTenant uuid.UUID
+	}{
+		{
+			name:           "tenant from header",
+			headerValue:    other.String(),
+			expectedTenant: other,
+		},
+		{
+			name:           "default tenant without header",
+			expectedTenant: tenant.DefaultID,
+		},
+		{
+			name:           "default tenant for invalid header",
+			headerValue:    "not-a-uuid",
+			expectedTenant: tenant.DefaultID,
+		},
+		{
+			name:           "default tenant for nil header",
+			headerValue:    uuid.Nil.String(),
+			expectedTenant: tenant.DefaultID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uuid.UUID
+			handler := PublicTenant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = tenant.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/pvz/nearby", nil)
+			if tt.headerValue != "" {
+				req.Header.Set(TenantHeader, tt.headerValue)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedTenant, got)
+		})
+	}
+}
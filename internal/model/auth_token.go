@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Назначения одноразовых токенов
+const (
+	AuthTokenVerifyEmail   = "verify_email"
+	AuthTokenResetPassword = "reset_password"
+)
+
+// AuthToken - одноразовый токен из письма. Сам токен знает только получатель письма,
+// хранится его SHA-256
+type AuthToken struct {
+	TokenHash string
+	UserID    uuid.UUID
+	Purpose   string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,7 @@
+package model
+
+import "errors"
+
+// ErrNotFound - хранилище не нашло запись. Репозитории оборачивают в нее отсутствие строки,
+// чтобы сервис отличал "нет данных" от сбоя базы
+var ErrNotFound = errors.New("record not found")
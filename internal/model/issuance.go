@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Issuance - выдача товара покупателю
+type Issuance struct {
+	ID               uuid.UUID
+	ProductID        uuid.UUID
+	PvzID            uuid.UUID
+	IssuedAt         time.Time
+	EmployeeID       string
+	ConfirmationCode string
+}
+
+// ProductReturn - возврат ранее выданного товара в ПВЗ
+type ProductReturn struct {
+	ID         uuid.UUID
+	ProductID  uuid.UUID
+	PvzID      uuid.UUID
+	ReturnedAt time.Time
+	EmployeeID string
+	Reason     string
+}
@@ -0,0 +1,8 @@
+package model
+
+// Mail - письмо пользователю, тело в text/plain
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,70 @@
+package model
+
+import "math"
+
+// EarthRadiusMeters - средний радиус Земли для формулы гаверсинусов
+const EarthRadiusMeters = 6371008.8
+
+// NearbyQuery - поиск ПВЗ в радиусе от точки
+type NearbyQuery struct {
+	Point        GeoPoint
+	RadiusMeters float64
+	Limit        int
+}
+
+// NearbyPvz - найденный ПВЗ с расстоянием до точки поиска
+type NearbyPvz struct {
+	Pvz            Pvz
+	DistanceMeters float64
+	IsOpen         bool
+}
+
+// GeoBox - ограничивающий прямоугольник в градусах
+type GeoBox struct {
+	MinLat float64
+	MaxLat float64
+	MinLng float64
+	MaxLng float64
+}
+
+// DistanceMeters возвращает расстояние по поверхности Земли между точками
+func (p GeoPoint) DistanceMeters(to GeoPoint) float64 {
+	dLat := radians(to.Lat - p.Lat)
+	dLng := radians(to.Lng - p.Lng)
+
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(p.Lat))*math.Cos(radians(to.Lat))*math.Pow(math.Sin(dLng/2), 2)
+
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBox возвращает прямоугольник, в который попадает круг радиуса radius.
+// Если круг задевает полюс или линию перемены дат, долгота не ограничивается
+func (p GeoPoint) BoundingBox(radius float64) GeoBox {
+	delta := radius / EarthRadiusMeters * 180 / math.Pi
+
+	box := GeoBox{
+		MinLat: p.Lat - delta,
+		MaxLat: p.Lat + delta,
+		MinLng: -180,
+		MaxLng: 180,
+	}
+
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = math.Max(box.MinLat, -90)
+		box.MaxLat = math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	deltaLng := math.Asin(math.Min(1, math.Sin(radians(delta))/math.Cos(radians(p.Lat)))) * 180 / math.Pi
+	if p.Lng-deltaLng > -180 && p.Lng+deltaLng < 180 {
+		box.MinLng = p.Lng - deltaLng
+		box.MaxLng = p.Lng + deltaLng
+	}
+
+	return box
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
@@ -11,4 +11,18 @@ type Product struct {
 	DateTime    time.Time
 	TypeProduct string
 	ReceptionID uuid.UUID
+	// Необязательные данные посылки, пустые значения - не указано
+	Barcode     string
+	SKU         string
+	WeightGrams int
+	Dimensions  Dimensions
+	// CellID - ячейка хранения, uuid.Nil - товар не размещен
+	CellID uuid.UUID
+}
+
+// Dimensions - габариты посылки в миллиметрах
+type Dimensions struct {
+	LengthMM int
+	WidthMM  int
+	HeightMM int
 }
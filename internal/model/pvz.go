@@ -2,13 +2,131 @@ package model
 
 import (
 	"time"
+	// Часовые пояса ПВЗ не должны зависеть от zoneinfo в образе
+	_ "time/tzdata"
 
 	"github.com/google/uuid"
 )
 
+// DefaultTimezone - часовой пояс ПВЗ, если он не задан
+const DefaultTimezone = "Europe/Moscow"
+
+// Статусы жизненного цикла ПВЗ
+const (
+	PvzStatusActive         = "active"
+	PvzStatusSuspended      = "suspended"
+	PvzStatusDecommissioned = "decommissioned"
+)
+
 type Pvz struct {
 	ID               uuid.UUID
 	RegistrationDate time.Time
 	City             string
-	Receptions       []Reception
+	Status           string
+	Address          string
+	// Coordinates - nil, если координаты не заданы
+	Coordinates  *GeoPoint
+	Timezone     string
+	WorkingHours WorkingHours
+	// MaxDailyReceptions - сколько приемок можно открыть за день, 0 - без ограничения
+	MaxDailyReceptions int
+	// ReceptionOverrideUntil - до этого момента приемки открываются без проверки графика и лимита
+	ReceptionOverrideUntil time.Time
+	Receptions             []Reception
+}
+
+// PvzPatch - изменения профиля ПВЗ, nil-поля не меняются
+type PvzPatch struct {
+	ID                     uuid.UUID
+	Address                *string
+	Coordinates            *GeoPoint
+	Timezone               *string
+	WorkingHours           *WorkingHours
+	MaxDailyReceptions     *int
+	ReceptionOverrideUntil *time.Time
+}
+
+// ChangeStamp - отметка последнего изменения набора ПВЗ вместе с их приемками,
+// товарами, выдачами и ячейками. Count меняется при добавлении ПВЗ, даже если время совпало
+type ChangeStamp struct {
+	ChangedAt time.Time
+	Count     int
+}
+
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// IsActive сообщает, принимает ли ПВЗ новые приемки и товары
+func (p *Pvz) IsActive() bool {
+	return p.Status == PvzStatusActive
+}
+
+// CanChangeStatus проверяет переход статуса: приостановленный ПВЗ можно
+// вернуть в работу, выведенный из эксплуатации - уже нельзя
+func (p *Pvz) CanChangeStatus(to string) bool {
+	switch to {
+	case PvzStatusSuspended:
+		return p.Status == PvzStatusActive
+	case PvzStatusActive:
+		return p.Status == PvzStatusSuspended
+	case PvzStatusDecommissioned:
+		return p.Status == PvzStatusActive || p.Status == PvzStatusSuspended
+	}
+	return false
+}
+
+// Apply переносит заданные поля patch в профиль ПВЗ
+func (p *Pvz) Apply(patch PvzPatch) {
+	if patch.Address != nil {
+		p.Address = *patch.Address
+	}
+	if patch.Coordinates != nil {
+		point := *patch.Coordinates
+		p.Coordinates = &point
+	}
+	if patch.Timezone != nil {
+		p.Timezone = *patch.Timezone
+	}
+	if patch.WorkingHours != nil {
+		p.WorkingHours = *patch.WorkingHours
+	}
+	if patch.MaxDailyReceptions != nil {
+		p.MaxDailyReceptions = *patch.MaxDailyReceptions
+	}
+	if patch.ReceptionOverrideUntil != nil {
+		p.ReceptionOverrideUntil = *patch.ReceptionOverrideUntil
+	}
+}
+
+// Location возвращает часовой пояс ПВЗ
+func (p *Pvz) Location() (*time.Location, error) {
+	if p.Timezone == "" {
+		return time.LoadLocation(DefaultTimezone)
+	}
+	return time.LoadLocation(p.Timezone)
+}
+
+// Day возвращает границы местных суток ПВЗ, в которые попадает t
+func (p *Pvz) Day(t time.Time) (time.Time, time.Time, error) {
+	loc, err := p.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	local := t.In(loc)
+	begin := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	return begin, begin.AddDate(0, 0, 1), nil
+}
+
+// IsOpen проверяет, работает ли ПВЗ в момент t по его местному времени
+func (p *Pvz) IsOpen(t time.Time) (bool, error) {
+	loc, err := p.Location()
+	if err != nil {
+		return false, err
+	}
+
+	return p.WorkingHours.IsOpen(t.In(loc)), nil
 }
@@ -7,4 +7,6 @@ type PvzInfoQuery struct {
 	EndDate   time.Time
 	Page      int
 	Limit     int
+	// IncludeInactive - показывать и выведенные из эксплуатации ПВЗ
+	IncludeInactive bool
 }
@@ -6,12 +6,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// Причины закрытия приемки
+const (
+	CloseReasonManual  = "manual"
+	CloseReasonSync    = "sync"
+	CloseReasonTimeout = "timeout"
+)
+
 type Reception struct {
 	ID       uuid.UUID
 	DateTime time.Time
 	Products []Product
 	IsClosed bool // true = "closed", false = "in_progress"
 	PvzID    uuid.UUID
+	// CloseReason и ClosedAt заполняются при закрытии
+	CloseReason string
+	ClosedAt    time.Time
+	// FlaggedAt - когда приемка помечена как зависшая, RemindedAt - когда отправлено напоминание
+	FlaggedAt  time.Time
+	RemindedAt time.Time
 }
 
 func (r *Reception) Status() string {
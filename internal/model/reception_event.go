@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Типы уведомлений о зависших приемках
+const (
+	ReceptionEventReminder   = "reception.reminder"
+	ReceptionEventFlagged    = "reception.flagged"
+	ReceptionEventAutoClosed = "reception.auto_closed"
+)
+
+// ReceptionEvent - уведомление о приемке, которую долго не закрывают
+type ReceptionEvent struct {
+	Type      string
+	Reception Reception
+	// OpenFor - сколько приемка была открыта к моменту события
+	OpenFor time.Duration
+}
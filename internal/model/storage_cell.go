@@ -0,0 +1,71 @@
+package model
+
+import "github.com/google/uuid"
+
+// Размерные классы ячеек хранения по возрастанию
+const (
+	SizeSmall  = "small"
+	SizeMedium = "medium"
+	SizeLarge  = "large"
+)
+
+// Предельная длина самой длинной стороны посылки для класса, мм
+const (
+	MaxSmallSideMM  = 350
+	MaxMediumSideMM = 600
+)
+
+// StorageCell - ячейка (полка) хранения в ПВЗ
+type StorageCell struct {
+	ID        uuid.UUID
+	PvzID     uuid.UUID
+	Code      string
+	Capacity  int
+	SizeClass string
+	// Occupied - число товаров в ячейке, которые еще не выданы
+	Occupied int
+}
+
+// Free возвращает число свободных мест в ячейке
+func (c *StorageCell) Free() int {
+	if c.Occupied >= c.Capacity {
+		return 0
+	}
+	return c.Capacity - c.Occupied
+}
+
+// Fits проверяет, помещается ли в ячейку посылка размерного класса sizeClass.
+// Пустой класс (габариты не указаны) подходит к любой ячейке
+func (c *StorageCell) Fits(sizeClass string) bool {
+	return SizeRank(c.SizeClass) >= SizeRank(sizeClass)
+}
+
+// SizeClassFor определяет размерный класс посылки по самой длинной стороне.
+// Для посылки без габаритов возвращает пустую строку
+func SizeClassFor(d Dimensions) string {
+	longest := max(d.LengthMM, d.WidthMM, d.HeightMM)
+
+	switch {
+	case longest == 0:
+		return ""
+	case longest <= MaxSmallSideMM:
+		return SizeSmall
+	case longest <= MaxMediumSideMM:
+		return SizeMedium
+	}
+
+	return SizeLarge
+}
+
+// SizeRank возвращает порядковый номер размерного класса, 0 - класс не задан
+func SizeRank(sizeClass string) int {
+	switch sizeClass {
+	case SizeSmall:
+		return 1
+	case SizeMedium:
+		return 2
+	case SizeLarge:
+		return 3
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы операций офлайн-синхронизации
+const (
+	SyncOpenReception  = "open_reception"
+	SyncAddProduct     = "add_product"
+	SyncDeleteProduct  = "delete_last_product"
+	SyncCloseReception = "close_reception"
+)
+
+// SyncOperation - операция из журнала сканера.
+// ID генерирует клиент, он же становится ID созданной приемки или товара.
+// DateTime - время операции на устройстве
+type SyncOperation struct {
+	ID          uuid.UUID
+	PvzID       uuid.UUID
+	Type        string
+	DateTime    time.Time
+	TypeProduct string
+	// Данные посылки для add_product, как в POST /products. Проверяются тем же сервисом товаров
+	Barcode     string
+	SKU         string
+	WeightGrams int
+	Dimensions  Dimensions
+	CellID      uuid.UUID
+	Applied     bool
+	Code        string
+	Message     string
+}
+
+// SyncResult - итог применения журнала: состояние последней приемки ПВЗ и отклоненные операции
+type SyncResult struct {
+	PvzID     uuid.UUID
+	Reception *Reception
+	Applied   []uuid.UUID
+	Rejected  []SyncOperation
+}
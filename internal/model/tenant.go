@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant - оператор маркетплейса. Его ПВЗ, приемки, товары и подписки не видны другим операторам
+type Tenant struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	// OpenRegistration - пользователи могут сами регистрироваться у оператора через POST /register
+	OpenRegistration bool
+}
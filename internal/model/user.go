@@ -1,10 +1,17 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
 	ID       uuid.UUID
 	Email    string
 	Password string
 	Role     string
+	TenantID uuid.UUID
+	// EmailVerifiedAt - когда пользователь подтвердил почту, нулевое время - не подтверждал
+	EmailVerifiedAt time.Time
 }
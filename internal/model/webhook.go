@@ -0,0 +1,86 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий, на которые подписываются партнеры
+const (
+	WebhookEventReceptionCreated = "reception.created"
+	WebhookEventReceptionClosed  = "reception.closed"
+	WebhookEventProductReceived  = "product.received"
+	// WebhookEventTest отправляется только кнопкой "тестовое событие", фильтры подписки не учитываются
+	WebhookEventTest = "webhook.test"
+)
+
+// WebhookEventTypes - события, доступные для подписки
+var WebhookEventTypes = []string{
+	WebhookEventReceptionCreated,
+	WebhookEventReceptionClosed,
+	WebhookEventProductReceived,
+}
+
+// Статусы доставки: pending ждет попытки, delivered - получатель ответил 2xx,
+// dead - попытки исчерпаны
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookSubscription - подписка партнера на события
+type WebhookSubscription struct {
+	ID         uuid.UUID
+	URL        string
+	EventTypes []string
+	// PvzIDs - фильтр по ПВЗ, пустой список - все ПВЗ
+	PvzIDs    []uuid.UUID
+	Secret    string
+	CreatedAt time.Time
+}
+
+// Matches сообщает, нужно ли доставить подписке событие eventType из ПВЗ pvzID
+func (s *WebhookSubscription) Matches(eventType string, pvzID uuid.UUID) bool {
+	if !slices.Contains(s.EventTypes, eventType) {
+		return false
+	}
+	return len(s.PvzIDs) == 0 || slices.Contains(s.PvzIDs, pvzID)
+}
+
+// WebhookEvent - событие до отправки. Data сериализуется в поле data тела запроса
+type WebhookEvent struct {
+	ID         uuid.UUID
+	Type       string
+	PvzID      uuid.UUID
+	OccurredAt time.Time
+	Data       any
+}
+
+// WebhookDelivery - одна доставка события одной подписке
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	// ResponseStatus - HTTP статус последней попытки, 0 - ответа не было
+	ResponseStatus int
+	CreatedAt      time.Time
+	DeliveredAt    time.Time
+}
+
+// WebhookDeliveryQuery - журнал доставок подписки
+type WebhookDeliveryQuery struct {
+	SubscriptionID uuid.UUID
+	// Status - фильтр по статусу, пустой - все
+	Status string
+	Page   int
+	Limit  int
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// MinutesInDay - верхняя граница времени закрытия, 24:00
+const MinutesInDay = 24 * 60
+
+// WorkingHours - недельный график ПВЗ с исключениями на отдельные даты.
+// Пустой график означает круглосуточную работу
+type WorkingHours struct {
+	// Weekly - часы по дням недели, дня нет в карте - выходной
+	Weekly     map[time.Weekday]DayHours
+	Exceptions []HoursException
+}
+
+// DayHours - часы работы в минутах от местной полуночи, [Open, Close)
+type DayHours struct {
+	Open  int
+	Close int
+}
+
+// HoursException - особый день: праздник или сокращенные часы
+type HoursException struct {
+	// Date - дата в календаре ПВЗ, время не учитывается
+	Date   time.Time
+	Closed bool
+	Hours  DayHours
+}
+
+func (h DayHours) Contains(minute int) bool {
+	return minute >= h.Open && minute < h.Close
+}
+
+func (h DayHours) Valid() bool {
+	return h.Open >= 0 && h.Open < h.Close && h.Close <= MinutesInDay
+}
+
+// IsSet сообщает, задан ли график
+func (w WorkingHours) IsSet() bool {
+	return len(w.Weekly) > 0 || len(w.Exceptions) > 0
+}
+
+// IsOpen проверяет график в местное время local
+func (w WorkingHours) IsOpen(local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+
+	for _, exception := range w.Exceptions {
+		if sameDate(exception.Date, local) {
+			return !exception.Closed && exception.Hours.Contains(minute)
+		}
+	}
+
+	if len(w.Weekly) == 0 {
+		return true
+	}
+
+	hours, ok := w.Weekly[local.Weekday()]
+	return ok && hours.Contains(minute)
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,29 @@
+// Package notifier содержит реализации service.ReceptionNotifier
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"pvz-service/internal/model"
+)
+
+// Log пишет уведомления в лог сервиса. Используется, пока не подключен другой канал доставки
+type Log struct {
+	logger *slog.Logger
+}
+
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (n *Log) NotifyReception(ctx context.Context, event model.ReceptionEvent) error {
+	n.logger.WarnContext(ctx, "Reception is open too long",
+		"event", event.Type,
+		"reception_id", event.Reception.ID.String(),
+		"pvz_id", event.Reception.PvzID.String(),
+		"open_for", event.OpenFor.Round(time.Second).String(),
+	)
+	return nil
+}
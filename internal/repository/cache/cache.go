@@ -0,0 +1,304 @@
+// Package cache - кеширующая обертка над service.Repository для частых выборок по id:
+// ПВЗ, приемки, последней приемки ПВЗ и последнего товара приемки.
+// Записи сбрасываются точечно при изменениях, которые проходят через обертку.
+// Ключи включают оператора из контекста: одинаковый id у разных операторов - разные записи.
+// Внутри транзакции кеш не читается и не пополняется, сброшенные в ней записи сбрасываются еще раз после ее конца.
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tenant"
+)
+
+// Виды закешированных выборок, они же префиксы ключей
+const (
+	kindPvz           = "pvz"
+	kindReception     = "reception"
+	kindLastReception = "last_reception"
+	kindLastProduct   = "last_product"
+)
+
+var kinds = []string{kindPvz, kindReception, kindLastReception, kindLastProduct}
+
+// Stats - попадания и промахи одного вида выборок
+type Stats struct {
+	Hits   int64
+	Misses int64
+}
+
+type counters struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Repository кеширует выборки по id, остальные методы уходят в repo как есть.
+// Значения хранятся закодированными: вызывающий код меняет полученные структуры
+// (например, UpdatePvz), и это не должно задевать кеш
+type Repository struct {
+	service.Repository
+	backend Backend
+	ttl     time.Duration
+
+	// mu упорядочивает запись в кеш и инвалидацию. gen растет при каждой инвалидации:
+	// значение, прочитанное из repo до изменения, в кеш уже не попадет
+	mu  sync.Mutex
+	gen uint64
+	// versions - поколение ключей вида, увеличение сбрасывает весь вид сразу
+	versions map[string]uint64
+
+	stats map[string]*counters
+}
+
+func New(repo service.Repository, backend Backend, ttl time.Duration) *Repository {
+	c := &Repository{
+		Repository: repo,
+		backend:    backend,
+		ttl:        ttl,
+		versions:   make(map[string]uint64),
+		stats:      make(map[string]*counters),
+	}
+	for _, kind := range kinds {
+		c.stats[kind] = &counters{}
+	}
+	return c
+}
+
+// Stats возвращает попадания и промахи по видам выборок
+func (c *Repository) Stats() map[string]Stats {
+	out := make(map[string]Stats, len(c.stats))
+	for kind, s := range c.stats {
+		out[kind] = Stats{Hits: s.hits.Load(), Misses: s.misses.Load()}
+	}
+	return out
+}
+
+// txKeys - записи, сброшенные внутри транзакции
+type txKeys struct {
+	mu    sync.Mutex
+	keys  []key
+	kinds []string
+}
+
+type txKey struct{}
+
+// InTx выполняет fn в транзакции repo. Пока она не завершена, другие запросы видят старые данные
+// и могут вернуть их в кеш, поэтому сброшенные в транзакции записи сбрасываются повторно после нее
+func (c *Repository) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return c.Repository.InTx(ctx, fn)
+	}
+
+	pending := &txKeys{}
+	defer func() {
+		pending.mu.Lock()
+		defer pending.mu.Unlock()
+		if len(pending.keys) > 0 {
+			c.invalidate(ctx, pending.keys...)
+		}
+		for _, kind := range pending.kinds {
+			c.invalidateKind(ctx, kind)
+		}
+	}()
+
+	return c.Repository.InTx(ctx, func(ctx context.Context) error {
+		return fn(context.WithValue(ctx, txKey{}, pending))
+	})
+}
+
+func (c *Repository) GetPvzByID(ctx context.Context, id uuid.UUID) (*model.Pvz, error) {
+	return cached(ctx, c, kindPvz, id, func() (*model.Pvz, error) {
+		return c.Repository.GetPvzByID(ctx, id)
+	})
+}
+
+func (c *Repository) UpdatePvzProfile(ctx context.Context, pvz model.Pvz) error {
+	defer c.invalidate(ctx, key{kindPvz, pvz.ID})
+	return c.Repository.UpdatePvzProfile(ctx, pvz)
+}
+
+func (c *Repository) UpdatePvzStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	defer c.invalidate(ctx, key{kindPvz, id})
+	return c.Repository.UpdatePvzStatus(ctx, id, from, to)
+}
+
+func (c *Repository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*model.Reception, error) {
+	return cached(ctx, c, kindReception, id, func() (*model.Reception, error) {
+		return c.Repository.GetReceptionByID(ctx, id)
+	})
+}
+
+func (c *Repository) GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error) {
+	return cached(ctx, c, kindLastReception, pvzID, func() (*model.Reception, error) {
+		return c.Repository.GetLastReception(ctx, pvzID)
+	})
+}
+
+func (c *Repository) CreateReception(ctx context.Context, pvzID uuid.UUID) (uuid.UUID, error) {
+	defer c.invalidate(ctx, key{kindLastReception, pvzID})
+	return c.Repository.CreateReception(ctx, pvzID)
+}
+
+func (c *Repository) InsertReception(ctx context.Context, reception model.Reception) error {
+	defer c.invalidate(ctx, key{kindReception, reception.ID}, key{kindLastReception, reception.PvzID})
+	return c.Repository.InsertReception(ctx, reception)
+}
+
+func (c *Repository) CloseReception(ctx context.Context, receptionID uuid.UUID, reason string) error {
+	defer c.invalidateReception(ctx, receptionID)
+	return c.Repository.CloseReception(ctx, receptionID, reason)
+}
+
+func (c *Repository) FlagReception(ctx context.Context, receptionID uuid.UUID) error {
+	defer c.invalidateReception(ctx, receptionID)
+	return c.Repository.FlagReception(ctx, receptionID)
+}
+
+func (c *Repository) MarkReceptionReminded(ctx context.Context, receptionID uuid.UUID) error {
+	defer c.invalidateReception(ctx, receptionID)
+	return c.Repository.MarkReceptionReminded(ctx, receptionID)
+}
+
+func (c *Repository) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*model.Product, error) {
+	return cached(ctx, c, kindLastProduct, receptionID, func() (*model.Product, error) {
+		return c.Repository.GetLastProduct(ctx, receptionID)
+	})
+}
+
+func (c *Repository) CreateProduct(ctx context.Context, product model.Product) (uuid.UUID, error) {
+	defer c.invalidate(ctx, key{kindLastProduct, product.ReceptionID})
+	return c.Repository.CreateProduct(ctx, product)
+}
+
+func (c *Repository) InsertProduct(ctx context.Context, product model.Product) error {
+	defer c.invalidate(ctx, key{kindLastProduct, product.ReceptionID})
+	return c.Repository.InsertProduct(ctx, product)
+}
+
+func (c *Repository) DeleteProductByID(ctx context.Context, id uuid.UUID) error {
+	// Приемку товара узнаем до удаления, потом его уже не найти
+	product, err := c.Repository.GetProductByID(ctx, id)
+	if err != nil {
+		defer c.invalidateKind(ctx, kindLastProduct)
+	} else {
+		defer c.invalidate(ctx, key{kindLastProduct, product.ReceptionID})
+	}
+	return c.Repository.DeleteProductByID(ctx, id)
+}
+
+// DeleteStorageCell отвязывает товары от ячейки, какие из них закешированы - неизвестно
+func (c *Repository) DeleteStorageCell(ctx context.Context, id uuid.UUID) error {
+	defer c.invalidateKind(ctx, kindLastProduct)
+	return c.Repository.DeleteStorageCell(ctx, id)
+}
+
+type key struct {
+	kind string
+	id   uuid.UUID
+}
+
+// cached отдает значение из кеша или загружает его через load и кладет в кеш.
+// Ошибки, в том числе "не найдено", не кешируются. Без оператора в контексте и в транзакции
+// кеш не используется: в транзакции видны ее незафиксированные изменения
+func cached[T any](ctx context.Context, c *Repository, kind string, id uuid.UUID, load func() (*T, error)) (*T, error) {
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok || ctx.Value(txKey{}) != nil {
+		return load()
+	}
+
+	c.mu.Lock()
+	k, gen := c.keyString(tenantID, key{kind, id}), c.gen
+	c.mu.Unlock()
+
+	if data, ok := c.backend.Get(ctx, k); ok {
+		var value T
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err == nil {
+			c.stats[kind].hits.Add(1)
+			return &value, nil
+		}
+	}
+	c.stats[kind].misses.Add(1)
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(value); err != nil {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen == gen {
+		c.backend.Set(ctx, k, buf.Bytes(), c.ttl)
+	}
+
+	return value, nil
+}
+
+// invalidate удаляет записи после изменения данных
+func (c *Repository) invalidate(ctx context.Context, keys ...key) {
+	if pending, ok := ctx.Value(txKey{}).(*txKeys); ok {
+		pending.mu.Lock()
+		pending.keys = append(pending.keys, keys...)
+		pending.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	tenantID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, c.keyString(tenantID, k))
+	}
+	c.backend.Delete(ctx, out...)
+}
+
+// invalidateKind сбрасывает все записи вида: старые ключи больше не читаются и вытесняются сами
+func (c *Repository) invalidateKind(ctx context.Context, kind string) {
+	if pending, ok := ctx.Value(txKey{}).(*txKeys); ok {
+		pending.mu.Lock()
+		pending.kinds = append(pending.kinds, kind)
+		pending.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.versions[kind]++
+}
+
+// invalidateReception сбрасывает приемку и последнюю приемку ее ПВЗ.
+// ПВЗ приемки не меняется, поэтому его можно брать и из кеша
+func (c *Repository) invalidateReception(ctx context.Context, receptionID uuid.UUID) {
+	reception, err := c.GetReceptionByID(ctx, receptionID)
+	if err != nil {
+		c.invalidate(ctx, key{kindReception, receptionID})
+		c.invalidateKind(ctx, kindLastReception)
+		return
+	}
+	c.invalidate(ctx, key{kindReception, receptionID}, key{kindLastReception, reception.PvzID})
+}
+
+// keyString строит ключ бэкенда. Вызывать под c.mu
+func (c *Repository) keyString(tenantID uuid.UUID, k key) string {
+	return fmt.Sprintf("%s:%d:%s:%s", k.kind, c.versions[k.kind], tenantID, k.id)
+}
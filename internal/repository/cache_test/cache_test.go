@@ -0,0 +1,294 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/cache"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tenant"
+)
+
+func newCache() *cache.Repository {
+	return cache.New(repository.NewMemoryRepository(memdb.NewStorage()), cache.NewLRU(100), time.Minute)
+}
+
+func TestCacheRepository_Stats(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := newCache()
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	first, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+
+	// Полученную структуру меняют вызывающие, кеш это не задевает
+	first.City = "Казань"
+	second, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, "Москва", second.City)
+
+	// Ошибки не кешируются
+	_, err = repo.GetLastReception(ctx, pvzID)
+	assert.Error(t, err)
+	_, err = repo.GetLastReception(ctx, pvzID)
+	assert.Error(t, err)
+
+	stats := repo.Stats()
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 1}, stats["pvz"])
+	assert.Equal(t, cache.Stats{Hits: 0, Misses: 2}, stats["last_reception"])
+}
+
+// После каждого изменения через обертку выборки видят новый статус приемки и ПВЗ
+func TestCacheRepository_Invalidation(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := newCache()
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	last, err := repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.False(t, last.IsClosed)
+	byID, err := repo.GetReceptionByID(ctx, receptionID)
+	require.NoError(t, err)
+	assert.False(t, byID.IsClosed)
+
+	require.NoError(t, repo.FlagReception(ctx, receptionID))
+	byID, err = repo.GetReceptionByID(ctx, receptionID)
+	require.NoError(t, err)
+	assert.False(t, byID.FlaggedAt.IsZero())
+
+	cellID, err := repo.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 5, SizeClass: model.SizeSmall})
+	require.NoError(t, err)
+	firstID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID})
+	require.NoError(t, err)
+	_, err = repo.GetLastProduct(ctx, receptionID)
+	require.NoError(t, err)
+
+	secondID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "одежда", ReceptionID: receptionID, CellID: cellID})
+	require.NoError(t, err)
+	product, err := repo.GetLastProduct(ctx, receptionID)
+	require.NoError(t, err)
+	assert.Equal(t, secondID, product.ID)
+	assert.Equal(t, cellID, product.CellID)
+
+	require.NoError(t, repo.DeleteStorageCell(ctx, cellID))
+	product, err = repo.GetLastProduct(ctx, receptionID)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, product.CellID)
+
+	require.NoError(t, repo.DeleteProductByID(ctx, secondID))
+	product, err = repo.GetLastProduct(ctx, receptionID)
+	require.NoError(t, err)
+	assert.Equal(t, firstID, product.ID)
+
+	require.NoError(t, repo.CloseReception(ctx, receptionID, model.CloseReasonManual))
+	last, err = repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.True(t, last.IsClosed)
+	byID, err = repo.GetReceptionByID(ctx, receptionID)
+	require.NoError(t, err)
+	assert.True(t, byID.IsClosed)
+
+	nextID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	last, err = repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, nextID, last.ID)
+	assert.False(t, last.IsClosed)
+
+	require.NoError(t, repo.UpdatePvzStatus(ctx, pvzID, model.PvzStatusActive, model.PvzStatusSuspended))
+	pvz, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PvzStatusSuspended, pvz.Status)
+
+	pvz.Address = "ул. Тверская, 1"
+	require.NoError(t, repo.UpdatePvzProfile(ctx, *pvz))
+	pvz, err = repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, "ул. Тверская, 1", pvz.Address)
+}
+
+// slowLastReception останавливает чтение последней приемки после запроса к хранилищу,
+// чтобы закрытие приемки успело пройти между чтением и записью в кеш
+type slowLastReception struct {
+	service.Repository
+	loaded  chan struct{}
+	proceed chan struct{}
+}
+
+func (r *slowLastReception) GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error) {
+	reception, err := r.Repository.GetLastReception(ctx, pvzID)
+	if r.loaded != nil {
+		close(r.loaded)
+		<-r.proceed
+		r.loaded = nil
+	}
+	return reception, err
+}
+
+func TestCacheRepository_ConcurrentClose(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	inner := &slowLastReception{Repository: repository.NewMemoryRepository(memdb.NewStorage())}
+	repo := cache.New(inner, cache.NewLRU(100), time.Minute)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	inner.loaded, inner.proceed = make(chan struct{}), make(chan struct{})
+	done := make(chan *model.Reception)
+	go func() {
+		reception, _ := repo.GetLastReception(ctx, pvzID)
+		done <- reception
+	}()
+
+	<-inner.loaded
+	require.NoError(t, repo.CloseReception(ctx, receptionID, model.CloseReasonManual))
+	close(inner.proceed)
+
+	// Запрос, начатый до закрытия, видит открытую приемку, но в кеш ее не кладет
+	assert.False(t, (<-done).IsClosed)
+
+	last, err := repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.True(t, last.IsClosed)
+}
+
+// uncommittedTx имитирует транзакцию Postgres: пока идет beforeCommit, изменения транзакции
+// еще не видны другим и чтение последней приемки отдает snapshot - состояние до нее
+type uncommittedTx struct {
+	service.Repository
+	snapshot     *model.Reception
+	beforeCommit func()
+	committing   bool
+}
+
+func (r *uncommittedTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	r.committing = true
+	r.beforeCommit()
+	r.committing = false
+	return nil
+}
+
+func (r *uncommittedTx) GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error) {
+	if r.committing {
+		snapshot := *r.snapshot
+		return &snapshot, nil
+	}
+	return r.Repository.GetLastReception(ctx, pvzID)
+}
+
+// Запись, прочитанная другим запросом до фиксации транзакции, сбрасывается после нее
+func TestCacheRepository_Transaction(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	inner := &uncommittedTx{Repository: repository.NewMemoryRepository(memdb.NewStorage())}
+	repo := cache.New(inner, cache.NewLRU(100), time.Minute)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	open, err := repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	inner.snapshot = open
+	inner.beforeCommit = func() {
+		reception, err := repo.GetLastReception(ctx, pvzID)
+		require.NoError(t, err)
+		assert.False(t, reception.IsClosed)
+	}
+
+	err = repo.InTx(ctx, func(ctx context.Context) error {
+		// В транзакции кеш не читается
+		if _, err := repo.GetLastReception(ctx, pvzID); err != nil {
+			return err
+		}
+		return repo.CloseReception(ctx, receptionID, model.CloseReasonManual)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, cache.Stats{Hits: 0, Misses: 2}, repo.Stats()["last_reception"])
+
+	last, err := repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.True(t, last.IsClosed)
+}
+
+// Одинаковый id у разных операторов - разные записи кеша: чужой ПВЗ не отдается даже из кеша
+func TestCacheRepository_TenantKeys(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := newCache()
+
+	other, err := repo.CreateTenant(ctx, "acme")
+	require.NoError(t, err)
+	otherCtx := tenant.WithID(context.Background(), other.ID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	_, err = repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+
+	_, err = repo.GetPvzByID(otherCtx, pvzID)
+	assert.Error(t, err)
+	_, err = repo.GetPvzByID(context.Background(), pvzID)
+	assert.Error(t, err)
+
+	_, err = repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, cache.Stats{Hits: 1, Misses: 2}, repo.Stats()["pvz"])
+}
+
+type testWebhookConfig struct{}
+
+func (testWebhookConfig) GetDeliveryTimeout() time.Duration     { return time.Second }
+func (testWebhookConfig) GetDeliveryWorkers() int               { return 1 }
+func (testWebhookConfig) GetDeliveryPassTimeout() time.Duration { return time.Minute }
+func (testWebhookConfig) GetMaxAttempts() int                   { return 1 }
+func (testWebhookConfig) GetRetryBackoff() (time.Duration, time.Duration) {
+	return time.Second, time.Second
+}
+func (testWebhookConfig) GetAllowPrivateTargets() bool { return true }
+
+// Сценарий сканера: после закрытия приемки товар в нее не добавить, хотя открытая приемка была в кеше
+func TestCacheRepository_ClosedReceptionRejectsProducts(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	serv := service.NewService(newCache(), "secret", testWebhookConfig{})
+
+	pvz, err := serv.AddNewPvz(ctx, model.Pvz{City: "Москва"})
+	require.NoError(t, err)
+	_, err = serv.CreateReception(ctx, model.Reception{PvzID: pvz.ID})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = serv.AddProduct(ctx, model.Product{TypeProduct: "обувь"}, *pvz)
+		require.NoError(t, err)
+	}
+
+	_, err = serv.CloseReception(ctx, model.Reception{PvzID: pvz.ID})
+	require.NoError(t, err)
+
+	_, err = serv.AddProduct(ctx, model.Product{TypeProduct: "обувь"}, *pvz)
+	var serviceErr *service.Error
+	require.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, service.CodeReceptionAlreadyClosed, serviceErr.Code)
+
+	err = serv.DeleteProduct(ctx, *pvz)
+	require.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, service.CodeReceptionAlreadyClosed, serviceErr.Code)
+}
@@ -0,0 +1,19 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/cache"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/repository/repotest"
+	"pvz-service/internal/service"
+)
+
+// Обертка не должна менять поведение хранилища
+func TestCacheRepository_Conformance(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) service.Repository {
+		return cache.New(repository.NewMemoryRepository(memdb.NewStorage()), cache.NewLRU(100), time.Minute)
+	})
+}
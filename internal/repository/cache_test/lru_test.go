@@ -0,0 +1,57 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"pvz-service/internal/repository/cache"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("вытесняется давно не использованная запись", func(t *testing.T) {
+		lru := cache.NewLRU(2)
+		lru.Set(ctx, "a", []byte("1"), time.Minute)
+		lru.Set(ctx, "b", []byte("2"), time.Minute)
+
+		_, ok := lru.Get(ctx, "a")
+		assert.True(t, ok)
+
+		lru.Set(ctx, "c", []byte("3"), time.Minute)
+		assert.Equal(t, 2, lru.Len())
+
+		_, ok = lru.Get(ctx, "b")
+		assert.False(t, ok)
+		value, ok := lru.Get(ctx, "a")
+		assert.True(t, ok)
+		assert.Equal(t, []byte("1"), value)
+	})
+
+	t.Run("запись истекает через ttl", func(t *testing.T) {
+		lru := cache.NewLRU(2)
+		lru.Set(ctx, "a", []byte("1"), 10*time.Millisecond)
+		lru.Set(ctx, "b", []byte("2"), time.Minute)
+
+		time.Sleep(20 * time.Millisecond)
+
+		_, ok := lru.Get(ctx, "a")
+		assert.False(t, ok)
+		_, ok = lru.Get(ctx, "b")
+		assert.True(t, ok)
+		assert.Equal(t, 1, lru.Len())
+	})
+
+	t.Run("удаление", func(t *testing.T) {
+		lru := cache.NewLRU(2)
+		lru.Set(ctx, "a", []byte("1"), time.Minute)
+		lru.Set(ctx, "a", []byte("2"), time.Minute)
+		lru.Delete(ctx, "a", "missing")
+
+		_, ok := lru.Get(ctx, "a")
+		assert.False(t, ok)
+		assert.Zero(t, lru.Len())
+	})
+}
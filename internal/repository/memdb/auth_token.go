@@ -0,0 +1,79 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateAuthToken = "failed to create auth token"
+	AuthTokenNotFound     = "auth token not found, expired or already used"
+	duplicateAuthToken    = "duplicate key value violates unique constraint on token_hash"
+)
+
+// AuthTokenRepository хранит одноразовые токены из писем в корневом Storage, рядом с пользователями
+type AuthTokenRepository struct {
+	storage *Storage
+}
+
+func NewAuthTokenRepository(storage *Storage) *AuthTokenRepository {
+	return &AuthTokenRepository{
+		storage: storage,
+	}
+}
+
+func (r *AuthTokenRepository) CreateAuthToken(_ context.Context, token model.AuthToken) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.authTokens[token.TokenHash]; ok {
+		return fmt.Errorf("%s: %s", FailedCreateAuthToken, duplicateAuthToken)
+	}
+	// Аналог внешнего ключа auth_token -> users
+	if _, ok := r.storage.users[token.UserID]; !ok {
+		return fmt.Errorf("%s: %s", FailedCreateAuthToken, UserNotFound)
+	}
+
+	r.storage.authTokens[token.TokenHash] = model.AuthToken{
+		TokenHash: token.TokenHash,
+		UserID:    token.UserID,
+		Purpose:   token.Purpose,
+		ExpiresAt: token.ExpiresAt.UTC().Truncate(time.Microsecond),
+		CreatedAt: r.storage.now(),
+	}
+
+	return nil
+}
+
+func (r *AuthTokenRepository) UseAuthToken(_ context.Context, purpose, tokenHash string, now time.Time) (*model.AuthToken, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	token, ok := r.storage.authTokens[tokenHash]
+	if !ok || token.Purpose != purpose || !token.UsedAt.IsZero() || !token.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%s: %w", AuthTokenNotFound, model.ErrNotFound)
+	}
+
+	token.UsedAt = now.UTC().Truncate(time.Microsecond)
+	r.storage.authTokens[tokenHash] = token
+
+	return &token, nil
+}
+
+func (r *AuthTokenRepository) UseUserAuthTokens(_ context.Context, userID uuid.UUID, purpose string, now time.Time) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	for hash, token := range r.storage.authTokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt.IsZero() {
+			token.UsedAt = now.UTC().Truncate(time.Microsecond)
+			r.storage.authTokens[hash] = token
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateIssuance = "failed to create issuance"
+	FailedCreateReturn   = "failed to create product return"
+	IssuanceNotFound     = "issuance not found"
+	ReturnNotFound       = "product return not found"
+)
+
+type IssuanceRepository struct {
+	storage *Storage
+}
+
+func NewIssuanceRepository(storage *Storage) *IssuanceRepository {
+	return &IssuanceRepository{
+		storage: storage,
+	}
+}
+
+func (r *IssuanceRepository) CreateIssuance(ctx context.Context, issuance model.Issuance) (*model.Issuance, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Аналог fk_issuance_product_id и fk_issuance_pvz_id
+	if !s.productAndPvzExist(issuance.ProductID, issuance.PvzID) {
+		return nil, fmt.Errorf(FailedCreateIssuance)
+	}
+
+	issuance.ID = uuid.New()
+	issuance.IssuedAt = s.now()
+	s.issuances = append(s.issuances, issuance)
+	s.touchPvz(issuance.PvzID)
+
+	return &issuance, nil
+}
+
+func (r *IssuanceRepository) CreateReturn(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.productAndPvzExist(ret.ProductID, ret.PvzID) {
+		return nil, fmt.Errorf(FailedCreateReturn)
+	}
+
+	ret.ID = uuid.New()
+	ret.ReturnedAt = s.now()
+	s.returns = append(s.returns, ret)
+	s.touchPvz(ret.PvzID)
+
+	return &ret, nil
+}
+
+// LockProduct только проверяет, что товар есть: транзакции Transactor и так идут по очереди
+func (r *IssuanceRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.products[productID]; !ok {
+		return fmt.Errorf("%s: %w", productNotFound, model.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *IssuanceRepository) GetLastIssuance(ctx context.Context, productID uuid.UUID) (*model.Issuance, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.issuances) - 1; i >= 0; i-- {
+		if issuance := s.issuances[i]; issuance.ProductID == productID {
+			return &issuance, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", IssuanceNotFound, model.ErrNotFound)
+}
+
+func (r *IssuanceRepository) GetLastReturn(ctx context.Context, productID uuid.UUID) (*model.ProductReturn, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.returns) - 1; i >= 0; i-- {
+		if ret := s.returns[i]; ret.ProductID == productID {
+			return &ret, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", ReturnNotFound, model.ErrNotFound)
+}
+
+func (r *IssuanceRepository) GetStock(ctx context.Context, pvzID uuid.UUID) ([]model.Product, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	balance := s.issuanceBalance()
+
+	var result []model.Product
+	for _, id := range s.prodOrder {
+		product := s.products[id]
+		reception := s.receptions[product.ReceptionID]
+		if reception.PvzID == pvzID && reception.IsClosed && balance[id] == 0 {
+			result = append(result, product)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateTime.Before(result[j].DateTime)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,195 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/tenant"
+)
+
+const (
+	NoRowsAffected = "no rows affected"
+	TenantNotSet   = "tenant is not set in context"
+	TenantNotFound = "tenant not found"
+)
+
+// Storage - общее хранилище для всех in-memory репозиториев.
+// Повторяет ограничения схемы Postgres: уникальность email,
+// внешние ключи reception -> pvz, product -> reception и storage_cell, sync_operation -> pvz,
+// issuance и product_return -> product и pvz, storage_cell -> pvz,
+// webhook_delivery -> webhook_subscription, auth_token -> users.
+// Пользователи и операторы хранятся в корневом Storage, данные каждого оператора - в своем
+// (аналог RLS в Postgres): репозитории берут его через tenant и чужих записей не видят
+type Storage struct {
+	mu sync.RWMutex
+	// txMu выстраивает транзакции Transactor в очередь, см. InTx
+	txMu sync.Mutex
+
+	users       map[uuid.UUID]model.User
+	emails      map[string]uuid.UUID
+	authTokens  map[string]model.AuthToken
+	tenants     map[uuid.UUID]model.Tenant
+	tenantOrder []uuid.UUID
+	shards      map[uuid.UUID]*Storage
+
+	pvzs       map[uuid.UUID]model.Pvz
+	pvzOrder   []uuid.UUID
+	receptions map[uuid.UUID]model.Reception
+	recOrder   []uuid.UUID
+	products   map[uuid.UUID]model.Product
+	prodOrder  []uuid.UUID
+	syncOps    map[syncKey]model.SyncOperation
+	// Выдачи и возвраты в порядке записи, время в них строго возрастает
+	issuances []model.Issuance
+	returns   []model.ProductReturn
+	cells     map[uuid.UUID]model.StorageCell
+	webhooks  map[uuid.UUID]model.WebhookSubscription
+	// Доставки отдаются в журнал по created_at, порядок записи совпадает с ним
+	deliveries    map[uuid.UUID]model.WebhookDelivery
+	deliveryOrder []uuid.UUID
+	// Аналог pvz.changed_at, который в Postgres обновляют триггеры
+	pvzChanged map[uuid.UUID]time.Time
+
+	lastTime time.Time
+}
+
+func NewStorage() *Storage {
+	s := &Storage{
+		users:      make(map[uuid.UUID]model.User),
+		emails:     make(map[string]uuid.UUID),
+		authTokens: make(map[string]model.AuthToken),
+		tenants:    make(map[uuid.UUID]model.Tenant),
+		shards:     make(map[uuid.UUID]*Storage),
+	}
+
+	// Как в миграции 00016
+	s.addTenant(model.Tenant{ID: tenant.DefaultID, Name: "default", OpenRegistration: true})
+	return s
+}
+
+func newShard() *Storage {
+	return &Storage{
+		pvzs:       make(map[uuid.UUID]model.Pvz),
+		receptions: make(map[uuid.UUID]model.Reception),
+		products:   make(map[uuid.UUID]model.Product),
+		syncOps:    make(map[syncKey]model.SyncOperation),
+		cells:      make(map[uuid.UUID]model.StorageCell),
+		webhooks:   make(map[uuid.UUID]model.WebhookSubscription),
+		deliveries: make(map[uuid.UUID]model.WebhookDelivery),
+		pvzChanged: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// CheckReadiness - хранилище в памяти всегда готово
+func (s *Storage) CheckReadiness(_ context.Context) error {
+	return nil
+}
+
+// tenant возвращает хранилище оператора из контекста
+func (s *Storage) tenant(ctx context.Context) (*Storage, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf(TenantNotSet)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shard, ok := s.shards[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s", TenantNotFound, id)
+	}
+	return shard, nil
+}
+
+// addTenant регистрирует оператора и заводит ему хранилище. Вызывать под s.mu.
+func (s *Storage) addTenant(t model.Tenant) {
+	t.CreatedAt = s.now()
+	s.tenants[t.ID] = t
+	s.tenantOrder = append(s.tenantOrder, t.ID)
+	s.shards[t.ID] = newShard()
+}
+
+// now возвращает аналог NOW() для TIMESTAMP: UTC с точностью до микросекунды.
+// Время строго возрастает, чтобы сортировка по date_time была однозначной,
+// как у последовательных запросов в Postgres. Вызывать под s.mu.
+func (s *Storage) now() time.Time {
+	t := time.Now().UTC().Truncate(time.Microsecond)
+	if !t.After(s.lastTime) {
+		t = s.lastTime.Add(time.Microsecond)
+	}
+	s.lastTime = t
+	return t
+}
+
+// touchPvz отмечает изменение ПВЗ или его приемок, товаров, выдач и ячеек. Вызывать под s.mu.
+func (s *Storage) touchPvz(id uuid.UUID) {
+	if _, ok := s.pvzs[id]; ok {
+		s.pvzChanged[id] = s.now()
+	}
+}
+
+// touchPvzByReception отмечает изменение ПВЗ, которому принадлежит приемка. Вызывать под s.mu.
+func (s *Storage) touchPvzByReception(receptionID uuid.UUID) {
+	if reception, ok := s.receptions[receptionID]; ok {
+		s.touchPvz(reception.PvzID)
+	}
+}
+
+func removeID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
+
+// barcodeScanned проверяет, есть ли уже товар с тем же штрихкодом в приемке.
+// Вызывать под s.mu.
+func (s *Storage) barcodeScanned(product model.Product) bool {
+	if product.Barcode == "" {
+		return false
+	}
+	for _, p := range s.products {
+		if p.ReceptionID == product.ReceptionID && p.Barcode == product.Barcode {
+			return true
+		}
+	}
+	return false
+}
+
+// cellExists проверяет необязательный внешний ключ product -> storage_cell. Вызывать под s.mu.
+func (s *Storage) cellExists(id uuid.UUID) bool {
+	if id == uuid.Nil {
+		return true
+	}
+	_, ok := s.cells[id]
+	return ok
+}
+
+// productAndPvzExist проверяет внешние ключи выдачи и возврата. Вызывать под s.mu.
+func (s *Storage) productAndPvzExist(productID, pvzID uuid.UUID) bool {
+	if _, ok := s.products[productID]; !ok {
+		return false
+	}
+	_, ok := s.pvzs[pvzID]
+	return ok
+}
+
+// issuanceBalance возвращает для товаров разницу числа выдач и возвратов,
+// 0 - товар на складе. Вызывать под s.mu.
+func (s *Storage) issuanceBalance() map[uuid.UUID]int {
+	balance := make(map[uuid.UUID]int)
+	for _, issuance := range s.issuances {
+		balance[issuance.ProductID]++
+	}
+	for _, ret := range s.returns {
+		balance[ret.ProductID]--
+	}
+	return balance
+}
@@ -0,0 +1,184 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateProduct = "failed to Create Product"
+	productNotFound     = "product not found"
+)
+
+type ProductRepository struct {
+	storage *Storage
+}
+
+func NewProductRepository(storage *Storage) *ProductRepository {
+	return &ProductRepository{
+		storage: storage,
+	}
+}
+
+func (r *ProductRepository) CreateProduct(ctx context.Context, product model.Product) (uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Аналог fk_reception_id, fk_product_cell_id и uq_product_reception_id_barcode
+	if _, ok := s.receptions[product.ReceptionID]; !ok || !s.cellExists(product.CellID) {
+		return uuid.Nil, fmt.Errorf(FailedCreateProduct)
+	}
+	if s.barcodeScanned(product) {
+		return uuid.Nil, fmt.Errorf(FailedCreateProduct)
+	}
+
+	product.ID = uuid.New()
+	product.DateTime = s.now()
+	s.products[product.ID] = product
+	s.prodOrder = append(s.prodOrder, product.ID)
+	s.touchPvzByReception(product.ReceptionID)
+
+	return product.ID, nil
+}
+
+// InsertProduct сохраняет товар с ID и временем, заданными клиентом (офлайн-синхронизация)
+func (r *ProductRepository) InsertProduct(ctx context.Context, product model.Product) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.receptions[product.ReceptionID]; !ok || !s.cellExists(product.CellID) {
+		return fmt.Errorf(FailedCreateProduct)
+	}
+	if _, ok := s.products[product.ID]; ok || s.barcodeScanned(product) {
+		return fmt.Errorf(FailedCreateProduct)
+	}
+
+	s.products[product.ID] = product
+	s.prodOrder = append(s.prodOrder, product.ID)
+	s.touchPvzByReception(product.ReceptionID)
+
+	return nil
+}
+
+func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	product, ok := s.products[id]
+	if !ok {
+		return nil, fmt.Errorf(productNotFound)
+	}
+
+	return &product, nil
+}
+
+func (r *ProductRepository) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*model.Product, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var last *model.Product
+	for _, id := range s.prodOrder {
+		product := s.products[id]
+		if product.ReceptionID != receptionID {
+			continue
+		}
+		if last == nil || product.DateTime.After(last.DateTime) {
+			last = &product
+		}
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf(productNotFound)
+	}
+
+	return last, nil
+}
+
+func (r *ProductRepository) GetProductsByBarcode(ctx context.Context, barcode string) ([]model.Product, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.Product
+	for _, id := range s.prodOrder {
+		product := s.products[id]
+		if product.Barcode == barcode {
+			result = append(result, product)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateTime.After(result[j].DateTime)
+	})
+
+	return result, nil
+}
+
+func (r *ProductRepository) DeleteProductByID(ctx context.Context, id uuid.UUID) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, ok := s.products[id]
+	if !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	delete(s.products, id)
+	s.touchPvzByReception(product.ReceptionID)
+	s.prodOrder = removeID(s.prodOrder, id)
+
+	return nil
+}
+
+func (r *ProductRepository) GetProductSliceByReceptionID(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.Product
+	for _, id := range s.prodOrder {
+		product := s.products[id]
+		if product.ReceptionID == receptionID {
+			result = append(result, product)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,240 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	PvzNotFound        = "pvz not found"
+	FailedUpdatePvz    = "failed to update Pvz"
+	FailedUpdateStatus = "failed to update Pvz status"
+)
+
+type PVZRepository struct {
+	storage *Storage
+}
+
+func NewPVZRepository(storage *Storage) *PVZRepository {
+	return &PVZRepository{
+		storage: storage,
+	}
+}
+
+func (r *PVZRepository) CreatePvz(ctx context.Context, city string) (uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := uuid.New()
+	s.pvzs[id] = model.Pvz{
+		ID:               id,
+		RegistrationDate: s.now(),
+		City:             city,
+		Status:           model.PvzStatusActive,
+		Timezone:         model.DefaultTimezone,
+	}
+	s.pvzOrder = append(s.pvzOrder, id)
+	s.touchPvz(id)
+
+	return id, nil
+}
+
+func (r *PVZRepository) GetPvzByID(ctx context.Context, id uuid.UUID) (*model.Pvz, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pvz, ok := s.pvzs[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", PvzNotFound, model.ErrNotFound)
+	}
+
+	return clonePvz(pvz), nil
+}
+
+// UpdatePvzProfile сохраняет адрес, координаты, график и лимиты ПВЗ
+func (r *PVZRepository) UpdatePvzProfile(ctx context.Context, pvz model.Pvz) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.pvzs[pvz.ID]
+	if !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	// Аналог CHECK-ограничений таблицы pvz
+	if pvz.MaxDailyReceptions < 0 {
+		return fmt.Errorf(FailedUpdatePvz)
+	}
+
+	updated := clonePvz(pvz)
+	stored.Address = updated.Address
+	stored.Coordinates = updated.Coordinates
+	stored.Timezone = updated.Timezone
+	stored.WorkingHours = updated.WorkingHours
+	stored.MaxDailyReceptions = updated.MaxDailyReceptions
+	stored.ReceptionOverrideUntil = updated.ReceptionOverrideUntil.UTC().Truncate(time.Microsecond)
+	s.pvzs[pvz.ID] = stored
+	s.touchPvz(pvz.ID)
+
+	return nil
+}
+
+// clonePvz копирует ПВЗ вместе с картой графика, чтобы вызывающий не менял хранилище
+func clonePvz(pvz model.Pvz) *model.Pvz {
+	if pvz.Coordinates != nil {
+		point := *pvz.Coordinates
+		pvz.Coordinates = &point
+	}
+
+	if pvz.WorkingHours.Weekly != nil {
+		weekly := make(map[time.Weekday]model.DayHours, len(pvz.WorkingHours.Weekly))
+		for day, hours := range pvz.WorkingHours.Weekly {
+			weekly[day] = hours
+		}
+		pvz.WorkingHours.Weekly = weekly
+	}
+	pvz.WorkingHours.Exceptions = append([]model.HoursException(nil), pvz.WorkingHours.Exceptions...)
+
+	return &pvz
+}
+
+// UpdatePvzStatus меняет статус ПВЗ, только если он все еще равен from
+func (r *PVZRepository) UpdatePvzStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pvz, ok := s.pvzs[id]
+	if !ok || pvz.Status != from {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	// Аналог CHECK-ограничения колонки status
+	switch to {
+	case model.PvzStatusActive, model.PvzStatusSuspended, model.PvzStatusDecommissioned:
+	default:
+		return fmt.Errorf(FailedUpdateStatus)
+	}
+
+	pvz.Status = to
+	s.pvzs[id] = pvz
+	s.touchPvz(id)
+
+	return nil
+}
+
+// GetNearbyPvz ищет ПВЗ с координатами в радиусе от точки, кроме выведенных из эксплуатации, ближайшие первыми
+func (r *PVZRepository) GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]model.NearbyPvz, 0, nearby.Limit)
+	for _, id := range s.pvzOrder {
+		pvz := s.pvzs[id]
+		if pvz.Coordinates == nil || pvz.Status == model.PvzStatusDecommissioned {
+			continue
+		}
+
+		distance := nearby.Point.DistanceMeters(*pvz.Coordinates)
+		if distance > nearby.RadiusMeters {
+			continue
+		}
+
+		result = append(result, model.NearbyPvz{Pvz: *clonePvz(pvz), DistanceMeters: distance})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].DistanceMeters != result[j].DistanceMeters {
+			return result[i].DistanceMeters < result[j].DistanceMeters
+		}
+		return result[i].Pvz.ID.String() < result[j].Pvz.ID.String()
+	})
+
+	if len(result) > nearby.Limit {
+		result = result[:nearby.Limit]
+	}
+
+	return result, nil
+}
+
+func (r *PVZRepository) GetIDListPvz(ctx context.Context) ([]uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]uuid.UUID, 0, len(s.pvzOrder))
+	result = append(result, s.pvzOrder...)
+
+	return result, nil
+}
+
+// GetPvzChangeStamp возвращает время последнего изменения ПВЗ и его данных
+func (r *PVZRepository) GetPvzChangeStamp(ctx context.Context, id uuid.UUID) (model.ChangeStamp, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return model.ChangeStamp{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	changedAt, ok := s.pvzChanged[id]
+	if !ok {
+		return model.ChangeStamp{}, fmt.Errorf("%s: %w", PvzNotFound, model.ErrNotFound)
+	}
+
+	return model.ChangeStamp{ChangedAt: changedAt, Count: 1}, nil
+}
+
+// GetPvzListChangeStamp возвращает отметку изменения всех ПВЗ для ETag списка
+func (r *PVZRepository) GetPvzListChangeStamp(ctx context.Context) (model.ChangeStamp, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return model.ChangeStamp{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stamp := model.ChangeStamp{Count: len(s.pvzChanged)}
+	for _, changedAt := range s.pvzChanged {
+		if changedAt.After(stamp.ChangedAt) {
+			stamp.ChangedAt = changedAt
+		}
+	}
+
+	return stamp, nil
+}
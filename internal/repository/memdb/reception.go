@@ -0,0 +1,269 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateReception = "failed to Create Reception"
+	ReceptionNotFound     = "reception not found"
+	FailedUpdateReception = "failed to update reception"
+)
+
+type ReceptionRepository struct {
+	storage *Storage
+}
+
+func NewReceptionRepository(storage *Storage) *ReceptionRepository {
+	return &ReceptionRepository{
+		storage: storage,
+	}
+}
+
+func (r *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UUID) (uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Аналог fk_pvz_id
+	if _, ok := s.pvzs[pvzID]; !ok {
+		return uuid.Nil, fmt.Errorf(FailedCreateReception)
+	}
+
+	id := uuid.New()
+	s.receptions[id] = model.Reception{
+		ID:       id,
+		DateTime: s.now(),
+		IsClosed: false,
+		PvzID:    pvzID,
+	}
+	s.recOrder = append(s.recOrder, id)
+	s.touchPvz(pvzID)
+
+	return id, nil
+}
+
+// InsertReception сохраняет приемку с ID и временем, заданными клиентом (офлайн-синхронизация)
+func (r *ReceptionRepository) InsertReception(ctx context.Context, reception model.Reception) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.pvzs[reception.PvzID]; !ok {
+		return fmt.Errorf(FailedCreateReception)
+	}
+	if _, ok := s.receptions[reception.ID]; ok {
+		return fmt.Errorf(FailedCreateReception)
+	}
+
+	reception.Products = nil
+	s.receptions[reception.ID] = reception
+	s.recOrder = append(s.recOrder, reception.ID)
+	s.touchPvz(reception.PvzID)
+
+	return nil
+}
+
+func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*model.Reception, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	reception, ok := s.receptions[id]
+	if !ok {
+		return nil, fmt.Errorf(ReceptionNotFound)
+	}
+
+	return &reception, nil
+}
+
+func (r *ReceptionRepository) GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var last *model.Reception
+	for _, id := range s.recOrder {
+		reception := s.receptions[id]
+		if reception.PvzID != pvzID {
+			continue
+		}
+		if last == nil || reception.DateTime.After(last.DateTime) {
+			last = &reception
+		}
+	}
+
+	if last == nil {
+		return nil, fmt.Errorf("%s: %w", ReceptionNotFound, model.ErrNotFound)
+	}
+
+	return last, nil
+}
+
+// CloseReception закрывает открытую приемку и запоминает причину закрытия
+func (r *ReceptionRepository) CloseReception(ctx context.Context, receptionID uuid.UUID, reason string) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reception, ok := s.receptions[receptionID]
+	if !ok || reception.IsClosed {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	// Аналог CHECK на close_reason
+	switch reason {
+	case model.CloseReasonManual, model.CloseReasonSync, model.CloseReasonTimeout:
+	default:
+		return fmt.Errorf(FailedUpdateReception)
+	}
+
+	reception.IsClosed = true
+	reception.CloseReason = reason
+	reception.ClosedAt = s.now()
+	s.receptions[receptionID] = reception
+	s.touchPvz(reception.PvzID)
+
+	return nil
+}
+
+// FlagReception помечает открытую приемку как зависшую
+func (r *ReceptionRepository) FlagReception(ctx context.Context, receptionID uuid.UUID) error {
+	return r.markOpenReception(ctx, receptionID, func(reception *model.Reception) *time.Time {
+		return &reception.FlaggedAt
+	})
+}
+
+// MarkReceptionReminded запоминает отправку напоминания
+func (r *ReceptionRepository) MarkReceptionReminded(ctx context.Context, receptionID uuid.UUID) error {
+	return r.markOpenReception(ctx, receptionID, func(reception *model.Reception) *time.Time {
+		return &reception.RemindedAt
+	})
+}
+
+// markOpenReception ставит текущее время в незаполненное поле открытой приемки
+func (r *ReceptionRepository) markOpenReception(ctx context.Context, receptionID uuid.UUID, field func(*model.Reception) *time.Time) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reception, ok := s.receptions[receptionID]
+	if !ok || reception.IsClosed {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	mark := field(&reception)
+	if !mark.IsZero() {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	*mark = s.now()
+	s.receptions[receptionID] = reception
+	s.touchPvz(reception.PvzID)
+
+	return nil
+}
+
+// GetOpenReceptionsBefore возвращает открытые приемки, начатые раньше before, от самой старой
+func (r *ReceptionRepository) GetOpenReceptionsBefore(ctx context.Context, before time.Time) ([]model.Reception, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.Reception
+	for _, id := range s.recOrder {
+		reception := s.receptions[id]
+		if !reception.IsClosed && reception.DateTime.Before(before) {
+			result = append(result, reception)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].DateTime.Equal(result[j].DateTime) {
+			return result[i].DateTime.Before(result[j].DateTime)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+
+	return result, nil
+}
+
+// CountReceptions считает приемки ПВЗ, открытые в интервале [begin, end)
+func (r *ReceptionRepository) CountReceptions(ctx context.Context, pvzID uuid.UUID, begin time.Time, end time.Time) (int, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, reception := range s.receptions {
+		if reception.PvzID == pvzID && !reception.DateTime.Before(begin) && reception.DateTime.Before(end) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *ReceptionRepository) GetReceptionsSliceWithTimeRange(ctx context.Context, begin time.Time, end time.Time) ([]model.Reception, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.Reception
+	for _, id := range s.recOrder {
+		reception := s.receptions[id]
+
+		if !begin.IsZero() && reception.DateTime.Before(begin) {
+			continue
+		}
+		if !end.IsZero() && reception.DateTime.After(end) {
+			continue
+		}
+
+		result = append(result, reception)
+	}
+
+	return result, nil
+}
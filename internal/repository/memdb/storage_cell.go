@@ -0,0 +1,112 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateStorageCell = "failed to create storage cell"
+	FailedDeleteStorageCell = "failed to delete storage cell"
+)
+
+type StorageCellRepository struct {
+	storage *Storage
+}
+
+func NewStorageCellRepository(storage *Storage) *StorageCellRepository {
+	return &StorageCellRepository{
+		storage: storage,
+	}
+}
+
+func (r *StorageCellRepository) CreateStorageCell(ctx context.Context, cell model.StorageCell) (uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Аналог fk_storage_cell_pvz_id и uq_storage_cell_pvz_id_code
+	if _, ok := s.pvzs[cell.PvzID]; !ok {
+		return uuid.Nil, fmt.Errorf(FailedCreateStorageCell)
+	}
+	for _, c := range s.cells {
+		if c.PvzID == cell.PvzID && c.Code == cell.Code {
+			return uuid.Nil, fmt.Errorf(FailedCreateStorageCell)
+		}
+	}
+
+	cell.ID = uuid.New()
+	cell.Occupied = 0
+	s.cells[cell.ID] = cell
+	s.touchPvz(cell.PvzID)
+
+	return cell.ID, nil
+}
+
+func (r *StorageCellRepository) DeleteStorageCell(ctx context.Context, id uuid.UUID) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cell, ok := s.cells[id]
+	if !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	delete(s.cells, id)
+	s.touchPvz(cell.PvzID)
+
+	// ON DELETE SET NULL
+	for productID, product := range s.products {
+		if product.CellID == id {
+			product.CellID = uuid.Nil
+			s.products[productID] = product
+		}
+	}
+
+	return nil
+}
+
+func (r *StorageCellRepository) GetStorageCells(ctx context.Context, pvzID uuid.UUID) ([]model.StorageCell, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	balance := s.issuanceBalance()
+	occupied := make(map[uuid.UUID]int)
+	for id, product := range s.products {
+		if product.CellID != uuid.Nil && balance[id] == 0 {
+			occupied[product.CellID]++
+		}
+	}
+
+	var result []model.StorageCell
+	for _, cell := range s.cells {
+		if cell.PvzID == pvzID {
+			cell.Occupied = occupied[cell.ID]
+			result = append(result, cell)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedSaveSyncOperation = "failed to save sync operation"
+	SyncOperationNotFound   = "sync operation not found"
+)
+
+// syncKey - аналог первичного ключа (pvz_id, id): UUID операции уникален внутри ПВЗ
+type syncKey struct {
+	pvzID uuid.UUID
+	id    uuid.UUID
+}
+
+type SyncRepository struct {
+	storage *Storage
+}
+
+func NewSyncRepository(storage *Storage) *SyncRepository {
+	return &SyncRepository{
+		storage: storage,
+	}
+}
+
+func (r *SyncRepository) GetSyncOperation(ctx context.Context, pvzID, id uuid.UUID) (*model.SyncOperation, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	op, ok := s.syncOps[syncKey{pvzID: pvzID, id: id}]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", SyncOperationNotFound, model.ErrNotFound)
+	}
+
+	return &op, nil
+}
+
+func (r *SyncRepository) SaveSyncOperation(ctx context.Context, op model.SyncOperation) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Аналог fk_sync_pvz_id и первичного ключа
+	if _, ok := s.pvzs[op.PvzID]; !ok {
+		return fmt.Errorf(FailedSaveSyncOperation)
+	}
+	key := syncKey{pvzID: op.PvzID, id: op.ID}
+	if _, ok := s.syncOps[key]; ok {
+		return fmt.Errorf(FailedSaveSyncOperation)
+	}
+
+	s.syncOps[key] = op
+
+	return nil
+}
+
+func (r *SyncRepository) GetReceptionProducts(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.Product
+	for _, id := range s.prodOrder {
+		product := s.products[id]
+		if product.ReceptionID == receptionID {
+			result = append(result, product)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DateTime.Before(result[j].DateTime)
+	})
+
+	return result, nil
+}
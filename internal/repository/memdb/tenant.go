@@ -0,0 +1,76 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateTenant = "failed to create tenant"
+	duplicateTenant    = "duplicate key value violates unique constraint on tenant name"
+)
+
+type TenantRepository struct {
+	storage *Storage
+}
+
+func NewTenantRepository(storage *Storage) *TenantRepository {
+	return &TenantRepository{
+		storage: storage,
+	}
+}
+
+func (r *TenantRepository) CreateTenant(_ context.Context, name string) (*model.Tenant, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	for _, t := range r.storage.tenants {
+		if t.Name == name {
+			return nil, fmt.Errorf("%s: %s", FailedCreateTenant, duplicateTenant)
+		}
+	}
+
+	id := uuid.New()
+	r.storage.addTenant(model.Tenant{ID: id, Name: name})
+
+	t := r.storage.tenants[id]
+	return &t, nil
+}
+
+func (r *TenantRepository) GetTenantByID(_ context.Context, id uuid.UUID) (*model.Tenant, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	t, ok := r.storage.tenants[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", TenantNotFound, model.ErrNotFound)
+	}
+	return &t, nil
+}
+
+func (r *TenantRepository) SetTenantRegistration(_ context.Context, id uuid.UUID, open bool) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	t, ok := r.storage.tenants[id]
+	if !ok {
+		return fmt.Errorf("%s: %w", TenantNotFound, model.ErrNotFound)
+	}
+	t.OpenRegistration = open
+	r.storage.tenants[id] = t
+	return nil
+}
+
+func (r *TenantRepository) GetTenants(_ context.Context) ([]model.Tenant, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	tenants := make([]model.Tenant, 0, len(r.storage.tenantOrder))
+	for _, id := range r.storage.tenantOrder {
+		tenants = append(tenants, r.storage.tenants[id])
+	}
+	return tenants, nil
+}
@@ -0,0 +1,28 @@
+package memdb
+
+import "context"
+
+type txKey struct{}
+
+// Transactor - аналог транзакций Postgres для хранилища в памяти.
+// Транзакции выполняются строго по очереди, поэтому проверка и запись внутри одной
+// не пересекаются с другими транзакциями. Отката нет: изменения, сделанные до ошибки, остаются
+type Transactor struct {
+	storage *Storage
+}
+
+func NewTransactor(storage *Storage) *Transactor {
+	return &Transactor{storage: storage}
+}
+
+// InTx выполняет fn под общей блокировкой транзакций. Вложенный вызов ее не берет повторно
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	t.storage.txMu.Lock()
+	defer t.storage.txMu.Unlock()
+
+	return fn(context.WithValue(ctx, txKey{}, struct{}{}))
+}
@@ -0,0 +1,99 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateUser = "failed to Create User"
+	UserNotFound     = "user not found"
+	duplicateEmail   = "duplicate key value violates unique constraint on email"
+)
+
+type UserRepository struct {
+	storage *Storage
+}
+
+func NewUserRepository(storage *Storage) *UserRepository {
+	return &UserRepository{
+		storage: storage,
+	}
+}
+
+func (r *UserRepository) CreateUser(_ context.Context, user *model.User) (uuid.UUID, error) {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	if _, ok := r.storage.emails[user.Email]; ok {
+		return uuid.Nil, fmt.Errorf("%s: %s", FailedCreateUser, duplicateEmail)
+	}
+	// Аналог внешнего ключа users -> tenant
+	if _, ok := r.storage.tenants[user.TenantID]; !ok {
+		return uuid.Nil, fmt.Errorf("%s: %s", FailedCreateUser, TenantNotFound)
+	}
+
+	id := uuid.New()
+	r.storage.users[id] = model.User{
+		ID:       id,
+		Email:    user.Email,
+		Password: user.Password,
+		Role:     user.Role,
+		TenantID: user.TenantID,
+		// TIMESTAMP в Postgres хранит время без зоны с точностью до микросекунды
+		EmailVerifiedAt: user.EmailVerifiedAt.UTC().Truncate(time.Microsecond),
+	}
+	r.storage.emails[user.Email] = id
+
+	return id, nil
+}
+
+func (r *UserRepository) GetUserByEmail(_ context.Context, email string) (*model.User, error) {
+	r.storage.mu.RLock()
+	defer r.storage.mu.RUnlock()
+
+	id, ok := r.storage.emails[email]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s: %w", UserNotFound, email, model.ErrNotFound)
+	}
+
+	user := r.storage.users[id]
+	return &user, nil
+}
+
+func (r *UserRepository) UpdatePassword(_ context.Context, id uuid.UUID, password string) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	user, ok := r.storage.users[id]
+	if !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	user.Password = password
+	r.storage.users[id] = user
+
+	return nil
+}
+
+// MarkEmailVerified запоминает подтверждение почты. Повторное подтверждение время не меняет
+func (r *UserRepository) MarkEmailVerified(_ context.Context, id uuid.UUID, at time.Time) error {
+	r.storage.mu.Lock()
+	defer r.storage.mu.Unlock()
+
+	user, ok := r.storage.users[id]
+	if !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	if user.EmailVerifiedAt.IsZero() {
+		user.EmailVerifiedAt = at.UTC().Truncate(time.Microsecond)
+		r.storage.users[id] = user
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	WebhookNotFound             = "webhook subscription not found"
+	FailedCreateWebhookDelivery = "failed to create webhook delivery"
+	FailedUpdateWebhookDelivery = "failed to update webhook delivery"
+	WebhookDeliveryNotFound     = "webhook delivery not found"
+)
+
+type WebhookRepository struct {
+	storage *Storage
+}
+
+func NewWebhookRepository(storage *Storage) *WebhookRepository {
+	return &WebhookRepository{
+		storage: storage,
+	}
+}
+
+// cloneWebhook копирует срезы, чтобы вызывающий код не менял хранилище
+func cloneWebhook(sub model.WebhookSubscription) model.WebhookSubscription {
+	sub.EventTypes = slices.Clone(sub.EventTypes)
+	sub.PvzIDs = slices.Clone(sub.PvzIDs)
+	if sub.PvzIDs == nil {
+		sub.PvzIDs = []uuid.UUID{}
+	}
+	return sub
+}
+
+// deliveryStatusValid - аналог CHECK на webhook_delivery.status
+func deliveryStatusValid(status string) bool {
+	switch status {
+	case model.WebhookDeliveryPending, model.WebhookDeliveryDelivered, model.WebhookDeliveryDead:
+		return true
+	}
+	return false
+}
+
+func cloneDelivery(delivery model.WebhookDelivery) model.WebhookDelivery {
+	delivery.Payload = slices.Clone(delivery.Payload)
+	return delivery
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, sub model.WebhookSubscription) (uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub = cloneWebhook(sub)
+	sub.ID = uuid.New()
+	sub.CreatedAt = s.now()
+	s.webhooks[sub.ID] = sub
+
+	return sub.ID, nil
+}
+
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.WebhookSubscription, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sub, ok := s.webhooks[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", WebhookNotFound, model.ErrNotFound)
+	}
+
+	sub = cloneWebhook(sub)
+	return &sub, nil
+}
+
+func (r *WebhookRepository) GetWebhooks(ctx context.Context) ([]model.WebhookSubscription, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.WebhookSubscription
+	for _, sub := range s.webhooks {
+		result = append(result, cloneWebhook(sub))
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.webhooks[id]; !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	delete(s.webhooks, id)
+
+	// ON DELETE CASCADE
+	order := s.deliveryOrder[:0]
+	for _, deliveryID := range s.deliveryOrder {
+		if s.deliveries[deliveryID].SubscriptionID == id {
+			delete(s.deliveries, deliveryID)
+			continue
+		}
+		order = append(order, deliveryID)
+	}
+	s.deliveryOrder = order
+
+	return nil
+}
+
+func (r *WebhookRepository) CreateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) (uuid.UUID, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Аналог fk_delivery_subscription_id
+	if _, ok := s.webhooks[delivery.SubscriptionID]; !ok || !deliveryStatusValid(delivery.Status) {
+		return uuid.Nil, fmt.Errorf(FailedCreateWebhookDelivery)
+	}
+
+	delivery = cloneDelivery(delivery)
+	delivery.ID = uuid.New()
+	delivery.CreatedAt = s.now()
+	s.deliveries[delivery.ID] = delivery
+	s.deliveryOrder = append(s.deliveryOrder, delivery.ID)
+
+	return delivery.ID, nil
+}
+
+func (r *WebhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !deliveryStatusValid(delivery.Status) {
+		return fmt.Errorf(FailedUpdateWebhookDelivery)
+	}
+
+	stored, ok := s.deliveries[delivery.ID]
+	if !ok {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.LastError = delivery.LastError
+	stored.ResponseStatus = delivery.ResponseStatus
+	stored.DeliveredAt = delivery.DeliveredAt
+	s.deliveries[delivery.ID] = stored
+
+	return nil
+}
+
+func (r *WebhookRepository) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", WebhookDeliveryNotFound, model.ErrNotFound)
+	}
+
+	delivery = cloneDelivery(delivery)
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []model.WebhookDelivery
+	for _, id := range s.deliveryOrder {
+		delivery := s.deliveries[id]
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			result = append(result, cloneDelivery(delivery))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].NextAttemptAt.Before(result[j].NextAttemptAt)
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+func (r *WebhookRepository) GetWebhookDeliveries(ctx context.Context, q model.WebhookDeliveryQuery) ([]model.WebhookDelivery, error) {
+	s, err := r.storage.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []model.WebhookDelivery
+	for i := len(s.deliveryOrder) - 1; i >= 0; i-- {
+		delivery := s.deliveries[s.deliveryOrder[i]]
+		if delivery.SubscriptionID != q.SubscriptionID {
+			continue
+		}
+		if q.Status != "" && delivery.Status != q.Status {
+			continue
+		}
+		matched = append(matched, cloneDelivery(delivery))
+	}
+
+	offset := (q.Page - 1) * q.Limit
+	if offset >= len(matched) {
+		return nil, nil
+	}
+
+	return matched[offset:min(offset+q.Limit, len(matched))], nil
+}
@@ -0,0 +1,16 @@
+package memdb_test
+
+import (
+	"testing"
+
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/repository/repotest"
+	"pvz-service/internal/service"
+)
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) service.Repository {
+		return repository.NewMemoryRepository(memdb.NewStorage())
+	})
+}
@@ -0,0 +1,116 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateAuthToken = "failed to create auth token"
+	FailedUseAuthTokens   = "failed to use auth tokens"
+	AuthTokenNotFound     = "auth token not found, expired or already used"
+)
+
+const (
+	authTokenTable           = "auth_token"
+	authTokenHashColumn      = "token_hash"
+	authTokenUserIDColumn    = "user_id"
+	authTokenPurposeColumn   = "purpose"
+	authTokenExpiresAtColumn = "expires_at"
+	authTokenUsedAtColumn    = "used_at"
+	authTokenCreatedAtColumn = "created_at"
+)
+
+// AuthTokenRepository хранит одноразовые токены из писем. Токены принадлежат пользователям,
+// а не операторам, поэтому репозиторий работает с пулом без TenantDB
+type AuthTokenRepository struct {
+	DB DB
+}
+
+func NewAuthTokenRepository(db DB) *AuthTokenRepository {
+	return &AuthTokenRepository{
+		DB: db,
+	}
+}
+
+func (r *AuthTokenRepository) CreateAuthToken(ctx context.Context, token model.AuthToken) error {
+	query, args, err := sq.
+		Insert(authTokenTable).
+		Columns(authTokenHashColumn, authTokenUserIDColumn, authTokenPurposeColumn, authTokenExpiresAtColumn).
+		Values(token.TokenHash, token.UserID, token.Purpose, token.ExpiresAt).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	if _, err = r.DB.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: %w", FailedCreateAuthToken, err)
+	}
+
+	return nil
+}
+
+// UseAuthToken гасит действующий токен и возвращает его. Проверка и отметка идут одним
+// UPDATE, поэтому из двух одновременных запросов с одним токеном пройдет только один
+func (r *AuthTokenRepository) UseAuthToken(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.AuthToken, error) {
+	query, args, err := sq.
+		Update(authTokenTable).
+		Set(authTokenUsedAtColumn, now).
+		Where(sq.Eq{
+			authTokenHashColumn:    tokenHash,
+			authTokenPurposeColumn: purpose,
+			authTokenUsedAtColumn:  nil,
+		}).
+		Where(sq.Gt{authTokenExpiresAtColumn: now}).
+		Suffix("RETURNING " + authTokenHashColumn + ", " + authTokenUserIDColumn + ", " + authTokenPurposeColumn + ", " +
+			authTokenExpiresAtColumn + ", " + authTokenUsedAtColumn + ", " + authTokenCreatedAtColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	var token model.AuthToken
+	err = r.DB.QueryRow(ctx, query, args...).Scan(
+		&token.TokenHash,
+		&token.UserID,
+		&token.Purpose,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, notFound(AuthTokenNotFound, err)
+	}
+
+	return &token, nil
+}
+
+// UseUserAuthTokens гасит все действующие токены пользователя с этим назначением
+func (r *AuthTokenRepository) UseUserAuthTokens(ctx context.Context, userID uuid.UUID, purpose string, now time.Time) error {
+	query, args, err := sq.
+		Update(authTokenTable).
+		Set(authTokenUsedAtColumn, now).
+		Where(sq.Eq{
+			authTokenUserIDColumn:  userID,
+			authTokenPurposeColumn: purpose,
+			authTokenUsedAtColumn:  nil,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	if _, err = r.DB.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s: %w", FailedUseAuthTokens, err)
+	}
+
+	return nil
+}
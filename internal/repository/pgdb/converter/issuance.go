@@ -0,0 +1,28 @@
+package converter
+
+import (
+	"pvz-service/internal/model"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+func ToIssuanceFromIssuanceRepo(issuance *modelRepo.Issuance) *model.Issuance {
+	return &model.Issuance{
+		ID:               issuance.ID,
+		ProductID:        issuance.ProductID,
+		PvzID:            issuance.PvzID,
+		IssuedAt:         issuance.IssuedAt,
+		EmployeeID:       issuance.EmployeeID,
+		ConfirmationCode: issuance.ConfirmationCode,
+	}
+}
+
+func ToProductReturnFromProductReturnRepo(ret *modelRepo.ProductReturn) *model.ProductReturn {
+	return &model.ProductReturn{
+		ID:         ret.ID,
+		ProductID:  ret.ProductID,
+		PvzID:      ret.PvzID,
+		ReturnedAt: ret.ReturnedAt,
+		EmployeeID: ret.EmployeeID,
+		Reason:     ret.Reason,
+	}
+}
@@ -1,15 +1,30 @@
 package converter
 
 import (
+	"github.com/google/uuid"
 	"pvz-service/internal/model"
 	modelRepo "pvz-service/internal/repository/pgdb/model"
 )
 
 func ToProductFromProductRepo(product *modelRepo.Product) *model.Product {
+	cellID := uuid.Nil
+	if product.CellID != nil {
+		cellID = *product.CellID
+	}
+
 	return &model.Product{
 		ID:          product.ID,
 		DateTime:    product.DateTime,
 		TypeProduct: product.TypeProduct,
 		ReceptionID: product.ReceptionID,
+		Barcode:     product.Barcode,
+		SKU:         product.SKU,
+		WeightGrams: product.WeightGrams,
+		Dimensions: model.Dimensions{
+			LengthMM: product.LengthMM,
+			WidthMM:  product.WidthMM,
+			HeightMM: product.HeightMM,
+		},
+		CellID: cellID,
 	}
 }
@@ -1,14 +1,77 @@
 package converter
 
 import (
+	"time"
+
 	"pvz-service/internal/model"
 	modelRepo "pvz-service/internal/repository/pgdb/model"
 )
 
+const dateLayout = "2006-01-02"
+
 func ToPvzFromPvzRepo(pvz *modelRepo.Pvz) *model.Pvz {
-	return &model.Pvz{
-		ID:               pvz.ID,
-		RegistrationDate: pvz.RegistrationDate,
-		City:             pvz.City,
+	result := &model.Pvz{
+		ID:                 pvz.ID,
+		RegistrationDate:   pvz.RegistrationDate,
+		City:               pvz.City,
+		Status:             pvz.Status,
+		Address:            pvz.Address,
+		Timezone:           pvz.Timezone,
+		WorkingHours:       ToWorkingHoursFromWorkingHoursRepo(pvz.WorkingHours),
+		MaxDailyReceptions: pvz.MaxDailyReceptions,
+	}
+
+	if pvz.Latitude != nil && pvz.Longitude != nil {
+		result.Coordinates = &model.GeoPoint{Lat: *pvz.Latitude, Lng: *pvz.Longitude}
+	}
+	if pvz.ReceptionOverrideUntil != nil {
+		result.ReceptionOverrideUntil = *pvz.ReceptionOverrideUntil
+	}
+
+	return result
+}
+
+func ToWorkingHoursFromWorkingHoursRepo(hours modelRepo.WorkingHours) model.WorkingHours {
+	var result model.WorkingHours
+
+	if len(hours.Weekly) > 0 {
+		result.Weekly = make(map[time.Weekday]model.DayHours, len(hours.Weekly))
+		for day, h := range hours.Weekly {
+			result.Weekly[time.Weekday(day)] = model.DayHours{Open: h.Open, Close: h.Close}
+		}
 	}
+
+	for _, e := range hours.Exceptions {
+		// Даты пишет только ToWorkingHoursRepoFromWorkingHours, формат всегда верный
+		date, _ := time.Parse(dateLayout, e.Date)
+		result.Exceptions = append(result.Exceptions, model.HoursException{
+			Date:   date,
+			Closed: e.Closed,
+			Hours:  model.DayHours{Open: e.Open, Close: e.Close},
+		})
+	}
+
+	return result
+}
+
+func ToWorkingHoursRepoFromWorkingHours(hours model.WorkingHours) modelRepo.WorkingHours {
+	var result modelRepo.WorkingHours
+
+	if len(hours.Weekly) > 0 {
+		result.Weekly = make(map[int]modelRepo.DayHours, len(hours.Weekly))
+		for day, h := range hours.Weekly {
+			result.Weekly[int(day)] = modelRepo.DayHours{Open: h.Open, Close: h.Close}
+		}
+	}
+
+	for _, e := range hours.Exceptions {
+		result.Exceptions = append(result.Exceptions, modelRepo.HoursException{
+			Date:   e.Date.Format(dateLayout),
+			Closed: e.Closed,
+			Open:   e.Hours.Open,
+			Close:  e.Hours.Close,
+		})
+	}
+
+	return result
 }
@@ -6,11 +6,26 @@ import (
 )
 
 func ToReceptionFromReceptionRepo(reception *modelRepo.Reception) *model.Reception {
-	return &model.Reception{
+	result := &model.Reception{
 		ID:       reception.ID,
 		DateTime: reception.DateTime,
 		Products: nil,
 		IsClosed: reception.IsClosedStatus,
 		PvzID:    reception.PvzID,
 	}
+
+	if reception.CloseReason != nil {
+		result.CloseReason = *reception.CloseReason
+	}
+	if reception.ClosedAt != nil {
+		result.ClosedAt = *reception.ClosedAt
+	}
+	if reception.FlaggedAt != nil {
+		result.FlaggedAt = *reception.FlaggedAt
+	}
+	if reception.RemindedAt != nil {
+		result.RemindedAt = *reception.RemindedAt
+	}
+
+	return result
 }
@@ -0,0 +1,17 @@
+package converter
+
+import (
+	"pvz-service/internal/model"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+func ToStorageCellFromStorageCellRepo(cell *modelRepo.StorageCell) *model.StorageCell {
+	return &model.StorageCell{
+		ID:        cell.ID,
+		PvzID:     cell.PvzID,
+		Code:      cell.Code,
+		Capacity:  cell.Capacity,
+		SizeClass: cell.SizeClass,
+		Occupied:  cell.Occupied,
+	}
+}
@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"pvz-service/internal/model"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+func ToSyncOperationFromSyncOperationRepo(op *modelRepo.SyncOperation) *model.SyncOperation {
+	return &model.SyncOperation{
+		ID:          op.ID,
+		PvzID:       op.PvzID,
+		Type:        op.Type,
+		DateTime:    op.ClientTime,
+		TypeProduct: op.TypeProduct,
+		Applied:     op.Applied,
+		Code:        op.Code,
+		Message:     op.Message,
+	}
+}
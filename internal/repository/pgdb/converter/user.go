@@ -6,10 +6,15 @@ import (
 )
 
 func ToUserFromUserRepo(user *modelRepo.User) *model.User {
-	return &model.User{
+	result := &model.User{
 		ID:       user.ID,
 		Email:    user.Email,
 		Password: user.Password,
 		Role:     user.Role,
+		TenantID: user.TenantID,
 	}
+	if user.EmailVerifiedAt != nil {
+		result.EmailVerifiedAt = *user.EmailVerifiedAt
+	}
+	return result
 }
@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+func ToWebhookSubscriptionFromRepo(sub *modelRepo.WebhookSubscription) (*model.WebhookSubscription, error) {
+	pvzIDs := make([]uuid.UUID, 0, len(sub.PvzIDs))
+	for _, raw := range sub.PvzIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		pvzIDs = append(pvzIDs, id)
+	}
+
+	return &model.WebhookSubscription{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: sub.EventTypes,
+		PvzIDs:     pvzIDs,
+		Secret:     sub.Secret,
+		CreatedAt:  sub.CreatedAt,
+	}, nil
+}
+
+// ToPvzIDsRepo переводит фильтр ПВЗ в значение для колонки pvz_ids
+func ToPvzIDsRepo(ids []uuid.UUID) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.String())
+	}
+	return result
+}
+
+func ToWebhookDeliveryFromRepo(delivery *modelRepo.WebhookDelivery) *model.WebhookDelivery {
+	result := &model.WebhookDelivery{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastError:      delivery.LastError,
+		ResponseStatus: delivery.ResponseStatus,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = *delivery.DeliveredAt
+	}
+
+	return result
+}
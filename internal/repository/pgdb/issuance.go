@@ -0,0 +1,194 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb/converter"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+const (
+	FailedCreateIssuance = "failed to create issuance"
+	FailedCreateReturn   = "failed to create product return"
+	IssuanceNotFound     = "issuance not found"
+	ReturnNotFound       = "product return not found"
+)
+
+const (
+	issuanceTable          = "issuance"
+	returnTable            = "product_return"
+	movementIDColumn       = "id"
+	movementProductColumn  = "product_id"
+	movementPvzColumn      = "pvz_id"
+	movementEmployeeColumn = "employee_id"
+	issuedAtColumn         = "issued_at"
+	confirmationCodeColumn = "confirmation_code"
+	returnedAtColumn       = "returned_at"
+	reasonColumn           = "reason"
+)
+
+// stockCondition - товар на складе, если каждая его выдача закрыта возвратом
+const stockCondition = "(SELECT COUNT(*) FROM " + issuanceTable + " i WHERE i.product_id = p.id) = " +
+	"(SELECT COUNT(*) FROM " + returnTable + " pr WHERE pr.product_id = p.id)"
+
+type IssuanceRepository struct {
+	DB DB
+	// ReadDB - для выборки остатков ПВЗ
+	ReadDB DB
+}
+
+func NewIssuanceRepository(db DB) *IssuanceRepository {
+	return &IssuanceRepository{
+		DB:     db,
+		ReadDB: db,
+	}
+}
+
+func (r *IssuanceRepository) CreateIssuance(ctx context.Context, issuance model.Issuance) (*model.Issuance, error) {
+	query, args, err := sq.
+		Insert(issuanceTable).
+		Columns(movementProductColumn, movementPvzColumn, movementEmployeeColumn, confirmationCodeColumn).
+		Values(issuance.ProductID, issuance.PvzID, issuance.EmployeeID, issuance.ConfirmationCode).
+		Suffix("RETURNING " + movementIDColumn + ", " + issuedAtColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&issuance.ID, &issuance.IssuedAt); err != nil {
+		return nil, fmt.Errorf(FailedCreateIssuance)
+	}
+
+	return &issuance, nil
+}
+
+func (r *IssuanceRepository) CreateReturn(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error) {
+	query, args, err := sq.
+		Insert(returnTable).
+		Columns(movementProductColumn, movementPvzColumn, movementEmployeeColumn, reasonColumn).
+		Values(ret.ProductID, ret.PvzID, ret.EmployeeID, ret.Reason).
+		Suffix("RETURNING " + movementIDColumn + ", " + returnedAtColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&ret.ID, &ret.ReturnedAt); err != nil {
+		return nil, fmt.Errorf(FailedCreateReturn)
+	}
+
+	return &ret, nil
+}
+
+// LockProduct блокирует строку товара до конца транзакции, поэтому выдачи и возвраты одного
+// товара проверяются и записываются по очереди. Вне транзакции блокировка снимается сразу
+func (r *IssuanceRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	query, args, err := sq.
+		Select(productIDColumn).
+		From(productTable).
+		Where(sq.Eq{productIDColumn: productID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	var id uuid.UUID
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return notFound(productNotFound, err)
+	}
+
+	return nil
+}
+
+func (r *IssuanceRepository) GetLastIssuance(ctx context.Context, productID uuid.UUID) (*model.Issuance, error) {
+	var issuance modelRepo.Issuance
+
+	query, args, err := sq.
+		Select(movementIDColumn, movementProductColumn, movementPvzColumn, issuedAtColumn,
+			movementEmployeeColumn, confirmationCodeColumn).
+		From(issuanceTable).
+		Where(sq.Eq{movementProductColumn: productID}).
+		OrderBy(issuedAtColumn + " DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(
+		&issuance.ID,
+		&issuance.ProductID,
+		&issuance.PvzID,
+		&issuance.IssuedAt,
+		&issuance.EmployeeID,
+		&issuance.ConfirmationCode,
+	); err != nil {
+		return nil, notFound(IssuanceNotFound, err)
+	}
+
+	return converter.ToIssuanceFromIssuanceRepo(&issuance), nil
+}
+
+func (r *IssuanceRepository) GetLastReturn(ctx context.Context, productID uuid.UUID) (*model.ProductReturn, error) {
+	var ret modelRepo.ProductReturn
+
+	query, args, err := sq.
+		Select(movementIDColumn, movementProductColumn, movementPvzColumn, returnedAtColumn,
+			movementEmployeeColumn, reasonColumn).
+		From(returnTable).
+		Where(sq.Eq{movementProductColumn: productID}).
+		OrderBy(returnedAtColumn + " DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(
+		&ret.ID,
+		&ret.ProductID,
+		&ret.PvzID,
+		&ret.ReturnedAt,
+		&ret.EmployeeID,
+		&ret.Reason,
+	); err != nil {
+		return nil, notFound(ReturnNotFound, err)
+	}
+
+	return converter.ToProductReturnFromProductReturnRepo(&ret), nil
+}
+
+// GetStock возвращает товары закрытых приемок ПВЗ, которые сейчас находятся на складе:
+// принятые, за вычетом выданных, плюс возвращенные
+func (r *IssuanceRepository) GetStock(ctx context.Context, pvzID uuid.UUID) ([]model.Product, error) {
+	columns := make([]string, 0, len(productColumns))
+	for _, column := range productColumns {
+		columns = append(columns, "p."+column)
+	}
+
+	query, args, err := sq.
+		Select(columns...).
+		From(productTable + " p").
+		Join(receptionTable + " r ON r." + receptionIDColumn + " = p." + receptionIDFKColumn).
+		Where(sq.Eq{"r." + pvzIDColumnFK: pvzID}).
+		Where(sq.Eq{"r." + isClosedStatus: true}).
+		Where(stockCondition).
+		OrderBy("p." + dateTimeProductColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	return queryProducts(ctx, r.ReadDB, query, args...)
+}
@@ -0,0 +1,25 @@
+package modelRepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Issuance struct {
+	ID               uuid.UUID `db:"id"`
+	ProductID        uuid.UUID `db:"product_id, foreign key"`
+	PvzID            uuid.UUID `db:"pvz_id, foreign key"`
+	IssuedAt         time.Time `db:"issued_at"`
+	EmployeeID       string    `db:"employee_id"`
+	ConfirmationCode string    `db:"confirmation_code"`
+}
+
+type ProductReturn struct {
+	ID         uuid.UUID `db:"id"`
+	ProductID  uuid.UUID `db:"product_id, foreign key"`
+	PvzID      uuid.UUID `db:"pvz_id, foreign key"`
+	ReturnedAt time.Time `db:"returned_at"`
+	EmployeeID string    `db:"employee_id"`
+	Reason     string    `db:"reason"`
+}
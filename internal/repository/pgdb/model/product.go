@@ -11,4 +11,12 @@ type Product struct {
 	DateTime    time.Time `db:"date_time"`
 	TypeProduct string    `db:"type_product"`
 	ReceptionID uuid.UUID `db:"reception_id, foreign key"`
+	Barcode     string    `db:"barcode"`
+	SKU         string    `db:"sku"`
+	WeightGrams int       `db:"weight_grams"`
+	LengthMM    int       `db:"length_mm"`
+	WidthMM     int       `db:"width_mm"`
+	HeightMM    int       `db:"height_mm"`
+	// CellID - nil, если товар не размещен в ячейке
+	CellID *uuid.UUID `db:"cell_id, foreign key"`
 }
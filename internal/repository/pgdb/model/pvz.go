@@ -7,7 +7,34 @@ import (
 )
 
 type Pvz struct {
-	ID               uuid.UUID `db:"id"`
-	RegistrationDate time.Time `db:"registration_date"`
-	City             string    `db:"city"`
+	ID                     uuid.UUID    `db:"id"`
+	RegistrationDate       time.Time    `db:"registration_date"`
+	City                   string       `db:"city"`
+	Status                 string       `db:"status"`
+	Address                string       `db:"address"`
+	Latitude               *float64     `db:"latitude"`
+	Longitude              *float64     `db:"longitude"`
+	Timezone               string       `db:"timezone"`
+	WorkingHours           WorkingHours `db:"working_hours"`
+	MaxDailyReceptions     int          `db:"max_daily_receptions"`
+	ReceptionOverrideUntil *time.Time   `db:"reception_override_until"`
+}
+
+// WorkingHours - график в колонке working_hours (JSONB).
+// Ключи Weekly - номер дня недели, 0 - воскресенье, как в time.Weekday
+type WorkingHours struct {
+	Weekly     map[int]DayHours `json:"weekly,omitempty"`
+	Exceptions []HoursException `json:"exceptions,omitempty"`
+}
+
+type DayHours struct {
+	Open  int `json:"open"`
+	Close int `json:"close"`
+}
+
+type HoursException struct {
+	Date   string `json:"date"`
+	Closed bool   `json:"closed,omitempty"`
+	Open   int    `json:"open,omitempty"`
+	Close  int    `json:"close,omitempty"`
 }
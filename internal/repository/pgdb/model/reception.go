@@ -7,8 +7,12 @@ import (
 )
 
 type Reception struct {
-	ID             uuid.UUID `db:"id"`
-	DateTime       time.Time `db:"date_time"`
-	IsClosedStatus bool      `db:"is_closed"`
-	PvzID          uuid.UUID `db:"pvz_id, foreign key"`
+	ID             uuid.UUID  `db:"id"`
+	DateTime       time.Time  `db:"date_time"`
+	IsClosedStatus bool       `db:"is_closed"`
+	PvzID          uuid.UUID  `db:"pvz_id, foreign key"`
+	CloseReason    *string    `db:"close_reason"`
+	ClosedAt       *time.Time `db:"closed_at"`
+	FlaggedAt      *time.Time `db:"flagged_at"`
+	RemindedAt     *time.Time `db:"reminded_at"`
 }
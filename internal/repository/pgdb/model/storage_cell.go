@@ -0,0 +1,13 @@
+package modelRepo
+
+import "github.com/google/uuid"
+
+type StorageCell struct {
+	ID        uuid.UUID `db:"id"`
+	PvzID     uuid.UUID `db:"pvz_id, foreign key"`
+	Code      string    `db:"code"`
+	Capacity  int       `db:"capacity"`
+	SizeClass string    `db:"size_class"`
+	// Occupied не хранится, считается по товарам ячейки
+	Occupied int `db:"occupied"`
+}
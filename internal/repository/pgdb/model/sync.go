@@ -0,0 +1,18 @@
+package modelRepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SyncOperation struct {
+	ID          uuid.UUID `db:"id"`
+	PvzID       uuid.UUID `db:"pvz_id, foreign key"`
+	Type        string    `db:"type"`
+	ClientTime  time.Time `db:"client_time"`
+	TypeProduct string    `db:"type_product"`
+	Applied     bool      `db:"applied"`
+	Code        string    `db:"code"`
+	Message     string    `db:"message"`
+}
@@ -1,10 +1,16 @@
 package modelRepo
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
-	ID       uuid.UUID `db:"id"`
-	Email    string    `db:"email"`
-	Password string    `db:"password"`
-	Role     string    `db:"role"`
+	ID              uuid.UUID  `db:"id"`
+	Email           string     `db:"email"`
+	Password        string     `db:"password"`
+	Role            string     `db:"role"`
+	TenantID        uuid.UUID  `db:"tenant_id"`
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
 }
@@ -0,0 +1,31 @@
+package modelRepo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscription struct {
+	ID         uuid.UUID `db:"id"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	PvzIDs     []string  `db:"pvz_ids"`
+	Secret     string    `db:"secret"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID  `db:"id"`
+	SubscriptionID uuid.UUID  `db:"subscription_id, foreign key"`
+	EventID        uuid.UUID  `db:"event_id"`
+	EventType      string     `db:"event_type"`
+	Payload        []byte     `db:"payload"`
+	Status         string     `db:"status"`
+	Attempts       int        `db:"attempts"`
+	NextAttemptAt  time.Time  `db:"next_attempt_at"`
+	LastError      string     `db:"last_error"`
+	ResponseStatus int        `db:"response_status"`
+	CreatedAt      time.Time  `db:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+}
@@ -2,9 +2,12 @@ package pgdb
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"pvz-service/internal/model"
 )
 
 type DB interface {
@@ -12,3 +15,12 @@ type DB interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
 }
+
+// notFound оборачивает отсутствие строки в model.ErrNotFound с сообщением msg.
+// Остальные ошибки - сбой базы, они возвращаются как есть
+func notFound(msg string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: %w", msg, model.ErrNotFound)
+	}
+	return err
+}
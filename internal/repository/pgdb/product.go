@@ -23,25 +23,70 @@ const (
 	dateTimeProductColumn = "date_time"
 	typeProductColumn     = "type_product"
 	receptionIDFKColumn   = "reception_id"
+	barcodeColumn         = "barcode"
+	skuColumn             = "sku"
+	weightColumn          = "weight_grams"
+	lengthColumn          = "length_mm"
+	widthColumn           = "width_mm"
+	heightColumn          = "height_mm"
+	cellIDFKColumn        = "cell_id"
 )
 
+// productColumns - порядок колонок во всех выборках товаров, его ожидает scanProduct
+var productColumns = []string{
+	productIDColumn, dateTimeProductColumn, typeProductColumn, receptionIDFKColumn,
+	barcodeColumn, skuColumn, weightColumn, lengthColumn, widthColumn, heightColumn, cellIDFKColumn,
+}
+
+// rowScanner - общий интерфейс pgx.Row и pgx.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanProduct(row rowScanner) (*model.Product, error) {
+	var product modelRepo.Product
+
+	if err := row.Scan(
+		&product.ID,
+		&product.DateTime,
+		&product.TypeProduct,
+		&product.ReceptionID,
+		&product.Barcode,
+		&product.SKU,
+		&product.WeightGrams,
+		&product.LengthMM,
+		&product.WidthMM,
+		&product.HeightMM,
+		&product.CellID,
+	); err != nil {
+		return nil, err
+	}
+
+	return converter.ToProductFromProductRepo(&product), nil
+}
+
 type ProductRepository struct {
 	DB DB
+	// ReadDB - для выборки товаров приемки
+	ReadDB DB
 }
 
 func NewProductRepository(db DB) *ProductRepository {
 	return &ProductRepository{
-		DB: db,
+		DB:     db,
+		ReadDB: db,
 	}
 }
 
-func (r *ProductRepository) CreateProduct(ctx context.Context, typeProduct string, recepID uuid.UUID) (uuid.UUID, error) {
+func (r *ProductRepository) CreateProduct(ctx context.Context, product model.Product) (uuid.UUID, error) {
 	var id uuid.UUID
 
 	query, args, err := sq.
 		Insert(productTable).
-		Columns(typeProductColumn, receptionIDFKColumn).
-		Values(typeProduct, recepID).
+		Columns(typeProductColumn, receptionIDFKColumn, barcodeColumn, skuColumn,
+			weightColumn, lengthColumn, widthColumn, heightColumn, cellIDFKColumn).
+		Values(product.TypeProduct, product.ReceptionID, product.Barcode, product.SKU, product.WeightGrams,
+			product.Dimensions.LengthMM, product.Dimensions.WidthMM, product.Dimensions.HeightMM, nullableUUID(product.CellID)).
 		Suffix("RETURNING " + productIDColumn).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -58,11 +103,30 @@ func (r *ProductRepository) CreateProduct(ctx context.Context, typeProduct strin
 	return id, nil
 }
 
-func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
-	var product modelRepo.Product
+// InsertProduct сохраняет товар с ID и временем, заданными клиентом (офлайн-синхронизация)
+func (r *ProductRepository) InsertProduct(ctx context.Context, product model.Product) error {
+	query, args, err := sq.
+		Insert(productTable).
+		Columns(productColumns...).
+		Values(product.ID, product.DateTime, product.TypeProduct, product.ReceptionID, product.Barcode, product.SKU,
+			product.WeightGrams, product.Dimensions.LengthMM, product.Dimensions.WidthMM, product.Dimensions.HeightMM,
+			nullableUUID(product.CellID)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
 
+	if _, err = r.DB.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf(FailedCreateProduct)
+	}
+
+	return nil
+}
+
+func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
 	query, args, err := sq.
-		Select(productIDColumn, dateTimeProductColumn, typeProductColumn, receptionIDFKColumn).
+		Select(productColumns...).
 		From(productTable).
 		Where(sq.Eq{productIDColumn: id}).
 		PlaceholderFormat(sq.Dollar).
@@ -71,24 +135,17 @@ func (r *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
 
-	err = r.DB.QueryRow(ctx, query, args...).Scan(
-		&product.ID,
-		&product.DateTime,
-		&product.TypeProduct,
-		&product.ReceptionID,
-	)
+	product, err := scanProduct(r.DB.QueryRow(ctx, query, args...))
 	if err != nil {
 		return nil, fmt.Errorf(productNotFound)
 	}
 
-	return converter.ToProductFromProductRepo(&product), nil
+	return product, nil
 }
 
 func (r *ProductRepository) GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*model.Product, error) {
-	var product modelRepo.Product
-
 	query, args, err := sq.
-		Select(productIDColumn, dateTimeProductColumn, typeProductColumn, receptionIDFKColumn).
+		Select(productColumns...).
 		From(productTable).
 		Where(sq.Eq{receptionIDFKColumn: receptionID}).
 		OrderBy(dateTimeColumn + " DESC").
@@ -99,17 +156,28 @@ func (r *ProductRepository) GetLastProduct(ctx context.Context, receptionID uuid
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
 
-	err = r.DB.QueryRow(ctx, query, args...).Scan(
-		&product.ID,
-		&product.DateTime,
-		&product.TypeProduct,
-		&product.ReceptionID,
-	)
+	product, err := scanProduct(r.DB.QueryRow(ctx, query, args...))
 	if err != nil {
 		return nil, fmt.Errorf(productNotFound)
 	}
 
-	return converter.ToProductFromProductRepo(&product), nil
+	return product, nil
+}
+
+// GetProductsByBarcode возвращает все товары со штрихкодом, сначала последние принятые
+func (r *ProductRepository) GetProductsByBarcode(ctx context.Context, barcode string) ([]model.Product, error) {
+	query, args, err := sq.
+		Select(productColumns...).
+		From(productTable).
+		Where(sq.Eq{barcodeColumn: barcode}).
+		OrderBy(dateTimeProductColumn + " DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	return queryProducts(ctx, r.DB, query, args...)
 }
 
 func (r *ProductRepository) DeleteProductByID(ctx context.Context, id uuid.UUID) error {
@@ -137,9 +205,8 @@ func (r *ProductRepository) DeleteProductByID(ctx context.Context, id uuid.UUID)
 }
 
 func (r *ProductRepository) GetProductSliceByReceptionID(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error) {
-	var result []model.Product
 	query, args, err := sq.
-		Select(productIDColumn, dateTimeProductColumn, typeProductColumn, receptionIDFKColumn).
+		Select(productColumns...).
 		From(productTable).
 		Where(sq.Eq{receptionIDFKColumn: receptionID}).
 		PlaceholderFormat(sq.Dollar).
@@ -147,7 +214,14 @@ func (r *ProductRepository) GetProductSliceByReceptionID(ctx context.Context, re
 	if err != nil {
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
-	rows, err := r.DB.Query(ctx, query, args...)
+
+	return queryProducts(ctx, r.ReadDB, query, args...)
+}
+
+func queryProducts(ctx context.Context, db DB, query string, args ...any) ([]model.Product, error) {
+	var result []model.Product
+
+	rows, err := db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(FailedExecuteQuery)
 	}
@@ -155,17 +229,11 @@ func (r *ProductRepository) GetProductSliceByReceptionID(ctx context.Context, re
 	defer rows.Close()
 
 	for rows.Next() {
-		var productRepo modelRepo.Product
-		if err = rows.Scan(
-			&productRepo.ID,
-			&productRepo.DateTime,
-			&productRepo.TypeProduct,
-			&productRepo.ReceptionID,
-		); err != nil {
-			return nil, fmt.Errorf("%s", FailedScanRow)
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf(FailedScanRow)
 		}
 
-		product := converter.ToProductFromProductRepo(&productRepo)
 		result = append(result, *product)
 	}
 
@@ -175,3 +243,11 @@ func (r *ProductRepository) GetProductSliceByReceptionID(ctx context.Context, re
 
 	return result, nil
 }
+
+// nullableUUID превращает uuid.Nil в NULL для необязательных внешних ключей
+func nullableUUID(id uuid.UUID) any {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
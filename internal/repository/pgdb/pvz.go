@@ -2,7 +2,9 @@ package pgdb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -12,24 +14,93 @@ import (
 )
 
 const (
-	FailedCreatePvz = "failed to Create Pvz"
-	PvzNotFound     = "pvz not found"
+	FailedCreatePvz    = "failed to Create Pvz"
+	FailedUpdatePvz    = "failed to update Pvz"
+	FailedUpdateStatus = "failed to update Pvz status"
+	PvzNotFound        = "pvz not found"
 )
 
 const (
-	pvzTable               = "pvz"
-	pvzIDColumn            = "id"
-	dateRegistrationColumn = "registration_date"
-	cityColumn             = "city"
+	pvzTable                     = "pvz"
+	pvzIDColumn                  = "id"
+	dateRegistrationColumn       = "registration_date"
+	cityColumn                   = "city"
+	statusColumn                 = "status"
+	addressColumn                = "address"
+	latitudeColumn               = "latitude"
+	longitudeColumn              = "longitude"
+	timezoneColumn               = "timezone"
+	workingHoursColumn           = "working_hours"
+	maxDailyReceptionsColumn     = "max_daily_receptions"
+	receptionOverrideUntilColumn = "reception_override_until"
+	distanceColumn               = "distance"
+	changedAtColumn              = "changed_at"
 )
 
+// pvzColumns - порядок колонок в выборках ПВЗ, его ожидает scanPvz
+var pvzColumns = []string{
+	pvzIDColumn, dateRegistrationColumn, cityColumn, statusColumn, addressColumn, latitudeColumn, longitudeColumn,
+	timezoneColumn, workingHoursColumn, maxDailyReceptionsColumn, receptionOverrideUntilColumn,
+}
+
+// haversineExpr - расстояние в метрах от точки (?, ?) до координат ПВЗ, без PostGIS
+var haversineExpr = fmt.Sprintf(
+	"2 * %[1]s * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(%[2]s - ?) / 2), 2) + "+
+		"COS(RADIANS(?)) * COS(RADIANS(%[2]s)) * POWER(SIN(RADIANS(%[3]s - ?) / 2), 2))))",
+	strconv.FormatFloat(model.EarthRadiusMeters, 'f', -1, 64), latitudeColumn, longitudeColumn,
+)
+
+// Товары ставят отметку на приемку, а не на строку pvz, чтобы сканы не ждали блокировку ПВЗ.
+// Отметка ПВЗ - наибольшее из pvz.changed_at и отметок товаров его приемок
+var (
+	pvzChangedAtExpr = fmt.Sprintf("GREATEST(%[1]s.%[2]s, (SELECT COALESCE(MAX(%[3]s), 'epoch') FROM %[4]s WHERE %[4]s.%[5]s = %[1]s.%[6]s))",
+		pvzTable, changedAtColumn, productsChangedAtColumn, receptionTable, pvzIDColumnFK, pvzIDColumn)
+	pvzListChangedAtExpr = fmt.Sprintf("GREATEST(COALESCE(MAX(%[1]s), 'epoch'), (SELECT COALESCE(MAX(%[2]s), 'epoch') FROM %[3]s))",
+		changedAtColumn, productsChangedAtColumn, receptionTable)
+)
+
+// scanPvz читает колонки pvzColumns, extra - значения дополнительных колонок после них
+func scanPvz(row rowScanner, extra ...any) (*model.Pvz, error) {
+	var (
+		pvz          modelRepo.Pvz
+		workingHours []byte
+	)
+
+	dest := []any{
+		&pvz.ID,
+		&pvz.RegistrationDate,
+		&pvz.City,
+		&pvz.Status,
+		&pvz.Address,
+		&pvz.Latitude,
+		&pvz.Longitude,
+		&pvz.Timezone,
+		&workingHours,
+		&pvz.MaxDailyReceptions,
+		&pvz.ReceptionOverrideUntil,
+	}
+
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(workingHours, &pvz.WorkingHours); err != nil {
+		return nil, err
+	}
+
+	return converter.ToPvzFromPvzRepo(&pvz), nil
+}
+
 type PVZRepository struct {
 	DB DB
+	// ReadDB - реплика для списка ПВЗ, по умолчанию совпадает с DB
+	ReadDB DB
 }
 
 func NewPVZRepository(db DB) *PVZRepository {
 	return &PVZRepository{
-		DB: db,
+		DB:     db,
+		ReadDB: db,
 	}
 }
 
@@ -57,10 +128,8 @@ func (r *PVZRepository) CreatePvz(ctx context.Context, city string) (uuid.UUID,
 }
 
 func (r *PVZRepository) GetPvzByID(ctx context.Context, id uuid.UUID) (*model.Pvz, error) {
-	var pvz modelRepo.Pvz
-
 	query, args, err := sq.
-		Select(pvzIDColumn, dateRegistrationColumn, cityColumn).
+		Select(pvzColumns...).
 		From(pvzTable).
 		Where(sq.Eq{pvzIDColumn: id}).
 		PlaceholderFormat(sq.Dollar).
@@ -69,16 +138,130 @@ func (r *PVZRepository) GetPvzByID(ctx context.Context, id uuid.UUID) (*model.Pv
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
 
-	err = r.DB.QueryRow(ctx, query, args...).Scan(
-		&pvz.ID,
-		&pvz.RegistrationDate,
-		&pvz.City,
-	)
+	pvz, err := scanPvz(r.DB.QueryRow(ctx, query, args...))
 	if err != nil {
-		return nil, fmt.Errorf(PvzNotFound)
+		return nil, notFound(PvzNotFound, err)
 	}
 
-	return converter.ToPvzFromPvzRepo(&pvz), nil
+	return pvz, nil
+}
+
+// UpdatePvzProfile сохраняет адрес, координаты, график и лимиты ПВЗ
+func (r *PVZRepository) UpdatePvzProfile(ctx context.Context, pvz model.Pvz) error {
+	workingHours, err := json.Marshal(converter.ToWorkingHoursRepoFromWorkingHours(pvz.WorkingHours))
+	if err != nil {
+		return fmt.Errorf(FailedUpdatePvz)
+	}
+
+	var latitude, longitude, overrideUntil any
+	if pvz.Coordinates != nil {
+		latitude, longitude = pvz.Coordinates.Lat, pvz.Coordinates.Lng
+	}
+	if !pvz.ReceptionOverrideUntil.IsZero() {
+		// TIMESTAMP без пояса, как и остальные даты в базе, хранится в UTC
+		overrideUntil = pvz.ReceptionOverrideUntil.UTC()
+	}
+
+	query, args, err := sq.
+		Update(pvzTable).
+		Set(addressColumn, pvz.Address).
+		Set(latitudeColumn, latitude).
+		Set(longitudeColumn, longitude).
+		Set(timezoneColumn, pvz.Timezone).
+		Set(workingHoursColumn, string(workingHours)).
+		Set(maxDailyReceptionsColumn, pvz.MaxDailyReceptions).
+		Set(receptionOverrideUntilColumn, overrideUntil).
+		Where(sq.Eq{pvzIDColumn: pvz.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf(FailedUpdatePvz)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
+
+// UpdatePvzStatus меняет статус ПВЗ, только если он все еще равен from.
+// Так два одновременных перехода не перезапишут друг друга
+func (r *PVZRepository) UpdatePvzStatus(ctx context.Context, id uuid.UUID, from, to string) error {
+	query, args, err := sq.
+		Update(pvzTable).
+		Set(statusColumn, to).
+		Where(sq.Eq{pvzIDColumn: id, statusColumn: from}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf(FailedUpdateStatus)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
+
+// GetNearbyPvz ищет ПВЗ с координатами в радиусе от точки, кроме выведенных из эксплуатации, ближайшие первыми.
+// Прямоугольник по индексу на координатах отсекает дальние ПВЗ до расчета расстояния
+func (r *PVZRepository) GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error) {
+	point := nearby.Point
+	box := point.BoundingBox(nearby.RadiusMeters)
+
+	query, args, err := sq.
+		Select(pvzColumns...).
+		Column(sq.Alias(sq.Expr(haversineExpr, point.Lat, point.Lat, point.Lng), distanceColumn)).
+		From(pvzTable).
+		Where(sq.And{
+			sq.NotEq{statusColumn: model.PvzStatusDecommissioned},
+			sq.Expr(latitudeColumn+" BETWEEN ? AND ?", box.MinLat, box.MaxLat),
+			sq.Expr(longitudeColumn+" BETWEEN ? AND ?", box.MinLng, box.MaxLng),
+			sq.Expr(haversineExpr+" <= ?", point.Lat, point.Lat, point.Lng, nearby.RadiusMeters),
+		}).
+		OrderBy(distanceColumn, pvzIDColumn).
+		Limit(uint64(nearby.Limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	rows, err := r.ReadDB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	defer rows.Close()
+	result := make([]model.NearbyPvz, 0, nearby.Limit)
+	for rows.Next() {
+		var distance float64
+
+		pvz, err := scanPvz(rows, &distance)
+		if err != nil {
+			return nil, fmt.Errorf(FailedScanRow)
+		}
+
+		result = append(result, model.NearbyPvz{Pvz: *pvz, DistanceMeters: distance})
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf(FailedScanRow)
+	}
+
+	return result, nil
 }
 
 func (r *PVZRepository) GetIDListPvz(ctx context.Context) ([]uuid.UUID, error) {
@@ -90,7 +273,7 @@ func (r *PVZRepository) GetIDListPvz(ctx context.Context) ([]uuid.UUID, error) {
 	if err != nil {
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
-	rows, err := r.DB.Query(ctx, query, args...)
+	rows, err := r.ReadDB.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(FailedExecuteQuery)
 	}
@@ -112,3 +295,43 @@ func (r *PVZRepository) GetIDListPvz(ctx context.Context) ([]uuid.UUID, error) {
 
 	return result, nil
 }
+
+// GetPvzChangeStamp возвращает время последнего изменения ПВЗ и его данных.
+// Читает с реплики: отметка не новее данных, иначе клиент закешировал бы старый ответ под новым ETag
+func (r *PVZRepository) GetPvzChangeStamp(ctx context.Context, id uuid.UUID) (model.ChangeStamp, error) {
+	query, args, err := sq.
+		Select(pvzChangedAtExpr).
+		From(pvzTable).
+		Where(sq.Eq{pvzIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return model.ChangeStamp{}, fmt.Errorf(FailedBuildQuery)
+	}
+
+	stamp := model.ChangeStamp{Count: 1}
+	if err = r.ReadDB.QueryRow(ctx, query, args...).Scan(&stamp.ChangedAt); err != nil {
+		return model.ChangeStamp{}, notFound(PvzNotFound, err)
+	}
+
+	return stamp, nil
+}
+
+// GetPvzListChangeStamp возвращает отметку изменения всех ПВЗ для ETag списка
+func (r *PVZRepository) GetPvzListChangeStamp(ctx context.Context) (model.ChangeStamp, error) {
+	query, args, err := sq.
+		Select("COUNT(*)", pvzListChangedAtExpr).
+		From(pvzTable).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return model.ChangeStamp{}, fmt.Errorf(FailedBuildQuery)
+	}
+
+	var stamp model.ChangeStamp
+	if err = r.ReadDB.QueryRow(ctx, query, args...).Scan(&stamp.Count, &stamp.ChangedAt); err != nil {
+		return model.ChangeStamp{}, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	return stamp, nil
+}
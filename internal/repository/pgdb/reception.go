@@ -26,15 +26,49 @@ const (
 	dateTimeColumn    = "date_time"
 	isClosedStatus    = "is_closed"
 	pvzIDColumnFK     = "pvz_id"
+	closeReasonColumn = "close_reason"
+	closedAtColumn    = "closed_at"
+	flaggedAtColumn   = "flagged_at"
+	remindedAtColumn  = "reminded_at"
+	// productsChangedAtColumn - время последнего изменения товаров приемки, ставит триггер
+	productsChangedAtColumn = "products_changed_at"
 )
 
+// receptionColumns - порядок колонок в выборках приемок, его ожидает scanReception
+var receptionColumns = []string{
+	receptionIDColumn, dateTimeColumn, isClosedStatus, pvzIDColumnFK,
+	closeReasonColumn, closedAtColumn, flaggedAtColumn, remindedAtColumn,
+}
+
+func scanReception(row rowScanner) (*model.Reception, error) {
+	var reception modelRepo.Reception
+
+	if err := row.Scan(
+		&reception.ID,
+		&reception.DateTime,
+		&reception.IsClosedStatus,
+		&reception.PvzID,
+		&reception.CloseReason,
+		&reception.ClosedAt,
+		&reception.FlaggedAt,
+		&reception.RemindedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return converter.ToReceptionFromReceptionRepo(&reception), nil
+}
+
 type ReceptionRepository struct {
 	DB DB
+	// ReadDB - для выборки приемок по диапазону дат
+	ReadDB DB
 }
 
 func NewReceptionRepository(db DB) *ReceptionRepository {
 	return &ReceptionRepository{
-		DB: db,
+		DB:     db,
+		ReadDB: db,
 	}
 }
 
@@ -59,11 +93,28 @@ func (r *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UU
 	return id, nil
 }
 
-func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*model.Reception, error) {
-	var reception modelRepo.Reception
+// InsertReception сохраняет приемку с ID и временем, заданными клиентом (офлайн-синхронизация)
+func (r *ReceptionRepository) InsertReception(ctx context.Context, reception model.Reception) error {
+	query, args, err := sq.
+		Insert(receptionTable).
+		Columns(receptionIDColumn, dateTimeColumn, isClosedStatus, pvzIDColumnFK).
+		Values(reception.ID, reception.DateTime, reception.IsClosed, reception.PvzID).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	if _, err = r.DB.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf(FailedCreateReception)
+	}
+
+	return nil
+}
 
+func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*model.Reception, error) {
 	query, args, err := sq.
-		Select(receptionIDColumn, dateTimeColumn, isClosedStatus, pvzIDColumnFK).
+		Select(receptionColumns...).
 		From(receptionTable).
 		Where(sq.Eq{receptionIDColumn: id}).
 		PlaceholderFormat(sq.Dollar).
@@ -72,23 +123,17 @@ func (r *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
 
-	if err = r.DB.QueryRow(ctx, query, args...).Scan(
-		&reception.ID,
-		&reception.DateTime,
-		&reception.IsClosedStatus,
-		&reception.PvzID,
-	); err != nil {
+	reception, err := scanReception(r.DB.QueryRow(ctx, query, args...))
+	if err != nil {
 		return nil, fmt.Errorf(ReceptionNotFound)
 	}
 
-	return converter.ToReceptionFromReceptionRepo(&reception), nil
+	return reception, nil
 }
 
 func (r *ReceptionRepository) GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error) {
-	var reception modelRepo.Reception
-
 	query, args, err := sq.
-		Select(receptionIDColumn, dateTimeColumn, isClosedStatus, pvzIDColumnFK).
+		Select(receptionColumns...).
 		From(receptionTable).
 		Where(sq.Eq{pvzIDColumnFK: pvzID}).
 		OrderBy(dateTimeColumn + " DESC").
@@ -99,30 +144,58 @@ func (r *ReceptionRepository) GetLastReception(ctx context.Context, pvzID uuid.U
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
 
-	if err = r.DB.QueryRow(ctx, query, args...).Scan(
-		&reception.ID,
-		&reception.DateTime,
-		&reception.IsClosedStatus,
-		&reception.PvzID,
-	); err != nil {
-		return nil, fmt.Errorf(ReceptionNotFound)
+	reception, err := scanReception(r.DB.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, notFound(ReceptionNotFound, err)
 	}
 
-	return converter.ToReceptionFromReceptionRepo(&reception), nil
+	return reception, nil
 }
 
-func (r *ReceptionRepository) CloseReception(ctx context.Context, receptionID uuid.UUID) error {
+// CloseReception закрывает открытую приемку и запоминает причину закрытия.
+// Уже закрытая приемка не меняется, в этом случае возвращается NoRowsAffected
+func (r *ReceptionRepository) CloseReception(ctx context.Context, receptionID uuid.UUID, reason string) error {
 	query, args, err := sq.
 		Update(receptionTable).
 		Set(isClosedStatus, true).
-		Where(sq.Eq{receptionIDColumn: receptionID}).
+		Set(closeReasonColumn, reason).
+		Set(closedAtColumn, sq.Expr("NOW()")).
+		Where(sq.Eq{receptionIDColumn: receptionID, isClosedStatus: false}).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf(FailedBuildQuery)
 	}
 
-	// Выполняем запрос
+	return r.execAffected(ctx, query, args)
+}
+
+// FlagReception помечает открытую приемку как зависшую. Повторная пометка возвращает NoRowsAffected
+func (r *ReceptionRepository) FlagReception(ctx context.Context, receptionID uuid.UUID) error {
+	return r.markOpenReception(ctx, receptionID, flaggedAtColumn)
+}
+
+// MarkReceptionReminded запоминает отправку напоминания. Повторная отметка возвращает NoRowsAffected
+func (r *ReceptionRepository) MarkReceptionReminded(ctx context.Context, receptionID uuid.UUID) error {
+	return r.markOpenReception(ctx, receptionID, remindedAtColumn)
+}
+
+// markOpenReception ставит NOW() в пустую колонку column открытой приемки
+func (r *ReceptionRepository) markOpenReception(ctx context.Context, receptionID uuid.UUID, column string) error {
+	query, args, err := sq.
+		Update(receptionTable).
+		Set(column, sq.Expr("NOW()")).
+		Where(sq.Eq{receptionIDColumn: receptionID, isClosedStatus: false, column: nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	return r.execAffected(ctx, query, args)
+}
+
+func (r *ReceptionRepository) execAffected(ctx context.Context, query string, args []any) error {
 	cmdTag, err := r.DB.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf(FailedExecuteQuery)
@@ -135,11 +208,74 @@ func (r *ReceptionRepository) CloseReception(ctx context.Context, receptionID uu
 	return nil
 }
 
+// GetOpenReceptionsBefore возвращает открытые приемки, начатые раньше before, от самой старой.
+// Читает из основной базы: по результату планировщик закрывает приемки
+func (r *ReceptionRepository) GetOpenReceptionsBefore(ctx context.Context, before time.Time) ([]model.Reception, error) {
+	var result []model.Reception
+
+	query, args, err := sq.
+		Select(receptionColumns...).
+		From(receptionTable).
+		Where(sq.Eq{isClosedStatus: false}).
+		Where(sq.Lt{dateTimeColumn: before}).
+		OrderBy(dateTimeColumn, receptionIDColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		reception, err := scanReception(rows)
+		if err != nil {
+			return nil, fmt.Errorf(FailedScanRow)
+		}
+
+		result = append(result, *reception)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf(FailedScanRow)
+	}
+
+	return result, nil
+}
+
+// CountReceptions считает приемки ПВЗ, открытые в интервале [begin, end)
+func (r *ReceptionRepository) CountReceptions(ctx context.Context, pvzID uuid.UUID, begin time.Time, end time.Time) (int, error) {
+	var count int
+
+	query, args, err := sq.
+		Select("COUNT(*)").
+		From(receptionTable).
+		Where(sq.Eq{pvzIDColumnFK: pvzID}).
+		Where(sq.GtOrEq{dateTimeColumn: begin}).
+		Where(sq.Lt{dateTimeColumn: end}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	return count, nil
+}
+
 func (r *ReceptionRepository) GetReceptionsSliceWithTimeRange(ctx context.Context, begin time.Time, end time.Time) ([]model.Reception, error) {
 	var result []model.Reception
 
 	queryBuilder := sq.
-		Select(receptionIDColumn, dateTimeColumn, isClosedStatus, pvzIDColumnFK).
+		Select(receptionColumns...).
 		From(receptionTable).
 		PlaceholderFormat(sq.Dollar)
 
@@ -159,7 +295,7 @@ func (r *ReceptionRepository) GetReceptionsSliceWithTimeRange(ctx context.Contex
 		return nil, fmt.Errorf(FailedBuildQuery)
 	}
 
-	rows, err := r.DB.Query(ctx, query, args...)
+	rows, err := r.ReadDB.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(FailedExecuteQuery)
 	}
@@ -167,17 +303,11 @@ func (r *ReceptionRepository) GetReceptionsSliceWithTimeRange(ctx context.Contex
 	defer rows.Close()
 
 	for rows.Next() {
-		var receptionRepo modelRepo.Reception
-		if err = rows.Scan(
-			&receptionRepo.ID,
-			&receptionRepo.DateTime,
-			&receptionRepo.IsClosedStatus,
-			&receptionRepo.PvzID,
-		); err != nil {
+		reception, err := scanReception(rows)
+		if err != nil {
 			return nil, fmt.Errorf(FailedScanRow)
 		}
 
-		reception := converter.ToReceptionFromReceptionRepo(&receptionRepo)
 		result = append(result, *reception)
 	}
 
@@ -0,0 +1,129 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb/converter"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+const (
+	FailedCreateStorageCell = "failed to create storage cell"
+	FailedDeleteStorageCell = "failed to delete storage cell"
+)
+
+const (
+	storageCellTable    = "storage_cell"
+	cellIDColumn        = "id"
+	cellPvzIDColumn     = "pvz_id"
+	cellCodeColumn      = "code"
+	cellCapacityColumn  = "capacity"
+	cellSizeClassColumn = "size_class"
+)
+
+type StorageCellRepository struct {
+	DB DB
+}
+
+func NewStorageCellRepository(db DB) *StorageCellRepository {
+	return &StorageCellRepository{
+		DB: db,
+	}
+}
+
+func (r *StorageCellRepository) CreateStorageCell(ctx context.Context, cell model.StorageCell) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	query, args, err := sq.
+		Insert(storageCellTable).
+		Columns(cellPvzIDColumn, cellCodeColumn, cellCapacityColumn, cellSizeClassColumn).
+		Values(cell.PvzID, cell.Code, cell.Capacity, cell.SizeClass).
+		Suffix("RETURNING " + cellIDColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return uuid.Nil, fmt.Errorf(FailedCreateStorageCell)
+	}
+
+	return id, nil
+}
+
+// DeleteStorageCell удаляет ячейку, у ее товаров cell_id становится NULL
+func (r *StorageCellRepository) DeleteStorageCell(ctx context.Context, id uuid.UUID) error {
+	query, args, err := sq.
+		Delete(storageCellTable).
+		Where(sq.Eq{cellIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf(FailedDeleteStorageCell)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
+
+// GetStorageCells возвращает ячейки ПВЗ по коду вместе с заполненностью.
+// Выданные покупателям товары место в ячейке не занимают
+func (r *StorageCellRepository) GetStorageCells(ctx context.Context, pvzID uuid.UUID) ([]model.StorageCell, error) {
+	var result []model.StorageCell
+
+	query, args, err := sq.
+		Select("c."+cellIDColumn, "c."+cellPvzIDColumn, "c."+cellCodeColumn, "c."+cellCapacityColumn,
+			"c."+cellSizeClassColumn, "COUNT(p."+productIDColumn+")").
+		From(storageCellTable + " c").
+		LeftJoin(productTable + " p ON p." + cellIDFKColumn + " = c." + cellIDColumn + " AND " + stockCondition).
+		Where(sq.Eq{"c." + cellPvzIDColumn: pvzID}).
+		GroupBy("c." + cellIDColumn).
+		OrderBy("c." + cellCodeColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var cell modelRepo.StorageCell
+		if err = rows.Scan(
+			&cell.ID,
+			&cell.PvzID,
+			&cell.Code,
+			&cell.Capacity,
+			&cell.SizeClass,
+			&cell.Occupied,
+		); err != nil {
+			return nil, fmt.Errorf(FailedScanRow)
+		}
+
+		result = append(result, *converter.ToStorageCellFromStorageCellRepo(&cell))
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf(FailedScanRow)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb/converter"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+const (
+	FailedSaveSyncOperation = "failed to save sync operation"
+	SyncOperationNotFound   = "sync operation not found"
+)
+
+const (
+	syncOperationTable = "sync_operation"
+	syncIDColumn       = "id"
+	syncPvzIDColumnFK  = "pvz_id"
+	syncTypeColumn     = "type"
+	clientTimeColumn   = "client_time"
+	appliedColumn      = "applied"
+	codeColumn         = "code"
+	messageColumn      = "message"
+)
+
+// SyncRepository - журнал операций, принятых от сканеров через /sync
+type SyncRepository struct {
+	DB DB
+}
+
+func NewSyncRepository(db DB) *SyncRepository {
+	return &SyncRepository{
+		DB: db,
+	}
+}
+
+// GetSyncOperation ищет операцию в журнале ПВЗ: тот же UUID в журнале другого ПВЗ - другая операция
+func (r *SyncRepository) GetSyncOperation(ctx context.Context, pvzID, id uuid.UUID) (*model.SyncOperation, error) {
+	var op modelRepo.SyncOperation
+
+	query, args, err := sq.
+		Select(syncIDColumn, syncPvzIDColumnFK, syncTypeColumn, clientTimeColumn, typeProductColumn, appliedColumn, codeColumn, messageColumn).
+		From(syncOperationTable).
+		Where(sq.Eq{syncIDColumn: id, syncPvzIDColumnFK: pvzID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(
+		&op.ID,
+		&op.PvzID,
+		&op.Type,
+		&op.ClientTime,
+		&op.TypeProduct,
+		&op.Applied,
+		&op.Code,
+		&op.Message,
+	); err != nil {
+		return nil, notFound(SyncOperationNotFound, err)
+	}
+
+	return converter.ToSyncOperationFromSyncOperationRepo(&op), nil
+}
+
+func (r *SyncRepository) SaveSyncOperation(ctx context.Context, op model.SyncOperation) error {
+	query, args, err := sq.
+		Insert(syncOperationTable).
+		Columns(syncIDColumn, syncPvzIDColumnFK, syncTypeColumn, clientTimeColumn, typeProductColumn, appliedColumn, codeColumn, messageColumn).
+		Values(op.ID, op.PvzID, op.Type, op.DateTime, op.TypeProduct, op.Applied, op.Code, op.Message).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	if _, err = r.DB.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf(FailedSaveSyncOperation)
+	}
+
+	return nil
+}
+
+// GetReceptionProducts возвращает товары приемки в порядке добавления.
+// В отличие от GetProductSliceByReceptionID читает основную базу,
+// чтобы ответ /sync содержал только что записанные товары
+func (r *SyncRepository) GetReceptionProducts(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error) {
+	query, args, err := sq.
+		Select(productColumns...).
+		From(productTable).
+		Where(sq.Eq{receptionIDFKColumn: receptionID}).
+		OrderBy(dateTimeProductColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	return queryProducts(ctx, r.DB, query, args...)
+}
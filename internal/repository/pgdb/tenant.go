@@ -0,0 +1,133 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	FailedCreateTenant = "failed to create tenant"
+	FailedGetTenants   = "failed to get tenants"
+	FailedUpdateTenant = "failed to update tenant"
+	TenantNotFound     = "tenant not found"
+)
+
+const (
+	tenantTable           = "tenant"
+	tenantIDColumn        = "id"
+	tenantNameColumn      = "name"
+	tenantCreatedAtColumn = "created_at"
+	tenantOpenRegColumn   = "open_registration"
+)
+
+var tenantColumns = []string{tenantIDColumn, tenantNameColumn, tenantCreatedAtColumn, tenantOpenRegColumn}
+
+// TenantRepository работает со списком операторов. Таблица tenant не под RLS,
+// поэтому репозиторий получает пул без TenantDB
+type TenantRepository struct {
+	DB DB
+}
+
+func NewTenantRepository(db DB) *TenantRepository {
+	return &TenantRepository{
+		DB: db,
+	}
+}
+
+func (r *TenantRepository) CreateTenant(ctx context.Context, name string) (*model.Tenant, error) {
+	query, args, err := sq.
+		Insert(tenantTable).
+		Columns(tenantNameColumn).
+		Values(name).
+		Suffix("RETURNING " + strings.Join(tenantColumns, ", ")).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	var t model.Tenant
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&t.ID, &t.Name, &t.CreatedAt, &t.OpenRegistration); err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedCreateTenant, err)
+	}
+
+	return &t, nil
+}
+
+func (r *TenantRepository) GetTenantByID(ctx context.Context, id uuid.UUID) (*model.Tenant, error) {
+	query, args, err := sq.
+		Select(tenantColumns...).
+		From(tenantTable).
+		Where(sq.Eq{tenantIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	var t model.Tenant
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&t.ID, &t.Name, &t.CreatedAt, &t.OpenRegistration); err != nil {
+		return nil, notFound(TenantNotFound, err)
+	}
+
+	return &t, nil
+}
+
+func (r *TenantRepository) SetTenantRegistration(ctx context.Context, id uuid.UUID, open bool) error {
+	query, args, err := sq.
+		Update(tenantTable).
+		Set(tenantOpenRegColumn, open).
+		Where(sq.Eq{tenantIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	tag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedUpdateTenant, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", TenantNotFound, model.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *TenantRepository) GetTenants(ctx context.Context) ([]model.Tenant, error) {
+	query, args, err := sq.
+		Select(tenantColumns...).
+		From(tenantTable).
+		OrderBy(tenantCreatedAtColumn, tenantIDColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedGetTenants, err)
+	}
+	defer rows.Close()
+
+	var tenants []model.Tenant
+	for rows.Next() {
+		var t model.Tenant
+		if err = rows.Scan(&t.ID, &t.Name, &t.CreatedAt, &t.OpenRegistration); err != nil {
+			return nil, fmt.Errorf("%s: %w", FailedGetTenants, err)
+		}
+		tenants = append(tenants, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", FailedGetTenants, err)
+	}
+
+	return tenants, nil
+}
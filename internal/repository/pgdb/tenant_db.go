@@ -0,0 +1,126 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"pvz-service/internal/tenant"
+)
+
+// ErrTenantNotSet - запрос к данным операторов без оператора в контексте
+var ErrTenantNotSet = errors.New("tenant is not set in context")
+
+// tenantScopeSQL переключает транзакцию на роль без обхода RLS и задает оператора для политик
+// и значения tenant_id по умолчанию (миграция 00016). Обе настройки живут до конца транзакции
+const tenantScopeSQL = "SELECT set_config('role', 'pvz_tenant', true), set_config('app.tenant_id', $1, true)"
+
+// Batcher - пул или соединение, которое отправляет пачку запросов за один обмен
+type Batcher interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// TenantDB выполняет каждый запрос в пачке после tenantScopeSQL. Пачка идет одной неявной
+// транзакцией, поэтому оператор не переживает запрос и не достается следующему
+// пользователю соединения из пула. Так изоляция держится в одном месте, а не в каждом WHERE
+type TenantDB struct {
+	db Batcher
+}
+
+func NewTenantDB(db Batcher) *TenantDB {
+	return &TenantDB{db: db}
+}
+
+func (t *TenantDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	results, err := t.send(ctx, sql, args...)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return &tenantRow{row: results.QueryRow(), results: results}
+}
+
+func (t *TenantDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	results, err := t.send(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		_ = results.Close()
+		return nil, err
+	}
+	return &tenantRows{Rows: rows, results: results}, nil
+}
+
+func (t *TenantDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	results, err := t.send(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := results.Exec()
+	if closeErr := results.Close(); err == nil {
+		err = closeErr
+	}
+	return tag, err
+}
+
+// send отправляет пачку и читает результат tenantScopeSQL. Результат запроса читает вызывающий
+func (t *TenantDB) send(ctx context.Context, sql string, args ...any) (pgx.BatchResults, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil, ErrTenantNotSet
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(tenantScopeSQL, id.String())
+	batch.Queue(sql, args...)
+
+	// В транзакции из Transactor пачка идет в нее, настройки оператора живут до ее конца
+	var db Batcher = t.db
+	if tx, ok := txFromContext(ctx); ok {
+		db = tx
+	}
+
+	results := db.SendBatch(ctx, batch)
+	if _, err := results.Exec(); err != nil {
+		_ = results.Close()
+		return nil, err
+	}
+	return results, nil
+}
+
+// tenantRow закрывает пачку после Scan, иначе соединение не вернется в пул
+type tenantRow struct {
+	row     pgx.Row
+	results pgx.BatchResults
+}
+
+func (r *tenantRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if closeErr := r.results.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// tenantRows закрывает пачку вместе со строками
+type tenantRows struct {
+	pgx.Rows
+	results pgx.BatchResults
+}
+
+func (r *tenantRows) Close() {
+	r.Rows.Close()
+	_ = r.results.Close()
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
@@ -0,0 +1,83 @@
+package pgdb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// TxBeginner - пул, который открывает транзакции
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type txKey struct{}
+
+// Transactor выполняет функцию в одной транзакции и кладет ее в контекст.
+// TenantDB и TxDB отправляют запросы с таким контекстом в транзакцию, а не в пул.
+// Транзакция не смешивает данные операторов с общими таблицами: после первого запроса
+// через TenantDB она работает под ролью pvz_tenant, у которой нет прав на users и auth_token
+type Transactor struct {
+	db TxBeginner
+}
+
+func NewTransactor(db TxBeginner) *Transactor {
+	return &Transactor{db: db}
+}
+
+// InTx фиксирует транзакцию, если fn вернула nil, иначе откатывает ее.
+// Вложенный вызов выполняется в уже открытой транзакции
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+			return errors.Join(err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func txFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// TxDB - соединение для общих таблиц: запросы с транзакцией в контексте идут в нее
+type TxDB struct {
+	db DB
+}
+
+func NewTxDB(db DB) *TxDB {
+	return &TxDB{db: db}
+}
+
+func (t *TxDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.conn(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (t *TxDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.conn(ctx).Query(ctx, sql, args...)
+}
+
+func (t *TxDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.conn(ctx).Exec(ctx, sql, args...)
+}
+
+func (t *TxDB) conn(ctx context.Context) DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return t.db
+}
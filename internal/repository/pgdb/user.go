@@ -3,6 +3,7 @@ package pgdb
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"pvz-service/internal/model"
 	"pvz-service/internal/repository/pgdb/converter"
@@ -13,16 +14,20 @@ import (
 )
 
 const (
-	FailedCreateUser = "failed to Create User"
-	UserNotFound     = "user not found"
+	FailedCreateUser     = "failed to Create User"
+	UserNotFound         = "user not found"
+	FailedUpdatePassword = "failed to update password"
+	FailedVerifyEmail    = "failed to verify email"
 )
 
 const (
-	usersTable     = "users"
-	userIDColumn   = "id"
-	emailColumn    = "email"
-	passwordColumn = "password"
-	roleColumn     = "role"
+	usersTable            = "users"
+	userIDColumn          = "id"
+	emailColumn           = "email"
+	passwordColumn        = "password"
+	roleColumn            = "role"
+	userTenantIDColumn    = "tenant_id"
+	emailVerifiedAtColumn = "email_verified_at"
 )
 
 type UserRepository struct {
@@ -40,8 +45,8 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *model.User) (uuid
 
 	query, args, err := sq.
 		Insert(usersTable).
-		Columns(emailColumn, passwordColumn, roleColumn).
-		Values(user.Email, user.Password, user.Role).
+		Columns(emailColumn, passwordColumn, roleColumn, userTenantIDColumn, emailVerifiedAtColumn).
+		Values(user.Email, user.Password, user.Role, user.TenantID, nullableTime(user.EmailVerifiedAt)).
 		Suffix("RETURNING " + userIDColumn).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -61,7 +66,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 	var user modelRepo.User
 
 	query, args, err := sq.
-		Select(userIDColumn, emailColumn, passwordColumn, roleColumn).
+		Select(userIDColumn, emailColumn, passwordColumn, roleColumn, userTenantIDColumn, emailVerifiedAtColumn).
 		From(usersTable).
 		Where(sq.Eq{emailColumn: email}).
 		PlaceholderFormat(sq.Dollar).
@@ -75,10 +80,57 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*mod
 		&user.Email,
 		&user.Password,
 		&user.Role,
+		&user.TenantID,
+		&user.EmailVerifiedAt,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %s", UserNotFound, email)
+		return nil, notFound(UserNotFound+": "+email, err)
 	}
 
 	return converter.ToUserFromUserRepo(&user), nil
 }
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, password string) error {
+	query, args, err := sq.
+		Update(usersTable).
+		Set(passwordColumn, password).
+		Where(sq.Eq{userIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedUpdatePassword, err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
+
+// MarkEmailVerified запоминает подтверждение почты. Повторное подтверждение время не меняет
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query, args, err := sq.
+		Update(usersTable).
+		Set(emailVerifiedAtColumn, sq.Expr("COALESCE("+emailVerifiedAtColumn+", ?)", at)).
+		Where(sq.Eq{userIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedBuildQuery, err)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", FailedVerifyEmail, err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
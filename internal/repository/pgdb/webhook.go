@@ -0,0 +1,356 @@
+package pgdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb/converter"
+	modelRepo "pvz-service/internal/repository/pgdb/model"
+)
+
+const (
+	FailedCreateWebhook         = "failed to create webhook subscription"
+	FailedDeleteWebhook         = "failed to delete webhook subscription"
+	WebhookNotFound             = "webhook subscription not found"
+	FailedCreateWebhookDelivery = "failed to create webhook delivery"
+	FailedUpdateWebhookDelivery = "failed to update webhook delivery"
+	WebhookDeliveryNotFound     = "webhook delivery not found"
+)
+
+const (
+	webhookTable           = "webhook_subscription"
+	webhookIDColumn        = "id"
+	webhookURLColumn       = "url"
+	webhookEventsColumn    = "event_types"
+	webhookPvzIDsColumn    = "pvz_ids"
+	webhookSecretColumn    = "secret"
+	webhookCreatedAtColumn = "created_at"
+
+	deliveryTable                = "webhook_delivery"
+	deliveryIDColumn             = "id"
+	deliverySubscriptionIDColumn = "subscription_id"
+	deliveryEventIDColumn        = "event_id"
+	deliveryEventTypeColumn      = "event_type"
+	deliveryPayloadColumn        = "payload"
+	deliveryStatusColumn         = "status"
+	deliveryAttemptsColumn       = "attempts"
+	deliveryNextAttemptAtColumn  = "next_attempt_at"
+	deliveryLastErrorColumn      = "last_error"
+	deliveryResponseStatusColumn = "response_status"
+	deliveryCreatedAtColumn      = "created_at"
+	deliveryDeliveredAtColumn    = "delivered_at"
+)
+
+var webhookColumns = []string{
+	webhookIDColumn, webhookURLColumn, webhookEventsColumn, webhookPvzIDsColumn, webhookSecretColumn, webhookCreatedAtColumn,
+}
+
+var deliveryColumns = []string{
+	deliveryIDColumn, deliverySubscriptionIDColumn, deliveryEventIDColumn, deliveryEventTypeColumn, deliveryPayloadColumn,
+	deliveryStatusColumn, deliveryAttemptsColumn, deliveryNextAttemptAtColumn, deliveryLastErrorColumn,
+	deliveryResponseStatusColumn, deliveryCreatedAtColumn, deliveryDeliveredAtColumn,
+}
+
+type WebhookRepository struct {
+	DB DB
+}
+
+func NewWebhookRepository(db DB) *WebhookRepository {
+	return &WebhookRepository{
+		DB: db,
+	}
+}
+
+func scanWebhook(row rowScanner) (*model.WebhookSubscription, error) {
+	var sub modelRepo.WebhookSubscription
+
+	if err := row.Scan(
+		&sub.ID,
+		&sub.URL,
+		&sub.EventTypes,
+		&sub.PvzIDs,
+		&sub.Secret,
+		&sub.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return converter.ToWebhookSubscriptionFromRepo(&sub)
+}
+
+func scanWebhookDelivery(row rowScanner) (*model.WebhookDelivery, error) {
+	var delivery modelRepo.WebhookDelivery
+
+	if err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastError,
+		&delivery.ResponseStatus,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	); err != nil {
+		return nil, err
+	}
+
+	return converter.ToWebhookDeliveryFromRepo(&delivery), nil
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, sub model.WebhookSubscription) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	query, args, err := sq.
+		Insert(webhookTable).
+		Columns(webhookURLColumn, webhookEventsColumn, webhookPvzIDsColumn, webhookSecretColumn).
+		Values(sub.URL, sub.EventTypes, converter.ToPvzIDsRepo(sub.PvzIDs), sub.Secret).
+		Suffix("RETURNING " + webhookIDColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return uuid.Nil, fmt.Errorf(FailedCreateWebhook)
+	}
+
+	return id, nil
+}
+
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.WebhookSubscription, error) {
+	query, args, err := sq.
+		Select(webhookColumns...).
+		From(webhookTable).
+		Where(sq.Eq{webhookIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	sub, err := scanWebhook(r.DB.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, notFound(WebhookNotFound, err)
+	}
+
+	return sub, nil
+}
+
+// GetWebhooks возвращает все подписки в порядке создания
+func (r *WebhookRepository) GetWebhooks(ctx context.Context) ([]model.WebhookSubscription, error) {
+	var result []model.WebhookSubscription
+
+	query, args, err := sq.
+		Select(webhookColumns...).
+		From(webhookTable).
+		OrderBy(webhookCreatedAtColumn, webhookIDColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		sub, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf(FailedScanRow)
+		}
+		result = append(result, *sub)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf(FailedScanRow)
+	}
+
+	return result, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом ее доставок
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	query, args, err := sq.
+		Delete(webhookTable).
+		Where(sq.Eq{webhookIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf(FailedDeleteWebhook)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) CreateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) (uuid.UUID, error) {
+	var id uuid.UUID
+
+	query, args, err := sq.
+		Insert(deliveryTable).
+		Columns(
+			deliverySubscriptionIDColumn, deliveryEventIDColumn, deliveryEventTypeColumn, deliveryPayloadColumn,
+			deliveryStatusColumn, deliveryAttemptsColumn, deliveryNextAttemptAtColumn, deliveryLastErrorColumn,
+			deliveryResponseStatusColumn, deliveryDeliveredAtColumn,
+		).
+		Values(
+			delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload),
+			delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError,
+			delivery.ResponseStatus, nullableTime(delivery.DeliveredAt),
+		).
+		Suffix("RETURNING " + deliveryIDColumn).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	if err = r.DB.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		return uuid.Nil, fmt.Errorf(FailedCreateWebhookDelivery)
+	}
+
+	return id, nil
+}
+
+// UpdateWebhookDelivery сохраняет результат попытки доставки
+func (r *WebhookRepository) UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	query, args, err := sq.
+		Update(deliveryTable).
+		Set(deliveryStatusColumn, delivery.Status).
+		Set(deliveryAttemptsColumn, delivery.Attempts).
+		Set(deliveryNextAttemptAtColumn, delivery.NextAttemptAt).
+		Set(deliveryLastErrorColumn, delivery.LastError).
+		Set(deliveryResponseStatusColumn, delivery.ResponseStatus).
+		Set(deliveryDeliveredAtColumn, nullableTime(delivery.DeliveredAt)).
+		Where(sq.Eq{deliveryIDColumn: delivery.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf(FailedBuildQuery)
+	}
+
+	cmdTag, err := r.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf(FailedUpdateWebhookDelivery)
+	}
+
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf(NoRowsAffected)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	query, args, err := sq.
+		Select(deliveryColumns...).
+		From(deliveryTable).
+		Where(sq.Eq{deliveryIDColumn: id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	delivery, err := scanWebhookDelivery(r.DB.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, notFound(WebhookDeliveryNotFound, err)
+	}
+
+	return delivery, nil
+}
+
+// GetDueWebhookDeliveries возвращает до limit доставок, которым пора сделать попытку, от самой давней
+func (r *WebhookRepository) GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	query, args, err := sq.
+		Select(deliveryColumns...).
+		From(deliveryTable).
+		Where(sq.Eq{deliveryStatusColumn: model.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{deliveryNextAttemptAtColumn: now}).
+		OrderBy(deliveryNextAttemptAtColumn, deliveryIDColumn).
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	return r.queryDeliveries(ctx, query, args)
+}
+
+// GetWebhookDeliveries возвращает журнал доставок подписки, новые первыми
+func (r *WebhookRepository) GetWebhookDeliveries(ctx context.Context, q model.WebhookDeliveryQuery) ([]model.WebhookDelivery, error) {
+	builder := sq.
+		Select(deliveryColumns...).
+		From(deliveryTable).
+		Where(sq.Eq{deliverySubscriptionIDColumn: q.SubscriptionID}).
+		OrderBy(deliveryCreatedAtColumn+" DESC", deliveryIDColumn+" DESC").
+		Limit(uint64(q.Limit)).
+		Offset(uint64((q.Page - 1) * q.Limit)).
+		PlaceholderFormat(sq.Dollar)
+
+	if q.Status != "" {
+		builder = builder.Where(sq.Eq{deliveryStatusColumn: q.Status})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf(FailedBuildQuery)
+	}
+
+	return r.queryDeliveries(ctx, query, args)
+}
+
+func (r *WebhookRepository) queryDeliveries(ctx context.Context, query string, args []any) ([]model.WebhookDelivery, error) {
+	var result []model.WebhookDelivery
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(FailedExecuteQuery)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf(FailedScanRow)
+		}
+		result = append(result, *delivery)
+	}
+
+	if rows.Err() != nil {
+		return nil, fmt.Errorf(FailedScanRow)
+	}
+
+	return result, nil
+}
+
+// nullableTime переводит нулевое время в NULL
+func nullableTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,109 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb"
+)
+
+func TestAuthTokenRepository_CreateAuthToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewAuthTokenRepository(mock)
+	token := model.AuthToken{
+		TokenHash: "hash",
+		UserID:    uuid.New(),
+		Purpose:   model.AuthTokenVerifyEmail,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("успешное создание токена", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO auth_token \(token_hash,user_id,purpose,expires_at\) VALUES \(\$1,\$2,\$3,\$4\)`).
+			WithArgs(token.TokenHash, token.UserID, token.Purpose, token.ExpiresAt).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		require.NoError(t, repo.CreateAuthToken(context.Background(), token))
+	})
+
+	t.Run("ошибка вставки", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO auth_token`).
+			WithArgs(token.TokenHash, token.UserID, token.Purpose, token.ExpiresAt).
+			WillReturnError(errors.New("duplicate key value violates unique constraint"))
+
+		assert.ErrorContains(t, repo.CreateAuthToken(context.Background(), token), pgdb.FailedCreateAuthToken)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthTokenRepository_UseAuthToken(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewAuthTokenRepository(mock)
+	now := time.Now()
+
+	t.Run("токен гасится одним запросом", func(t *testing.T) {
+		userID := uuid.New()
+		mock.ExpectQuery(`UPDATE auth_token SET used_at = \$1 WHERE purpose = \$2 AND token_hash = \$3 AND used_at IS NULL AND expires_at > \$4 RETURNING`).
+			WithArgs(now, model.AuthTokenResetPassword, "hash", now).
+			WillReturnRows(mock.NewRows([]string{"token_hash", "user_id", "purpose", "expires_at", "used_at", "created_at"}).
+				AddRow("hash", userID, model.AuthTokenResetPassword, now.Add(time.Hour), now, now.Add(-time.Minute)))
+
+		token, err := repo.UseAuthToken(context.Background(), model.AuthTokenResetPassword, "hash", now)
+		require.NoError(t, err)
+		assert.Equal(t, userID, token.UserID)
+		assert.Equal(t, now, token.UsedAt)
+	})
+
+	t.Run("токен истек или уже использован", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE auth_token SET used_at`).
+			WithArgs(now, model.AuthTokenResetPassword, "hash", now).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.UseAuthToken(context.Background(), model.AuthTokenResetPassword, "hash", now)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.ErrorContains(t, err, pgdb.AuthTokenNotFound)
+	})
+
+	t.Run("сбой базы", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE auth_token SET used_at`).
+			WithArgs(now, model.AuthTokenResetPassword, "hash", now).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.UseAuthToken(context.Background(), model.AuthTokenResetPassword, "hash", now)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthTokenRepository_UseUserAuthTokens(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewAuthTokenRepository(mock)
+	userID, now := uuid.New(), time.Now()
+
+	mock.ExpectExec(`UPDATE auth_token SET used_at = \$1 WHERE purpose = \$2 AND used_at IS NULL AND user_id = \$3`).
+		WithArgs(now, model.AuthTokenResetPassword, userID.String()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+
+	require.NoError(t, repo.UseUserAuthTokens(context.Background(), userID, model.AuthTokenResetPassword, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,37 @@
+package pgdb_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/pgdb"
+	"pvz-service/internal/repository/repotest"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tenant"
+)
+
+// Прогон общего набора проверок на живой базе с примененными миграциями.
+// Запускается только при заданном PVZ_TEST_DATABASE_DSN
+func TestPostgresRepository_Conformance(t *testing.T) {
+	dsn := os.Getenv("PVZ_TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("PVZ_TEST_DATABASE_DSN is not set")
+	}
+
+	pool, err := pgxpool.Connect(context.Background(), dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	repotest.RunConformance(t, func(t *testing.T) service.Repository {
+		_, err := pool.Exec(context.Background(), "TRUNCATE users, pvz, webhook_subscription CASCADE")
+		require.NoError(t, err)
+		// Операторы, созданные подтестами, удаляются после их данных
+		_, err = pool.Exec(context.Background(), "DELETE FROM tenant WHERE id <> $1", tenant.DefaultID)
+		require.NoError(t, err)
+		return repository.NewRepository(pool, pgdb.NewTenantDB(pool))
+	})
+}
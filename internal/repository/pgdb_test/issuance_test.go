@@ -0,0 +1,196 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb"
+)
+
+func TestIssuanceRepository_CreateIssuance(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewIssuanceRepository(mock)
+	issuance := model.Issuance{ProductID: uuid.New(), PvzID: uuid.New(), EmployeeID: "employee-1", ConfirmationCode: "4821"}
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+		now := time.Now().UTC()
+
+		mock.ExpectQuery(`INSERT INTO issuance \(product_id,pvz_id,employee_id,confirmation_code\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id, issued_at`).
+			WithArgs(issuance.ProductID, issuance.PvzID, issuance.EmployeeID, issuance.ConfirmationCode).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "issued_at"}).AddRow(id, now))
+
+		created, err := repo.CreateIssuance(context.Background(), issuance)
+		require.NoError(t, err)
+		assert.Equal(t, id, created.ID)
+		assert.Equal(t, now, created.IssuedAt)
+		assert.Equal(t, issuance.ConfirmationCode, created.ConfirmationCode)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("foreign key violation", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO issuance`).
+			WithArgs(issuance.ProductID, issuance.PvzID, issuance.EmployeeID, issuance.ConfirmationCode).
+			WillReturnError(errors.New("violates foreign key constraint"))
+
+		created, err := repo.CreateIssuance(context.Background(), issuance)
+		assert.Nil(t, created)
+		assert.EqualError(t, err, pgdb.FailedCreateIssuance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestIssuanceRepository_CreateReturn(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewIssuanceRepository(mock)
+	ret := model.ProductReturn{ProductID: uuid.New(), PvzID: uuid.New(), EmployeeID: "employee-1", Reason: "брак"}
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(`INSERT INTO product_return \(product_id,pvz_id,employee_id,reason\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id, returned_at`).
+		WithArgs(ret.ProductID, ret.PvzID, ret.EmployeeID, ret.Reason).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "returned_at"}).AddRow(id, now))
+
+	created, err := repo.CreateReturn(context.Background(), ret)
+	require.NoError(t, err)
+	assert.Equal(t, id, created.ID)
+	assert.Equal(t, now, created.ReturnedAt)
+	assert.Equal(t, ret.Reason, created.Reason)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIssuanceRepository_GetLastIssuance(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewIssuanceRepository(mock)
+	productID := uuid.New()
+
+	t.Run("found", func(t *testing.T) {
+		id, pvzID := uuid.New(), uuid.New()
+		now := time.Now().UTC()
+
+		mock.ExpectQuery(`SELECT id, product_id, pvz_id, issued_at, employee_id, confirmation_code FROM issuance WHERE product_id = \$1 ORDER BY issued_at DESC LIMIT 1`).
+			WithArgs(productID.String()).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "product_id", "pvz_id", "issued_at", "employee_id", "confirmation_code"}).
+				AddRow(id, productID, pvzID, now, "employee-1", "4821"))
+
+		issuance, err := repo.GetLastIssuance(context.Background(), productID)
+		require.NoError(t, err)
+		assert.Equal(t, &model.Issuance{
+			ID: id, ProductID: productID, PvzID: pvzID, IssuedAt: now, EmployeeID: "employee-1", ConfirmationCode: "4821",
+		}, issuance)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not issued", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM issuance`).
+			WithArgs(productID.String()).
+			WillReturnError(pgx.ErrNoRows)
+
+		issuance, err := repo.GetLastIssuance(context.Background(), productID)
+		assert.Nil(t, issuance)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM issuance`).
+			WithArgs(productID.String()).
+			WillReturnError(errors.New("connection refused"))
+
+		issuance, err := repo.GetLastIssuance(context.Background(), productID)
+		assert.Nil(t, issuance)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestIssuanceRepository_GetLastReturn(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewIssuanceRepository(mock)
+	productID := uuid.New()
+
+	mock.ExpectQuery(`SELECT id, product_id, pvz_id, returned_at, employee_id, reason FROM product_return WHERE product_id = \$1 ORDER BY returned_at DESC LIMIT 1`).
+		WithArgs(productID.String()).
+		WillReturnError(pgx.ErrNoRows)
+
+	ret, err := repo.GetLastReturn(context.Background(), productID)
+	assert.Nil(t, ret)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIssuanceRepository_LockProduct(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewIssuanceRepository(mock)
+	productID := uuid.New()
+
+	t.Run("locked", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id FROM product WHERE id = \$1 FOR UPDATE`).
+			WithArgs(productID.String()).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(productID))
+
+		assert.NoError(t, repo.LockProduct(context.Background(), productID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id FROM product WHERE id = \$1 FOR UPDATE`).
+			WithArgs(productID.String()).
+			WillReturnError(pgx.ErrNoRows)
+
+		assert.ErrorIs(t, repo.LockProduct(context.Background(), productID), model.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestIssuanceRepository_GetStock(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewIssuanceRepository(mock)
+	pvzID := uuid.New()
+	productID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(`SELECT p.id, p.date_time, p.type_product, p.reception_id, p.barcode, p.sku, p.weight_grams, p.length_mm, p.width_mm, p.height_mm, p.cell_id `+
+		`FROM product p JOIN reception r ON r.id = p.reception_id `+
+		`WHERE r.pvz_id = \$1 AND r.is_closed = \$2 `+
+		`AND \(SELECT COUNT\(\*\) FROM issuance i WHERE i.product_id = p.id\) = \(SELECT COUNT\(\*\) FROM product_return pr WHERE pr.product_id = p.id\) `+
+		`ORDER BY p.date_time`).
+		WithArgs(pvzID.String(), true).
+		WillReturnRows(pgxmock.NewRows(productRowColumns).
+			AddRow(productID, now, "обувь", receptionID, "", "", 0, 0, 0, 0, nil))
+
+	products, err := repo.GetStock(context.Background(), pvzID)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, productID, products[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,9 +9,15 @@ import (
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
 	"pvz-service/internal/repository/pgdb"
 )
 
+var productRowColumns = []string{
+	"id", "date_time", "type_product", "reception_id",
+	"barcode", "sku", "weight_grams", "length_mm", "width_mm", "height_mm", "cell_id",
+}
+
 func TestProductRepository_CreateProduct(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	require.NoError(t, err)
@@ -19,15 +25,20 @@ func TestProductRepository_CreateProduct(t *testing.T) {
 
 	repo := pgdb.NewProductRepository(mock)
 
-	typeProduct := "Техника"
-	receptionID := uuid.New()
+	product := model.Product{
+		TypeProduct: "Техника",
+		ReceptionID: uuid.New(),
+		Barcode:     "4006381333931",
+		WeightGrams: 500,
+		Dimensions:  model.Dimensions{LengthMM: 10, WidthMM: 20, HeightMM: 30},
+	}
 	newID := uuid.New()
 
-	mock.ExpectQuery(`INSERT INTO product\s*\(type_product,reception_id\)\s*VALUES\s*\(\$1,\$2\)\s*RETURNING id`).
-		WithArgs(typeProduct, receptionID).
+	mock.ExpectQuery(`INSERT INTO product\s*\(type_product,reception_id,barcode,sku,weight_grams,length_mm,width_mm,height_mm,cell_id\)\s*VALUES\s*\(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9\)\s*RETURNING id`).
+		WithArgs(product.TypeProduct, product.ReceptionID, product.Barcode, "", 500, 10, 20, 30, nil).
 		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(newID))
 
-	id, err := repo.CreateProduct(context.Background(), typeProduct, receptionID)
+	id, err := repo.CreateProduct(context.Background(), product)
 	require.NoError(t, err)
 	assert.Equal(t, newID, id)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -45,11 +56,11 @@ func TestProductRepository_GetProductByID(t *testing.T) {
 	now := time.Now().UTC()
 	typeProduct := "Одежда"
 
-	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id FROM product WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id, barcode, sku, weight_grams, length_mm, width_mm, height_mm, cell_id FROM product WHERE id = \$1`).
 		WithArgs(productID.String()).
 		WillReturnRows(
-			pgxmock.NewRows([]string{"id", "date_time", "type_product", "reception_id"}).
-				AddRow(productID, now, typeProduct, receptionID),
+			pgxmock.NewRows(productRowColumns).
+				AddRow(productID, now, typeProduct, receptionID, "", "", 0, 0, 0, 0, nil),
 		)
 
 	product, err := repo.GetProductByID(context.Background(), productID)
@@ -73,11 +84,11 @@ func TestProductRepository_GetLastProduct(t *testing.T) {
 	now := time.Now().UTC()
 	typeProduct := "Книги"
 
-	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id FROM product WHERE reception_id = \$1 ORDER BY date_time DESC LIMIT 1`).
+	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id, barcode, sku, weight_grams, length_mm, width_mm, height_mm, cell_id FROM product WHERE reception_id = \$1 ORDER BY date_time DESC LIMIT 1`).
 		WithArgs(receptionID.String()).
 		WillReturnRows(
-			pgxmock.NewRows([]string{"id", "date_time", "type_product", "reception_id"}).
-				AddRow(productID, now, typeProduct, receptionID),
+			pgxmock.NewRows(productRowColumns).
+				AddRow(productID, now, typeProduct, receptionID, "", "", 0, 0, 0, 0, nil),
 		)
 
 	product, err := repo.GetLastProduct(context.Background(), receptionID)
@@ -119,11 +130,11 @@ func TestProductRepository_GetProductSliceByReceptionID(t *testing.T) {
 	now := time.Now().UTC()
 	typeProduct := "Техника"
 
-	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id FROM product WHERE reception_id = \$1`).
+	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id, barcode, sku, weight_grams, length_mm, width_mm, height_mm, cell_id FROM product WHERE reception_id = \$1`).
 		WithArgs(receptionID.String()).
 		WillReturnRows(
-			pgxmock.NewRows([]string{"id", "date_time", "type_product", "reception_id"}).
-				AddRow(productID, now, typeProduct, receptionID),
+			pgxmock.NewRows(productRowColumns).
+				AddRow(productID, now, typeProduct, receptionID, "", "", 0, 0, 0, 0, nil),
 		)
 
 	products, err := repo.GetProductSliceByReceptionID(context.Background(), receptionID)
@@ -135,3 +146,33 @@ func TestProductRepository_GetProductSliceByReceptionID(t *testing.T) {
 	assert.Equal(t, receptionID, products[0].ReceptionID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestProductRepository_GetProductsByBarcode(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewProductRepository(mock)
+
+	barcode := "4006381333931"
+	productID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(`SELECT id, date_time, type_product, reception_id, barcode, sku, weight_grams, length_mm, width_mm, height_mm, cell_id FROM product WHERE barcode = \$1 ORDER BY date_time DESC`).
+		WithArgs(barcode).
+		WillReturnRows(
+			pgxmock.NewRows(productRowColumns).
+				AddRow(productID, now, "обувь", receptionID, barcode, "SKU-7", 1500, 300, 200, 100, nil),
+		)
+
+	products, err := repo.GetProductsByBarcode(context.Background(), barcode)
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, productID, products[0].ID)
+	assert.Equal(t, barcode, products[0].Barcode)
+	assert.Equal(t, "SKU-7", products[0].SKU)
+	assert.Equal(t, 1500, products[0].WeightGrams)
+	assert.Equal(t, model.Dimensions{LengthMM: 300, WidthMM: 200, HeightMM: 100}, products[0].Dimensions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,13 +3,16 @@ package pgdb_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
 	"pvz-service/internal/repository/pgdb"
 )
 
@@ -33,6 +36,13 @@ func TestPVZRepository_CreatePvz(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+var pvzRowColumns = []string{
+	"id", "registration_date", "city", "status", "address", "latitude", "longitude",
+	"timezone", "working_hours", "max_daily_receptions", "reception_override_until",
+}
+
+var pvzSelectColumns = strings.Join(pvzRowColumns, ", ")
+
 func TestPVZRepository_GetPvzByID_NotFound(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	assert.NoError(t, err)
@@ -43,16 +53,26 @@ func TestPVZRepository_GetPvzByID_NotFound(t *testing.T) {
 	id := uuid.New()
 
 	// Мокаем SQL-запрос на получение с ошибкой (если запись не найдена)
-	mock.ExpectQuery(`^SELECT id, registration_date, city FROM pvz WHERE id = \$1`).
-		WithArgs(id).
-		WillReturnError(fmt.Errorf("pvz not found"))
+	mock.ExpectQuery(`^SELECT ` + pvzSelectColumns + ` FROM pvz WHERE id = \$1`).
+		WithArgs(id.String()).
+		WillReturnError(pgx.ErrNoRows)
 
 	// Пытаемся получить запись по несуществующему ID
 	pvz, err := repo.GetPvzByID(context.Background(), id)
-	assert.Error(t, err)                       // Ожидаем ошибку
-	assert.Nil(t, pvz)                         // Ожидаем, что возвращаемый объект nil
-	assert.EqualError(t, err, "pvz not found") // Проверяем, что ошибка именно такая, как мы ожидаем
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	assert.Nil(t, pvz)
+	assert.EqualError(t, err, "pvz not found: record not found")
+
+	// Сбой базы - не "ПВЗ не найден"
+	mock.ExpectQuery(`^SELECT ` + pvzSelectColumns + ` FROM pvz WHERE id = \$1`).
+		WithArgs(id.String()).
+		WillReturnError(fmt.Errorf("connection refused"))
 
+	pvz, err = repo.GetPvzByID(context.Background(), id)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, model.ErrNotFound)
+	assert.Nil(t, pvz)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestPVZRepository_GetPvzByID_AfterCreate(t *testing.T) {
@@ -78,11 +98,11 @@ func TestPVZRepository_GetPvzByID_AfterCreate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, id, createdID)
 
-	mock.ExpectQuery(`SELECT id, registration_date, city FROM pvz WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT ` + pvzSelectColumns + ` FROM pvz WHERE id = \$1`).
 		WithArgs(id.String()).
 		WillReturnRows(
-			pgxmock.NewRows([]string{"id", "registration_date", "city"}).
-				AddRow(id, registrationDate, city),
+			pgxmock.NewRows(pvzRowColumns).
+				AddRow(id, registrationDate, city, "active", "", nil, nil, model.DefaultTimezone, []byte("{}"), 0, nil),
 		)
 
 	pvz, err := repo.GetPvzByID(context.Background(), createdID)
@@ -119,3 +139,221 @@ func TestPVZRepository_GetIDListPvz(t *testing.T) {
 	assert.Contains(t, ids, id2)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestPVZRepository_ReadReplica(t *testing.T) {
+	primary, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer primary.Close()
+
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	repo := pgdb.NewPVZRepository(primary)
+	repo.ReadDB = replica
+
+	pvzID := uuid.New()
+
+	// список ПВЗ читается с реплики
+	replica.ExpectQuery("SELECT id FROM pvz").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(pvzID))
+
+	// поиск по id остается на primary: он нужен сразу после создания
+	primary.ExpectQuery("SELECT " + pvzSelectColumns + " FROM pvz").
+		WithArgs(pvzID.String()).
+		WillReturnRows(pgxmock.NewRows(pvzRowColumns).
+			AddRow(pvzID, time.Now(), "Москва", "active", "", nil, nil, model.DefaultTimezone, []byte("{}"), 0, nil))
+
+	ids, err := repo.GetIDListPvz(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{pvzID}, ids)
+
+	_, err = repo.GetPvzByID(context.Background(), pvzID)
+	require.NoError(t, err)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestPVZRepository_GetPvzByID_Profile(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewPVZRepository(mock)
+
+	id := uuid.New()
+	lat, lng := 55.7558, 37.6173
+	overrideUntil := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	hours := `{"weekly":{"1":{"open":540,"close":1260}},"exceptions":[{"date":"2024-03-08","closed":true}]}`
+
+	mock.ExpectQuery(`SELECT ` + pvzSelectColumns + ` FROM pvz WHERE id = \$1`).
+		WithArgs(id.String()).
+		WillReturnRows(pgxmock.NewRows(pvzRowColumns).
+			AddRow(id, time.Now(), "Москва", "suspended", "ул. Тверская, 1", &lat, &lng, "Europe/Moscow", []byte(hours), 3, &overrideUntil))
+
+	pvz, err := repo.GetPvzByID(context.Background(), id)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ул. Тверская, 1", pvz.Address)
+	assert.Equal(t, &model.GeoPoint{Lat: lat, Lng: lng}, pvz.Coordinates)
+	assert.Equal(t, 3, pvz.MaxDailyReceptions)
+	assert.Equal(t, model.PvzStatusSuspended, pvz.Status)
+	assert.Equal(t, overrideUntil, pvz.ReceptionOverrideUntil)
+	assert.Equal(t, map[time.Weekday]model.DayHours{time.Monday: {Open: 540, Close: 1260}}, pvz.WorkingHours.Weekly)
+	assert.Equal(t, []model.HoursException{{Date: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), Closed: true}},
+		pvz.WorkingHours.Exceptions)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZRepository_UpdatePvzProfile(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewPVZRepository(mock)
+
+	pvz := model.Pvz{
+		ID:       uuid.New(),
+		Address:  "Невский пр., 28",
+		Timezone: "Europe/Moscow",
+		WorkingHours: model.WorkingHours{
+			Weekly: map[time.Weekday]model.DayHours{time.Saturday: {Open: 600, Close: 1080}},
+		},
+		MaxDailyReceptions: 2,
+	}
+
+	mock.ExpectExec(`UPDATE pvz SET address = \$1, latitude = \$2, longitude = \$3, timezone = \$4, `+
+		`working_hours = \$5, max_daily_receptions = \$6, reception_override_until = \$7 WHERE id = \$8`).
+		WithArgs(pvz.Address, nil, nil, pvz.Timezone, `{"weekly":{"6":{"open":600,"close":1080}}}`, 2, nil, pvz.ID.String()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	require.NoError(t, repo.UpdatePvzProfile(context.Background(), pvz))
+
+	// Координаты и разрешение модератора пишутся как значения
+	pvz.Coordinates = &model.GeoPoint{Lat: 59.93, Lng: 30.33}
+	pvz.ReceptionOverrideUntil = time.Date(2024, 3, 1, 23, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	mock.ExpectExec(`UPDATE pvz SET`).
+		WithArgs(pvz.Address, 59.93, 30.33, pvz.Timezone, pgxmock.AnyArg(), 2,
+			time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC), pvz.ID.String()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	assert.EqualError(t, repo.UpdatePvzProfile(context.Background(), pvz), pgdb.NoRowsAffected)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZRepository_GetNearbyPvz(t *testing.T) {
+	primary, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer primary.Close()
+
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	repo := pgdb.NewPVZRepository(primary)
+	repo.ReadDB = replica
+
+	point := model.GeoPoint{Lat: 55.75, Lng: 37.62}
+	box := point.BoundingBox(2000)
+	id := uuid.New()
+	lat, lng := 55.76, 37.61
+
+	// Расстояние считается в SQL, поиск идет по реплике
+	replica.ExpectQuery(`SELECT `+pvzSelectColumns+`, \(2 \* 6371008\.8 \* ASIN\(.+\)\) AS distance FROM pvz `+
+		`WHERE \(status <> \$4 AND latitude BETWEEN \$5 AND \$6 AND longitude BETWEEN \$7 AND \$8 AND 2 \* .+ <= \$12\) `+
+		`ORDER BY distance, id LIMIT 5`).
+		WithArgs(point.Lat, point.Lat, point.Lng, model.PvzStatusDecommissioned, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng,
+			point.Lat, point.Lat, point.Lng, 2000.0).
+		WillReturnRows(pgxmock.NewRows(append(pvzRowColumns, "distance")).
+			AddRow(id, time.Now(), "Москва", "active", "", &lat, &lng, "Europe/Moscow", []byte(`{}`), 0, nil, 1250.5))
+
+	list, err := repo.GetNearbyPvz(context.Background(), model.NearbyQuery{Point: point, RadiusMeters: 2000, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, id, list[0].Pvz.ID)
+	assert.Equal(t, &model.GeoPoint{Lat: lat, Lng: lng}, list[0].Pvz.Coordinates)
+	assert.Equal(t, 1250.5, list[0].DistanceMeters)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestPVZRepository_UpdatePvzStatus(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewPVZRepository(mock)
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE pvz SET status = \$1 WHERE id = \$2 AND status = \$3`).
+		WithArgs(model.PvzStatusSuspended, id.String(), model.PvzStatusActive).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	require.NoError(t, repo.UpdatePvzStatus(context.Background(), id, model.PvzStatusActive, model.PvzStatusSuspended))
+
+	// Статус уже сменили параллельно - строка не обновляется
+	mock.ExpectExec(`UPDATE pvz SET status = \$1 WHERE id = \$2 AND status = \$3`).
+		WithArgs(model.PvzStatusActive, id.String(), model.PvzStatusSuspended).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+	assert.EqualError(t, repo.UpdatePvzStatus(context.Background(), id, model.PvzStatusSuspended, model.PvzStatusActive),
+		pgdb.NoRowsAffected)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPVZRepository_ChangeStamps(t *testing.T) {
+	primary, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer primary.Close()
+
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer replica.Close()
+
+	repo := pgdb.NewPVZRepository(primary)
+	repo.ReadDB = replica
+
+	pvzID := uuid.New()
+	changedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// отметки читаются с реплики, как и данные, по которым строится ответ
+	// и учитывают отметки товаров на приемках ПВЗ
+	pvzStampQuery := `^SELECT GREATEST\(pvz\.changed_at, \(SELECT COALESCE\(MAX\(products_changed_at\), 'epoch'\) ` +
+		`FROM reception WHERE reception\.pvz_id = pvz\.id\)\) FROM pvz WHERE id = \$1`
+	replica.ExpectQuery(pvzStampQuery).
+		WithArgs(pvzID.String()).
+		WillReturnRows(pgxmock.NewRows([]string{"greatest"}).AddRow(changedAt))
+	replica.ExpectQuery(pvzStampQuery).
+		WithArgs(pvzID.String()).
+		WillReturnError(pgx.ErrNoRows)
+	replica.ExpectQuery(pvzStampQuery).
+		WithArgs(pvzID.String()).
+		WillReturnError(fmt.Errorf("connection refused"))
+	replica.ExpectQuery(`^SELECT COUNT\(\*\), GREATEST\(COALESCE\(MAX\(changed_at\), 'epoch'\), ` +
+		`\(SELECT COALESCE\(MAX\(products_changed_at\), 'epoch'\) FROM reception\)\) FROM pvz`).
+		WillReturnRows(pgxmock.NewRows([]string{"count", "greatest"}).AddRow(3, changedAt))
+
+	stamp, err := repo.GetPvzChangeStamp(context.Background(), pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeStamp{ChangedAt: changedAt, Count: 1}, stamp)
+
+	_, err = repo.GetPvzChangeStamp(context.Background(), pvzID)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	_, err = repo.GetPvzChangeStamp(context.Background(), pvzID)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, model.ErrNotFound)
+
+	stamp, err = repo.GetPvzListChangeStamp(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, model.ChangeStamp{ChangedAt: changedAt, Count: 3}, stamp)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
@@ -7,13 +7,19 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 
+	"pvz-service/internal/model"
 	"pvz-service/internal/repository/pgdb"
 	modelRepo "pvz-service/internal/repository/pgdb/model"
 )
 
+var receptionColumns = []string{
+	"id", "date_time", "is_closed", "pvz_id", "close_reason", "closed_at", "flagged_at", "reminded_at",
+}
+
 func TestCreateReception(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	if err != nil {
@@ -71,10 +77,10 @@ func TestGetReceptionByID(t *testing.T) {
 			PvzID:          uuid.New(),
 		}
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(id.String()).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID))
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID, nil, nil, nil, nil))
 
 		reception, err := repo.GetReceptionByID(context.Background(), id)
 
@@ -89,7 +95,7 @@ func TestGetReceptionByID(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		id := uuid.New()
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(id.String()).
 			WillReturnError(errors.New("no rows in result set"))
 
@@ -120,10 +126,10 @@ func TestGetLastReception(t *testing.T) {
 			PvzID:          pvzID,
 		}
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(pvzID.String()).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID))
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID, nil, nil, nil, nil))
 
 		reception, err := repo.GetLastReception(context.Background(), pvzID)
 
@@ -138,15 +144,30 @@ func TestGetLastReception(t *testing.T) {
 	t.Run("not found", func(t *testing.T) {
 		pvzID := uuid.New()
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(pvzID.String()).
-			WillReturnError(errors.New("no rows in result set"))
+			WillReturnError(pgx.ErrNoRows)
+
+		reception, err := repo.GetLastReception(context.Background(), pvzID)
+
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Nil(t, reception)
+		assert.Equal(t, "reception not found: record not found", err.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error is not not found", func(t *testing.T) {
+		pvzID := uuid.New()
+
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
+			WithArgs(pvzID.String()).
+			WillReturnError(errors.New("connection refused"))
 
 		reception, err := repo.GetLastReception(context.Background(), pvzID)
 
 		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
 		assert.Nil(t, reception)
-		assert.Equal(t, "reception not found", err.Error())
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
@@ -164,10 +185,10 @@ func TestCloseReception(t *testing.T) {
 		receptionID := uuid.New()
 
 		mock.ExpectExec("UPDATE reception").
-			WithArgs(true, receptionID.String()).
+			WithArgs(true, model.CloseReasonManual, receptionID.String(), false).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
 
-		err := repo.CloseReception(context.Background(), receptionID)
+		err := repo.CloseReception(context.Background(), receptionID, model.CloseReasonManual)
 
 		assert.NoError(t, err)
 		assert.NoError(t, mock.ExpectationsWereMet())
@@ -177,10 +198,10 @@ func TestCloseReception(t *testing.T) {
 		receptionID := uuid.New()
 
 		mock.ExpectExec("UPDATE reception").
-			WithArgs(true, receptionID.String()).
+			WithArgs(true, model.CloseReasonManual, receptionID.String(), false).
 			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
 
-		err := repo.CloseReception(context.Background(), receptionID)
+		err := repo.CloseReception(context.Background(), receptionID, model.CloseReasonManual)
 
 		assert.Error(t, err)
 		assert.Equal(t, "no rows affected", err.Error())
@@ -191,10 +212,10 @@ func TestCloseReception(t *testing.T) {
 		receptionID := uuid.New()
 
 		mock.ExpectExec("UPDATE reception").
-			WithArgs(true, receptionID.String()).
+			WithArgs(true, model.CloseReasonManual, receptionID.String(), false).
 			WillReturnError(errors.New("database error"))
 
-		err := repo.CloseReception(context.Background(), receptionID)
+		err := repo.CloseReception(context.Background(), receptionID, model.CloseReasonManual)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to execute query")
@@ -231,11 +252,11 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 			},
 		}
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(begin, end).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow(expectedReceptions[0].ID, expectedReceptions[0].DateTime, expectedReceptions[0].IsClosedStatus, expectedReceptions[0].PvzID).
-				AddRow(expectedReceptions[1].ID, expectedReceptions[1].DateTime, expectedReceptions[1].IsClosedStatus, expectedReceptions[1].PvzID))
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(expectedReceptions[0].ID, expectedReceptions[0].DateTime, expectedReceptions[0].IsClosedStatus, expectedReceptions[0].PvzID, nil, nil, nil, nil).
+				AddRow(expectedReceptions[1].ID, expectedReceptions[1].DateTime, expectedReceptions[1].IsClosedStatus, expectedReceptions[1].PvzID, nil, nil, nil, nil))
 
 		receptions, err := repo.GetReceptionsSliceWithTimeRange(context.Background(), begin, end)
 
@@ -254,10 +275,10 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 			PvzID:          uuid.New(),
 		}
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(begin).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID))
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID, nil, nil, nil, nil))
 
 		receptions, err := repo.GetReceptionsSliceWithTimeRange(context.Background(), begin, time.Time{})
 
@@ -275,10 +296,10 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 			PvzID:          uuid.New(),
 		}
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(end).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID))
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(expectedReception.ID, expectedReception.DateTime, expectedReception.IsClosedStatus, expectedReception.PvzID, nil, nil, nil, nil))
 
 		receptions, err := repo.GetReceptionsSliceWithTimeRange(context.Background(), time.Time{}, end)
 
@@ -304,10 +325,10 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 			},
 		}
 
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow(expectedReceptions[0].ID, expectedReceptions[0].DateTime, expectedReceptions[0].IsClosedStatus, expectedReceptions[0].PvzID).
-				AddRow(expectedReceptions[1].ID, expectedReceptions[1].DateTime, expectedReceptions[1].IsClosedStatus, expectedReceptions[1].PvzID))
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(expectedReceptions[0].ID, expectedReceptions[0].DateTime, expectedReceptions[0].IsClosedStatus, expectedReceptions[0].PvzID, nil, nil, nil, nil).
+				AddRow(expectedReceptions[1].ID, expectedReceptions[1].DateTime, expectedReceptions[1].IsClosedStatus, expectedReceptions[1].PvzID, nil, nil, nil, nil))
 
 		receptions, err := repo.GetReceptionsSliceWithTimeRange(context.Background(), time.Time{}, time.Time{})
 
@@ -319,7 +340,7 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 	})
 
 	t.Run("error", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(begin, end).
 			WillReturnError(errors.New("database error"))
 
@@ -332,10 +353,10 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 	})
 
 	t.Run("scan error", func(t *testing.T) {
-		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id FROM reception").
+		mock.ExpectQuery("SELECT id, date_time, is_closed, pvz_id, close_reason, closed_at, flagged_at, reminded_at FROM reception").
 			WithArgs(begin, end).
-			WillReturnRows(pgxmock.NewRows([]string{"id", "date_time", "is_closed", "pvz_id"}).
-				AddRow("invalid-uuid", now, false, uuid.New())) // Invalid UUID format
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow("invalid-uuid", now, false, uuid.New(), nil, nil, nil, nil)) // Invalid UUID format
 
 		receptions, err := repo.GetReceptionsSliceWithTimeRange(context.Background(), begin, end)
 
@@ -345,3 +366,114 @@ func TestGetReceptionsSliceWithTimeRange(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestCountReceptions(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewReceptionRepository(mock)
+
+	pvzID := uuid.New()
+	begin := time.Date(2024, 3, 1, 21, 0, 0, 0, time.UTC)
+	end := begin.Add(24 * time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reception WHERE pvz_id = \$1 AND date_time >= \$2 AND date_time < \$3`).
+			WithArgs(pvzID.String(), begin, end).
+			WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+
+		count, err := repo.CountReceptions(context.Background(), pvzID, begin, end)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reception`).
+			WithArgs(pvzID.String(), begin, end).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.CountReceptions(context.Background(), pvzID, begin, end)
+
+		assert.EqualError(t, err, pgdb.FailedExecuteQuery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMarkReception(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewReceptionRepository(mock)
+
+	receptionID := uuid.New()
+
+	t.Run("flag", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE reception SET flagged_at = NOW\(\) WHERE flagged_at IS NULL AND id = \$1 AND is_closed = \$2`).
+			WithArgs(receptionID.String(), false).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+		assert.NoError(t, repo.FlagReception(context.Background(), receptionID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already reminded", func(t *testing.T) {
+		mock.ExpectExec(`UPDATE reception SET reminded_at = NOW\(\) WHERE id = \$1 AND is_closed = \$2 AND reminded_at IS NULL`).
+			WithArgs(receptionID.String(), false).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+
+		err := repo.MarkReceptionReminded(context.Background(), receptionID)
+
+		assert.EqualError(t, err, pgdb.NoRowsAffected)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetOpenReceptionsBefore(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewReceptionRepository(mock)
+
+	before := time.Now().Add(-24 * time.Hour)
+	flaggedAt := time.Now().Add(-time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+
+		mock.ExpectQuery(`SELECT .+ FROM reception WHERE is_closed = \$1 AND date_time < \$2 ORDER BY date_time, id`).
+			WithArgs(false, before).
+			WillReturnRows(pgxmock.NewRows(receptionColumns).
+				AddRow(id, before.Add(-time.Hour), false, uuid.New(), nil, nil, &flaggedAt, nil))
+
+		receptions, err := repo.GetOpenReceptionsBefore(context.Background(), before)
+
+		assert.NoError(t, err)
+		assert.Len(t, receptions, 1)
+		assert.Equal(t, id, receptions[0].ID)
+		assert.Equal(t, flaggedAt, receptions[0].FlaggedAt)
+		assert.True(t, receptions[0].RemindedAt.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .+ FROM reception`).
+			WithArgs(false, before).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.GetOpenReceptionsBefore(context.Background(), before)
+
+		assert.EqualError(t, err, pgdb.FailedExecuteQuery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,111 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb"
+)
+
+func TestStorageCellRepository_CreateStorageCell(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewStorageCellRepository(mock)
+	cell := model.StorageCell{PvzID: uuid.New(), Code: "A-01", Capacity: 10, SizeClass: model.SizeSmall}
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+
+		mock.ExpectQuery(`INSERT INTO storage_cell \(pvz_id,code,capacity,size_class\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id`).
+			WithArgs(cell.PvzID, cell.Code, cell.Capacity, cell.SizeClass).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(id))
+
+		got, err := repo.CreateStorageCell(context.Background(), cell)
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate code", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO storage_cell`).
+			WithArgs(cell.PvzID, cell.Code, cell.Capacity, cell.SizeClass).
+			WillReturnError(errors.New("duplicate key value violates unique constraint"))
+
+		got, err := repo.CreateStorageCell(context.Background(), cell)
+		assert.Equal(t, uuid.Nil, got)
+		assert.EqualError(t, err, pgdb.FailedCreateStorageCell)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStorageCellRepository_DeleteStorageCell(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewStorageCellRepository(mock)
+	id := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM storage_cell WHERE id = \$1`).
+		WithArgs(id.String()).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	err = repo.DeleteStorageCell(context.Background(), id)
+	assert.EqualError(t, err, pgdb.NoRowsAffected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorageCellRepository_GetStorageCells(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewStorageCellRepository(mock)
+	pvzID := uuid.New()
+	cellID := uuid.New()
+
+	mock.ExpectQuery(`SELECT c.id, c.pvz_id, c.code, c.capacity, c.size_class, COUNT\(p.id\) FROM storage_cell c ` +
+		`LEFT JOIN product p ON p.cell_id = c.id AND \(SELECT COUNT\(\*\) FROM issuance i WHERE i.product_id = p.id\) = ` +
+		`\(SELECT COUNT\(\*\) FROM product_return pr WHERE pr.product_id = p.id\) ` +
+		`WHERE c.pvz_id = \$1 GROUP BY c.id ORDER BY c.code`).
+		WithArgs(pvzID.String()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "pvz_id", "code", "capacity", "size_class", "occupied"}).
+			AddRow(cellID, pvzID, "A-01", 10, model.SizeSmall, 3))
+
+	cells, err := repo.GetStorageCells(context.Background(), pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, []model.StorageCell{{
+		ID: cellID, PvzID: pvzID, Code: "A-01", Capacity: 10, SizeClass: model.SizeSmall, Occupied: 3,
+	}}, cells)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_GetProductByIDWithCell(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewProductRepository(mock)
+	productID := uuid.New()
+	cellID := uuid.New()
+
+	mock.ExpectQuery(`SELECT .* FROM product WHERE id = \$1`).
+		WithArgs(productID.String()).
+		WillReturnRows(pgxmock.NewRows(productRowColumns).
+			AddRow(productID, time.Now().UTC(), "обувь", uuid.New(), "", "", 0, 0, 0, 0, &cellID))
+
+	product, err := repo.GetProductByID(context.Background(), productID)
+	require.NoError(t, err)
+	assert.Equal(t, cellID, product.CellID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,198 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb"
+)
+
+func TestInsertReception(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewReceptionRepository(mock)
+	reception := model.Reception{ID: uuid.New(), DateTime: time.Now().UTC(), PvzID: uuid.New()}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO reception \\(id,date_time,is_closed,pvz_id\\)").
+			WithArgs(reception.ID, reception.DateTime, false, reception.PvzID).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.InsertReception(context.Background(), reception))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO reception").
+			WithArgs(reception.ID, reception.DateTime, false, reception.PvzID).
+			WillReturnError(errors.New("duplicate key value violates unique constraint"))
+
+		err := repo.InsertReception(context.Background(), reception)
+
+		assert.EqualError(t, err, pgdb.FailedCreateReception)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestInsertProduct(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewProductRepository(mock)
+	product := model.Product{ID: uuid.New(), DateTime: time.Now().UTC(), TypeProduct: "обувь", ReceptionID: uuid.New()}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO product \\(id,date_time,type_product,reception_id,barcode,sku,weight_grams,length_mm,width_mm,height_mm,cell_id\\)").
+			WithArgs(product.ID, product.DateTime, product.TypeProduct, product.ReceptionID, "", "", 0, 0, 0, 0, nil).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.InsertProduct(context.Background(), product))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO product").
+			WithArgs(product.ID, product.DateTime, product.TypeProduct, product.ReceptionID, "", "", 0, 0, 0, 0, nil).
+			WillReturnError(errors.New("database error"))
+
+		err := repo.InsertProduct(context.Background(), product)
+
+		assert.EqualError(t, err, pgdb.FailedCreateProduct)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSyncOperation(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewSyncRepository(mock)
+	op := model.SyncOperation{
+		ID:       uuid.New(),
+		PvzID:    uuid.New(),
+		Type:     model.SyncCloseReception,
+		DateTime: time.Now().UTC(),
+		Code:     "reception_already_closed",
+		Message:  "reception  has  been already closed in this pvz.",
+	}
+	columns := []string{"id", "pvz_id", "type", "client_time", "type_product", "applied", "code", "message"}
+
+	t.Run("save", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO sync_operation").
+			WithArgs(op.ID, op.PvzID, op.Type, op.DateTime, op.TypeProduct, op.Applied, op.Code, op.Message).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+		assert.NoError(t, repo.SaveSyncOperation(context.Background(), op))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("save error", func(t *testing.T) {
+		mock.ExpectExec("INSERT INTO sync_operation").
+			WithArgs(op.ID, op.PvzID, op.Type, op.DateTime, op.TypeProduct, op.Applied, op.Code, op.Message).
+			WillReturnError(errors.New("duplicate key value violates unique constraint"))
+
+		err := repo.SaveSyncOperation(context.Background(), op)
+
+		assert.EqualError(t, err, pgdb.FailedSaveSyncOperation)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("get", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, pvz_id, type, client_time, type_product, applied, code, message FROM sync_operation").
+			WithArgs(op.ID.String(), op.PvzID.String()).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(op.ID, op.PvzID, op.Type, op.DateTime, op.TypeProduct, op.Applied, op.Code, op.Message))
+
+		got, err := repo.GetSyncOperation(context.Background(), op.PvzID, op.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &op, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, pvz_id, type, client_time, type_product, applied, code, message FROM sync_operation").
+			WithArgs(op.ID.String(), op.PvzID.String()).
+			WillReturnError(pgx.ErrNoRows)
+
+		got, err := repo.GetSyncOperation(context.Background(), op.PvzID, op.ID)
+
+		assert.Nil(t, got)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, pvz_id, type, client_time, type_product, applied, code, message FROM sync_operation").
+			WithArgs(op.ID.String(), op.PvzID.String()).
+			WillReturnError(errors.New("connection refused"))
+
+		got, err := repo.GetSyncOperation(context.Background(), op.PvzID, op.ID)
+
+		assert.Nil(t, got)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetReceptionProducts(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	repo := pgdb.NewSyncRepository(mock)
+	receptionID := uuid.New()
+
+	t.Run("ordered by time", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+		now := time.Now().UTC()
+
+		mock.ExpectQuery("SELECT id, date_time, type_product, reception_id, barcode, sku, weight_grams, length_mm, width_mm, height_mm, cell_id FROM product WHERE reception_id = \\$1 ORDER BY date_time").
+			WithArgs(receptionID.String()).
+			WillReturnRows(pgxmock.NewRows(productRowColumns).
+				AddRow(first, now, "обувь", receptionID, "", "", 0, 0, 0, 0, nil).
+				AddRow(second, now.Add(time.Second), "одежда", receptionID, "", "", 0, 0, 0, 0, nil))
+
+		products, err := repo.GetReceptionProducts(context.Background(), receptionID)
+
+		assert.NoError(t, err)
+		if assert.Len(t, products, 2) {
+			assert.Equal(t, first, products[0].ID)
+			assert.Equal(t, second, products[1].ID)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT id, date_time, type_product, reception_id, barcode, sku, weight_grams, length_mm, width_mm, height_mm, cell_id FROM product").
+			WithArgs(receptionID.String()).
+			WillReturnError(errors.New("database error"))
+
+		products, err := repo.GetReceptionProducts(context.Background(), receptionID)
+
+		assert.Nil(t, products)
+		assert.EqualError(t, err, pgdb.FailedExecuteQuery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
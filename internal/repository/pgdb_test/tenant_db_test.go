@@ -0,0 +1,126 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/repository/pgdb"
+	"pvz-service/internal/tenant"
+)
+
+// fakeBatcher запоминает отправленную пачку и отдает заготовленные результаты
+type fakeBatcher struct {
+	batch   *pgx.Batch
+	results *fakeResults
+}
+
+func (b *fakeBatcher) SendBatch(_ context.Context, batch *pgx.Batch) pgx.BatchResults {
+	b.batch = batch
+	return b.results
+}
+
+type fakeResults struct {
+	pgx.BatchResults
+	scopeErr error
+	execs    int
+	closed   int
+}
+
+func (r *fakeResults) Exec() (pgconn.CommandTag, error) {
+	r.execs++
+	if r.execs == 1 {
+		return pgconn.CommandTag("SELECT 1"), r.scopeErr
+	}
+	return pgconn.CommandTag("UPDATE 1"), nil
+}
+
+func (r *fakeResults) QueryRow() pgx.Row {
+	return fakeRow{}
+}
+
+func (r *fakeResults) Query() (pgx.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+func (r *fakeResults) Close() error {
+	r.closed++
+	return nil
+}
+
+type fakeRow struct{}
+
+func (fakeRow) Scan(dest ...any) error {
+	*dest[0].(*int) = 42
+	return nil
+}
+
+type fakeRows struct {
+	pgx.Rows
+	closed bool
+}
+
+func (r *fakeRows) Close() {
+	r.closed = true
+}
+
+func TestTenantDB(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	t.Run("QueryRow", func(t *testing.T) {
+		db := &fakeBatcher{results: &fakeResults{}}
+		var got int
+		require.NoError(t, pgdb.NewTenantDB(db).QueryRow(ctx, "SELECT 42").Scan(&got))
+
+		assert.Equal(t, 42, got)
+		require.Equal(t, 2, db.batch.Len())
+		assert.Equal(t, 1, db.results.closed)
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		db := &fakeBatcher{results: &fakeResults{}}
+		rows, err := pgdb.NewTenantDB(db).Query(ctx, "SELECT id FROM pvz")
+		require.NoError(t, err)
+		assert.Equal(t, 0, db.results.closed)
+
+		rows.Close()
+		assert.Equal(t, 1, db.results.closed)
+	})
+
+	t.Run("Exec", func(t *testing.T) {
+		db := &fakeBatcher{results: &fakeResults{}}
+		tag, err := pgdb.NewTenantDB(db).Exec(ctx, "UPDATE pvz SET status = $1", "active")
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1), tag.RowsAffected())
+		assert.Equal(t, 2, db.results.execs)
+		assert.Equal(t, 1, db.results.closed)
+	})
+
+	t.Run("Scope error", func(t *testing.T) {
+		scopeErr := errors.New("role \"pvz_tenant\" does not exist")
+		db := &fakeBatcher{results: &fakeResults{scopeErr: scopeErr}}
+		_, err := pgdb.NewTenantDB(db).Exec(ctx, "DELETE FROM pvz")
+
+		assert.ErrorIs(t, err, scopeErr)
+		assert.Equal(t, 1, db.results.execs)
+		assert.Equal(t, 1, db.results.closed)
+	})
+
+	t.Run("Tenant not set", func(t *testing.T) {
+		db := &fakeBatcher{results: &fakeResults{}}
+		tenantDB := pgdb.NewTenantDB(db)
+
+		var got int
+		assert.ErrorIs(t, tenantDB.QueryRow(context.Background(), "SELECT 42").Scan(&got), pgdb.ErrTenantNotSet)
+		_, err := tenantDB.Query(context.Background(), "SELECT id FROM pvz")
+		assert.ErrorIs(t, err, pgdb.ErrTenantNotSet)
+		_, err = tenantDB.Exec(context.Background(), "DELETE FROM pvz")
+		assert.ErrorIs(t, err, pgdb.ErrTenantNotSet)
+		assert.Nil(t, db.batch)
+	})
+}
@@ -0,0 +1,115 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb"
+)
+
+func TestTenantRepository_CreateTenant(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewTenantRepository(mock)
+
+	t.Run("успешное создание оператора", func(t *testing.T) {
+		id, createdAt := uuid.New(), time.Now()
+		mock.ExpectQuery(`INSERT INTO tenant \(name\) VALUES \(\$1\) RETURNING id, name, created_at, open_registration`).
+			WithArgs("acme").
+			WillReturnRows(mock.NewRows([]string{"id", "name", "created_at", "open_registration"}).AddRow(id, "acme", createdAt, false))
+
+		got, err := repo.CreateTenant(context.Background(), "acme")
+		require.NoError(t, err)
+		assert.Equal(t, id, got.ID)
+		assert.Equal(t, "acme", got.Name)
+		assert.Equal(t, createdAt, got.CreatedAt)
+		assert.False(t, got.OpenRegistration)
+	})
+
+	t.Run("занятое имя", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO tenant`).
+			WithArgs("acme").
+			WillReturnError(errors.New("duplicate key value violates unique constraint"))
+
+		_, err := repo.CreateTenant(context.Background(), "acme")
+		assert.ErrorContains(t, err, pgdb.FailedCreateTenant)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepository_GetTenants(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewTenantRepository(mock)
+
+	t.Run("список по времени создания", func(t *testing.T) {
+		first, second := uuid.New(), uuid.New()
+		createdAt := time.Now()
+		mock.ExpectQuery(`SELECT id, name, created_at, open_registration FROM tenant ORDER BY created_at, id`).
+			WillReturnRows(mock.NewRows([]string{"id", "name", "created_at", "open_registration"}).
+				AddRow(first, "default", createdAt, true).
+				AddRow(second, "acme", createdAt.Add(time.Minute), false))
+
+		tenants, err := repo.GetTenants(context.Background())
+		require.NoError(t, err)
+		require.Len(t, tenants, 2)
+		assert.Equal(t, first, tenants[0].ID)
+		assert.Equal(t, "acme", tenants[1].Name)
+		assert.True(t, tenants[0].OpenRegistration)
+	})
+
+	t.Run("оператор не найден", func(t *testing.T) {
+		id := uuid.New()
+		mock.ExpectQuery(`SELECT id, name, created_at, open_registration FROM tenant WHERE id = \$1`).
+			WithArgs(id.String()).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetTenantByID(context.Background(), id)
+		assert.ErrorContains(t, err, pgdb.TenantNotFound)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTenantRepository_SetTenantRegistration(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewTenantRepository(mock)
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE tenant SET open_registration = \$1 WHERE id = \$2`).
+		WithArgs(true, id.String()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`UPDATE tenant SET open_registration = \$1 WHERE id = \$2`).
+		WithArgs(false, id.String()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	mock.ExpectExec(`UPDATE tenant SET open_registration = \$1 WHERE id = \$2`).
+		WithArgs(false, id.String()).
+		WillReturnError(errors.New("connection refused"))
+
+	require.NoError(t, repo.SetTenantRegistration(context.Background(), id, true))
+	assert.ErrorIs(t, repo.SetTenantRegistration(context.Background(), id, false), model.ErrNotFound)
+
+	err = repo.SetTenantRegistration(context.Background(), id, false)
+	assert.ErrorContains(t, err, pgdb.FailedUpdateTenant)
+	assert.NotErrorIs(t, err, model.ErrNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
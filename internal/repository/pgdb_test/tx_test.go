@@ -0,0 +1,113 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/repository/pgdb"
+	"pvz-service/internal/tenant"
+)
+
+// fakeTx считает запросы, отправленные в транзакцию, и ее завершение
+type fakeTx struct {
+	pgx.Tx
+	fakeBatcher
+	execs      int
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *fakeTx) SendBatch(ctx context.Context, batch *pgx.Batch) pgx.BatchResults {
+	return tx.fakeBatcher.SendBatch(ctx, batch)
+}
+
+func (tx *fakeTx) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	tx.execs++
+	return pgconn.CommandTag("UPDATE 1"), nil
+}
+
+func (tx *fakeTx) Commit(context.Context) error {
+	tx.committed = true
+	return nil
+}
+
+func (tx *fakeTx) Rollback(context.Context) error {
+	tx.rolledBack = true
+	return nil
+}
+
+type fakeBeginner struct {
+	tx    *fakeTx
+	begun int
+}
+
+func (b *fakeBeginner) Begin(context.Context) (pgx.Tx, error) {
+	b.begun++
+	return b.tx, nil
+}
+
+// fakePool - пул вне транзакции, считает отправленные в него запросы
+type fakePool struct {
+	pgdb.DB
+	execs int
+}
+
+func (p *fakePool) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	p.execs++
+	return pgconn.CommandTag("UPDATE 1"), nil
+}
+
+func TestTransactor(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	t.Run("Commit", func(t *testing.T) {
+		tx := &fakeTx{fakeBatcher: fakeBatcher{results: &fakeResults{}}}
+		beginner := &fakeBeginner{tx: tx}
+		pool, scopedPool := &fakePool{}, &fakeBatcher{results: &fakeResults{}}
+		shared, scoped := pgdb.NewTxDB(pool), pgdb.NewTenantDB(scopedPool)
+
+		err := pgdb.NewTransactor(beginner).InTx(ctx, func(ctx context.Context) error {
+			if _, err := shared.Exec(ctx, "UPDATE users SET password = $1", "hash"); err != nil {
+				return err
+			}
+			// Вложенный вызов не открывает вторую транзакцию
+			return pgdb.NewTransactor(beginner).InTx(ctx, func(ctx context.Context) error {
+				_, err := scoped.Exec(ctx, "UPDATE pvz SET status = $1", "active")
+				return err
+			})
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, beginner.begun)
+		assert.True(t, tx.committed)
+		assert.False(t, tx.rolledBack)
+		assert.Equal(t, 1, tx.execs)
+		require.NotNil(t, tx.batch)
+		assert.Equal(t, 2, tx.batch.Len())
+		assert.Zero(t, pool.execs)
+		assert.Nil(t, scopedPool.batch)
+
+		// Вне транзакции запросы идут в пул
+		_, err = shared.Exec(ctx, "UPDATE users SET password = $1", "hash")
+		require.NoError(t, err)
+		assert.Equal(t, 1, pool.execs)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		tx := &fakeTx{}
+		fnErr := errors.New("reception not closed")
+
+		err := pgdb.NewTransactor(&fakeBeginner{tx: tx}).InTx(ctx, func(context.Context) error {
+			return fnErr
+		})
+
+		assert.ErrorIs(t, err, fnErr)
+		assert.True(t, tx.rolledBack)
+		assert.False(t, tx.committed)
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/pashagolub/pgxmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,10 +29,11 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			Email:    "test@example.com",
 			Password: "hashedpass",
 			Role:     "admin",
+			TenantID: uuid.New(),
 		}
 
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(user.Email, user.Password, user.Role).
+			WithArgs(user.Email, user.Password, user.Role, user.TenantID, (*time.Time)(nil)).
 			WillReturnRows(mock.NewRows([]string{"id"}).AddRow(expectedID))
 
 		id, err := repo.CreateUser(context.Background(), user)
@@ -43,10 +46,11 @@ func TestUserRepository_CreateUser(t *testing.T) {
 			Email:    "fail@example.com",
 			Password: "failpass",
 			Role:     "user",
+			TenantID: uuid.New(),
 		}
 
 		mock.ExpectQuery(`INSERT INTO users`).
-			WithArgs(user.Email, user.Password, user.Role).
+			WithArgs(user.Email, user.Password, user.Role, user.TenantID, (*time.Time)(nil)).
 			WillReturnError(errors.New("insert error"))
 
 		id, err := repo.CreateUser(context.Background(), user)
@@ -68,28 +72,43 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 			Email:    "find@example.com",
 			Password: "securepass",
 			Role:     "admin",
+			TenantID: uuid.New(),
 		}
 
-		mock.ExpectQuery(`SELECT id, email, password, role FROM users`).
+		mock.ExpectQuery(`SELECT id, email, password, role, tenant_id, email_verified_at FROM users`).
 			WithArgs(expectedUser.Email).
-			WillReturnRows(mock.NewRows([]string{"id", "email", "password", "role"}).
-				AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Password, expectedUser.Role))
+			WillReturnRows(mock.NewRows([]string{"id", "email", "password", "role", "tenant_id", "email_verified_at"}).
+				AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Password, expectedUser.Role, expectedUser.TenantID, nil))
 
 		user, err := repo.GetUserByEmail(context.Background(), expectedUser.Email)
 		require.NoError(t, err)
 		assert.Equal(t, expectedUser.Email, user.Email)
 		assert.Equal(t, expectedUser.Role, user.Role)
+		assert.Equal(t, expectedUser.TenantID, user.TenantID)
 	})
 
 	t.Run("пользователь не найден", func(t *testing.T) {
 		email := "missing@example.com"
 
-		mock.ExpectQuery(`SELECT id, email, password, role FROM users`).
+		mock.ExpectQuery(`SELECT id, email, password, role, tenant_id, email_verified_at FROM users`).
 			WithArgs(email).
-			WillReturnError(errors.New("no rows in result set"))
+			WillReturnError(pgx.ErrNoRows)
+
+		user, err := repo.GetUserByEmail(context.Background(), email)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Nil(t, user)
+	})
+
+	t.Run("сбой базы - не отсутствие пользователя", func(t *testing.T) {
+		email := "user@example.com"
+
+		mock.ExpectQuery(`SELECT id, email, password, role, tenant_id, email_verified_at FROM users`).
+			WithArgs(email).
+			WillReturnError(errors.New("connection refused"))
 
 		user, err := repo.GetUserByEmail(context.Background(), email)
 		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
 		assert.Nil(t, user)
 	})
 }
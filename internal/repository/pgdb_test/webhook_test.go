@@ -0,0 +1,256 @@
+package pgdb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository/pgdb"
+)
+
+var webhookRowColumns = []string{"id", "url", "event_types", "pvz_ids", "secret", "created_at"}
+
+var deliveryRowColumns = []string{
+	"id", "subscription_id", "event_id", "event_type", "payload", "status", "attempts",
+	"next_attempt_at", "last_error", "response_status", "created_at", "delivered_at",
+}
+
+func TestWebhookRepository_CreateWebhook(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	pvzID := uuid.New()
+	sub := model.WebhookSubscription{
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.WebhookEventReceptionClosed},
+		PvzIDs:     []uuid.UUID{pvzID},
+		Secret:     "secret",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		id := uuid.New()
+
+		mock.ExpectQuery(`INSERT INTO webhook_subscription \(url,event_types,pvz_ids,secret\) VALUES \(\$1,\$2,\$3,\$4\) RETURNING id`).
+			WithArgs(sub.URL, sub.EventTypes, []string{pvzID.String()}, sub.Secret).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(id))
+
+		got, err := repo.CreateWebhook(context.Background(), sub)
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO webhook_subscription`).
+			WithArgs(sub.URL, sub.EventTypes, []string{pvzID.String()}, sub.Secret).
+			WillReturnError(errors.New("connection refused"))
+
+		got, err := repo.CreateWebhook(context.Background(), sub)
+		assert.Equal(t, uuid.Nil, got)
+		assert.EqualError(t, err, pgdb.FailedCreateWebhook)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookRepository_GetWebhookByID(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	id := uuid.New()
+	pvzID := uuid.New()
+	createdAt := time.Now().UTC()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT id, url, event_types, pvz_ids, secret, created_at FROM webhook_subscription WHERE id = \$1`).
+			WithArgs(id.String()).
+			WillReturnRows(pgxmock.NewRows(webhookRowColumns).
+				AddRow(id, "https://partner.example/hooks", []string{model.WebhookEventProductReceived},
+					[]string{pvzID.String()}, "secret", createdAt))
+
+		sub, err := repo.GetWebhookByID(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, &model.WebhookSubscription{
+			ID:         id,
+			URL:        "https://partner.example/hooks",
+			EventTypes: []string{model.WebhookEventProductReceived},
+			PvzIDs:     []uuid.UUID{pvzID},
+			Secret:     "secret",
+			CreatedAt:  createdAt,
+		}, sub)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM webhook_subscription WHERE id = \$1`).
+			WithArgs(id.String()).
+			WillReturnError(pgx.ErrNoRows)
+
+		_, err := repo.GetWebhookByID(context.Background(), id)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.EqualError(t, err, pgdb.WebhookNotFound+": record not found")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("db error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM webhook_subscription WHERE id = \$1`).
+			WithArgs(id.String()).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.GetWebhookByID(context.Background(), id)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, model.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWebhookRepository_DeleteWebhook(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	id := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM webhook_subscription WHERE id = \$1`).
+		WithArgs(id.String()).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	err = repo.DeleteWebhook(context.Background(), id)
+	assert.EqualError(t, err, pgdb.NoRowsAffected)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_CreateWebhookDelivery(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	id := uuid.New()
+	delivery := model.WebhookDelivery{
+		SubscriptionID: uuid.New(),
+		EventID:        uuid.New(),
+		EventType:      model.WebhookEventReceptionCreated,
+		Payload:        []byte(`{"type":"reception.created"}`),
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now().UTC(),
+	}
+
+	mock.ExpectQuery(`INSERT INTO webhook_delivery \(subscription_id,event_id,event_type,payload,status,attempts,`+
+		`next_attempt_at,last_error,response_status,delivered_at\) VALUES \(\$1,\$2,\$3,\$4,\$5,\$6,\$7,\$8,\$9,\$10\) RETURNING id`).
+		WithArgs(delivery.SubscriptionID, delivery.EventID, delivery.EventType, string(delivery.Payload),
+			delivery.Status, 0, delivery.NextAttemptAt, "", 0, (*time.Time)(nil)).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(id))
+
+	got, err := repo.CreateWebhookDelivery(context.Background(), delivery)
+	require.NoError(t, err)
+	assert.Equal(t, id, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_UpdateWebhookDelivery(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	deliveredAt := time.Now().UTC()
+	delivery := model.WebhookDelivery{
+		ID:             uuid.New(),
+		Status:         model.WebhookDeliveryDelivered,
+		Attempts:       2,
+		NextAttemptAt:  deliveredAt.Add(-time.Minute),
+		ResponseStatus: 204,
+		DeliveredAt:    deliveredAt,
+	}
+
+	mock.ExpectExec(`UPDATE webhook_delivery SET status = \$1, attempts = \$2, next_attempt_at = \$3, last_error = \$4, `+
+		`response_status = \$5, delivered_at = \$6 WHERE id = \$7`).
+		WithArgs(delivery.Status, 2, delivery.NextAttemptAt, "", 204, &deliveredAt, delivery.ID.String()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	require.NoError(t, repo.UpdateWebhookDelivery(context.Background(), delivery))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetDueWebhookDeliveries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	now := time.Now().UTC()
+	id := uuid.New()
+	subID := uuid.New()
+	eventID := uuid.New()
+
+	mock.ExpectQuery(`SELECT .* FROM webhook_delivery WHERE status = \$1 AND next_attempt_at <= \$2 `+
+		`ORDER BY next_attempt_at, id LIMIT 100`).
+		WithArgs(model.WebhookDeliveryPending, now).
+		WillReturnRows(pgxmock.NewRows(deliveryRowColumns).
+			AddRow(id, subID, eventID, model.WebhookEventProductReceived, []byte(`{}`), model.WebhookDeliveryPending, 1,
+				now.Add(-time.Second), "unexpected response status 500", 500, now.Add(-time.Minute), (*time.Time)(nil)))
+
+	deliveries, err := repo.GetDueWebhookDeliveries(context.Background(), now, 100)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.WebhookDelivery{
+		ID:             id,
+		SubscriptionID: subID,
+		EventID:        eventID,
+		EventType:      model.WebhookEventProductReceived,
+		Payload:        []byte(`{}`),
+		Status:         model.WebhookDeliveryPending,
+		Attempts:       1,
+		NextAttemptAt:  now.Add(-time.Second),
+		LastError:      "unexpected response status 500",
+		ResponseStatus: 500,
+		CreatedAt:      now.Add(-time.Minute),
+	}, deliveries[0])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebhookRepository_GetWebhookDeliveries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	repo := pgdb.NewWebhookRepository(mock)
+	subID := uuid.New()
+
+	t.Run("со статусом", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM webhook_delivery WHERE subscription_id = \$1 AND status = \$2 `+
+			`ORDER BY created_at DESC, id DESC LIMIT 10 OFFSET 20`).
+			WithArgs(subID.String(), model.WebhookDeliveryDead).
+			WillReturnRows(pgxmock.NewRows(deliveryRowColumns))
+
+		deliveries, err := repo.GetWebhookDeliveries(context.Background(), model.WebhookDeliveryQuery{
+			SubscriptionID: subID, Status: model.WebhookDeliveryDead, Page: 3, Limit: 10,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ошибка запроса", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .* FROM webhook_delivery WHERE subscription_id = \$1 ORDER BY`).
+			WithArgs(subID.String()).
+			WillReturnError(errors.New("connection refused"))
+
+		_, err := repo.GetWebhookDeliveries(context.Background(), model.WebhookDeliveryQuery{SubscriptionID: subID, Page: 1, Limit: 10})
+		assert.EqualError(t, err, pgdb.FailedExecuteQuery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
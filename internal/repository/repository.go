@@ -2,21 +2,88 @@ package repository
 
 import (
 	"github.com/jackc/pgx/v4/pgxpool"
+	"pvz-service/internal/repository/memdb"
 	"pvz-service/internal/repository/pgdb"
 )
 
+// SchemaVersion - версия схемы БД, которую ожидает код.
+// Увеличивается вместе с каждой новой миграцией
+const SchemaVersion = 21
+
 type Repository struct {
+	*pgdb.Transactor
 	*pgdb.UserRepository
+	*pgdb.AuthTokenRepository
+	*pgdb.TenantRepository
 	*pgdb.PVZRepository
 	*pgdb.ReceptionRepository
 	*pgdb.ProductRepository
+	*pgdb.SyncRepository
+	*pgdb.IssuanceRepository
+	*pgdb.StorageCellRepository
+	*pgdb.WebhookRepository
+}
+
+// NewRepository собирает репозиторий поверх основной базы.
+// readDB получает только списочные выборки (GET /pvz, остатки ПВЗ), запись и чтение
+// сразу после записи всегда идут в db. Данные операторов читаются и пишутся через
+// pgdb.TenantDB, поэтому readDB тоже должен быть обернут в него.
+// Пользователи, их токены и операторы общие и работают с db напрямую.
+// Запросы внутри InTx идут в транзакцию, кроме выборок из readDB
+func NewRepository(db *pgxpool.Pool, readDB pgdb.DB) *Repository {
+	scoped := pgdb.NewTenantDB(db)
+	shared := pgdb.NewTxDB(db)
+
+	repo := &Repository{
+		Transactor:            pgdb.NewTransactor(db),
+		UserRepository:        pgdb.NewUserRepository(shared),
+		AuthTokenRepository:   pgdb.NewAuthTokenRepository(shared),
+		TenantRepository:      pgdb.NewTenantRepository(db),
+		PVZRepository:         pgdb.NewPVZRepository(scoped),
+		ReceptionRepository:   pgdb.NewReceptionRepository(scoped),
+		ProductRepository:     pgdb.NewProductRepository(scoped),
+		SyncRepository:        pgdb.NewSyncRepository(scoped),
+		IssuanceRepository:    pgdb.NewIssuanceRepository(scoped),
+		StorageCellRepository: pgdb.NewStorageCellRepository(scoped),
+		WebhookRepository:     pgdb.NewWebhookRepository(scoped),
+	}
+
+	repo.PVZRepository.ReadDB = readDB
+	repo.ReceptionRepository.ReadDB = readDB
+	repo.ProductRepository.ReadDB = readDB
+	repo.IssuanceRepository.ReadDB = readDB
+
+	return repo
+}
+
+// MemoryRepository - реализация хранилища в памяти процесса,
+// для локального запуска и тестов без базы данных
+type MemoryRepository struct {
+	*memdb.Transactor
+	*memdb.UserRepository
+	*memdb.AuthTokenRepository
+	*memdb.TenantRepository
+	*memdb.PVZRepository
+	*memdb.ReceptionRepository
+	*memdb.ProductRepository
+	*memdb.SyncRepository
+	*memdb.IssuanceRepository
+	*memdb.StorageCellRepository
+	*memdb.WebhookRepository
 }
 
-func NewRepository(db *pgxpool.Pool) *Repository {
-	return &Repository{
-		UserRepository:      pgdb.NewUserRepository(db),
-		PVZRepository:       pgdb.NewPVZRepository(db),
-		ReceptionRepository: pgdb.NewReceptionRepository(db),
-		ProductRepository:   pgdb.NewProductRepository(db),
+func NewMemoryRepository(storage *memdb.Storage) *MemoryRepository {
+	return &MemoryRepository{
+		Transactor:            memdb.NewTransactor(storage),
+		UserRepository:        memdb.NewUserRepository(storage),
+		AuthTokenRepository:   memdb.NewAuthTokenRepository(storage),
+		TenantRepository:      memdb.NewTenantRepository(storage),
+		PVZRepository:         memdb.NewPVZRepository(storage),
+		ReceptionRepository:   memdb.NewReceptionRepository(storage),
+		ProductRepository:     memdb.NewProductRepository(storage),
+		SyncRepository:        memdb.NewSyncRepository(storage),
+		IssuanceRepository:    memdb.NewIssuanceRepository(storage),
+		StorageCellRepository: memdb.NewStorageCellRepository(storage),
+		WebhookRepository:     memdb.NewWebhookRepository(storage),
 	}
 }
@@ -0,0 +1,1075 @@
+// Package repotest содержит общий набор проверок для реализаций service.Repository.
+// Любое хранилище должно проходить его одинаково: порядок выборок,
+// уникальность и внешние ключи повторяют схему Postgres.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+	"pvz-service/internal/tenant"
+)
+
+// Factory возвращает пустое хранилище для одного подтеста
+type Factory func(t *testing.T) service.Repository
+
+func RunConformance(t *testing.T, newRepo Factory) {
+	t.Run("users", func(t *testing.T) { testUsers(t, newRepo(t)) })
+	t.Run("pvz", func(t *testing.T) { testPvz(t, newRepo(t)) })
+	t.Run("receptions", func(t *testing.T) { testReceptions(t, newRepo(t)) })
+	t.Run("products", func(t *testing.T) { testProducts(t, newRepo(t)) })
+	t.Run("sync", func(t *testing.T) { testSync(t, newRepo(t)) })
+	t.Run("barcodes", func(t *testing.T) { testBarcodes(t, newRepo(t)) })
+	t.Run("issuances", func(t *testing.T) { testIssuances(t, newRepo(t)) })
+	t.Run("storage cells", func(t *testing.T) { testStorageCells(t, newRepo(t)) })
+	t.Run("pvz profile", func(t *testing.T) { testPvzProfile(t, newRepo(t)) })
+	t.Run("nearby pvz", func(t *testing.T) { testNearbyPvz(t, newRepo(t)) })
+	t.Run("pvz status", func(t *testing.T) { testPvzStatus(t, newRepo(t)) })
+	t.Run("stale receptions", func(t *testing.T) { testStaleReceptions(t, newRepo(t)) })
+	t.Run("webhooks", func(t *testing.T) { testWebhooks(t, newRepo(t)) })
+	t.Run("change stamps", func(t *testing.T) { testChangeStamps(t, newRepo(t)) })
+	t.Run("tenant isolation", func(t *testing.T) { testTenantIsolation(t, newRepo(t)) })
+	t.Run("auth tokens", func(t *testing.T) { testAuthTokens(t, newRepo(t)) })
+}
+
+func testUsers(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	user := &model.User{Email: "user@example.com", Password: "hash", Role: "employee", TenantID: tenant.DefaultID}
+	id, err := repo.CreateUser(ctx, user)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, id)
+
+	got, err := repo.GetUserByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+	assert.Equal(t, user.Email, got.Email)
+	assert.Equal(t, user.Password, got.Password)
+	assert.Equal(t, user.Role, got.Role)
+	assert.Equal(t, user.TenantID, got.TenantID)
+
+	// email уникален
+	_, err = repo.CreateUser(ctx, &model.User{Email: user.Email, Password: "other", Role: "moderator", TenantID: tenant.DefaultID})
+	assert.Error(t, err)
+
+	// Внешний ключ users -> tenant
+	_, err = repo.CreateUser(ctx, &model.User{Email: "other@example.com", Password: "hash", Role: "employee", TenantID: uuid.New()})
+	assert.Error(t, err)
+
+	_, err = repo.GetUserByEmail(ctx, "missing@example.com")
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func testPvz(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	first, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	second, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	pvz, err := repo.GetPvzByID(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, first, pvz.ID)
+	assert.Equal(t, "Москва", pvz.City)
+	assert.False(t, pvz.RegistrationDate.IsZero())
+
+	_, err = repo.GetPvzByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	ids, err := repo.GetIDListPvz(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{first, second}, ids)
+}
+
+func testReceptions(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	// внешний ключ на pvz
+	_, err := repo.CreateReception(ctx, uuid.New())
+	assert.Error(t, err)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	otherPvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+
+	_, err = repo.GetLastReception(ctx, pvzID)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	firstID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	first, err := repo.GetReceptionByID(ctx, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, pvzID, first.PvzID)
+	assert.False(t, first.IsClosed)
+
+	require.NoError(t, repo.CloseReception(ctx, firstID, model.CloseReasonManual))
+	first, err = repo.GetReceptionByID(ctx, firstID)
+	require.NoError(t, err)
+	assert.True(t, first.IsClosed)
+	assert.Equal(t, model.CloseReasonManual, first.CloseReason)
+	assert.False(t, first.ClosedAt.IsZero())
+
+	// закрытая приемка повторно не закрывается
+	assert.Error(t, repo.CloseReception(ctx, firstID, model.CloseReasonTimeout))
+
+	secondID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	otherID, err := repo.CreateReception(ctx, otherPvzID)
+	require.NoError(t, err)
+
+	// последняя приемка - самая поздняя по date_time в рамках своего ПВЗ
+	last, err := repo.GetLastReception(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, secondID, last.ID)
+	assert.False(t, last.IsClosed)
+
+	last, err = repo.GetLastReception(ctx, otherPvzID)
+	require.NoError(t, err)
+	assert.Equal(t, otherID, last.ID)
+
+	assert.Error(t, repo.CloseReception(ctx, uuid.New(), model.CloseReasonManual))
+
+	_, err = repo.GetReceptionByID(ctx, uuid.New())
+	assert.Error(t, err)
+
+	all, err := repo.GetReceptionsSliceWithTimeRange(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{firstID, secondID, otherID}, receptionIDs(all))
+
+	second, err := repo.GetReceptionByID(ctx, secondID)
+	require.NoError(t, err)
+
+	// границы диапазона включаются
+	fromSecond, err := repo.GetReceptionsSliceWithTimeRange(ctx, second.DateTime, time.Time{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{secondID, otherID}, receptionIDs(fromSecond))
+
+	untilSecond, err := repo.GetReceptionsSliceWithTimeRange(ctx, time.Time{}, second.DateTime)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{firstID, secondID}, receptionIDs(untilSecond))
+
+	other, err := repo.GetReceptionByID(ctx, otherID)
+	require.NoError(t, err)
+
+	future, err := repo.GetReceptionsSliceWithTimeRange(ctx, other.DateTime.Add(time.Second), time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, future)
+}
+
+func testProducts(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	// внешний ключ на reception
+	_, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: uuid.New()})
+	assert.Error(t, err)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	_, err = repo.GetLastProduct(ctx, receptionID)
+	assert.Error(t, err)
+
+	products, err := repo.GetProductSliceByReceptionID(ctx, receptionID)
+	require.NoError(t, err)
+	assert.Empty(t, products)
+
+	firstID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "электроника", ReceptionID: receptionID})
+	require.NoError(t, err)
+	secondID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "одежда", ReceptionID: receptionID})
+	require.NoError(t, err)
+
+	product, err := repo.GetProductByID(ctx, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, "электроника", product.TypeProduct)
+	assert.Equal(t, receptionID, product.ReceptionID)
+	assert.Empty(t, product.Barcode)
+
+	// LIFO: последним считается самый поздний товар
+	last, err := repo.GetLastProduct(ctx, receptionID)
+	require.NoError(t, err)
+	assert.Equal(t, secondID, last.ID)
+
+	products, err = repo.GetProductSliceByReceptionID(ctx, receptionID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{firstID, secondID}, productIDs(products))
+
+	require.NoError(t, repo.DeleteProductByID(ctx, secondID))
+	assert.Error(t, repo.DeleteProductByID(ctx, secondID))
+
+	_, err = repo.GetProductByID(ctx, secondID)
+	assert.Error(t, err)
+
+	last, err = repo.GetLastProduct(ctx, receptionID)
+	require.NoError(t, err)
+	assert.Equal(t, firstID, last.ID)
+}
+
+func testSync(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	// Время и ID задает клиент
+	clientTime := time.Date(2024, time.March, 1, 9, 30, 0, 123456000, time.UTC)
+	reception := model.Reception{ID: uuid.New(), DateTime: clientTime, PvzID: pvzID}
+	require.NoError(t, repo.InsertReception(ctx, reception))
+	assert.Error(t, repo.InsertReception(ctx, reception))
+	assert.Error(t, repo.InsertReception(ctx, model.Reception{ID: uuid.New(), DateTime: clientTime, PvzID: uuid.New()}))
+
+	gotReception, err := repo.GetReceptionByID(ctx, reception.ID)
+	require.NoError(t, err)
+	assert.True(t, clientTime.Equal(gotReception.DateTime))
+	assert.False(t, gotReception.IsClosed)
+
+	second := model.Product{ID: uuid.New(), DateTime: clientTime.Add(2 * time.Minute), TypeProduct: "обувь", ReceptionID: reception.ID}
+	first := model.Product{ID: uuid.New(), DateTime: clientTime.Add(time.Minute), TypeProduct: "одежда", ReceptionID: reception.ID}
+	require.NoError(t, repo.InsertProduct(ctx, second))
+	require.NoError(t, repo.InsertProduct(ctx, first))
+	assert.Error(t, repo.InsertProduct(ctx, first))
+	assert.Error(t, repo.InsertProduct(ctx, model.Product{ID: uuid.New(), DateTime: clientTime, TypeProduct: "обувь", ReceptionID: uuid.New()}))
+
+	last, err := repo.GetLastProduct(ctx, reception.ID)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, last.ID)
+
+	// Товары в ответе синхронизации упорядочены по времени клиента
+	products, err := repo.GetReceptionProducts(ctx, reception.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first.ID, second.ID}, productIDs(products))
+
+	products, err = repo.GetReceptionProducts(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, products)
+
+	_, err = repo.GetSyncOperation(ctx, pvzID, reception.ID)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	applied := model.SyncOperation{ID: reception.ID, PvzID: pvzID, Type: model.SyncOpenReception, DateTime: clientTime, Applied: true}
+	require.NoError(t, repo.SaveSyncOperation(ctx, applied))
+	assert.Error(t, repo.SaveSyncOperation(ctx, applied))
+
+	rejected := model.SyncOperation{
+		ID:          uuid.New(),
+		PvzID:       pvzID,
+		Type:        model.SyncAddProduct,
+		DateTime:    clientTime,
+		TypeProduct: "обувь",
+		Code:        service.CodeSyncOutOfOrder,
+		Message:     service.SyncOutOfOrder,
+	}
+	require.NoError(t, repo.SaveSyncOperation(ctx, rejected))
+
+	// внешний ключ на pvz
+	assert.Error(t, repo.SaveSyncOperation(ctx, model.SyncOperation{ID: uuid.New(), PvzID: uuid.New(), Type: model.SyncCloseReception, DateTime: clientTime}))
+
+	// UUID операции уникален только внутри ПВЗ: тот же UUID от сканера другого ПВЗ записывается отдельно
+	otherPvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+	reused := model.SyncOperation{ID: applied.ID, PvzID: otherPvzID, Type: model.SyncCloseReception, DateTime: clientTime, Applied: true}
+	require.NoError(t, repo.SaveSyncOperation(ctx, reused))
+
+	got, err := repo.GetSyncOperation(ctx, otherPvzID, applied.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.SyncCloseReception, got.Type)
+
+	// Операция ищется только в журнале своего ПВЗ
+	_, err = repo.GetSyncOperation(ctx, uuid.New(), applied.ID)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	got, err = repo.GetSyncOperation(ctx, pvzID, applied.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Applied)
+	assert.Equal(t, model.SyncOpenReception, got.Type)
+	assert.Equal(t, pvzID, got.PvzID)
+	assert.True(t, clientTime.Equal(got.DateTime))
+
+	got, err = repo.GetSyncOperation(ctx, pvzID, rejected.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Applied)
+	assert.Equal(t, rejected.TypeProduct, got.TypeProduct)
+	assert.Equal(t, rejected.Code, got.Code)
+	assert.Equal(t, rejected.Message, got.Message)
+}
+
+func testBarcodes(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+	firstReception, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	parcel := model.Product{
+		TypeProduct: "электроника",
+		ReceptionID: firstReception,
+		Barcode:     "4006381333931",
+		SKU:         "SKU-1",
+		WeightGrams: 1200,
+		Dimensions:  model.Dimensions{LengthMM: 300, WidthMM: 200, HeightMM: 100},
+	}
+	firstID, err := repo.CreateProduct(ctx, parcel)
+	require.NoError(t, err)
+
+	got, err := repo.GetProductByID(ctx, firstID)
+	require.NoError(t, err)
+	assert.Equal(t, parcel.Barcode, got.Barcode)
+	assert.Equal(t, parcel.SKU, got.SKU)
+	assert.Equal(t, parcel.WeightGrams, got.WeightGrams)
+	assert.Equal(t, parcel.Dimensions, got.Dimensions)
+
+	// Штрихкод уникален в пределах приемки, товары без штрихкода не ограничены
+	_, err = repo.CreateProduct(ctx, parcel)
+	assert.Error(t, err)
+	assert.Error(t, repo.InsertProduct(ctx, model.Product{
+		ID: uuid.New(), DateTime: time.Now().UTC(), TypeProduct: "обувь", ReceptionID: firstReception, Barcode: parcel.Barcode,
+	}))
+	for i := 0; i < 2; i++ {
+		_, err = repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: firstReception})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.CloseReception(ctx, firstReception, model.CloseReasonManual))
+	secondReception, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	parcel.ReceptionID = secondReception
+	secondID, err := repo.CreateProduct(ctx, parcel)
+	require.NoError(t, err)
+
+	// Сначала последний принятый
+	products, err := repo.GetProductsByBarcode(ctx, parcel.Barcode)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{secondID, firstID}, productIDs(products))
+
+	products, err = repo.GetProductsByBarcode(ctx, "5901234123457")
+	require.NoError(t, err)
+	assert.Empty(t, products)
+}
+
+func testIssuances(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	otherPvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+
+	closed, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	first, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: closed})
+	require.NoError(t, err)
+	second, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "одежда", ReceptionID: closed})
+	require.NoError(t, err)
+	require.NoError(t, repo.CloseReception(ctx, closed, model.CloseReasonManual))
+
+	// Товары открытой приемки и другого ПВЗ в остатки не попадают
+	open, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	_, err = repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: open})
+	require.NoError(t, err)
+	otherReception, err := repo.CreateReception(ctx, otherPvzID)
+	require.NoError(t, err)
+	_, err = repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: otherReception})
+	require.NoError(t, err)
+	require.NoError(t, repo.CloseReception(ctx, otherReception, model.CloseReasonManual))
+
+	stock, err := repo.GetStock(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first, second}, productIDs(stock))
+
+	_, err = repo.GetLastIssuance(ctx, first)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	_, err = repo.GetLastReturn(ctx, first)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	// Блокировка товара вне транзакции - проверка, что он есть
+	require.NoError(t, repo.LockProduct(ctx, first))
+	assert.ErrorIs(t, repo.LockProduct(ctx, uuid.New()), model.ErrNotFound)
+
+	issuance, err := repo.CreateIssuance(ctx, model.Issuance{
+		ProductID: first, PvzID: pvzID, EmployeeID: "employee-1", ConfirmationCode: "4821",
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, issuance.ID)
+	assert.False(t, issuance.IssuedAt.IsZero())
+
+	got, err := repo.GetLastIssuance(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, issuance.ID, got.ID)
+	assert.Equal(t, "employee-1", got.EmployeeID)
+	assert.Equal(t, "4821", got.ConfirmationCode)
+	assert.True(t, issuance.IssuedAt.Equal(got.IssuedAt))
+
+	stock, err = repo.GetStock(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{second}, productIDs(stock))
+
+	// Возвращенный товар снова на складе
+	ret, err := repo.CreateReturn(ctx, model.ProductReturn{
+		ProductID: first, PvzID: pvzID, EmployeeID: "employee-2", Reason: "не подошел размер",
+	})
+	require.NoError(t, err)
+	assert.True(t, ret.ReturnedAt.After(issuance.IssuedAt))
+
+	gotReturn, err := repo.GetLastReturn(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, ret.ID, gotReturn.ID)
+	assert.Equal(t, "не подошел размер", gotReturn.Reason)
+
+	stock, err = repo.GetStock(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first, second}, productIDs(stock))
+
+	// Внешние ключи на товар и ПВЗ
+	_, err = repo.CreateIssuance(ctx, model.Issuance{ProductID: uuid.New(), PvzID: pvzID, EmployeeID: "e", ConfirmationCode: "1111"})
+	assert.Error(t, err)
+	_, err = repo.CreateReturn(ctx, model.ProductReturn{ProductID: first, PvzID: uuid.New(), EmployeeID: "e", Reason: "брак"})
+	assert.Error(t, err)
+}
+
+func testStorageCells(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	shelfB, err := repo.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "B-01", Capacity: 5, SizeClass: model.SizeLarge})
+	require.NoError(t, err)
+	shelfA, err := repo.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 2, SizeClass: model.SizeSmall})
+	require.NoError(t, err)
+
+	// Код уникален в пределах ПВЗ, ячейка ссылается на существующий ПВЗ
+	_, err = repo.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 1, SizeClass: model.SizeSmall})
+	assert.Error(t, err)
+	_, err = repo.CreateStorageCell(ctx, model.StorageCell{PvzID: uuid.New(), Code: "A-01", Capacity: 1, SizeClass: model.SizeSmall})
+	assert.Error(t, err)
+
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	first, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID, CellID: shelfA})
+	require.NoError(t, err)
+	_, err = repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID, CellID: shelfA})
+	require.NoError(t, err)
+	_, err = repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID, CellID: uuid.New()})
+	assert.Error(t, err)
+
+	got, err := repo.GetProductByID(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, shelfA, got.CellID)
+
+	// Сортировка по коду, выданный товар место не занимает
+	cells, err := repo.GetStorageCells(ctx, pvzID)
+	require.NoError(t, err)
+	require.Len(t, cells, 2)
+	assert.Equal(t, []string{"A-01", "B-01"}, []string{cells[0].Code, cells[1].Code})
+	assert.Equal(t, 2, cells[0].Occupied)
+	assert.Equal(t, 0, cells[1].Occupied)
+
+	require.NoError(t, repo.CloseReception(ctx, receptionID, model.CloseReasonManual))
+	_, err = repo.CreateIssuance(ctx, model.Issuance{ProductID: first, PvzID: pvzID, EmployeeID: "e", ConfirmationCode: "1234"})
+	require.NoError(t, err)
+
+	cells, err = repo.GetStorageCells(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cells[0].Occupied)
+
+	// При удалении ячейки товары остаются без ячейки
+	require.NoError(t, repo.DeleteStorageCell(ctx, shelfA))
+	assert.Error(t, repo.DeleteStorageCell(ctx, shelfA))
+
+	got, err = repo.GetProductByID(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, got.CellID)
+
+	cells, err = repo.GetStorageCells(ctx, pvzID)
+	require.NoError(t, err)
+	require.Len(t, cells, 1)
+	assert.Equal(t, shelfB, cells[0].ID)
+}
+
+func testPvzProfile(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+
+	// Новый ПВЗ - без адреса, графика и лимита, в поясе по умолчанию
+	pvz, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultTimezone, pvz.Timezone)
+	assert.Nil(t, pvz.Coordinates)
+	assert.False(t, pvz.WorkingHours.IsSet())
+	assert.True(t, pvz.ReceptionOverrideUntil.IsZero())
+
+	pvz.Address = "ул. Баумана, 10"
+	pvz.Coordinates = &model.GeoPoint{Lat: 55.7887, Lng: 49.1221}
+	pvz.WorkingHours = model.WorkingHours{
+		Weekly: map[time.Weekday]model.DayHours{
+			time.Monday:   {Open: 9 * 60, Close: 21 * 60},
+			time.Saturday: {Open: 10 * 60, Close: model.MinutesInDay},
+		},
+		Exceptions: []model.HoursException{
+			{Date: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), Closed: true},
+			{Date: time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC), Hours: model.DayHours{Open: 9 * 60, Close: 18 * 60}},
+		},
+	}
+	pvz.MaxDailyReceptions = 2
+	pvz.ReceptionOverrideUntil = time.Date(2024, 3, 1, 20, 30, 0, 0, time.UTC)
+	require.NoError(t, repo.UpdatePvzProfile(ctx, *pvz))
+
+	got, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, pvz.Address, got.Address)
+	assert.Equal(t, pvz.Coordinates, got.Coordinates)
+	assert.Equal(t, pvz.WorkingHours, got.WorkingHours)
+	assert.Equal(t, 2, got.MaxDailyReceptions)
+	assert.True(t, pvz.ReceptionOverrideUntil.Equal(got.ReceptionOverrideUntil))
+
+	// Сброс разрешения модератора
+	got.ReceptionOverrideUntil = time.Time{}
+	require.NoError(t, repo.UpdatePvzProfile(ctx, *got))
+	got, err = repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.True(t, got.ReceptionOverrideUntil.IsZero())
+
+	assert.Error(t, repo.UpdatePvzProfile(ctx, model.Pvz{ID: uuid.New(), Timezone: model.DefaultTimezone}))
+
+	// Приемки считаются по ПВЗ в полуоткрытом интервале
+	firstID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	first, err := repo.GetReceptionByID(ctx, firstID)
+	require.NoError(t, err)
+	require.NoError(t, repo.CloseReception(ctx, firstID, model.CloseReasonManual))
+	_, err = repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	otherPvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	_, err = repo.CreateReception(ctx, otherPvzID)
+	require.NoError(t, err)
+
+	count, err := repo.CountReceptions(ctx, pvzID, first.DateTime, first.DateTime.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = repo.CountReceptions(ctx, pvzID, first.DateTime.Add(-time.Hour), first.DateTime)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func testNearbyPvz(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	createAt := func(city string, point *model.GeoPoint) uuid.UUID {
+		id, err := repo.CreatePvz(ctx, city)
+		require.NoError(t, err)
+		if point != nil {
+			require.NoError(t, repo.UpdatePvzProfile(ctx, model.Pvz{ID: id, Timezone: model.DefaultTimezone, Coordinates: point}))
+		}
+		return id
+	}
+
+	// Второй ПВЗ примерно в 1.1 км к северу от первого, третий - в другом городе
+	nearID := createAt("Москва", &model.GeoPoint{Lat: 55.7520, Lng: 37.6175})
+	farID := createAt("Москва", &model.GeoPoint{Lat: 55.7620, Lng: 37.6175})
+	createAt("Казань", &model.GeoPoint{Lat: 55.7887, Lng: 49.1221})
+	createAt("Москва", nil)
+
+	point := model.GeoPoint{Lat: 55.7520, Lng: 37.6180}
+	list, err := repo.GetNearbyPvz(ctx, model.NearbyQuery{Point: point, RadiusMeters: 5000, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, nearID, list[0].Pvz.ID)
+	assert.Equal(t, farID, list[1].Pvz.ID)
+	assert.InDelta(t, 31, list[0].DistanceMeters, 1)
+	assert.InDelta(t, 1112, list[1].DistanceMeters, 5)
+	assert.Equal(t, model.DefaultTimezone, list[0].Pvz.Timezone)
+
+	list, err = repo.GetNearbyPvz(ctx, model.NearbyQuery{Point: point, RadiusMeters: 5000, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, nearID, list[0].Pvz.ID)
+
+	list, err = repo.GetNearbyPvz(ctx, model.NearbyQuery{Point: point, RadiusMeters: 500, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, nearID, list[0].Pvz.ID)
+}
+
+func testPvzStatus(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	pvz, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PvzStatusActive, pvz.Status)
+
+	require.NoError(t, repo.UpdatePvzStatus(ctx, pvzID, model.PvzStatusActive, model.PvzStatusSuspended))
+
+	// Статус меняется, только если он не изменился с момента чтения
+	assert.Error(t, repo.UpdatePvzStatus(ctx, pvzID, model.PvzStatusActive, model.PvzStatusDecommissioned))
+	assert.Error(t, repo.UpdatePvzStatus(ctx, uuid.New(), model.PvzStatusActive, model.PvzStatusSuspended))
+	assert.Error(t, repo.UpdatePvzStatus(ctx, pvzID, model.PvzStatusSuspended, "archived"))
+
+	pvz, err = repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PvzStatusSuspended, pvz.Status)
+
+	// Выведенный из эксплуатации ПВЗ не ищется рядом, но остается в базе вместе с приемками
+	point := model.GeoPoint{Lat: 55.75, Lng: 37.62}
+	require.NoError(t, repo.UpdatePvzProfile(ctx, model.Pvz{ID: pvzID, Timezone: model.DefaultTimezone, Coordinates: &point}))
+	_, err = repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	nearby, err := repo.GetNearbyPvz(ctx, model.NearbyQuery{Point: point, RadiusMeters: 100, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, nearby, 1)
+
+	require.NoError(t, repo.UpdatePvzStatus(ctx, pvzID, model.PvzStatusSuspended, model.PvzStatusDecommissioned))
+
+	nearby, err = repo.GetNearbyPvz(ctx, model.NearbyQuery{Point: point, RadiusMeters: 100, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, nearby)
+
+	ids, err := repo.GetIDListPvz(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, ids, pvzID)
+
+	_, err = repo.GetLastReception(ctx, pvzID)
+	assert.NoError(t, err)
+}
+
+func testStaleReceptions(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	old := model.Reception{ID: uuid.New(), DateTime: now.Add(-48 * time.Hour), PvzID: pvzID}
+	older := model.Reception{ID: uuid.New(), DateTime: now.Add(-72 * time.Hour), PvzID: pvzID}
+	closed := model.Reception{ID: uuid.New(), DateTime: now.Add(-96 * time.Hour), IsClosed: true, PvzID: pvzID}
+	for _, reception := range []model.Reception{old, older, closed} {
+		require.NoError(t, repo.InsertReception(ctx, reception))
+	}
+	freshID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	// только открытые приемки старше порога, от самой старой
+	stale, err := repo.GetOpenReceptionsBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{older.ID, old.ID}, receptionIDs(stale))
+
+	all, err := repo.GetOpenReceptionsBefore(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{older.ID, old.ID, freshID}, receptionIDs(all))
+
+	// отметки ставятся один раз
+	require.NoError(t, repo.MarkReceptionReminded(ctx, old.ID))
+	assert.Error(t, repo.MarkReceptionReminded(ctx, old.ID))
+	require.NoError(t, repo.FlagReception(ctx, old.ID))
+	assert.Error(t, repo.FlagReception(ctx, old.ID))
+	assert.Error(t, repo.FlagReception(ctx, closed.ID))
+	assert.Error(t, repo.FlagReception(ctx, uuid.New()))
+
+	got, err := repo.GetReceptionByID(ctx, old.ID)
+	require.NoError(t, err)
+	assert.False(t, got.RemindedAt.IsZero())
+	assert.False(t, got.FlaggedAt.IsZero())
+	assert.Empty(t, got.CloseReason)
+
+	require.NoError(t, repo.CloseReception(ctx, older.ID, model.CloseReasonTimeout))
+	got, err = repo.GetReceptionByID(ctx, older.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsClosed)
+	assert.Equal(t, model.CloseReasonTimeout, got.CloseReason)
+
+	// неизвестная причина не проходит CHECK
+	assert.Error(t, repo.CloseReception(ctx, old.ID, "forgotten"))
+
+	stale, err = repo.GetOpenReceptionsBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{old.ID}, receptionIDs(stale))
+}
+
+func testWebhooks(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	sub := model.WebhookSubscription{
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.WebhookEventReceptionClosed, model.WebhookEventProductReceived},
+		PvzIDs:     []uuid.UUID{pvzID},
+		Secret:     "secret",
+	}
+	subID, err := repo.CreateWebhook(ctx, sub)
+	require.NoError(t, err)
+	otherID, err := repo.CreateWebhook(ctx, model.WebhookSubscription{
+		URL:        "https://other.example/hooks",
+		EventTypes: []string{model.WebhookEventReceptionCreated},
+		Secret:     "other",
+	})
+	require.NoError(t, err)
+
+	got, err := repo.GetWebhookByID(ctx, subID)
+	require.NoError(t, err)
+	assert.Equal(t, sub.URL, got.URL)
+	assert.Equal(t, sub.EventTypes, got.EventTypes)
+	assert.Equal(t, sub.PvzIDs, got.PvzIDs)
+	assert.Equal(t, sub.Secret, got.Secret)
+	assert.False(t, got.CreatedAt.IsZero())
+
+	// пустой фильтр ПВЗ читается как пустой список
+	other, err := repo.GetWebhookByID(ctx, otherID)
+	require.NoError(t, err)
+	assert.Empty(t, other.PvzIDs)
+
+	subs, err := repo.GetWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	assert.Equal(t, []uuid.UUID{subID, otherID}, []uuid.UUID{subs[0].ID, subs[1].ID})
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	newDelivery := func(subscriptionID uuid.UUID, next time.Time) uuid.UUID {
+		id, err := repo.CreateWebhookDelivery(ctx, model.WebhookDelivery{
+			SubscriptionID: subscriptionID,
+			EventID:        uuid.New(),
+			EventType:      model.WebhookEventReceptionClosed,
+			Payload:        []byte(`{"type": "reception.closed"}`),
+			Status:         model.WebhookDeliveryPending,
+			NextAttemptAt:  next,
+		})
+		require.NoError(t, err)
+		return id
+	}
+
+	later := newDelivery(subID, now.Add(-time.Minute))
+	earlier := newDelivery(subID, now.Add(-time.Hour))
+	future := newDelivery(subID, now.Add(time.Hour))
+	otherDelivery := newDelivery(otherID, now.Add(-time.Second))
+
+	// внешний ключ на подписку
+	_, err = repo.CreateWebhookDelivery(ctx, model.WebhookDelivery{
+		SubscriptionID: uuid.New(), EventID: uuid.New(), EventType: model.WebhookEventTest,
+		Payload: []byte(`{}`), Status: model.WebhookDeliveryPending, NextAttemptAt: now,
+	})
+	assert.Error(t, err)
+
+	// к отправке - только пришедшие по времени, от самой давней
+	due, err := repo.GetDueWebhookDeliveries(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{earlier, later, otherDelivery}, deliveryIDs(due))
+
+	due, err = repo.GetDueWebhookDeliveries(ctx, now, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{earlier}, deliveryIDs(due))
+
+	delivered := due[0]
+	delivered.Status = model.WebhookDeliveryDelivered
+	delivered.Attempts = 1
+	delivered.ResponseStatus = 200
+	delivered.DeliveredAt = now
+	require.NoError(t, repo.UpdateWebhookDelivery(ctx, delivered))
+
+	stored, err := repo.GetWebhookDeliveryByID(ctx, earlier)
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryDelivered, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, 200, stored.ResponseStatus)
+	assert.Equal(t, now, stored.DeliveredAt)
+	assert.JSONEq(t, `{"type": "reception.closed"}`, string(stored.Payload))
+
+	// неизвестный статус не проходит CHECK
+	delivered.Status = "lost"
+	assert.Error(t, repo.UpdateWebhookDelivery(ctx, delivered))
+	assert.Error(t, repo.UpdateWebhookDelivery(ctx, model.WebhookDelivery{ID: uuid.New(), Status: model.WebhookDeliveryDead}))
+
+	// журнал подписки - новые первыми, с фильтром по статусу и страницами
+	history, err := repo.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{SubscriptionID: subID, Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{future, earlier, later}, deliveryIDs(history))
+
+	history, err = repo.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{SubscriptionID: subID, Page: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{later}, deliveryIDs(history))
+
+	history, err = repo.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{
+		SubscriptionID: subID, Status: model.WebhookDeliveryDelivered, Page: 1, Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{earlier}, deliveryIDs(history))
+
+	// удаление подписки уносит ее журнал
+	require.NoError(t, repo.DeleteWebhook(ctx, subID))
+	assert.Error(t, repo.DeleteWebhook(ctx, subID))
+	_, err = repo.GetWebhookByID(ctx, subID)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	_, err = repo.GetWebhookDeliveryByID(ctx, later)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	due, err = repo.GetDueWebhookDeliveries(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{otherDelivery}, deliveryIDs(due))
+}
+
+func testChangeStamps(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	_, err := repo.GetPvzChangeStamp(ctx, uuid.New())
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	otherPvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+
+	list, err := repo.GetPvzListChangeStamp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Count)
+
+	// Отметка ПВЗ сдвигается при изменении его самого и связанных с ним данных
+	last, err := repo.GetPvzChangeStamp(ctx, pvzID)
+	require.NoError(t, err)
+	advanced := func(step string) {
+		t.Helper()
+		stamp, err := repo.GetPvzChangeStamp(ctx, pvzID)
+		require.NoError(t, err)
+		assert.True(t, stamp.ChangedAt.After(last.ChangedAt), step)
+		last = stamp
+	}
+
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	advanced("create reception")
+
+	productID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID})
+	require.NoError(t, err)
+	advanced("create product")
+
+	require.NoError(t, repo.CloseReception(ctx, receptionID, model.CloseReasonManual))
+	advanced("close reception")
+
+	_, err = repo.CreateIssuance(ctx, model.Issuance{ProductID: productID, PvzID: pvzID, EmployeeID: "e", ConfirmationCode: "1234"})
+	require.NoError(t, err)
+	advanced("create issuance")
+
+	cellID, err := repo.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 1, SizeClass: model.SizeSmall})
+	require.NoError(t, err)
+	advanced("create storage cell")
+
+	require.NoError(t, repo.DeleteStorageCell(ctx, cellID))
+	advanced("delete storage cell")
+
+	pvz, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	pvz.Address = "ул. Тверская, 1"
+	require.NoError(t, repo.UpdatePvzProfile(ctx, *pvz))
+	advanced("update profile")
+
+	// Соседний ПВЗ не затронут, отметка списка - самая поздняя из всех
+	other, err := repo.GetPvzChangeStamp(ctx, otherPvzID)
+	require.NoError(t, err)
+	assert.True(t, other.ChangedAt.Before(last.ChangedAt))
+
+	list, err = repo.GetPvzListChangeStamp(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Count)
+	assert.True(t, list.ChangedAt.Equal(last.ChangedAt))
+}
+
+// Данные одного оператора не видны и не меняются другим, без оператора в контексте хранилище не работает
+func testTenantIsolation(t *testing.T, repo service.Repository) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	other, err := repo.CreateTenant(ctx, "tenant-"+uuid.NewString())
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, other.ID)
+	assert.False(t, other.CreatedAt.IsZero())
+	otherCtx := tenant.WithID(context.Background(), other.ID)
+
+	got, err := repo.GetTenantByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.Name, got.Name)
+	assert.False(t, got.OpenRegistration)
+	_, err = repo.GetTenantByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	// Регистрация у нового оператора закрыта, пока ее не откроет администратор
+	require.NoError(t, repo.SetTenantRegistration(ctx, other.ID, true))
+	got, err = repo.GetTenantByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.True(t, got.OpenRegistration)
+	assert.ErrorIs(t, repo.SetTenantRegistration(ctx, uuid.New(), true), model.ErrNotFound)
+	_, err = repo.CreateTenant(ctx, other.Name)
+	assert.Error(t, err)
+
+	tenants, err := repo.GetTenants(ctx)
+	require.NoError(t, err)
+	tenantIDs := make([]uuid.UUID, 0, len(tenants))
+	for _, item := range tenants {
+		tenantIDs = append(tenantIDs, item.ID)
+	}
+	assert.Contains(t, tenantIDs, tenant.DefaultID)
+	assert.Contains(t, tenantIDs, other.ID)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	productID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID, Barcode: "isolation-001"})
+	require.NoError(t, err)
+	_, err = repo.CreateWebhook(ctx, model.WebhookSubscription{
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.WebhookEventReceptionClosed},
+		Secret:     "secret",
+	})
+	require.NoError(t, err)
+
+	otherPvzID, err := repo.CreatePvz(otherCtx, "Казань")
+	require.NoError(t, err)
+
+	_, err = repo.GetPvzByID(otherCtx, pvzID)
+	assert.Error(t, err)
+	ids, err := repo.GetIDListPvz(otherCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{otherPvzID}, ids)
+	_, err = repo.GetReceptionByID(otherCtx, receptionID)
+	assert.Error(t, err)
+	_, err = repo.GetLastReception(otherCtx, pvzID)
+	assert.Error(t, err)
+	_, err = repo.GetProductByID(otherCtx, productID)
+	assert.Error(t, err)
+	products, err := repo.GetProductsByBarcode(otherCtx, "isolation-001")
+	require.NoError(t, err)
+	assert.Empty(t, products)
+	hooks, err := repo.GetWebhooks(otherCtx)
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+	stamp, err := repo.GetPvzListChangeStamp(otherCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stamp.Count)
+
+	// Изменения чужих данных не находят строк
+	assert.Error(t, repo.UpdatePvzStatus(otherCtx, pvzID, model.PvzStatusActive, model.PvzStatusSuspended))
+	assert.Error(t, repo.CloseReception(otherCtx, receptionID, model.CloseReasonManual))
+	assert.Error(t, repo.DeleteProductByID(otherCtx, productID))
+
+	pvz, err := repo.GetPvzByID(ctx, pvzID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PvzStatusActive, pvz.Status)
+	reception, err := repo.GetReceptionByID(ctx, receptionID)
+	require.NoError(t, err)
+	assert.False(t, reception.IsClosed)
+	_, err = repo.GetProductByID(ctx, productID)
+	assert.NoError(t, err)
+
+	_, err = repo.CreatePvz(context.Background(), "Сочи")
+	assert.Error(t, err)
+	_, err = repo.GetPvzByID(context.Background(), pvzID)
+	assert.Error(t, err)
+}
+
+func testAuthTokens(t *testing.T, repo service.Repository) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	userID, err := repo.CreateUser(ctx, &model.User{Email: "user@example.com", Password: "hash", Role: "employee", TenantID: tenant.DefaultID})
+	require.NoError(t, err)
+
+	user, err := repo.GetUserByEmail(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, user.EmailVerifiedAt.IsZero())
+
+	// Повторное подтверждение время не меняет
+	require.NoError(t, repo.MarkEmailVerified(ctx, userID, now))
+	require.NoError(t, repo.MarkEmailVerified(ctx, userID, now.Add(time.Hour)))
+	user, err = repo.GetUserByEmail(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.True(t, now.Equal(user.EmailVerifiedAt))
+
+	require.NoError(t, repo.UpdatePassword(ctx, userID, "new-hash"))
+	user, err = repo.GetUserByEmail(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", user.Password)
+
+	assert.Error(t, repo.UpdatePassword(ctx, uuid.New(), "hash"))
+	assert.Error(t, repo.MarkEmailVerified(ctx, uuid.New(), now))
+
+	newToken := func(hash, purpose string, expiresAt time.Time) model.AuthToken {
+		return model.AuthToken{TokenHash: hash, UserID: userID, Purpose: purpose, ExpiresAt: expiresAt}
+	}
+	require.NoError(t, repo.CreateAuthToken(ctx, newToken("verify", model.AuthTokenVerifyEmail, now.Add(time.Hour))))
+	require.NoError(t, repo.CreateAuthToken(ctx, newToken("reset-1", model.AuthTokenResetPassword, now.Add(time.Hour))))
+	require.NoError(t, repo.CreateAuthToken(ctx, newToken("reset-2", model.AuthTokenResetPassword, now.Add(time.Hour))))
+	require.NoError(t, repo.CreateAuthToken(ctx, newToken("expired", model.AuthTokenResetPassword, now.Add(-time.Minute))))
+
+	// Хеш токена уникален, внешний ключ auth_token -> users
+	assert.Error(t, repo.CreateAuthToken(ctx, newToken("verify", model.AuthTokenVerifyEmail, now.Add(time.Hour))))
+	assert.Error(t, repo.CreateAuthToken(ctx, model.AuthToken{
+		TokenHash: "orphan", UserID: uuid.New(), Purpose: model.AuthTokenVerifyEmail, ExpiresAt: now.Add(time.Hour),
+	}))
+
+	// Токен работает только для своего назначения и только один раз
+	_, err = repo.UseAuthToken(ctx, model.AuthTokenResetPassword, "verify", now)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	used, err := repo.UseAuthToken(ctx, model.AuthTokenVerifyEmail, "verify", now)
+	require.NoError(t, err)
+	assert.Equal(t, userID, used.UserID)
+	assert.Equal(t, model.AuthTokenVerifyEmail, used.Purpose)
+	assert.True(t, now.Equal(used.UsedAt))
+	_, err = repo.UseAuthToken(ctx, model.AuthTokenVerifyEmail, "verify", now)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	_, err = repo.UseAuthToken(ctx, model.AuthTokenResetPassword, "expired", now)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	_, err = repo.UseAuthToken(ctx, model.AuthTokenResetPassword, "missing", now)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+
+	// После сброса пароля остальные ссылки на сброс не работают
+	require.NoError(t, repo.UseUserAuthTokens(ctx, userID, model.AuthTokenResetPassword, now))
+	_, err = repo.UseAuthToken(ctx, model.AuthTokenResetPassword, "reset-1", now)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+	_, err = repo.UseAuthToken(ctx, model.AuthTokenResetPassword, "reset-2", now)
+	assert.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func receptionIDs(receptions []model.Reception) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(receptions))
+	for _, r := range receptions {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func productIDs(products []model.Product) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func deliveryIDs(deliveries []model.WebhookDelivery) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
@@ -0,0 +1,111 @@
+// Package scheduler запускает фоновые задачи по таймеру.
+// Если запущено несколько экземпляров сервиса, задачи выполняет только лидер
+package scheduler
+
+import (
+	"context"
+	log "log/slog"
+	"time"
+)
+
+// Leader - блокировка лидера. TryAcquire возвращает true, пока экземпляр остается лидером
+type Leader interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+// Job - фоновая задача. Ошибка попадает в лог и не мешает остальным задачам
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	interval time.Duration
+	leader   Leader
+	jobs     []Job
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func New(interval time.Duration, leader Leader, jobs ...Job) *Scheduler {
+	return &Scheduler{
+		interval: interval,
+		leader:   leader,
+		jobs:     jobs,
+	}
+}
+
+// Start запускает задачи в фоне: сразу и затем каждые interval
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	go s.loop(ctx)
+}
+
+// Stop останавливает таймер, дожидается текущего прохода и отдает лидерство
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return s.leader.Release(ctx)
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick выполняет задачи по очереди, если экземпляр - лидер
+func (s *Scheduler) tick(ctx context.Context) {
+	leader, err := s.leader.TryAcquire(ctx)
+	if err != nil {
+		log.Error("Scheduler leader election failed", log.Any("err", err))
+		return
+	}
+	if !leader {
+		log.Debug("Scheduler is standing by, another instance is the leader")
+		return
+	}
+
+	for _, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := job.Run(ctx); err != nil {
+			log.Error("Scheduled job failed", "job", job.Name, log.Any("err", err))
+		}
+	}
+}
+
+// Single - лидер без блокировки, когда экземпляр заведомо один (хранилище в памяти)
+type Single struct{}
+
+func (Single) TryAcquire(context.Context) (bool, error) {
+	return true, nil
+}
+
+func (Single) Release(context.Context) error {
+	return nil
+}
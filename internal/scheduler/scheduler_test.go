@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLeader struct {
+	leader   atomic.Bool
+	err      error
+	released atomic.Bool
+}
+
+func (l *fakeLeader) TryAcquire(context.Context) (bool, error) {
+	return l.leader.Load(), l.err
+}
+
+func (l *fakeLeader) Release(context.Context) error {
+	l.released.Store(true)
+	return nil
+}
+
+func countingJob(name string, runs *atomic.Int32, err error) Job {
+	return Job{
+		Name: name,
+		Run: func(context.Context) error {
+			runs.Add(1)
+			return err
+		},
+	}
+}
+
+func TestScheduler_Tick(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("лидер выполняет все задачи, ошибка одной не мешает остальным", func(t *testing.T) {
+		var first, second atomic.Int32
+		leader := &fakeLeader{}
+		leader.leader.Store(true)
+
+		s := New(time.Minute, leader,
+			countingJob("first", &first, errors.New("boom")),
+			countingJob("second", &second, nil),
+		)
+		s.tick(ctx)
+
+		assert.Equal(t, int32(1), first.Load())
+		assert.Equal(t, int32(1), second.Load())
+	})
+
+	t.Run("не лидер ничего не выполняет", func(t *testing.T) {
+		var runs atomic.Int32
+		s := New(time.Minute, &fakeLeader{}, countingJob("job", &runs, nil))
+		s.tick(ctx)
+
+		assert.Zero(t, runs.Load())
+	})
+
+	t.Run("ошибка выбора лидера пропускает проход", func(t *testing.T) {
+		var runs atomic.Int32
+		leader := &fakeLeader{err: errors.New("connection refused")}
+		leader.leader.Store(true)
+
+		s := New(time.Minute, leader, countingJob("job", &runs, nil))
+		s.tick(ctx)
+
+		assert.Zero(t, runs.Load())
+	})
+}
+
+func TestScheduler_StartStop(t *testing.T) {
+	var runs atomic.Int32
+	leader := &fakeLeader{}
+	leader.leader.Store(true)
+
+	s := New(10*time.Millisecond, leader, countingJob("job", &runs, nil))
+	s.Start(context.Background())
+
+	// Первый проход сразу после старта, следующие - по таймеру
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, s.Stop(context.Background()))
+	assert.True(t, leader.released.Load())
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+}
+
+func TestScheduler_StopWithoutStart(t *testing.T) {
+	s := New(time.Minute, Single{})
+	assert.NoError(t, s.Stop(context.Background()))
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	log "log/slog"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	InvalidToken        = "token is invalid, expired or already used"
+	EmailNotVerified    = "email is not verified"
+	MailNotConfigured   = "mail is not configured"
+	FailedIssueToken    = "failed to issue token"
+	FailedSendMail      = "failed to send mail"
+	FailedVerifyEmail   = "failed to verify email"
+	FailedResetPassword = "failed to reset password"
+)
+
+// authTokenBytes - случайная часть токена из письма, 256 бит
+const authTokenBytes = 32
+
+// Пути страниц клиента, на которые ведут ссылки из писем
+const (
+	VerifyEmailPath   = "/verify-email"
+	ResetPasswordPath = "/reset-password"
+)
+
+const (
+	verifyMailSubject = "Подтверждение почты"
+	verifyMailBody    = "Подтвердите почту для входа в сервис ПВЗ:\n\n%s\n\n" +
+		"Ссылка действует %s. Если вы не регистрировались, просто удалите письмо.\n"
+	resetMailSubject = "Сброс пароля"
+	resetMailBody    = "Чтобы задать новый пароль, перейдите по ссылке:\n\n%s\n\n" +
+		"Ссылка действует %s и срабатывает один раз. Если вы не запрашивали сброс, просто удалите письмо.\n"
+)
+
+// Mailer отправляет письма пользователям. Реализации - в пакете mailer
+type Mailer interface {
+	Send(ctx context.Context, mail model.Mail) error
+}
+
+type AuthTokenRepository interface {
+	CreateAuthToken(ctx context.Context, token model.AuthToken) error
+	UseAuthToken(ctx context.Context, purpose, tokenHash string, now time.Time) (*model.AuthToken, error)
+	UseUserAuthTokens(ctx context.Context, userID uuid.UUID, purpose string, now time.Time) error
+}
+
+// AccountConfig - сроки жизни токенов из писем и адрес клиента, на который ведут ссылки
+type AccountConfig interface {
+	GetVerificationTTL() time.Duration
+	GetResetTTL() time.Duration
+	GetLinkBaseURL() string
+	RequireVerifiedEmail() bool
+}
+
+// VerifyEmail подтверждает почту по токену из письма. Токен гасится в одной транзакции
+// с подтверждением: если почта не отметилась, ссылка остается рабочей
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	now := time.Now().UTC()
+	return s.Tx.InTx(ctx, func(ctx context.Context) error {
+		used, err := s.useToken(ctx, model.AuthTokenVerifyEmail, token, now, FailedVerifyEmail)
+		if err != nil {
+			return err
+		}
+
+		if err = s.userRepository.MarkEmailVerified(ctx, used.UserID, now); err != nil {
+			return NewInternalError(FailedVerifyEmail, err)
+		}
+
+		return nil
+	})
+}
+
+// ResendVerification повторяет письмо с подтверждением. Ответ один и тот же для любой почты,
+// как и у ForgotPassword: письмо уходит в фоне, неизвестная или уже подтвержденная почта
+// ничего не получает, ошибки только пишутся в лог
+func (s *AuthService) ResendVerification(ctx context.Context, email string) error {
+	if !s.mailEnabled() {
+		return NewInternalError(MailNotConfigured, nil)
+	}
+
+	user, err := s.userRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, model.ErrNotFound) {
+			log.ErrorContext(ctx, "Failed to get user for email verification", log.Any("err", err))
+		}
+		return nil
+	}
+	if !user.EmailVerifiedAt.IsZero() {
+		return nil
+	}
+
+	s.sendInBackground(ctx, *user, s.sendVerification, "Failed to send verification email")
+	return nil
+}
+
+// ForgotPassword отправляет ссылку для сброса пароля. Ответ один и тот же для любой почты:
+// токен и письмо готовятся в фоне, ошибки только пишутся в лог, поэтому ни по коду ответа,
+// ни по его времени нельзя узнать, зарегистрирован ли адрес
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	if !s.mailEnabled() {
+		return NewInternalError(MailNotConfigured, nil)
+	}
+
+	user, err := s.userRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, model.ErrNotFound) {
+			log.ErrorContext(ctx, "Failed to get user for password reset", log.Any("err", err))
+		}
+		return nil
+	}
+
+	s.sendInBackground(ctx, *user, s.sendReset, "Failed to send password reset email")
+	return nil
+}
+
+// WaitMails ждет писем, которые еще отправляются в фоне
+func (s *AuthService) WaitMails(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.mails.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ResetPassword задает новый пароль по токену из письма. Пароль проверяется до того,
+// как гасится токен: со слабым паролем ссылкой можно воспользоваться еще раз.
+// Токен гасится в одной транзакции со сменой пароля: если пароль не сохранился,
+// ссылка остается рабочей
+func (s *AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	if err := s.Policy.Check(password); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return s.Tx.InTx(ctx, func(ctx context.Context) error {
+		used, err := s.useToken(ctx, model.AuthTokenResetPassword, token, now, FailedResetPassword)
+		if err != nil {
+			return err
+		}
+
+		hashPass, err := s.Hasher.Hash(password)
+		if err != nil {
+			return NewInternalError("failed to hash pass", err)
+		}
+
+		if err = s.userRepository.UpdatePassword(ctx, used.UserID, hashPass); err != nil {
+			return NewInternalError(FailedResetPassword, err)
+		}
+
+		// Остальные ссылки на сброс больше не нужны. Письмо пришло на почту пользователя,
+		// так что почта заодно подтверждена
+		if err = s.Tokens.UseUserAuthTokens(ctx, used.UserID, model.AuthTokenResetPassword, now); err != nil {
+			return NewInternalError(FailedResetPassword, err)
+		}
+		if err = s.userRepository.MarkEmailVerified(ctx, used.UserID, now); err != nil {
+			return NewInternalError(FailedResetPassword, err)
+		}
+
+		return nil
+	})
+}
+
+func (s *AuthService) mailEnabled() bool {
+	return s.Mailer != nil && s.Tokens != nil && s.Accounts != nil
+}
+
+// useToken гасит токен из письма. Неизвестный, истекший или уже использованный токен -
+// ошибка клиента, сбой хранилища - внутренняя ошибка с сообщением failure
+func (s *AuthService) useToken(ctx context.Context, purpose, token string, now time.Time, failure string) (*model.AuthToken, error) {
+	used, err := s.Tokens.UseAuthToken(ctx, purpose, hashAuthToken(token), now)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, NewValidationError(CodeInvalidToken, InvalidToken)
+		}
+		return nil, NewInternalError(failure, err)
+	}
+	return used, nil
+}
+
+// sendInBackground отправляет письмо в фоне, чтобы ни код ответа, ни его время не зависели
+// от того, зарегистрирован ли адрес. Ошибки отправки только пишутся в лог
+func (s *AuthService) sendInBackground(ctx context.Context, user model.User, send func(context.Context, model.User) error, failure string) {
+	// Письмо не должно оборваться вместе с запросом, поэтому контекст без отмены
+	ctx = context.WithoutCancel(ctx)
+	s.mails.Add(1)
+	go func() {
+		defer s.mails.Done()
+		if err := send(ctx, user); err != nil {
+			log.ErrorContext(ctx, failure, "user_id", user.ID.String(), log.Any("err", err))
+		}
+	}()
+}
+
+func (s *AuthService) sendReset(ctx context.Context, user model.User) error {
+	ttl := s.Accounts.GetResetTTL()
+	token, err := s.issueToken(ctx, user.ID, model.AuthTokenResetPassword, ttl)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, model.Mail{
+		To:      user.Email,
+		Subject: resetMailSubject,
+		Body:    fmt.Sprintf(resetMailBody, s.link(ResetPasswordPath, token), formatTTL(ttl)),
+	})
+}
+
+func (s *AuthService) sendVerification(ctx context.Context, user model.User) error {
+	ttl := s.Accounts.GetVerificationTTL()
+	token, err := s.issueToken(ctx, user.ID, model.AuthTokenVerifyEmail, ttl)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, model.Mail{
+		To:      user.Email,
+		Subject: verifyMailSubject,
+		Body:    fmt.Sprintf(verifyMailBody, s.link(VerifyEmailPath, token), formatTTL(ttl)),
+	})
+}
+
+// issueToken создает токен и сохраняет его хеш. Сам токен уходит только в письмо
+func (s *AuthService) issueToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, authTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", NewInternalError(FailedIssueToken, err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := s.Tokens.CreateAuthToken(ctx, model.AuthToken{
+		TokenHash: hashAuthToken(token),
+		UserID:    userID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	})
+	if err != nil {
+		return "", NewInternalError(FailedIssueToken, err)
+	}
+
+	return token, nil
+}
+
+func (s *AuthService) send(ctx context.Context, mail model.Mail) error {
+	if err := s.Mailer.Send(ctx, mail); err != nil {
+		return NewInternalError(FailedSendMail, err)
+	}
+	return nil
+}
+
+func (s *AuthService) link(path, token string) string {
+	return strings.TrimRight(s.Accounts.GetLinkBaseURL(), "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+func hashAuthToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// formatTTL пишет срок действия ссылки для письма: "48 ч", "30 мин"
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d ч", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d мин", int(ttl.Round(time.Minute).Minutes()))
+}
+
+// validateEmail принимает только голый адрес, без имени и угловых скобок
+func validateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || !strings.Contains(addr.Address[strings.LastIndex(addr.Address, "@")+1:], ".") {
+		return NewValidationError(CodeInvalidEmail, InvalidEmailFormat)
+	}
+	return nil
+}
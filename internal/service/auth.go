@@ -2,15 +2,20 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	log "log/slog"
+	"sync"
 	"time"
 
 	"pvz-service/internal/model"
 	"pvz-service/internal/service/pkg/hash"
+	"pvz-service/internal/tenant"
 	"pvz-service/pkg/jwtutils"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 const (
@@ -19,16 +24,62 @@ const (
 
 	ModeratorRole  = "moderator"
 	ModeratorEmail = "moderator@test.com"
+
+	// AdminRole заводит операторов. Токен администратора выдает команда `pvz-service admin token`
+	AdminRole = "admin"
+)
+
+// InvalidCredentials одинаков для неизвестной почты и неверного пароля,
+// чтобы по ответу нельзя было проверить, зарегистрирован ли адрес
+const InvalidCredentials = "invalid email or password"
+
+// RegistrationClosed - оператор не принимает самостоятельную регистрацию или не существует
+const RegistrationClosed = "registration is closed for this tenant"
+
+// Claims токена
+const (
+	ClaimUserID   = "userId"
+	ClaimRole     = "role"
+	ClaimTenantID = "tenantId"
+	// ClaimDummy помечает токены /dummyLogin в окружении dev
+	ClaimDummy = "dummy"
 )
 
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *model.User) (uuid.UUID, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, at time.Time) error
 }
 
 type AuthService struct {
 	userRepository UserRepository
 	jwtSecret      string
+
+	// Tenants проверяет, что оператор открыт для регистрации
+	Tenants TenantRepository
+	// Policy проверяет пароль при регистрации и сбросе
+	Policy PasswordPolicy
+	// Hasher хеширует пароли настроенным алгоритмом. Хеши старых алгоритмов и параметров
+	// по-прежнему проверяются и пересчитываются при успешном входе
+	Hasher *hash.Passwords
+	// Tokens, Mailer и Accounts нужны для подтверждения почты и сброса пароля.
+	// Без Mailer письма при регистрации не отправляются, а сброс пароля недоступен
+	Tokens   AuthTokenRepository
+	Mailer   Mailer
+	Accounts AccountConfig
+	// MarkDummyTokens добавляет claim dummy в токены DummyAuth, по нему их можно отличить от настоящих
+	MarkDummyTokens bool
+	// Tx объединяет погашение токена из письма и изменение пользователя
+	Tx Transactor
+
+	// dummyHash проверяется при входе с неизвестной почтой. Считается при первом входе,
+	// когда Hasher уже настроен, поэтому стоит столько же, сколько проверка настоящего хеша
+	dummyHashOnce sync.Once
+	dummyHash     string
+
+	// mails - письма подтверждения и сброса пароля, которые еще отправляются в фоне
+	mails sync.WaitGroup
 }
 
 func NewAuthService(
@@ -37,106 +88,230 @@ func NewAuthService(
 	return &AuthService{
 		userRepository: repo,
 		jwtSecret:      jwt,
+		Policy:         DefaultPasswordPolicy(),
+		Hasher:         hash.Default(),
+		Tx:             nopTransactor{},
 	}
 }
 
+// Registration заводит пользователя и отправляет письмо для подтверждения почты.
+// Без оператора пользователь попадает к оператору по умолчанию
 func (s *AuthService) Registration(ctx context.Context, user model.User) (*model.User, error) {
-	hashPass, err := hash.HashPassword(user.Password)
+	if err := validateEmail(user.Email); err != nil {
+		return nil, err
+	}
+	if err := s.Policy.Check(user.Password); err != nil {
+		return nil, err
+	}
+
+	// Оператора задает заголовок X-Tenant-ID, а не тело запроса, и регистрация
+	// у него должна быть открыта администратором
+	user.TenantID = tenant.DefaultID
+	if id, ok := tenant.FromContext(ctx); ok {
+		user.TenantID = id
+	}
+	if err := s.checkRegistrationOpen(ctx, user.TenantID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.createUser(ctx, user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash pass")
+		return nil, err
+	}
+
+	// Пользователь уже создан: письмо можно запросить повторно, поэтому регистрация не падает
+	if s.mailEnabled() {
+		if err = s.sendVerification(ctx, *created); err != nil {
+			log.ErrorContext(ctx, "Failed to send verification email", "user_id", created.ID.String(), log.Any("err", err))
+		}
+	}
+
+	return created, nil
+}
+
+// checkRegistrationOpen отвечает одинаково для неизвестного оператора и закрытой регистрации,
+// чтобы по ответу нельзя было перебрать id операторов
+func (s *AuthService) checkRegistrationOpen(ctx context.Context, tenantID uuid.UUID) error {
+	if s.Tenants == nil {
+		return nil
+	}
+
+	t, err := s.Tenants.GetTenantByID(ctx, tenantID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return NewInternalError("failed to get tenant", err)
+	}
+	if err != nil || !t.OpenRegistration {
+		return NewForbiddenError(CodeRegistrationClosed, RegistrationClosed)
+	}
+
+	return nil
+}
+
+// createUser сохраняет пользователя без проверок почты и пароля, их делает вызывающий
+func (s *AuthService) createUser(ctx context.Context, user model.User) (*model.User, error) {
+	if user.TenantID == uuid.Nil {
+		user.TenantID = tenant.DefaultID
+	}
+
+	hashPass, err := s.Hasher.Hash(user.Password)
+	if err != nil {
+		return nil, NewInternalError("failed to hash pass", err)
 	}
 
 	user.Password = hashPass
 
 	if _, err := s.userRepository.GetUserByEmail(ctx, user.Email); err == nil {
-		return nil, fmt.Errorf("user already exist")
+		return nil, NewConflictError(CodeUserAlreadyExists, "user already exist")
 	}
 
 	userID, err := s.userRepository.CreateUser(ctx, &user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user")
+		return nil, NewInternalError("failed to create user", err)
 	}
 
 	return &model.User{
-		ID:       userID,
-		Email:    user.Email,
-		Password: hashPass,
-		Role:     user.Role,
+		ID:              userID,
+		Email:           user.Email,
+		Password:        hashPass,
+		Role:            user.Role,
+		TenantID:        user.TenantID,
+		EmailVerifiedAt: user.EmailVerifiedAt,
 	}, nil
 }
 
 func (s *AuthService) Authenticate(ctx context.Context, user model.User) (string, error) {
 	current, err := s.userRepository.GetUserByEmail(ctx, user.Email)
 	if err != nil {
-		return "", fmt.Errorf("user not found")
+		if !errors.Is(err, model.ErrNotFound) {
+			return "", NewInternalError("failed to get user", err)
+		}
+		// Хеш проверяется и для неизвестной почты: иначе ее выдает время ответа
+		_, _ = s.Hasher.Verify(s.getDummyHash(), user.Password)
+		return "", NewUnauthorizedError(CodeInvalidCredentials, InvalidCredentials)
+	}
+
+	rehash, err := s.Hasher.Verify(current.Password, user.Password)
+	if err != nil {
+		return "", NewUnauthorizedError(CodeInvalidCredentials, InvalidCredentials)
+	}
+
+	if s.Accounts != nil && s.Accounts.RequireVerifiedEmail() && current.EmailVerifiedAt.IsZero() {
+		return "", NewForbiddenError(CodeEmailNotVerified, EmailNotVerified)
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(current.Password), []byte(user.Password)); err != nil {
-		return "", fmt.Errorf("invalid email or password")
+	if rehash {
+		s.upgradeHash(ctx, current.ID, user.Password)
 	}
 
-	token, err := s.generateJWT(current.ID.String(), current.Role)
+	token, err := s.generateJWT(current.ID.String(), current.Role, current.TenantID)
 
 	return token, err
 }
 
+// getDummyHash возвращает хеш случайного пароля текущим алгоритмом. Если хешировать не удалось,
+// Verify с пустой строкой отработает быстрее, но вход все равно будет отклонен
+func (s *AuthService) getDummyHash() string {
+	s.dummyHashOnce.Do(func() {
+		password, err := randomPassword()
+		if err == nil {
+			s.dummyHash, _ = s.Hasher.Hash(password)
+		}
+	})
+	return s.dummyHash
+}
+
+// upgradeHash пересчитывает хеш устаревшего алгоритма или стоимости. Пароль в открытом виде
+// есть только при входе, поэтому обновление идет здесь. Ошибка не мешает входу: попробуем в следующий раз
+func (s *AuthService) upgradeHash(ctx context.Context, userID uuid.UUID, password string) {
+	hashPass, err := s.Hasher.Hash(password)
+	if err == nil {
+		err = s.userRepository.UpdatePassword(ctx, userID, hashPass)
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to upgrade password hash", "user_id", userID.String(), log.Any("err", err))
+	}
+}
+
+// DummyAuth выдает токен тестового пользователя роли. Пользователь заводится при первом вызове
+// со случайным паролем: войти под ним через /login нельзя, только через эту ручку
 func (s *AuthService) DummyAuth(ctx context.Context, user model.User) (string, error) {
 	userDummy, err := getTestUserByRole(user.Role)
 	if err != nil {
 		return "", err
 	}
 
-	token, err := s.Authenticate(ctx, *userDummy)
-
-	// Сработает только при первом вызове, либо при краше БД
-	// Если данный тестовый пользователь не был найден
-	// Попробуем его зарегать, а потом заново зайти
-	// Если получаем ошибку, то либо сменили пароль учетки, либо краш БД
+	current, err := s.userRepository.GetUserByEmail(ctx, userDummy.Email)
 	if err != nil {
-		_, err = s.Registration(ctx, *userDummy)
+		userDummy.Password, err = randomPassword()
 		if err != nil {
-			return "", fmt.Errorf("failed to create test user")
+			return "", NewInternalError("failed to generate test user password", err)
 		}
+		// Тестовым пользователям подтверждать почту нечем
+		userDummy.EmailVerifiedAt = time.Now().UTC()
 
-		token, err = s.Authenticate(ctx, *userDummy)
+		current, err = s.createUser(ctx, *userDummy)
 		if err != nil {
-			return "", fmt.Errorf("failed to authenticate test user")
+			return "", NewInternalError("failed to create test user", err)
 		}
 	}
 
-	return token, nil
+	claims := tokenClaims(current.ID.String(), current.Role, current.TenantID)
+	if s.MarkDummyTokens {
+		claims[ClaimDummy] = true
+	}
+
+	return s.signJWT(claims)
 }
 
 func getTestUserByRole(role string) (*model.User, error) {
 	switch role {
 	case EmployeeRole:
 		return &model.User{
-			Email:    EmployeeEmail,
-			Password: role,
-			Role:     EmployeeRole,
+			Email: EmployeeEmail,
+			Role:  EmployeeRole,
 		}, nil
 
 	case ModeratorRole:
 		return &model.User{
-			Email:    ModeratorEmail,
-			Password: role,
-			Role:     ModeratorRole,
+			Email: ModeratorEmail,
+			Role:  ModeratorRole,
 		}, nil
 	}
 
-	return nil, fmt.Errorf("forbidden role %s", role)
+	return nil, NewValidationError(CodeInvalidRole, fmt.Sprintf("forbidden role %s", role))
 }
 
-func (s *AuthService) generateJWT(userID string, role string) (string, error) {
-	claims := map[string]interface{}{
-		"userId": userID,
-		"role":   role,
-	}
+func (s *AuthService) generateJWT(userID string, role string, tenantID uuid.UUID) (string, error) {
+	return s.signJWT(tokenClaims(userID, role, tenantID))
+}
 
+func (s *AuthService) signJWT(claims map[string]interface{}) (string, error) {
 	token, err := jwtutils.Generate(claims, 24*time.Hour, s.jwtSecret)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate JWT token")
+		return "", NewInternalError("failed to generate JWT token", err)
 	}
 
 	return token, nil
 }
+
+// GenerateToken подписывает токен пользователя. Вынесено для выдачи токенов из командной строки
+func GenerateToken(secret, userID, role string, tenantID uuid.UUID, ttl time.Duration) (string, error) {
+	return jwtutils.Generate(tokenClaims(userID, role, tenantID), ttl, secret)
+}
+
+func tokenClaims(userID, role string, tenantID uuid.UUID) map[string]interface{} {
+	return map[string]interface{}{
+		ClaimUserID:   userID,
+		ClaimRole:     role,
+		ClaimTenantID: tenantID.String(),
+	}
+}
+
+// randomPassword - пароль тестового пользователя, его никто не знает
+func randomPassword() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
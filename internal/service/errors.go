@@ -0,0 +1,103 @@
+package service
+
+import "errors"
+
+// Категории доменных ошибок. Проверяются через errors.Is,
+// по ним HTTP-слой выбирает статус ответа.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrValidation   = errors.New("validation failed")
+	ErrInternal     = errors.New("internal error")
+)
+
+// Стабильные коды ошибок, которые получает клиент
+const (
+	CodeUserAlreadyExists       = "user_already_exists"
+	CodeInvalidCredentials      = "invalid_credentials"
+	CodeInvalidRole             = "invalid_role"
+	CodeReceptionNotClosed      = "reception_not_closed"
+	CodeReceptionAlreadyClosed  = "reception_already_closed"
+	CodeReceptionNotFound       = "reception_not_found"
+	CodeProductNotFound         = "product_not_found"
+	CodeDuplicateBarcode        = "duplicate_barcode"
+	CodePvzNotFound             = "pvz_not_found"
+	CodeSyncOutOfOrder          = "sync_out_of_order"
+	CodeSyncTimeInFuture        = "sync_time_in_future"
+	CodeInvalidSyncOperation    = "invalid_sync_operation"
+	CodeProductAlreadyIssued    = "product_already_issued"
+	CodeProductNotIssued        = "product_not_issued"
+	CodeStorageCellNotFound     = "storage_cell_not_found"
+	CodeStorageCellExists       = "storage_cell_already_exists"
+	CodeStorageCellFull         = "storage_cell_full"
+	CodeStorageCellTooSmall     = "storage_cell_too_small"
+	CodeStorageCellNotEmpty     = "storage_cell_not_empty"
+	CodeInvalidPvzProfile       = "invalid_pvz_profile"
+	CodePvzClosed               = "pvz_closed"
+	CodeReceptionLimitReached   = "reception_limit_reached"
+	CodePvzNotActive            = "pvz_not_active"
+	CodeInvalidPvzStatus        = "invalid_pvz_status_transition"
+	CodeInvalidWebhookURL       = "invalid_webhook_url"
+	CodeInvalidWebhookEvent     = "invalid_webhook_event_type"
+	CodeWebhookNotFound         = "webhook_not_found"
+	CodeWebhookDeliveryNotFound = "webhook_delivery_not_found"
+	CodeWebhookDeliveryNotDead  = "webhook_delivery_not_dead"
+	CodeTenantNotFound          = "tenant_not_found"
+	CodeTenantAlreadyExists     = "tenant_already_exists"
+	CodeInvalidTenantName       = "invalid_tenant_name"
+	CodeRegistrationClosed      = "registration_closed"
+	CodeWeakPassword            = "weak_password"
+	CodeInvalidEmail            = "invalid_email"
+	CodeInvalidToken            = "invalid_token"
+	CodeEmailNotVerified        = "email_not_verified"
+	CodeInternal                = "internal_error"
+)
+
+// Error - доменная ошибка сервиса.
+// Message безопасно отдавать клиенту, Err - исходная причина, она попадает только в логи.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+func NewValidationError(code, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+func NewInternalError(message string, err error) *Error {
+	return &Error{Kind: ErrInternal, Code: CodeInternal, Message: message, Err: err}
+}
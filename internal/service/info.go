@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"sort"
 
 	"github.com/google/uuid"
 	"pvz-service/internal/model"
 )
 
+const FailedGetInfo = "failed to get pvz info"
+
 type InfoService struct {
 	productRepository   ProductRepository
 	receptionRepository ReceptionRepository
@@ -24,13 +27,13 @@ func NewInfoService(repoProduct ProductRepository, repoRepository ReceptionRepos
 func (s *InfoService) GetInfoPvz(ctx context.Context, query *model.PvzInfoQuery) ([]*model.Pvz, error) {
 	receptions, err := s.receptionRepository.GetReceptionsSliceWithTimeRange(ctx, query.StartDate, query.EndDate)
 	if err != nil {
-		return nil, err
+		return nil, NewInternalError(FailedGetInfo, err)
 	}
 
 	for i, _ := range receptions {
 		receptions[i].Products, err = s.productRepository.GetProductSliceByReceptionID(ctx, receptions[i].ID)
 		if err != nil {
-			return nil, err
+			return nil, NewInternalError(FailedGetInfo, err)
 		}
 	}
 
@@ -40,7 +43,7 @@ func (s *InfoService) GetInfoPvz(ctx context.Context, query *model.PvzInfoQuery)
 	if query.StartDate.IsZero() && query.EndDate.IsZero() {
 		idList, err := s.pvzRepository.GetIDListPvz(ctx)
 		if err != nil {
-			return nil, err
+			return nil, NewInternalError(FailedGetInfo, err)
 		}
 		for _, id := range idList {
 			if _, ok := recepMap[id]; !ok {
@@ -55,15 +58,36 @@ func (s *InfoService) GetInfoPvz(ctx context.Context, query *model.PvzInfoQuery)
 	for k, _ := range recepMap {
 		pvz, err := s.pvzRepository.GetPvzByID(ctx, k)
 		if err != nil {
-			return nil, err
+			return nil, NewInternalError(FailedGetInfo, err)
+		}
+		if pvz.Status == model.PvzStatusDecommissioned && !query.IncludeInactive {
+			continue
 		}
 		pvz.Receptions = recepMap[k]
 		res = append(res, pvz)
 	}
 
+	// Страницы режутся из стабильного порядка, а не из порядка обхода map
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].RegistrationDate.Equal(res[j].RegistrationDate) {
+			return res[i].RegistrationDate.Before(res[j].RegistrationDate)
+		}
+		return res[i].ID.String() < res[j].ID.String()
+	})
+
 	return s.rangeAnswerByPagination(res, query.Page, query.Limit), nil
 }
 
+// GetInfoChangeStamp возвращает отметку изменения данных, из которых собирается GET /pvz
+func (s *InfoService) GetInfoChangeStamp(ctx context.Context) (model.ChangeStamp, error) {
+	stamp, err := s.pvzRepository.GetPvzListChangeStamp(ctx)
+	if err != nil {
+		return model.ChangeStamp{}, NewInternalError(FailedGetInfo, err)
+	}
+
+	return stamp, nil
+}
+
 func (s *InfoService) getMapReceptionsOrderByPvz(receptions []model.Reception) map[uuid.UUID][]model.Reception {
 	recepMap := make(map[uuid.UUID][]model.Reception, len(receptions))
 
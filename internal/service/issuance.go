@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	ProductNotInPvz        = "product was not received at this pvz"
+	ReceptionStillOpen     = "products of a reception in progress cannot be issued or returned"
+	ProductAlreadyIssued   = "product has already been issued"
+	ProductNotIssued       = "product has not been issued"
+	FailedIssuanceCreate   = "failed to issue product"
+	FailedIssuanceGet      = "failed to get product issuances and returns"
+	FailedReturnCreate     = "failed to return product"
+	FailedStockCalculation = "failed to calculate pvz stock"
+)
+
+type IssuanceRepository interface {
+	LockProduct(ctx context.Context, productID uuid.UUID) error
+	CreateIssuance(ctx context.Context, issuance model.Issuance) (*model.Issuance, error)
+	CreateReturn(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error)
+	GetLastIssuance(ctx context.Context, productID uuid.UUID) (*model.Issuance, error)
+	GetLastReturn(ctx context.Context, productID uuid.UUID) (*model.ProductReturn, error)
+	GetStock(ctx context.Context, pvzID uuid.UUID) ([]model.Product, error)
+}
+
+type IssuanceService struct {
+	issuanceRepository  IssuanceRepository
+	productRepository   ProductRepository
+	receptionRepository ReceptionRepository
+	pvzRepository       PvzRepository
+	// Tx - транзакция, в которой проверяется и записывается выдача или возврат
+	Tx Transactor
+}
+
+func NewIssuanceService(issuanceRepo IssuanceRepository, productRepo ProductRepository,
+	receptionRepo ReceptionRepository, pvzRepo PvzRepository) *IssuanceService {
+	return &IssuanceService{
+		issuanceRepository:  issuanceRepo,
+		productRepository:   productRepo,
+		receptionRepository: receptionRepo,
+		pvzRepository:       pvzRepo,
+		Tx:                  nopTransactor{},
+	}
+}
+
+// IssueProduct выдает покупателю товар, который сейчас лежит на складе ПВЗ.
+// Проверка и запись идут в одной транзакции под блокировкой товара, поэтому две
+// одновременные выдачи одного товара не пройдут обе
+func (s *IssuanceService) IssueProduct(ctx context.Context, issuance model.Issuance) (*model.Issuance, error) {
+	var created *model.Issuance
+
+	err := s.Tx.InTx(ctx, func(ctx context.Context) error {
+		issued, err := s.lockProduct(ctx, issuance.ProductID, issuance.PvzID)
+		if err != nil {
+			return err
+		}
+		if issued {
+			return NewConflictError(CodeProductAlreadyIssued, ProductAlreadyIssued)
+		}
+
+		created, err = s.issuanceRepository.CreateIssuance(ctx, issuance)
+		if err != nil {
+			return NewInternalError(FailedIssuanceCreate, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// ReturnProduct возвращает на склад ранее выданный товар. Как и выдача, идет под блокировкой товара
+func (s *IssuanceService) ReturnProduct(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error) {
+	var created *model.ProductReturn
+
+	err := s.Tx.InTx(ctx, func(ctx context.Context) error {
+		issued, err := s.lockProduct(ctx, ret.ProductID, ret.PvzID)
+		if err != nil {
+			return err
+		}
+		if !issued {
+			return NewConflictError(CodeProductNotIssued, ProductNotIssued)
+		}
+
+		created, err = s.issuanceRepository.CreateReturn(ctx, ret)
+		if err != nil {
+			return NewInternalError(FailedReturnCreate, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetStock возвращает товары, которые сейчас находятся на складе ПВЗ
+func (s *IssuanceService) GetStock(ctx context.Context, pvz model.Pvz) ([]model.Product, error) {
+	if _, err := s.pvzRepository.GetPvzByID(ctx, pvz.ID); err != nil {
+		return nil, pvzError(err)
+	}
+
+	products, err := s.issuanceRepository.GetStock(ctx, pvz.ID)
+	if err != nil {
+		return nil, NewInternalError(FailedStockCalculation, err)
+	}
+
+	return products, nil
+}
+
+// lockProduct блокирует товар до конца транзакции, проверяет его и сообщает, выдан ли он сейчас
+func (s *IssuanceService) lockProduct(ctx context.Context, productID, pvzID uuid.UUID) (bool, error) {
+	if err := s.issuanceRepository.LockProduct(ctx, productID); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return false, NewNotFoundError(CodeProductNotFound, ProductNotFound)
+		}
+		return false, NewInternalError(FailedIssuanceGet, err)
+	}
+
+	if err := s.checkProduct(ctx, productID, pvzID); err != nil {
+		return false, err
+	}
+
+	return s.isIssued(ctx, productID)
+}
+
+// checkProduct проверяет, что товар принят в этом ПВЗ и его приемка закрыта.
+// Товары открытой приемки еще можно удалить, поэтому выдавать их нельзя
+func (s *IssuanceService) checkProduct(ctx context.Context, productID, pvzID uuid.UUID) error {
+	product, err := s.productRepository.GetProductByID(ctx, productID)
+	if err != nil {
+		return NewNotFoundError(CodeProductNotFound, ProductNotFound)
+	}
+
+	reception, err := s.receptionRepository.GetReceptionByID(ctx, product.ReceptionID)
+	if err != nil {
+		return NewInternalError(FailedIssuanceCreate, err)
+	}
+
+	if reception.PvzID != pvzID {
+		return NewNotFoundError(CodeProductNotFound, ProductNotInPvz)
+	}
+
+	if !reception.IsClosed {
+		return NewConflictError(CodeReceptionNotClosed, ReceptionStillOpen)
+	}
+
+	return nil
+}
+
+// isIssued - товар выдан, если после последней выдачи не было возврата.
+// Сбой хранилища - не "выдачи нет", он возвращается как внутренняя ошибка
+func (s *IssuanceService) isIssued(ctx context.Context, productID uuid.UUID) (bool, error) {
+	issuance, err := s.issuanceRepository.GetLastIssuance(ctx, productID)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, NewInternalError(FailedIssuanceGet, err)
+	}
+
+	ret, err := s.issuanceRepository.GetLastReturn(ctx, productID)
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return true, nil
+	case err != nil:
+		return false, NewInternalError(FailedIssuanceGet, err)
+	}
+
+	return ret.ReturnedAt.Before(issuance.IssuedAt), nil
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "pvz-service/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// AuthTokenRepository is an autogenerated mock type for the AuthTokenRepository type
+type AuthTokenRepository struct {
+	mock.Mock
+}
+
+// CreateAuthToken provides a mock function with given fields: ctx, token
+func (_m *AuthTokenRepository) CreateAuthToken(ctx context.Context, token model.AuthToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuthToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.AuthToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseAuthToken provides a mock function with given fields: ctx, purpose, tokenHash, now
+func (_m *AuthTokenRepository) UseAuthToken(ctx context.Context, purpose string, tokenHash string, now time.Time) (*model.AuthToken, error) {
+	ret := _m.Called(ctx, purpose, tokenHash, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseAuthToken")
+	}
+
+	var r0 *model.AuthToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*model.AuthToken, error)); ok {
+		return rf(ctx, purpose, tokenHash, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *model.AuthToken); ok {
+		r0 = rf(ctx, purpose, tokenHash, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, purpose, tokenHash, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseUserAuthTokens provides a mock function with given fields: ctx, userID, purpose, now
+func (_m *AuthTokenRepository) UseUserAuthTokens(ctx context.Context, userID uuid.UUID, purpose string, now time.Time) error {
+	ret := _m.Called(ctx, userID, purpose, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseUserAuthTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, time.Time) error); ok {
+		r0 = rf(ctx, userID, purpose, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuthTokenRepository creates a new instance of AuthTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthTokenRepository {
+	mock := &AuthTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,199 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "pvz-service/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// IssuanceRepository is an autogenerated mock type for the IssuanceRepository type
+type IssuanceRepository struct {
+	mock.Mock
+}
+
+// CreateIssuance provides a mock function with given fields: ctx, issuance
+func (_m *IssuanceRepository) CreateIssuance(ctx context.Context, issuance model.Issuance) (*model.Issuance, error) {
+	ret := _m.Called(ctx, issuance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIssuance")
+	}
+
+	var r0 *model.Issuance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Issuance) (*model.Issuance, error)); ok {
+		return rf(ctx, issuance)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Issuance) *model.Issuance); ok {
+		r0 = rf(ctx, issuance)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Issuance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Issuance) error); ok {
+		r1 = rf(ctx, issuance)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateReturn provides a mock function with given fields: ctx, ret
+func (_m *IssuanceRepository) CreateReturn(ctx context.Context, ret model.ProductReturn) (*model.ProductReturn, error) {
+	ret_2 := _m.Called(ctx, ret)
+
+	if len(ret_2) == 0 {
+		panic("no return value specified for CreateReturn")
+	}
+
+	var r0 *model.ProductReturn
+	var r1 error
+	if rf, ok := ret_2.Get(0).(func(context.Context, model.ProductReturn) (*model.ProductReturn, error)); ok {
+		return rf(ctx, ret)
+	}
+	if rf, ok := ret_2.Get(0).(func(context.Context, model.ProductReturn) *model.ProductReturn); ok {
+		r0 = rf(ctx, ret)
+	} else {
+		if ret_2.Get(0) != nil {
+			r0 = ret_2.Get(0).(*model.ProductReturn)
+		}
+	}
+
+	if rf, ok := ret_2.Get(1).(func(context.Context, model.ProductReturn) error); ok {
+		r1 = rf(ctx, ret)
+	} else {
+		r1 = ret_2.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastIssuance provides a mock function with given fields: ctx, productID
+func (_m *IssuanceRepository) GetLastIssuance(ctx context.Context, productID uuid.UUID) (*model.Issuance, error) {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastIssuance")
+	}
+
+	var r0 *model.Issuance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*model.Issuance, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *model.Issuance); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Issuance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastReturn provides a mock function with given fields: ctx, productID
+func (_m *IssuanceRepository) GetLastReturn(ctx context.Context, productID uuid.UUID) (*model.ProductReturn, error) {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastReturn")
+	}
+
+	var r0 *model.ProductReturn
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*model.ProductReturn, error)); ok {
+		return rf(ctx, productID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *model.ProductReturn); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ProductReturn)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, productID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStock provides a mock function with given fields: ctx, pvzID
+func (_m *IssuanceRepository) GetStock(ctx context.Context, pvzID uuid.UUID) ([]model.Product, error) {
+	ret := _m.Called(ctx, pvzID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStock")
+	}
+
+	var r0 []model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.Product, error)); ok {
+		return rf(ctx, pvzID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.Product); ok {
+		r0 = rf(ctx, pvzID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, pvzID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LockProduct provides a mock function with given fields: ctx, productID
+func (_m *IssuanceRepository) LockProduct(ctx context.Context, productID uuid.UUID) error {
+	ret := _m.Called(ctx, productID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, productID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIssuanceRepository creates a new instance of IssuanceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIssuanceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IssuanceRepository {
+	mock := &IssuanceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
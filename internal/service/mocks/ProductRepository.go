@@ -16,9 +16,9 @@ type ProductRepository struct {
 	mock.Mock
 }
 
-// CreateProduct provides a mock function with given fields: ctx, typeProduct, recepID
-func (_m *ProductRepository) CreateProduct(ctx context.Context, typeProduct string, recepID uuid.UUID) (uuid.UUID, error) {
-	ret := _m.Called(ctx, typeProduct, recepID)
+// CreateProduct provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) CreateProduct(ctx context.Context, product model.Product) (uuid.UUID, error) {
+	ret := _m.Called(ctx, product)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateProduct")
@@ -26,19 +26,19 @@ func (_m *ProductRepository) CreateProduct(ctx context.Context, typeProduct stri
 
 	var r0 uuid.UUID
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) (uuid.UUID, error)); ok {
-		return rf(ctx, typeProduct, recepID)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Product) (uuid.UUID, error)); ok {
+		return rf(ctx, product)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) uuid.UUID); ok {
-		r0 = rf(ctx, typeProduct, recepID)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Product) uuid.UUID); ok {
+		r0 = rf(ctx, product)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(uuid.UUID)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
-		r1 = rf(ctx, typeProduct, recepID)
+	if rf, ok := ret.Get(1).(func(context.Context, model.Product) error); ok {
+		r1 = rf(ctx, product)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -154,6 +154,54 @@ func (_m *ProductRepository) GetProductSliceByReceptionID(ctx context.Context, r
 	return r0, r1
 }
 
+// GetProductsByBarcode provides a mock function with given fields: ctx, barcode
+func (_m *ProductRepository) GetProductsByBarcode(ctx context.Context, barcode string) ([]model.Product, error) {
+	ret := _m.Called(ctx, barcode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProductsByBarcode")
+	}
+
+	var r0 []model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Product, error)); ok {
+		return rf(ctx, barcode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Product); ok {
+		r0 = rf(ctx, barcode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, barcode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertProduct provides a mock function with given fields: ctx, product
+func (_m *ProductRepository) InsertProduct(ctx context.Context, product model.Product) error {
+	ret := _m.Called(ctx, product)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertProduct")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Product) error); ok {
+		r0 = rf(ctx, product)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewProductRepository creates a new instance of ProductRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewProductRepository(t interface {
@@ -76,6 +76,36 @@ func (_m *PvzRepository) GetIDListPvz(ctx context.Context) ([]uuid.UUID, error)
 	return r0, r1
 }
 
+// GetNearbyPvz provides a mock function with given fields: ctx, nearby
+func (_m *PvzRepository) GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error) {
+	ret := _m.Called(ctx, nearby)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNearbyPvz")
+	}
+
+	var r0 []model.NearbyPvz
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.NearbyQuery) ([]model.NearbyPvz, error)); ok {
+		return rf(ctx, nearby)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.NearbyQuery) []model.NearbyPvz); ok {
+		r0 = rf(ctx, nearby)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.NearbyPvz)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.NearbyQuery) error); ok {
+		r1 = rf(ctx, nearby)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPvzByID provides a mock function with given fields: ctx, id
 func (_m *PvzRepository) GetPvzByID(ctx context.Context, id uuid.UUID) (*model.Pvz, error) {
 	ret := _m.Called(ctx, id)
@@ -106,6 +136,98 @@ func (_m *PvzRepository) GetPvzByID(ctx context.Context, id uuid.UUID) (*model.P
 	return r0, r1
 }
 
+// GetPvzChangeStamp provides a mock function with given fields: ctx, id
+func (_m *PvzRepository) GetPvzChangeStamp(ctx context.Context, id uuid.UUID) (model.ChangeStamp, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPvzChangeStamp")
+	}
+
+	var r0 model.ChangeStamp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (model.ChangeStamp, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) model.ChangeStamp); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(model.ChangeStamp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPvzListChangeStamp provides a mock function with given fields: ctx
+func (_m *PvzRepository) GetPvzListChangeStamp(ctx context.Context) (model.ChangeStamp, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPvzListChangeStamp")
+	}
+
+	var r0 model.ChangeStamp
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (model.ChangeStamp, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) model.ChangeStamp); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(model.ChangeStamp)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePvzProfile provides a mock function with given fields: ctx, pvz
+func (_m *PvzRepository) UpdatePvzProfile(ctx context.Context, pvz model.Pvz) error {
+	ret := _m.Called(ctx, pvz)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePvzProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Pvz) error); ok {
+		r0 = rf(ctx, pvz)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePvzStatus provides a mock function with given fields: ctx, id, from, to
+func (_m *PvzRepository) UpdatePvzStatus(ctx context.Context, id uuid.UUID, from string, to string) error {
+	ret := _m.Called(ctx, id, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePvzStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, string) error); ok {
+		r0 = rf(ctx, id, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPvzRepository creates a new instance of PvzRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPvzRepository(t interface {
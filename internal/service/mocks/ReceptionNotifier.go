@@ -0,0 +1,47 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "pvz-service/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReceptionNotifier is an autogenerated mock type for the ReceptionNotifier type
+type ReceptionNotifier struct {
+	mock.Mock
+}
+
+// NotifyReception provides a mock function with given fields: ctx, event
+func (_m *ReceptionNotifier) NotifyReception(ctx context.Context, event model.ReceptionEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotifyReception")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.ReceptionEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReceptionNotifier creates a new instance of ReceptionNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReceptionNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReceptionNotifier {
+	mock := &ReceptionNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -18,17 +18,17 @@ type ReceptionRepository struct {
 	mock.Mock
 }
 
-// CloseReception provides a mock function with given fields: ctx, receptionID
-func (_m *ReceptionRepository) CloseReception(ctx context.Context, receptionID uuid.UUID) error {
-	ret := _m.Called(ctx, receptionID)
+// CloseReception provides a mock function with given fields: ctx, receptionID, reason
+func (_m *ReceptionRepository) CloseReception(ctx context.Context, receptionID uuid.UUID, reason string) error {
+	ret := _m.Called(ctx, receptionID, reason)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CloseReception")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, receptionID)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, receptionID, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -36,6 +36,34 @@ func (_m *ReceptionRepository) CloseReception(ctx context.Context, receptionID u
 	return r0
 }
 
+// CountReceptions provides a mock function with given fields: ctx, pvzID, begin, end
+func (_m *ReceptionRepository) CountReceptions(ctx context.Context, pvzID uuid.UUID, begin time.Time, end time.Time) (int, error) {
+	ret := _m.Called(ctx, pvzID, begin, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountReceptions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) (int, error)); ok {
+		return rf(ctx, pvzID, begin, end)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) int); ok {
+		r0 = rf(ctx, pvzID, begin, end)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, pvzID, begin, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateReception provides a mock function with given fields: ctx, pvzID
 func (_m *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.UUID) (uuid.UUID, error) {
 	ret := _m.Called(ctx, pvzID)
@@ -66,6 +94,24 @@ func (_m *ReceptionRepository) CreateReception(ctx context.Context, pvzID uuid.U
 	return r0, r1
 }
 
+// FlagReception provides a mock function with given fields: ctx, receptionID
+func (_m *ReceptionRepository) FlagReception(ctx context.Context, receptionID uuid.UUID) error {
+	ret := _m.Called(ctx, receptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FlagReception")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, receptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetLastReception provides a mock function with given fields: ctx, pvzID
 func (_m *ReceptionRepository) GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error) {
 	ret := _m.Called(ctx, pvzID)
@@ -96,6 +142,36 @@ func (_m *ReceptionRepository) GetLastReception(ctx context.Context, pvzID uuid.
 	return r0, r1
 }
 
+// GetOpenReceptionsBefore provides a mock function with given fields: ctx, before
+func (_m *ReceptionRepository) GetOpenReceptionsBefore(ctx context.Context, before time.Time) ([]model.Reception, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOpenReceptionsBefore")
+	}
+
+	var r0 []model.Reception
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]model.Reception, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []model.Reception); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Reception)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetReceptionByID provides a mock function with given fields: ctx, id
 func (_m *ReceptionRepository) GetReceptionByID(ctx context.Context, id uuid.UUID) (*model.Reception, error) {
 	ret := _m.Called(ctx, id)
@@ -156,6 +232,42 @@ func (_m *ReceptionRepository) GetReceptionsSliceWithTimeRange(ctx context.Conte
 	return r0, r1
 }
 
+// InsertReception provides a mock function with given fields: ctx, reception
+func (_m *ReceptionRepository) InsertReception(ctx context.Context, reception model.Reception) error {
+	ret := _m.Called(ctx, reception)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertReception")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Reception) error); ok {
+		r0 = rf(ctx, reception)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkReceptionReminded provides a mock function with given fields: ctx, receptionID
+func (_m *ReceptionRepository) MarkReceptionReminded(ctx context.Context, receptionID uuid.UUID) error {
+	ret := _m.Called(ctx, receptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReceptionReminded")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, receptionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewReceptionRepository creates a new instance of ReceptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewReceptionRepository(t interface {
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "pvz-service/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// StorageCellRepository is an autogenerated mock type for the StorageCellRepository type
+type StorageCellRepository struct {
+	mock.Mock
+}
+
+// CreateStorageCell provides a mock function with given fields: ctx, cell
+func (_m *StorageCellRepository) CreateStorageCell(ctx context.Context, cell model.StorageCell) (uuid.UUID, error) {
+	ret := _m.Called(ctx, cell)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateStorageCell")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.StorageCell) (uuid.UUID, error)); ok {
+		return rf(ctx, cell)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.StorageCell) uuid.UUID); ok {
+		r0 = rf(ctx, cell)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.StorageCell) error); ok {
+		r1 = rf(ctx, cell)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteStorageCell provides a mock function with given fields: ctx, id
+func (_m *StorageCellRepository) DeleteStorageCell(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStorageCell")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetStorageCells provides a mock function with given fields: ctx, pvzID
+func (_m *StorageCellRepository) GetStorageCells(ctx context.Context, pvzID uuid.UUID) ([]model.StorageCell, error) {
+	ret := _m.Called(ctx, pvzID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStorageCells")
+	}
+
+	var r0 []model.StorageCell
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.StorageCell, error)); ok {
+		return rf(ctx, pvzID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.StorageCell); ok {
+		r0 = rf(ctx, pvzID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.StorageCell)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, pvzID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStorageCellRepository creates a new instance of StorageCellRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStorageCellRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StorageCellRepository {
+	mock := &StorageCellRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
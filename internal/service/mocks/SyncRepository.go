@@ -0,0 +1,109 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "pvz-service/internal/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// SyncRepository is an autogenerated mock type for the SyncRepository type
+type SyncRepository struct {
+	mock.Mock
+}
+
+// GetReceptionProducts provides a mock function with given fields: ctx, receptionID
+func (_m *SyncRepository) GetReceptionProducts(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error) {
+	ret := _m.Called(ctx, receptionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReceptionProducts")
+	}
+
+	var r0 []model.Product
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]model.Product, error)); ok {
+		return rf(ctx, receptionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []model.Product); ok {
+		r0 = rf(ctx, receptionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Product)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, receptionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSyncOperation provides a mock function with given fields: ctx, pvzID, id
+func (_m *SyncRepository) GetSyncOperation(ctx context.Context, pvzID uuid.UUID, id uuid.UUID) (*model.SyncOperation, error) {
+	ret := _m.Called(ctx, pvzID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSyncOperation")
+	}
+
+	var r0 *model.SyncOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*model.SyncOperation, error)); ok {
+		return rf(ctx, pvzID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *model.SyncOperation); ok {
+		r0 = rf(ctx, pvzID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SyncOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, pvzID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveSyncOperation provides a mock function with given fields: ctx, op
+func (_m *SyncRepository) SaveSyncOperation(ctx context.Context, op model.SyncOperation) error {
+	ret := _m.Called(ctx, op)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveSyncOperation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.SyncOperation) error); ok {
+		r0 = rf(ctx, op)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSyncRepository creates a new instance of SyncRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSyncRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SyncRepository {
+	mock := &SyncRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxPasswordBytes - bcrypt не принимает пароли длиннее 72 байт
+const MaxPasswordBytes = 72
+
+const (
+	PasswordTooShort   = "password must be at least %d characters long"
+	PasswordTooLong    = "password must be at most 72 bytes long"
+	PasswordTooSimple  = "password must contain at least %d of: lowercase letters, uppercase letters, digits, other symbols"
+	PasswordBreached   = "password is too common, choose another one"
+	InvalidEmailFormat = "invalid email format"
+)
+
+// PasswordPolicy - требования к паролю при регистрации и сбросе пароля
+type PasswordPolicy struct {
+	MinLength int
+	// MinClasses - сколько разных классов символов нужно: строчные, прописные, цифры, прочие
+	MinClasses int
+	// Breached - утекшие и слишком частые пароли в нижнем регистре
+	Breached map[string]struct{}
+}
+
+// DefaultPasswordPolicy совпадает со значениями по умолчанию секции auth.password конфига
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:  8,
+		MinClasses: 2,
+	}
+}
+
+// Check проверяет пароль. Текст ошибки объясняет пользователю, что не так
+func (p PasswordPolicy) Check(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return NewValidationError(CodeWeakPassword, fmt.Sprintf(PasswordTooShort, p.MinLength))
+	}
+	if len(password) > MaxPasswordBytes {
+		return NewValidationError(CodeWeakPassword, PasswordTooLong)
+	}
+	if passwordClasses(password) < p.MinClasses {
+		return NewValidationError(CodeWeakPassword, fmt.Sprintf(PasswordTooSimple, p.MinClasses))
+	}
+	if _, ok := p.Breached[strings.ToLower(password)]; ok {
+		return NewValidationError(CodeWeakPassword, PasswordBreached)
+	}
+
+	return nil
+}
+
+func passwordClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	classes := 0
+	for _, has := range []bool{lower, upper, digit, other} {
+		if has {
+			classes++
+		}
+	}
+	return classes
+}
+
+// ReadBreachList читает список утекших паролей: по одному на строку, пустые строки
+// и строки с # в начале пропускаются
+func ReadBreachList(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breach list: %w", err)
+	}
+	defer file.Close()
+
+	list := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		list[strings.ToLower(line)] = struct{}{}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breach list: %w", err)
+	}
+
+	return list, nil
+}
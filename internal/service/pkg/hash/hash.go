@@ -1,13 +1,91 @@
+// Package hash хеширует пароли. Хеш хранит алгоритм и параметры в префиксе
+// ($2a$10$... у bcrypt, $argon2id$v=19$m=...,t=...,p=...$ у argon2id),
+// поэтому хеши разных алгоритмов и настроек живут в одной колонке
 package hash
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"errors"
+	"strings"
 
-func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	ErrMismatch         = errors.New("password does not match")
+	ErrUnknownAlgorithm = errors.New("unknown password hash algorithm")
+	ErrMalformedHash    = errors.New("malformed password hash")
+)
+
+// PasswordHasher - один алгоритм хеширования с настроенными параметрами
+type PasswordHasher interface {
+	Algorithm() string
+	Hash(password string) (string, error)
+	// Verify проверяет пароль по хешу своего алгоритма, параметры берутся из хеша
+	Verify(encoded, password string) error
+	// NeedsRehash сообщает, что хеш сделан с другими параметрами, чем настроены сейчас
+	NeedsRehash(encoded string) bool
+}
+
+// Passwords хеширует новые пароли текущим алгоритмом, а проверяет хеши любого
+// известного алгоритма. Устаревший хеш Verify отмечает, чтобы его пересчитали при входе
+type Passwords struct {
+	current PasswordHasher
+}
+
+func NewPasswords(current PasswordHasher) *Passwords {
+	return &Passwords{current: current}
+}
+
+// Default - bcrypt с bcrypt.DefaultCost, так хешировались пароли до настройки алгоритма
+func Default() *Passwords {
+	return NewPasswords(NewBcrypt(bcrypt.DefaultCost))
+}
 
-	if err != nil {
-		return "", err
+func (p *Passwords) Hash(password string) (string, error) {
+	return p.current.Hash(password)
+}
+
+// Verify проверяет пароль и сообщает, нужно ли пересчитать хеш текущим алгоритмом
+func (p *Passwords) Verify(encoded, password string) (rehash bool, err error) {
+	algorithm := Algorithm(encoded)
+
+	var hasher PasswordHasher
+	switch {
+	case algorithm == p.current.Algorithm():
+		hasher = p.current
+	case algorithm == AlgorithmBcrypt:
+		hasher = NewBcrypt(bcrypt.DefaultCost)
+	case algorithm == AlgorithmArgon2id:
+		hasher = NewArgon2id(DefaultArgon2idParams())
+	default:
+		return false, ErrUnknownAlgorithm
 	}
 
-	return string(hash), nil
+	if err = hasher.Verify(encoded, password); err != nil {
+		return false, err
+	}
+
+	return hasher != p.current || p.current.NeedsRehash(encoded), nil
+}
+
+// HashPassword хеширует пароль алгоритмом по умолчанию
+func HashPassword(password string) (string, error) {
+	return Default().Hash(password)
+}
+
+// Algorithm определяет алгоритм по префиксу хеша, для незнакомого хеша возвращает пустую строку
+func Algorithm(encoded string) string {
+	switch {
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return AlgorithmBcrypt
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		return AlgorithmArgon2id
+	default:
+		return ""
+	}
 }
@@ -1,9 +1,12 @@
 package hash
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -19,3 +22,122 @@ func TestHashPassword(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+// Недорогие параметры, чтобы тесты не тратили время на хеширование
+var testArgon2idParams = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+func TestArgon2id(t *testing.T) {
+	hasher := NewArgon2id(testArgon2idParams)
+
+	encoded, err := hasher.Hash("123qweASD-1d")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.Equal(t, AlgorithmArgon2id, Algorithm(encoded))
+
+	again, err := hasher.Hash("123qweASD-1d")
+	require.NoError(t, err)
+	assert.NotEqual(t, encoded, again, "соль случайная")
+
+	assert.NoError(t, hasher.Verify(encoded, "123qweASD-1d"))
+	assert.ErrorIs(t, hasher.Verify(encoded, "wrong"), ErrMismatch)
+	assert.False(t, hasher.NeedsRehash(encoded))
+	assert.True(t, NewArgon2id(Argon2idParams{Memory: 128, Iterations: 1, Parallelism: 1}).NeedsRehash(encoded))
+
+	for _, malformed := range []string{
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+	} {
+		assert.ErrorIs(t, hasher.Verify(malformed, "123qweASD-1d"), ErrMalformedHash, malformed)
+		assert.True(t, hasher.NeedsRehash(malformed), malformed)
+	}
+}
+
+func TestBcrypt(t *testing.T) {
+	hasher := NewBcrypt(bcrypt.MinCost)
+
+	encoded, err := hasher.Hash("123qweASD-1d")
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmBcrypt, Algorithm(encoded))
+
+	assert.NoError(t, hasher.Verify(encoded, "123qweASD-1d"))
+	assert.ErrorIs(t, hasher.Verify(encoded, "wrong"), ErrMismatch)
+	assert.False(t, hasher.NeedsRehash(encoded))
+	assert.True(t, NewBcrypt(bcrypt.MinCost+1).NeedsRehash(encoded))
+}
+
+func TestPasswords_Verify(t *testing.T) {
+	bcryptOld, err := NewBcrypt(bcrypt.MinCost).Hash("123qweASD-1d")
+	require.NoError(t, err)
+	bcryptCurrent, err := NewBcrypt(bcrypt.MinCost + 1).Hash("123qweASD-1d")
+	require.NoError(t, err)
+	argon, err := NewArgon2id(testArgon2idParams).Hash("123qweASD-1d")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		current    PasswordHasher
+		encoded    string
+		wantRehash bool
+	}{
+		{"тот же алгоритм и cost", NewBcrypt(bcrypt.MinCost + 1), bcryptCurrent, false},
+		{"cost устарел", NewBcrypt(bcrypt.MinCost + 1), bcryptOld, true},
+		{"переход на argon2id", NewArgon2id(testArgon2idParams), bcryptCurrent, true},
+		{"argon2id с теми же параметрами", NewArgon2id(testArgon2idParams), argon, false},
+		{"возврат на bcrypt", NewBcrypt(bcrypt.MinCost + 1), argon, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passwords := NewPasswords(tt.current)
+
+			rehash, err := passwords.Verify(tt.encoded, "123qweASD-1d")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRehash, rehash)
+
+			rehash, err = passwords.Verify(tt.encoded, "wrong")
+			assert.ErrorIs(t, err, ErrMismatch)
+			assert.False(t, rehash)
+		})
+	}
+
+	_, err = Default().Verify("plain-text", "plain-text")
+	assert.ErrorIs(t, err, ErrUnknownAlgorithm)
+}
+
+func BenchmarkBcrypt(b *testing.B) {
+	for _, cost := range []int{bcrypt.DefaultCost, 12} {
+		hasher := NewBcrypt(cost)
+		benchmarkHasher(b, fmt.Sprintf("cost=%d", cost), hasher)
+	}
+}
+
+func BenchmarkArgon2id(b *testing.B) {
+	benchmarkHasher(b, "owasp", NewArgon2id(DefaultArgon2idParams()))
+	benchmarkHasher(b, "m=64MiB,t=3,p=4", NewArgon2id(Argon2idParams{Memory: 64 * 1024, Iterations: 3, Parallelism: 4}))
+}
+
+func benchmarkHasher(b *testing.B, name string, hasher PasswordHasher) {
+	const password = "123qweASD-1d"
+
+	b.Run(name+"/hash", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := hasher.Hash(password); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	encoded, err := hasher.Hash(password)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run(name+"/verify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := hasher.Verify(encoded, password); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/google/uuid"
 	"pvz-service/internal/model"
@@ -13,12 +12,19 @@ const (
 	ProductNotFound         = "products do not exist"
 	FailedProductDelete     = "failed to delete product"
 	FailedProductCreate     = "failed to create product"
+	DuplicateBarcode        = "barcode has already been scanned into this reception"
+	FailedProductSearch     = "failed to find product"
+	StorageCellNotFound     = "storage cell not found in this pvz"
+	StorageCellFull         = "storage cell is full"
+	StorageCellTooSmall     = "parcel does not fit into the storage cell"
 )
 
 type ProductRepository interface {
-	CreateProduct(ctx context.Context, typeProduct string, recepID uuid.UUID) (uuid.UUID, error)
+	CreateProduct(ctx context.Context, product model.Product) (uuid.UUID, error)
+	InsertProduct(ctx context.Context, product model.Product) error
 	GetProductByID(ctx context.Context, id uuid.UUID) (*model.Product, error)
 	GetLastProduct(ctx context.Context, receptionID uuid.UUID) (*model.Product, error)
+	GetProductsByBarcode(ctx context.Context, barcode string) ([]model.Product, error)
 	DeleteProductByID(ctx context.Context, id uuid.UUID) error
 	GetProductSliceByReceptionID(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error)
 }
@@ -26,56 +32,179 @@ type ProductRepository interface {
 type ProductService struct {
 	productRepository   ProductRepository
 	receptionRepository ReceptionRepository
+	cellRepository      StorageCellRepository
+	pvzRepository       PvzRepository
+	// Events получает product.received
+	Events EventPublisher
 }
 
-func NewProductService(repoProduct ProductRepository, repoRepository ReceptionRepository) *ProductService {
+func NewProductService(repoProduct ProductRepository, repoRepository ReceptionRepository,
+	repoCell StorageCellRepository, repoPvz PvzRepository) *ProductService {
 	return &ProductService{
 		productRepository:   repoProduct,
 		receptionRepository: repoRepository,
+		cellRepository:      repoCell,
+		pvzRepository:       repoPvz,
+		Events:              nopPublisher{},
 	}
 }
 
 func (s *ProductService) AddProduct(ctx context.Context, product model.Product, pvz model.Pvz) (*model.Product, error) {
+	stored, err := s.pvzRepository.GetPvzByID(ctx, pvz.ID)
+	if err != nil {
+		return nil, pvzError(err)
+	}
+
+	if !stored.IsActive() {
+		return nil, NewConflictError(CodePvzNotActive, PvzNotActive)
+	}
+
 	reception, err := s.receptionRepository.GetLastReception(ctx, pvz.ID)
 	if err != nil {
-		return nil, fmt.Errorf(PvzOrReceptionsNotExist)
+		return nil, lastReceptionError(err)
 	}
 
 	if reception.IsClosed {
-		return nil, fmt.Errorf(ReceptionAlreadyClosed)
+		return nil, NewConflictError(CodeReceptionAlreadyClosed, ReceptionAlreadyClosed)
+	}
+
+	if err = s.checkBarcode(ctx, product.Barcode, reception.ID); err != nil {
+		return nil, err
+	}
+
+	product.CellID, err = s.placeProduct(ctx, product, pvz.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	idProduct, err := s.productRepository.CreateProduct(ctx, product.TypeProduct, reception.ID)
+	product.ReceptionID = reception.ID
+	idProduct, err := s.productRepository.CreateProduct(ctx, product)
 	if err != nil {
-		return nil, fmt.Errorf(FailedProductCreate)
+		return nil, NewInternalError(FailedProductCreate, err)
 	}
 
 	productAns, err := s.productRepository.GetProductByID(ctx, idProduct)
 	if err != nil {
-		return nil, fmt.Errorf(FailedProductCreate)
+		return nil, NewInternalError(FailedProductCreate, err)
 	}
 
+	s.Events.Publish(ctx, productEvent(*productAns, pvz.ID))
+
 	return productAns, nil
 }
 
 func (s *ProductService) DeleteProduct(ctx context.Context, pvz model.Pvz) error {
 	reception, err := s.receptionRepository.GetLastReception(ctx, pvz.ID)
 	if err != nil {
-		return fmt.Errorf(PvzOrReceptionsNotExist)
+		return lastReceptionError(err)
 	}
 
 	if reception.IsClosed {
-		return fmt.Errorf(ReceptionAlreadyClosed)
+		return NewConflictError(CodeReceptionAlreadyClosed, ReceptionAlreadyClosed)
 	}
 
 	product, err := s.productRepository.GetLastProduct(ctx, reception.ID)
 	if err != nil {
-		return fmt.Errorf(ProductNotFound)
+		return NewNotFoundError(CodeProductNotFound, ProductNotFound)
 	}
 
 	if err = s.productRepository.DeleteProductByID(ctx, product.ID); err != nil {
-		return fmt.Errorf("%s: %s", FailedProductDelete, err.Error())
+		return NewInternalError(FailedProductDelete, err)
+	}
+
+	return nil
+}
+
+// GetProductByBarcode возвращает последний принятый товар со штрихкодом
+func (s *ProductService) GetProductByBarcode(ctx context.Context, barcode string) (*model.Product, error) {
+	products, err := s.productRepository.GetProductsByBarcode(ctx, barcode)
+	if err != nil {
+		return nil, NewInternalError(FailedProductSearch, err)
+	}
+
+	if len(products) == 0 {
+		return nil, NewNotFoundError(CodeProductNotFound, ProductNotFound)
+	}
+
+	return &products[0], nil
+}
+
+// checkBarcode не дает отсканировать штрихкод в приемку повторно.
+// Уникальный индекс в базе тоже не даст дубль, здесь проверяем заранее ради понятной ошибки
+func (s *ProductService) checkBarcode(ctx context.Context, barcode string, receptionID uuid.UUID) error {
+	if barcode == "" {
+		return nil
+	}
+
+	scanned, err := s.productRepository.GetProductsByBarcode(ctx, barcode)
+	if err != nil {
+		return NewInternalError(FailedProductCreate, err)
+	}
+	for _, p := range scanned {
+		if p.ReceptionID == receptionID {
+			return NewConflictError(CodeDuplicateBarcode, DuplicateBarcode)
+		}
 	}
 
 	return nil
 }
+
+// placeProduct возвращает ячейку для товара. Ячейку, указанную сотрудником, проверяет
+// на размер и свободное место, иначе предлагает наименее заполненную подходящую.
+// Если подходящих ячеек нет, товар остается неразмещенным
+func (s *ProductService) placeProduct(ctx context.Context, product model.Product, pvzID uuid.UUID) (uuid.UUID, error) {
+	cells, err := s.cellRepository.GetStorageCells(ctx, pvzID)
+	if err != nil {
+		return uuid.Nil, NewInternalError(FailedProductCreate, err)
+	}
+
+	sizeClass := model.SizeClassFor(product.Dimensions)
+
+	if product.CellID == uuid.Nil {
+		return suggestCell(cells, sizeClass), nil
+	}
+
+	for _, cell := range cells {
+		if cell.ID != product.CellID {
+			continue
+		}
+		if !cell.Fits(sizeClass) {
+			return uuid.Nil, NewConflictError(CodeStorageCellTooSmall, StorageCellTooSmall)
+		}
+		if cell.Free() == 0 {
+			return uuid.Nil, NewConflictError(CodeStorageCellFull, StorageCellFull)
+		}
+		return cell.ID, nil
+	}
+
+	return uuid.Nil, NewNotFoundError(CodeStorageCellNotFound, StorageCellNotFound)
+}
+
+// suggestCell выбирает ячейку наименьшего подходящего размера, среди них - наименее заполненную
+func suggestCell(cells []model.StorageCell, sizeClass string) uuid.UUID {
+	var best *model.StorageCell
+
+	for i := range cells {
+		cell := &cells[i]
+		if !cell.Fits(sizeClass) || cell.Free() == 0 {
+			continue
+		}
+		if best == nil || lessLoaded(cell, best) {
+			best = cell
+		}
+	}
+
+	if best == nil {
+		return uuid.Nil
+	}
+
+	return best.ID
+}
+
+func lessLoaded(a, b *model.StorageCell) bool {
+	if rankA, rankB := model.SizeRank(a.SizeClass), model.SizeRank(b.SizeClass); rankA != rankB {
+		return rankA < rankB
+	}
+	// Сравнение долей occupied/capacity без деления
+	return a.Occupied*b.Capacity < b.Occupied*a.Capacity
+}
@@ -2,15 +2,39 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"pvz-service/internal/model"
 )
 
+const (
+	FailedPvzCreate = "failed to create pvz"
+	FailedPvzGet    = "failed to get pvz"
+	FailedPvzUpdate = "failed to update pvz"
+	FailedPvzNearby = "failed to find nearby pvz"
+	FailedPvzStatus = "failed to change pvz status"
+)
+
+const (
+	InvalidTimezone     = "unknown timezone"
+	InvalidCoordinates  = "latitude must be within [-90, 90] and longitude within [-180, 180]"
+	InvalidWorkingHours = "opening time must be before closing time, closing time at most 24:00, one exception per date"
+	InvalidDailyLimit   = "max daily receptions must not be negative"
+	InvalidPvzStatus    = "pvz status can't be changed this way"
+	PvzNotActive        = "pvz is suspended or decommissioned"
+)
+
 type PvzRepository interface {
 	CreatePvz(ctx context.Context, city string) (uuid.UUID, error)
 	GetPvzByID(ctx context.Context, id uuid.UUID) (*model.Pvz, error)
 	GetIDListPvz(ctx context.Context) ([]uuid.UUID, error)
+	UpdatePvzProfile(ctx context.Context, pvz model.Pvz) error
+	GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error)
+	UpdatePvzStatus(ctx context.Context, id uuid.UUID, from, to string) error
+	GetPvzChangeStamp(ctx context.Context, id uuid.UUID) (model.ChangeStamp, error)
+	GetPvzListChangeStamp(ctx context.Context) (model.ChangeStamp, error)
 }
 
 type PvzService struct {
@@ -24,13 +48,132 @@ func NewPvzService(repo PvzRepository) *PvzService {
 func (s *PvzService) AddNewPvz(ctx context.Context, pvzModel model.Pvz) (*model.Pvz, error) {
 	idPvz, err := s.pvzRepository.CreatePvz(ctx, pvzModel.City)
 	if err != nil {
-		return nil, err
+		return nil, NewInternalError(FailedPvzCreate, err)
 	}
 
 	pvz, err := s.pvzRepository.GetPvzByID(ctx, idPvz)
 	if err != nil {
-		return nil, err
+		return nil, NewInternalError(FailedPvzCreate, err)
+	}
+
+	return pvz, nil
+}
+
+// GetPvz возвращает профиль ПВЗ
+func (s *PvzService) GetPvz(ctx context.Context, pvzModel model.Pvz) (*model.Pvz, error) {
+	pvz, err := s.pvzRepository.GetPvzByID(ctx, pvzModel.ID)
+	if err != nil {
+		return nil, pvzError(err)
 	}
 
 	return pvz, nil
 }
+
+// GetPvzChangeStamp возвращает отметку последнего изменения ПВЗ, его приемок, товаров, выдач и ячеек
+func (s *PvzService) GetPvzChangeStamp(ctx context.Context, pvzModel model.Pvz) (model.ChangeStamp, error) {
+	stamp, err := s.pvzRepository.GetPvzChangeStamp(ctx, pvzModel.ID)
+	if err != nil {
+		return model.ChangeStamp{}, pvzError(err)
+	}
+
+	return stamp, nil
+}
+
+// UpdatePvz меняет заданные в patch поля профиля ПВЗ
+func (s *PvzService) UpdatePvz(ctx context.Context, patch model.PvzPatch) (*model.Pvz, error) {
+	pvz, err := s.GetPvz(ctx, model.Pvz{ID: patch.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	pvz.Apply(patch)
+	if err = validateProfile(pvz); err != nil {
+		return nil, err
+	}
+
+	if err = s.pvzRepository.UpdatePvzProfile(ctx, *pvz); err != nil {
+		return nil, NewInternalError(FailedPvzUpdate, err)
+	}
+
+	return s.GetPvz(ctx, *pvz)
+}
+
+// ChangePvzStatus приостанавливает, возобновляет или выводит ПВЗ из эксплуатации.
+// Данные ПВЗ при этом не удаляются
+func (s *PvzService) ChangePvzStatus(ctx context.Context, pvzModel model.Pvz, status string) (*model.Pvz, error) {
+	pvz, err := s.GetPvz(ctx, pvzModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pvz.CanChangeStatus(status) {
+		return nil, NewConflictError(CodeInvalidPvzStatus, InvalidPvzStatus)
+	}
+
+	if err = s.pvzRepository.UpdatePvzStatus(ctx, pvz.ID, pvz.Status, status); err != nil {
+		return nil, NewInternalError(FailedPvzStatus, err)
+	}
+
+	return s.GetPvz(ctx, *pvz)
+}
+
+// GetNearbyPvz возвращает ближайшие к точке ПВЗ и работают ли они сейчас по графику
+func (s *PvzService) GetNearbyPvz(ctx context.Context, nearby model.NearbyQuery) ([]model.NearbyPvz, error) {
+	result, err := s.pvzRepository.GetNearbyPvz(ctx, nearby)
+	if err != nil {
+		return nil, NewInternalError(FailedPvzNearby, err)
+	}
+
+	now := time.Now()
+	for i := range result {
+		result[i].IsOpen, err = result[i].Pvz.IsOpen(now)
+		if err != nil {
+			return nil, NewInternalError(FailedPvzNearby, err)
+		}
+	}
+
+	return result, nil
+}
+
+func validateProfile(pvz *model.Pvz) error {
+	if _, err := pvz.Location(); err != nil {
+		return NewValidationError(CodeInvalidPvzProfile, InvalidTimezone)
+	}
+
+	if c := pvz.Coordinates; c != nil && (c.Lat < -90 || c.Lat > 90 || c.Lng < -180 || c.Lng > 180) {
+		return NewValidationError(CodeInvalidPvzProfile, InvalidCoordinates)
+	}
+
+	if pvz.MaxDailyReceptions < 0 {
+		return NewValidationError(CodeInvalidPvzProfile, InvalidDailyLimit)
+	}
+
+	for _, hours := range pvz.WorkingHours.Weekly {
+		if !hours.Valid() {
+			return NewValidationError(CodeInvalidPvzProfile, InvalidWorkingHours)
+		}
+	}
+
+	dates := make(map[string]struct{}, len(pvz.WorkingHours.Exceptions))
+	for _, exception := range pvz.WorkingHours.Exceptions {
+		if !exception.Closed && !exception.Hours.Valid() {
+			return NewValidationError(CodeInvalidPvzProfile, InvalidWorkingHours)
+		}
+
+		date := exception.Date.Format("2006-01-02")
+		if _, ok := dates[date]; ok {
+			return NewValidationError(CodeInvalidPvzProfile, InvalidWorkingHours)
+		}
+		dates[date] = struct{}{}
+	}
+
+	return nil
+}
+
+// pvzError переводит ошибку чтения ПВЗ: ПВЗ нет - 404, сбой хранилища - 500
+func pvzError(err error) error {
+	if errors.Is(err, model.ErrNotFound) {
+		return NewNotFoundError(CodePvzNotFound, PvzNotFound)
+	}
+	return NewInternalError(FailedPvzGet, err)
+}
@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,61 +12,141 @@ import (
 const ReceptionWasNotClosed = "in this PVZ, the reception  has not been closed yet."
 const ReceptionAlreadyClosed = "reception  has  been already closed in this pvz."
 
+const (
+	PvzClosed             = "pvz is closed at this time"
+	ReceptionLimitReached = "daily reception limit of this pvz has been reached"
+	FailedReceptionCreate = "failed to create reception"
+	FailedReceptionClose  = "failed to close reception"
+	FailedReceptionGet    = "failed to get last reception"
+)
+
 type ReceptionRepository interface {
 	CreateReception(ctx context.Context, pvzID uuid.UUID) (uuid.UUID, error)
+	InsertReception(ctx context.Context, reception model.Reception) error
 	GetReceptionByID(ctx context.Context, id uuid.UUID) (*model.Reception, error)
 	GetLastReception(ctx context.Context, pvzID uuid.UUID) (*model.Reception, error)
-	CloseReception(ctx context.Context, receptionID uuid.UUID) error
+	CloseReception(ctx context.Context, receptionID uuid.UUID, reason string) error
+	FlagReception(ctx context.Context, receptionID uuid.UUID) error
+	MarkReceptionReminded(ctx context.Context, receptionID uuid.UUID) error
+	GetOpenReceptionsBefore(ctx context.Context, before time.Time) ([]model.Reception, error)
 	GetReceptionsSliceWithTimeRange(ctx context.Context, begin time.Time, end time.Time) ([]model.Reception, error)
+	CountReceptions(ctx context.Context, pvzID uuid.UUID, begin time.Time, end time.Time) (int, error)
 }
 
 type ReceptionService struct {
 	receptionRepository ReceptionRepository
+	pvzRepository       PvzRepository
+	// Events получает reception.created и reception.closed
+	Events EventPublisher
 }
 
-func NewReceptionService(repo ReceptionRepository) *ReceptionService {
+func NewReceptionService(repo ReceptionRepository, pvzRepo PvzRepository) *ReceptionService {
 	return &ReceptionService{
 		receptionRepository: repo,
+		pvzRepository:       pvzRepo,
+		Events:              nopPublisher{},
 	}
 }
 
 func (s *ReceptionService) CreateReception(ctx context.Context, receptionModel model.Reception) (*model.Reception, error) {
+	pvz, err := s.pvzRepository.GetPvzByID(ctx, receptionModel.PvzID)
+	if err != nil {
+		return nil, pvzError(err)
+	}
+
+	if !pvz.IsActive() {
+		return nil, NewConflictError(CodePvzNotActive, PvzNotActive)
+	}
+
 	// Проверяем наличие последней приемки в данном ПВЗ и смотрим, был ли он закрыт
 	reception, err := s.receptionRepository.GetLastReception(ctx, receptionModel.PvzID)
+	switch {
+	case err == nil && !reception.IsClosed:
+		return nil, NewConflictError(CodeReceptionNotClosed, ReceptionWasNotClosed)
+	case err != nil && !errors.Is(err, model.ErrNotFound):
+		return nil, NewInternalError(FailedReceptionGet, err)
+	}
 
-	if err == nil && !reception.IsClosed {
-		return nil, fmt.Errorf(ReceptionWasNotClosed)
+	if err = s.checkSchedule(ctx, pvz, time.Now()); err != nil {
+		return nil, err
 	}
 
-	// Если ПВЗ с таким ID нет, то Constraint вернет ошибку и приемка не будет создана
 	id, err := s.receptionRepository.CreateReception(ctx, receptionModel.PvzID)
 	if err != nil {
-		return nil, err
+		return nil, NewInternalError(FailedReceptionCreate, err)
 	}
 
 	rep, err := s.receptionRepository.GetReceptionByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, NewInternalError(FailedReceptionCreate, err)
 	}
 
+	s.Events.Publish(ctx, receptionEvent(model.WebhookEventReceptionCreated, *rep))
+
 	return rep, nil
 }
 
 func (s *ReceptionService) CloseReception(ctx context.Context, receptionModel model.Reception) (*model.Reception, error) {
 	reception, err := s.receptionRepository.GetLastReception(ctx, receptionModel.PvzID)
 	if err != nil {
-		return nil, fmt.Errorf(PvzOrReceptionsNotExist)
+		return nil, lastReceptionError(err)
 	}
 
 	if reception.IsClosed {
-		return nil, fmt.Errorf(ReceptionAlreadyClosed)
+		return nil, NewConflictError(CodeReceptionAlreadyClosed, ReceptionAlreadyClosed)
 	}
 
-	if err = s.receptionRepository.CloseReception(ctx, reception.ID); err != nil {
-		return nil, err
+	if err = s.receptionRepository.CloseReception(ctx, reception.ID, model.CloseReasonManual); err != nil {
+		return nil, NewInternalError(FailedReceptionClose, err)
 	}
 
 	reception.IsClosed = true
+	reception.CloseReason = model.CloseReasonManual
+
+	s.Events.Publish(ctx, receptionEvent(model.WebhookEventReceptionClosed, *reception))
 
 	return reception, nil
 }
+
+// checkSchedule проверяет график работы и дневной лимит приемок ПВЗ.
+// Пока действует разрешение модератора (ReceptionOverrideUntil), проверки пропускаются
+func (s *ReceptionService) checkSchedule(ctx context.Context, pvz *model.Pvz, now time.Time) error {
+	if now.Before(pvz.ReceptionOverrideUntil) {
+		return nil
+	}
+
+	open, err := pvz.IsOpen(now)
+	if err != nil {
+		return NewInternalError(FailedReceptionCreate, err)
+	}
+	if !open {
+		return NewConflictError(CodePvzClosed, PvzClosed)
+	}
+
+	if pvz.MaxDailyReceptions == 0 {
+		return nil
+	}
+
+	begin, end, err := pvz.Day(now)
+	if err != nil {
+		return NewInternalError(FailedReceptionCreate, err)
+	}
+
+	count, err := s.receptionRepository.CountReceptions(ctx, pvz.ID, begin.UTC(), end.UTC())
+	if err != nil {
+		return NewInternalError(FailedReceptionCreate, err)
+	}
+	if count >= pvz.MaxDailyReceptions {
+		return NewConflictError(CodeReceptionLimitReached, ReceptionLimitReached)
+	}
+
+	return nil
+}
+
+// lastReceptionError переводит ошибку GetLastReception: приемок нет - 404, сбой хранилища - 500
+func lastReceptionError(err error) error {
+	if errors.Is(err, model.ErrNotFound) {
+		return NewNotFoundError(CodeReceptionNotFound, PvzOrReceptionsNotExist)
+	}
+	return NewInternalError(FailedReceptionGet, err)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pvz-service/internal/model"
+)
+
+// Что делать с приемкой, открытой дольше порога
+const (
+	ReceptionTimeoutClose = "close"
+	ReceptionTimeoutFlag  = "flag"
+)
+
+const FailedReceptionSweep = "failed to process stale receptions"
+
+// ReceptionTimeoutConfig - пороги для зависших приемок, перечитываются на каждом проходе.
+// Нулевой порог выключает соответствующее действие
+type ReceptionTimeoutConfig interface {
+	GetReceptionTimeout() (maxOpen time.Duration, remindAfter time.Duration, action string)
+}
+
+// ReceptionNotifier доставляет уведомления о зависших приемках
+type ReceptionNotifier interface {
+	NotifyReception(ctx context.Context, event model.ReceptionEvent) error
+}
+
+// SweepResult - итоги одного прохода по открытым приемкам
+type SweepResult struct {
+	Reminded int
+	Flagged  int
+	Closed   int
+}
+
+type ReceptionTimeoutService struct {
+	receptionRepository ReceptionRepository
+	config              ReceptionTimeoutConfig
+	notifier            ReceptionNotifier
+	// Events получает reception.closed для приемок, закрытых по таймауту
+	Events EventPublisher
+}
+
+func NewReceptionTimeoutService(repo ReceptionRepository, cfg ReceptionTimeoutConfig, notifier ReceptionNotifier) *ReceptionTimeoutService {
+	return &ReceptionTimeoutService{
+		receptionRepository: repo,
+		config:              cfg,
+		notifier:            notifier,
+		Events:              nopPublisher{},
+	}
+}
+
+// SweepReceptions закрывает или помечает приемки, открытые дольше maxOpen,
+// и один раз напоминает о приемках, открытых дольше remindAfter.
+// Отметка в базе ставится до уведомления, поэтому при ошибке доставки повтора не будет.
+// Ошибка по одной приемке не останавливает проход, все ошибки возвращаются вместе
+func (s *ReceptionTimeoutService) SweepReceptions(ctx context.Context) (SweepResult, error) {
+	var result SweepResult
+
+	maxOpen, remindAfter, action := s.config.GetReceptionTimeout()
+
+	threshold := maxOpen
+	if remindAfter > 0 && (threshold == 0 || remindAfter < threshold) {
+		threshold = remindAfter
+	}
+	if threshold == 0 {
+		return result, nil
+	}
+
+	now := time.Now()
+
+	receptions, err := s.receptionRepository.GetOpenReceptionsBefore(ctx, now.Add(-threshold))
+	if err != nil {
+		return result, NewInternalError(FailedReceptionSweep, err)
+	}
+
+	var errs []error
+	for _, reception := range receptions {
+		openFor := now.Sub(reception.DateTime)
+
+		var (
+			event   string
+			counter *int
+		)
+
+		switch {
+		case maxOpen > 0 && openFor >= maxOpen && action == ReceptionTimeoutClose:
+			err = s.receptionRepository.CloseReception(ctx, reception.ID, model.CloseReasonTimeout)
+			reception.IsClosed, reception.CloseReason = true, model.CloseReasonTimeout
+			event, counter = model.ReceptionEventAutoClosed, &result.Closed
+		case maxOpen > 0 && openFor >= maxOpen:
+			if !reception.FlaggedAt.IsZero() {
+				continue
+			}
+			err = s.receptionRepository.FlagReception(ctx, reception.ID)
+			event, counter = model.ReceptionEventFlagged, &result.Flagged
+		case remindAfter > 0 && openFor >= remindAfter:
+			if !reception.RemindedAt.IsZero() {
+				continue
+			}
+			err = s.receptionRepository.MarkReceptionReminded(ctx, reception.ID)
+			event, counter = model.ReceptionEventReminder, &result.Reminded
+		default:
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		*counter++
+
+		if event == model.ReceptionEventAutoClosed {
+			s.Events.Publish(ctx, receptionEvent(model.WebhookEventReceptionClosed, reception))
+		}
+
+		if err = s.notifier.NotifyReception(ctx, model.ReceptionEvent{
+			Type:      event,
+			Reception: reception,
+			OpenFor:   openFor,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return result, NewInternalError(FailedReceptionSweep, errors.Join(errs...))
+	}
+
+	return result, nil
+}
@@ -1,10 +1,17 @@
 package service
 
 type Repository interface {
+	Transactor
 	UserRepository
+	AuthTokenRepository
+	TenantRepository
 	PvzRepository
 	ReceptionRepository
 	ProductRepository
+	SyncRepository
+	IssuanceRepository
+	StorageCellRepository
+	WebhookRepository
 }
 
 type Service struct {
@@ -13,14 +20,36 @@ type Service struct {
 	*ReceptionService
 	*ProductService
 	*InfoService
+	*SyncService
+	*IssuanceService
+	*StorageCellService
+	*WebhookService
+	*TenantService
 }
 
-func NewService(repo Repository, jwtSecret string) *Service {
-	return &Service{
-		AuthService:      NewAuthService(repo, jwtSecret),
-		PvzService:       NewPvzService(repo),
-		ReceptionService: NewReceptionService(repo),
-		ProductService:   NewProductService(repo, repo),
-		InfoService:      NewInfoService(repo, repo, repo),
+// NewService собирает сервисы и подключает к ним публикацию событий для вебхуков
+func NewService(repo Repository, jwtSecret string, webhookCfg WebhookConfig) *Service {
+	s := &Service{
+		AuthService:        NewAuthService(repo, jwtSecret),
+		PvzService:         NewPvzService(repo),
+		ReceptionService:   NewReceptionService(repo, repo),
+		ProductService:     NewProductService(repo, repo, repo, repo),
+		InfoService:        NewInfoService(repo, repo, repo),
+		SyncService:        NewSyncService(repo, repo, repo, repo, repo),
+		IssuanceService:    NewIssuanceService(repo, repo, repo, repo),
+		StorageCellService: NewStorageCellService(repo, repo),
+		WebhookService:     NewWebhookService(repo, repo, webhookCfg),
+		TenantService:      NewTenantService(repo),
 	}
+
+	s.AuthService.Tenants = repo
+	s.AuthService.Tokens = repo
+	s.AuthService.Tx = repo
+	s.ReceptionService.Events = s.WebhookService
+	s.ProductService.Events = s.WebhookService
+	s.SyncService.Events = s.WebhookService
+	s.SyncService.Tx = repo
+	s.IssuanceService.Tx = repo
+
+	return s
 }
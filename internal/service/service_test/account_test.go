@@ -0,0 +1,337 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+)
+
+type testAccountConfig struct {
+	verificationTTL time.Duration
+	resetTTL        time.Duration
+	requireVerified bool
+}
+
+func (c testAccountConfig) GetVerificationTTL() time.Duration { return c.verificationTTL }
+func (c testAccountConfig) GetResetTTL() time.Duration        { return c.resetTTL }
+func (c testAccountConfig) GetLinkBaseURL() string            { return "https://pvz.example/" }
+func (c testAccountConfig) RequireVerifiedEmail() bool        { return c.requireVerified }
+
+// outbox запоминает письма вместо отправки
+type outbox struct {
+	mu    sync.Mutex
+	mails []model.Mail
+}
+
+func (o *outbox) Send(_ context.Context, mail model.Mail) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.mails = append(o.mails, mail)
+	return nil
+}
+
+var linkToken = regexp.MustCompile(`https://pvz\.example(/[a-z-]+)\?token=([A-Za-z0-9_-]+)`)
+
+// lastToken возвращает токен из последнего письма и проверяет, куда ведет ссылка
+func (o *outbox) lastToken(t *testing.T, to, path string) string {
+	t.Helper()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	require.NotEmpty(t, o.mails)
+
+	mail := o.mails[len(o.mails)-1]
+	assert.Equal(t, to, mail.To)
+	match := linkToken.FindStringSubmatch(mail.Body)
+	require.NotNil(t, match, mail.Body)
+	assert.Equal(t, path, match[1])
+	return match[2]
+}
+
+func (o *outbox) count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.mails)
+}
+
+func newAccountService(cfg testAccountConfig) (*service.AuthService, *outbox) {
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	auth := service.NewService(repo, "jwt-secret", defaultWebhookConfig).AuthService
+
+	mails := &outbox{}
+	auth.Mailer = mails
+	auth.Accounts = cfg
+	return auth, mails
+}
+
+func serviceCode(t *testing.T, err error) string {
+	t.Helper()
+
+	var serviceErr *service.Error
+	require.ErrorAs(t, err, &serviceErr)
+	return serviceErr.Code
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	auth, mails := newAccountService(testAccountConfig{verificationTTL: time.Hour, resetTTL: time.Hour, requireVerified: true})
+
+	user := model.User{Email: "new@example.com", Password: "Sklad-2026", Role: service.EmployeeRole}
+	_, err := auth.Registration(ctx, user)
+	require.NoError(t, err)
+
+	// Пока почта не подтверждена, вход закрыт
+	_, err = auth.Authenticate(ctx, user)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	assert.Equal(t, service.CodeEmailNotVerified, serviceCode(t, err))
+
+	first := mails.lastToken(t, user.Email, service.VerifyEmailPath)
+
+	// Повторное письмо приходит с новой ссылкой, старая тоже действует до срока
+	resendVerification(t, auth, user.Email)
+	second := mails.lastToken(t, user.Email, service.VerifyEmailPath)
+	assert.NotEqual(t, first, second)
+
+	err = auth.VerifyEmail(ctx, "not-a-token")
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+
+	require.NoError(t, auth.VerifyEmail(ctx, second))
+	err = auth.VerifyEmail(ctx, second)
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+
+	_, err = auth.Authenticate(ctx, user)
+	require.NoError(t, err)
+
+	// Подтвержденной и неизвестной почте письма не уходят, но ответ тот же
+	sent := mails.count()
+	resendVerification(t, auth, user.Email)
+	resendVerification(t, auth, "missing@example.com")
+	assert.Equal(t, sent, mails.count())
+}
+
+// resendVerification запрашивает письмо с подтверждением и дожидается его отправки в фоне
+func resendVerification(t *testing.T, auth *service.AuthService, email string) {
+	t.Helper()
+
+	require.NoError(t, auth.ResendVerification(context.Background(), email))
+	require.NoError(t, auth.WaitMails(context.Background()))
+}
+
+// Токен гасится в той же транзакции, что и подтверждение почты. Сбой хранилища при погашении
+// токена - внутренняя ошибка, а не невалидная ссылка
+func TestAuthService_VerifyEmailTransaction(t *testing.T) {
+	userID := uuid.New()
+	inTx := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(txMarker{}) == 1 })
+
+	users := mocks.NewUserRepository(t)
+	tokens := mocks.NewAuthTokenRepository(t)
+	auth := service.NewAuthService(users, "jwt-secret")
+	auth.Tokens = tokens
+	tx := &markingTx{}
+	auth.Tx = tx
+
+	tokens.On("UseAuthToken", inTx, model.AuthTokenVerifyEmail, mock.Anything, mock.Anything).
+		Return(nil, errors.New("connection refused")).Once()
+	err := auth.VerifyEmail(context.Background(), "token")
+	assert.ErrorIs(t, err, service.ErrInternal)
+
+	tokens.On("UseAuthToken", inTx, model.AuthTokenVerifyEmail, mock.Anything, mock.Anything).
+		Return(&model.AuthToken{UserID: userID, Purpose: model.AuthTokenVerifyEmail}, nil).Once()
+	users.On("MarkEmailVerified", inTx, userID, mock.Anything).Return(errors.New("connection refused")).Once()
+	tx.calls = 0
+	err = auth.VerifyEmail(context.Background(), "token")
+	assert.ErrorIs(t, err, service.ErrInternal)
+	assert.Equal(t, 1, tx.calls)
+
+	tokens.On("UseAuthToken", inTx, model.AuthTokenVerifyEmail, mock.Anything, mock.Anything).
+		Return(nil, model.ErrNotFound).Once()
+	tx.calls = 0
+	err = auth.VerifyEmail(context.Background(), "token")
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+}
+
+// forgotPassword запрашивает сброс и дожидается письма, которое уходит в фоне
+func forgotPassword(t *testing.T, auth *service.AuthService, email string) {
+	t.Helper()
+
+	require.NoError(t, auth.ForgotPassword(context.Background(), email))
+	require.NoError(t, auth.WaitMails(context.Background()))
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctx := context.Background()
+	auth, mails := newAccountService(testAccountConfig{verificationTTL: time.Hour, resetTTL: time.Hour})
+
+	user := model.User{Email: "reset@example.com", Password: "Sklad-2026", Role: service.EmployeeRole}
+	_, err := auth.Registration(ctx, user)
+	require.NoError(t, err)
+
+	sent := mails.count()
+	forgotPassword(t, auth, "missing@example.com")
+	assert.Equal(t, sent, mails.count())
+
+	forgotPassword(t, auth, user.Email)
+	first := mails.lastToken(t, user.Email, service.ResetPasswordPath)
+	forgotPassword(t, auth, user.Email)
+	second := mails.lastToken(t, user.Email, service.ResetPasswordPath)
+
+	// Слабый пароль не гасит токен
+	err = auth.ResetPassword(ctx, first, "short")
+	assert.Equal(t, service.CodeWeakPassword, serviceCode(t, err))
+
+	require.NoError(t, auth.ResetPassword(ctx, first, "Novyi-Parol-1"))
+
+	_, err = auth.Authenticate(ctx, user)
+	assert.ErrorIs(t, err, service.ErrUnauthorized)
+	_, err = auth.Authenticate(ctx, model.User{Email: user.Email, Password: "Novyi-Parol-1"})
+	require.NoError(t, err)
+
+	// Токен одноразовый, а остальные ссылки на сброс гасятся вместе с ним
+	err = auth.ResetPassword(ctx, first, "Esche-Parol-2")
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+	err = auth.ResetPassword(ctx, second, "Esche-Parol-2")
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+
+	// Токен сброса не подтверждает почту через VerifyEmail
+	err = auth.VerifyEmail(ctx, first)
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+}
+
+func TestAuthService_ResetPasswordExpired(t *testing.T) {
+	ctx := context.Background()
+	auth, mails := newAccountService(testAccountConfig{verificationTTL: time.Hour, resetTTL: -time.Minute})
+
+	_, err := auth.Registration(ctx, model.User{Email: "late@example.com", Password: "Sklad-2026", Role: service.EmployeeRole})
+	require.NoError(t, err)
+
+	forgotPassword(t, auth, "late@example.com")
+	err = auth.ResetPassword(ctx, mails.lastToken(t, "late@example.com", service.ResetPasswordPath), "Novyi-Parol-1")
+	assert.Equal(t, service.CodeInvalidToken, serviceCode(t, err))
+}
+
+func TestAuthService_RegistrationValidation(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newAccountService(testAccountConfig{verificationTTL: time.Hour, resetTTL: time.Hour})
+
+	tests := []struct {
+		name  string
+		email string
+		pass  string
+		code  string
+	}{
+		{"почта без домена", "user@", "Sklad-2026", service.CodeInvalidEmail},
+		{"почта с именем", "Иван <user@example.com>", "Sklad-2026", service.CodeInvalidEmail},
+		{"домен без зоны", "user@localhost", "Sklad-2026", service.CodeInvalidEmail},
+		{"слабый пароль", "user@example.com", "12345678", service.CodeWeakPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.Registration(ctx, model.User{Email: tt.email, Password: tt.pass, Role: service.EmployeeRole})
+			assert.ErrorIs(t, err, service.ErrValidation)
+			assert.Equal(t, tt.code, serviceCode(t, err))
+		})
+	}
+}
+
+// Письмо не ушло - пользователь все равно создан, ссылку можно запросить повторно
+func TestAuthService_RegistrationMailFailure(t *testing.T) {
+	ctx := context.Background()
+	auth, _ := newAccountService(testAccountConfig{verificationTTL: time.Hour, resetTTL: time.Hour})
+
+	mailer := mocks.NewMailer(t)
+	mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp is down"))
+	auth.Mailer = mailer
+
+	user, err := auth.Registration(ctx, model.User{Email: "down@example.com", Password: "Sklad-2026", Role: service.EmployeeRole})
+	require.NoError(t, err)
+	assert.Equal(t, "down@example.com", user.Email)
+
+	// Ответ на повторный запрос не выдает, что письмо снова не ушло
+	assert.NoError(t, auth.ResendVerification(ctx, "down@example.com"))
+	require.NoError(t, auth.WaitMails(ctx))
+}
+
+// Ответ на запрос сброса не зависит от того, есть ли пользователь и ушло ли письмо
+func TestAuthService_ForgotPasswordFailures(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("письмо не ушло", func(t *testing.T) {
+		auth, _ := newAccountService(testAccountConfig{verificationTTL: time.Hour, resetTTL: time.Hour})
+		_, err := auth.Registration(ctx, model.User{Email: "down@example.com", Password: "Sklad-2026", Role: service.EmployeeRole})
+		require.NoError(t, err)
+
+		mailer := mocks.NewMailer(t)
+		mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp is down")).Once()
+		auth.Mailer = mailer
+
+		assert.NoError(t, auth.ForgotPassword(ctx, "down@example.com"))
+		require.NoError(t, auth.WaitMails(ctx))
+	})
+
+	t.Run("ошибка хранилища", func(t *testing.T) {
+		users := mocks.NewUserRepository(t)
+		users.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, errors.New("connection refused"))
+
+		auth := service.NewAuthService(users, "jwt-secret")
+		auth.Tokens = mocks.NewAuthTokenRepository(t)
+		auth.Mailer = mocks.NewMailer(t)
+		auth.Accounts = testAccountConfig{verificationTTL: time.Hour, resetTTL: time.Hour}
+
+		assert.NoError(t, auth.ForgotPassword(ctx, "user@example.com"))
+		require.NoError(t, auth.WaitMails(ctx))
+	})
+}
+
+// Токен гасится в той же транзакции, что и смена пароля: при ошибке транзакция откатывается
+// и ссылка остается рабочей
+func TestAuthService_ResetPasswordTransaction(t *testing.T) {
+	userID := uuid.New()
+	inTx := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(txMarker{}) == 1 })
+
+	users := mocks.NewUserRepository(t)
+	tokens := mocks.NewAuthTokenRepository(t)
+	tokens.On("UseAuthToken", inTx, model.AuthTokenResetPassword, mock.Anything, mock.Anything).
+		Return(&model.AuthToken{UserID: userID, Purpose: model.AuthTokenResetPassword}, nil).Once()
+	users.On("UpdatePassword", inTx, userID, mock.Anything).Return(errors.New("connection refused")).Once()
+
+	auth := service.NewAuthService(users, "jwt-secret")
+	auth.Tokens = tokens
+	tx := &markingTx{}
+	auth.Tx = tx
+
+	err := auth.ResetPassword(context.Background(), "token", "Novyi-Parol-1")
+	assert.ErrorIs(t, err, service.ErrInternal)
+	assert.Equal(t, 1, tx.calls)
+
+	tokens.On("UseAuthToken", inTx, model.AuthTokenResetPassword, mock.Anything, mock.Anything).
+		Return(&model.AuthToken{UserID: userID, Purpose: model.AuthTokenResetPassword}, nil).Once()
+	users.On("UpdatePassword", inTx, userID, mock.Anything).Return(nil).Once()
+	tokens.On("UseUserAuthTokens", inTx, userID, model.AuthTokenResetPassword, mock.Anything).Return(nil).Once()
+	users.On("MarkEmailVerified", inTx, userID, mock.Anything).Return(nil).Once()
+
+	tx.calls = 0
+	require.NoError(t, auth.ResetPassword(context.Background(), "token", "Novyi-Parol-1"))
+	assert.Equal(t, 1, tx.calls)
+}
+
+func TestAuthService_MailNotConfigured(t *testing.T) {
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	auth := service.NewService(repo, "jwt-secret", defaultWebhookConfig).AuthService
+
+	assert.ErrorIs(t, auth.ForgotPassword(context.Background(), "user@example.com"), service.ErrInternal)
+	assert.ErrorIs(t, auth.ResendVerification(context.Background(), "user@example.com"), service.ErrInternal)
+}
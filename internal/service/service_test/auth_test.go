@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,12 +15,14 @@ import (
 	"pvz-service/internal/model"
 	"pvz-service/internal/service"
 	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/service/pkg/hash"
+	"pvz-service/internal/tenant"
 )
 
 func TestAuthService_Registration(t *testing.T) {
 	ctx := context.Background()
 
-	password := "securepassword"
+	password := "secure-password"
 	//hashedPassword, _ := pkg.HashPassword(password) // желательно использовать стабильную фиктивную реализацию
 
 	testUser := model.User{
@@ -133,7 +136,8 @@ func TestAuthService_Authenticate(t *testing.T) {
 				user: model.User{Email: "notfound@example.com", Password: "somepass"},
 			},
 			mockSetup: func(repo *mocks.UserRepository) {
-				repo.On("GetUserByEmail", mock.Anything, "notfound@example.com").Return(nil, errors.New("not found"))
+				repo.On("GetUserByEmail", mock.Anything, "notfound@example.com").
+					Return(nil, fmt.Errorf("user not found: %w", model.ErrNotFound))
 			},
 			fields: fields{
 				userRepo: &mocks.UserRepository{},
@@ -142,7 +146,24 @@ func TestAuthService_Authenticate(t *testing.T) {
 				},
 			},
 			wantToken: "",
-			wantErr:   "user not found",
+			wantErr:   service.InvalidCredentials,
+		},
+		{
+			name: "storage failure",
+			args: args{
+				user: model.User{Email: "test@example.com", Password: "somepass"},
+			},
+			mockSetup: func(repo *mocks.UserRepository) {
+				repo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, errors.New("connection refused"))
+			},
+			fields: fields{
+				userRepo: &mocks.UserRepository{},
+				generateJWT: func(userID, role string) (string, error) {
+					return "", nil
+				},
+			},
+			wantToken: "",
+			wantErr:   "failed to get user: connection refused",
 		},
 		{
 			name: "invalid password",
@@ -188,82 +209,68 @@ func TestAuthService_Authenticate(t *testing.T) {
 	}
 }
 func TestAuthService_DummyAuth(t *testing.T) {
-	hashedPassModerator, _ := bcrypt.GenerateFromPassword([]byte(service.ModeratorRole), bcrypt.DefaultCost)
-	hashedPassEmployee, _ := bcrypt.GenerateFromPassword([]byte(service.EmployeeRole), bcrypt.DefaultCost)
-
 	moderatorID := uuid.New()
 	employeeID := uuid.New()
 
 	tests := []struct {
 		name       string
 		role       string
-		email      string
+		markDummy  bool
 		setupMocks func(repo *mocks.UserRepository)
+		wantUserID uuid.UUID
 		wantErr    bool
 	}{
 		{
-			name:  "moderator login success",
-			role:  service.ModeratorRole,
-			email: service.ModeratorEmail,
+			name: "moderator login success",
+			role: service.ModeratorRole,
 			setupMocks: func(repo *mocks.UserRepository) {
+				// Пароль существующего пользователя не проверяется
 				repo.On("GetUserByEmail", mock.Anything, service.ModeratorEmail).
 					Return(&model.User{
 						ID:       moderatorID,
 						Email:    service.ModeratorEmail,
-						Password: string(hashedPassModerator),
+						Password: "random",
 						Role:     service.ModeratorRole,
 					}, nil).Once()
 			},
-			wantErr: false,
+			wantUserID: moderatorID,
 		},
 		{
-			name:  "employee login success",
-			role:  service.EmployeeRole,
-			email: service.EmployeeEmail,
+			name:      "employee login success in first time",
+			role:      service.EmployeeRole,
+			markDummy: true,
 			setupMocks: func(repo *mocks.UserRepository) {
+				// Пользователя еще нет: его ищет DummyAuth, а затем проверка при создании
 				repo.On("GetUserByEmail", mock.Anything, service.EmployeeEmail).
-					Return(&model.User{
-						ID:       employeeID,
-						Email:    service.EmployeeEmail,
-						Password: string(hashedPassEmployee),
-						Role:     service.EmployeeRole,
-					}, nil).Once()
-			},
-			wantErr: false,
-		},
-		{
-			name:  "employee login success in first time",
-			role:  service.EmployeeRole,
-			email: service.EmployeeEmail,
-			setupMocks: func(repo *mocks.UserRepository) {
-				// В первом вызове GetUserByEmail возвращаем ошибку "user not found"
-				repo.On("GetUserByEmail", mock.Anything, service.EmployeeEmail).
-					Return(nil, fmt.Errorf("user not found")).Once()
-
-				// Во втором вызове GetUserByEmail снова возвращаем ошибку, так как на момент второго вызова еще нет пользователя
-				repo.On("GetUserByEmail", mock.Anything, service.EmployeeEmail).
-					Return(nil, fmt.Errorf("user not found")).Once()
-
-				// Хэшируем пароль заранее и используем его в моке
+					Return(nil, fmt.Errorf("user not found")).Twice()
 
+				// Пароль случайный, а не имя роли
 				repo.On("CreateUser", mock.Anything, mock.MatchedBy(func(user *model.User) bool {
-					// Проверяем, что поля структуры соответствуют ожидаемым значениям
 					return user.Email == service.EmployeeEmail &&
 						user.Role == service.EmployeeRole &&
-						user.Password != "" // Игнорируем проверку на конкретный хэш пароля
+						!user.EmailVerifiedAt.IsZero() &&
+						bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(service.EmployeeRole)) != nil
 				})).
 					Return(employeeID, nil).Once()
-
-				// Теперь, когда пользователь создан, на последующих вызовах GetUserByEmail возвращаем созданного пользователя
+			},
+			wantUserID: employeeID,
+		},
+		{
+			name: "create user failed",
+			role: service.EmployeeRole,
+			setupMocks: func(repo *mocks.UserRepository) {
 				repo.On("GetUserByEmail", mock.Anything, service.EmployeeEmail).
-					Return(&model.User{
-						ID:       employeeID,
-						Email:    service.EmployeeEmail,
-						Password: string(hashedPassEmployee),
-						Role:     service.EmployeeRole,
-					}, nil).Once()
+					Return(nil, fmt.Errorf("user not found")).Twice()
+				repo.On("CreateUser", mock.Anything, mock.Anything).
+					Return(uuid.Nil, errors.New("db error")).Once()
 			},
-			wantErr: false,
+			wantErr: true,
+		},
+		{
+			name:       "invalid role",
+			role:       "admin",
+			setupMocks: func(repo *mocks.UserRepository) {},
+			wantErr:    true,
 		},
 	}
 
@@ -273,23 +280,215 @@ func TestAuthService_DummyAuth(t *testing.T) {
 			tt.setupMocks(mockRepo)
 
 			authService := service.NewAuthService(mockRepo, "test")
+			authService.MarkDummyTokens = tt.markDummy
 
-			userDummy := model.User{
-				Email:    tt.email,
-				Password: tt.role,
-				Role:     tt.role,
-			}
-
-			token, err := authService.DummyAuth(context.Background(), userDummy)
+			token, err := authService.DummyAuth(context.Background(), model.User{Role: tt.role})
 
 			if tt.wantErr {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
-				require.NotEmpty(t, token)
+
+				claims := jwt.MapClaims{}
+				_, err = jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+					return []byte("test"), nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantUserID.String(), claims[service.ClaimUserID])
+				assert.Equal(t, tt.role, claims[service.ClaimRole])
+				_, dummy := claims[service.ClaimDummy]
+				assert.Equal(t, tt.markDummy, dummy)
 			}
 
 			mockRepo.AssertExpectations(t)
 		})
 	}
 }
+
+// Оператор берется из контекста (X-Tenant-ID), а не из тела запроса,
+// и регистрироваться можно только у оператора с открытой регистрацией
+func TestAuthService_RegistrationTenant(t *testing.T) {
+	other := uuid.New()
+
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		bodyTenant     uuid.UUID
+		setupMocks     func(users *mocks.UserRepository, tenants *mocks.TenantRepository)
+		expectedTenant uuid.UUID
+		expectedKind   error
+		expectedCode   string
+	}{
+		{
+			name: "без оператора пользователь попадает к оператору по умолчанию",
+			ctx:  context.Background(),
+			setupMocks: func(users *mocks.UserRepository, tenants *mocks.TenantRepository) {
+				tenants.On("GetTenantByID", mock.Anything, tenant.DefaultID).
+					Return(&model.Tenant{ID: tenant.DefaultID, OpenRegistration: true}, nil)
+				users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, errors.New("not found"))
+				users.On("CreateUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.TenantID == tenant.DefaultID
+				})).Return(uuid.New(), nil)
+			},
+			expectedTenant: tenant.DefaultID,
+		},
+		{
+			name: "оператор из заголовка с открытой регистрацией",
+			ctx:  tenant.WithID(context.Background(), other),
+			setupMocks: func(users *mocks.UserRepository, tenants *mocks.TenantRepository) {
+				tenants.On("GetTenantByID", mock.Anything, other).Return(&model.Tenant{ID: other, OpenRegistration: true}, nil)
+				users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, errors.New("not found"))
+				users.On("CreateUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.TenantID == other
+				})).Return(uuid.New(), nil)
+			},
+			expectedTenant: other,
+		},
+		{
+			name:       "оператор из тела запроса не учитывается",
+			ctx:        context.Background(),
+			bodyTenant: other,
+			setupMocks: func(users *mocks.UserRepository, tenants *mocks.TenantRepository) {
+				tenants.On("GetTenantByID", mock.Anything, tenant.DefaultID).
+					Return(&model.Tenant{ID: tenant.DefaultID, OpenRegistration: true}, nil)
+				users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(nil, errors.New("not found"))
+				users.On("CreateUser", mock.Anything, mock.MatchedBy(func(u *model.User) bool {
+					return u.TenantID == tenant.DefaultID
+				})).Return(uuid.New(), nil)
+			},
+			expectedTenant: tenant.DefaultID,
+		},
+		{
+			name: "регистрация у оператора закрыта",
+			ctx:  tenant.WithID(context.Background(), other),
+			setupMocks: func(users *mocks.UserRepository, tenants *mocks.TenantRepository) {
+				tenants.On("GetTenantByID", mock.Anything, other).Return(&model.Tenant{ID: other}, nil)
+			},
+			expectedKind: service.ErrForbidden,
+			expectedCode: service.CodeRegistrationClosed,
+		},
+		{
+			name: "неизвестный оператор неотличим от закрытого",
+			ctx:  tenant.WithID(context.Background(), other),
+			setupMocks: func(users *mocks.UserRepository, tenants *mocks.TenantRepository) {
+				tenants.On("GetTenantByID", mock.Anything, other).Return(nil, fmt.Errorf("tenant not found: %w", model.ErrNotFound))
+			},
+			expectedKind: service.ErrForbidden,
+			expectedCode: service.CodeRegistrationClosed,
+		},
+		{
+			name: "ошибка хранилища",
+			ctx:  tenant.WithID(context.Background(), other),
+			setupMocks: func(users *mocks.UserRepository, tenants *mocks.TenantRepository) {
+				tenants.On("GetTenantByID", mock.Anything, other).Return(nil, errors.New("connection refused"))
+			},
+			expectedKind: service.ErrInternal,
+			expectedCode: service.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUserRepository(t)
+			tenants := mocks.NewTenantRepository(t)
+			tt.setupMocks(users, tenants)
+
+			authService := service.NewAuthService(users, "test")
+			authService.Tenants = tenants
+
+			result, err := authService.Registration(tt.ctx, model.User{
+				Email:    "test@example.com",
+				Password: "secure-password",
+				Role:     service.EmployeeRole,
+				TenantID: tt.bodyTenant,
+			})
+
+			if tt.expectedCode != "" {
+				assert.ErrorIs(t, err, tt.expectedKind)
+				assertServiceCode(t, err, tt.expectedCode)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTenant, result.TenantID)
+		})
+	}
+}
+
+// Оператор пользователя попадает в токен, по нему middleware ограничивает данные запроса
+func TestAuthService_AuthenticateTenantClaim(t *testing.T) {
+	hashedPass, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	tenantID := uuid.New()
+
+	users := mocks.NewUserRepository(t)
+	users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&model.User{
+		ID:       uuid.New(),
+		Email:    "test@example.com",
+		Password: string(hashedPass),
+		Role:     service.EmployeeRole,
+		TenantID: tenantID,
+	}, nil)
+
+	token, err := service.NewAuthService(users, "test").
+		Authenticate(context.Background(), model.User{Email: "test@example.com", Password: "password123"})
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("test"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tenantID.String(), claims[service.ClaimTenantID])
+	assert.Equal(t, service.EmployeeRole, claims[service.ClaimRole])
+}
+
+// При входе хеш устаревшего алгоритма или стоимости пересчитывается настроенным
+func TestAuthService_AuthenticateRehash(t *testing.T) {
+	const password = "password123"
+	userID := uuid.New()
+	oldCost, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	argon2idParams := hash.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+	tests := []struct {
+		name       string
+		hasher     hash.PasswordHasher
+		updateErr  error
+		wantRehash bool
+		wantAlg    string
+	}{
+		{"тот же cost", hash.NewBcrypt(bcrypt.MinCost), nil, false, ""},
+		{"cost повышен", hash.NewBcrypt(bcrypt.MinCost + 1), nil, true, hash.AlgorithmBcrypt},
+		{"переход на argon2id", hash.NewArgon2id(argon2idParams), nil, true, hash.AlgorithmArgon2id},
+		{"ошибка сохранения не мешает входу", hash.NewArgon2id(argon2idParams), errors.New("db down"), true, hash.AlgorithmArgon2id},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUserRepository(t)
+			users.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&model.User{
+				ID:       userID,
+				Email:    "test@example.com",
+				Password: string(oldCost),
+				Role:     service.EmployeeRole,
+				TenantID: tenant.DefaultID,
+			}, nil)
+			if tt.wantRehash {
+				users.On("UpdatePassword", mock.Anything, userID, mock.MatchedBy(func(encoded string) bool {
+					rehash, err := hash.NewPasswords(tt.hasher).Verify(encoded, password)
+					return err == nil && !rehash && hash.Algorithm(encoded) == tt.wantAlg
+				})).Return(tt.updateErr)
+			}
+
+			authService := service.NewAuthService(users, "test")
+			authService.Hasher = hash.NewPasswords(tt.hasher)
+
+			token, err := authService.Authenticate(context.Background(), model.User{Email: "test@example.com", Password: password})
+			require.NoError(t, err)
+			assert.NotEmpty(t, token)
+
+			// С неверным паролем хеш не трогаем
+			_, err = authService.Authenticate(context.Background(), model.User{Email: "test@example.com", Password: "wrong"})
+			assert.ErrorIs(t, err, service.ErrUnauthorized)
+		})
+	}
+}
@@ -114,3 +114,46 @@ func TestInfoService_GetInfoPvz(t *testing.T) {
 		})
 	}
 }
+
+func TestInfoService_GetInfoPvzPagination(t *testing.T) {
+	registered := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	// В порядке выдачи: по дате регистрации, при равной дате - по id
+	pvzList := []*model.Pvz{
+		{ID: uuid.MustParse("bbbbbbbb-0000-0000-0000-000000000000"), RegistrationDate: registered},
+		{ID: uuid.MustParse("cccccccc-0000-0000-0000-000000000000"), RegistrationDate: registered},
+		{ID: uuid.MustParse("aaaaaaaa-0000-0000-0000-000000000000"), RegistrationDate: registered.Add(time.Hour)},
+		{ID: uuid.MustParse("dddddddd-0000-0000-0000-000000000000"), RegistrationDate: registered.Add(2 * time.Hour)},
+		{ID: uuid.MustParse("11111111-0000-0000-0000-000000000000"), RegistrationDate: registered.Add(3 * time.Hour)},
+	}
+
+	mockReceptionRepo := new(mocks.ReceptionRepository)
+	mockPvzRepo := new(mocks.PvzRepository)
+	mockReceptionRepo.On("GetReceptionsSliceWithTimeRange", mock.Anything, time.Time{}, time.Time{}).Return([]model.Reception{}, nil)
+
+	ids := make([]uuid.UUID, 0, len(pvzList))
+	for i := len(pvzList) - 1; i >= 0; i-- {
+		ids = append(ids, pvzList[i].ID)
+		pvz := *pvzList[i]
+		mockPvzRepo.On("GetPvzByID", mock.Anything, pvz.ID).Return(func(context.Context, uuid.UUID) *model.Pvz {
+			copied := pvz
+			return &copied
+		}, nil)
+	}
+	mockPvzRepo.On("GetIDListPvz", mock.Anything).Return(ids, nil)
+
+	service := service2.NewInfoService(new(mocks.ProductRepository), mockReceptionRepo, mockPvzRepo)
+
+	// Порядок обхода map каждый раз другой, страницы от него не зависят
+	for range 20 {
+		var got []uuid.UUID
+		for page := 1; page <= 3; page++ {
+			result, err := service.GetInfoPvz(context.Background(), &model.PvzInfoQuery{Page: page, Limit: 2})
+			assert.NoError(t, err)
+			for _, pvz := range result {
+				got = append(got, pvz.ID)
+			}
+		}
+
+		assert.Equal(t, []uuid.UUID{pvzList[0].ID, pvzList[1].ID, pvzList[2].ID, pvzList[3].ID, pvzList[4].ID}, got)
+	}
+}
@@ -0,0 +1,257 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
+)
+
+func TestIssuanceService_IssueProduct(t *testing.T) {
+	pvzID := uuid.New()
+	productID := uuid.New()
+	receptionID := uuid.New()
+	now := time.Now().UTC()
+	issuance := model.Issuance{ProductID: productID, PvzID: pvzID, EmployeeID: "employee-1", ConfirmationCode: "4821"}
+	product := &model.Product{ID: productID, ReceptionID: receptionID}
+
+	tests := []struct {
+		name          string
+		mockSetup     func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository)
+		expectedError error
+		expectedCode  string
+	}{
+		{
+			name: "товар не найден",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(fmt.Errorf("product not found: %w", model.ErrNotFound))
+			},
+			expectedError: service.ErrNotFound,
+			expectedCode:  service.CodeProductNotFound,
+		},
+		{
+			name: "товар принят в другом ПВЗ",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: uuid.New(), IsClosed: true}, nil)
+			},
+			expectedError: service.ErrNotFound,
+			expectedCode:  service.CodeProductNotFound,
+		},
+		{
+			name: "приемка еще открыта",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: pvzID}, nil)
+			},
+			expectedError: service.ErrConflict,
+			expectedCode:  service.CodeReceptionNotClosed,
+		},
+		{
+			name: "товар уже выдан",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: pvzID, IsClosed: true}, nil)
+				issRepo.On("GetLastIssuance", mock.Anything, productID).Return(&model.Issuance{IssuedAt: now}, nil)
+				issRepo.On("GetLastReturn", mock.Anything, productID).Return(nil, model.ErrNotFound)
+			},
+			expectedError: service.ErrConflict,
+			expectedCode:  service.CodeProductAlreadyIssued,
+		},
+		{
+			name: "повторная выдача после возврата",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: pvzID, IsClosed: true}, nil)
+				issRepo.On("GetLastIssuance", mock.Anything, productID).Return(&model.Issuance{IssuedAt: now.Add(-time.Hour)}, nil)
+				issRepo.On("GetLastReturn", mock.Anything, productID).Return(&model.ProductReturn{ReturnedAt: now}, nil)
+				issRepo.On("CreateIssuance", mock.Anything, issuance).Return(&model.Issuance{ID: uuid.New()}, nil)
+			},
+		},
+		{
+			name: "сбой блокировки товара",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(errors.New("connection refused"))
+			},
+			expectedError: service.ErrInternal,
+			expectedCode:  service.CodeInternal,
+		},
+		{
+			// Сбой чтения выдач не должен выглядеть как "товар не выдан" и приводить к повторной выдаче
+			name: "сбой чтения выдач",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: pvzID, IsClosed: true}, nil)
+				issRepo.On("GetLastIssuance", mock.Anything, productID).Return(nil, errors.New("connection refused"))
+			},
+			expectedError: service.ErrInternal,
+			expectedCode:  service.CodeInternal,
+		},
+		{
+			name: "сбой чтения возвратов",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: pvzID, IsClosed: true}, nil)
+				issRepo.On("GetLastIssuance", mock.Anything, productID).Return(&model.Issuance{IssuedAt: now}, nil)
+				issRepo.On("GetLastReturn", mock.Anything, productID).Return(nil, errors.New("connection refused"))
+			},
+			expectedError: service.ErrInternal,
+			expectedCode:  service.CodeInternal,
+		},
+		{
+			name: "ошибка записи выдачи",
+			mockSetup: func(issRepo *mocks.IssuanceRepository, prodRepo *mocks.ProductRepository, recRepo *mocks.ReceptionRepository) {
+				issRepo.On("LockProduct", mock.Anything, productID).Return(nil)
+				prodRepo.On("GetProductByID", mock.Anything, productID).Return(product, nil)
+				recRepo.On("GetReceptionByID", mock.Anything, receptionID).
+					Return(&model.Reception{ID: receptionID, PvzID: pvzID, IsClosed: true}, nil)
+				issRepo.On("GetLastIssuance", mock.Anything, productID).Return(nil, model.ErrNotFound)
+				issRepo.On("CreateIssuance", mock.Anything, issuance).Return(nil, errors.New("failed to create issuance"))
+			},
+			expectedError: service.ErrInternal,
+			expectedCode:  service.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issRepo := mocks.NewIssuanceRepository(t)
+			prodRepo := mocks.NewProductRepository(t)
+			recRepo := mocks.NewReceptionRepository(t)
+			tt.mockSetup(issRepo, prodRepo, recRepo)
+
+			s := service.NewIssuanceService(issRepo, prodRepo, recRepo, mocks.NewPvzRepository(t))
+			result, err := s.IssueProduct(context.Background(), issuance)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				var serviceErr *service.Error
+				require.ErrorAs(t, err, &serviceErr)
+				assert.Equal(t, tt.expectedCode, serviceErr.Code)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, result)
+		})
+	}
+}
+
+func TestIssuanceService_Flow(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	s := service.NewIssuanceService(repo, repo, repo, repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	productID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID})
+	require.NoError(t, err)
+	require.NoError(t, repo.CloseReception(ctx, receptionID, model.CloseReasonManual))
+
+	ret := model.ProductReturn{ProductID: productID, PvzID: pvzID, EmployeeID: "employee-1", Reason: "брак"}
+
+	// Вернуть можно только выданный товар
+	_, err = s.ReturnProduct(ctx, ret)
+	assert.ErrorIs(t, err, service.ErrConflict)
+
+	issued, err := s.IssueProduct(ctx, model.Issuance{
+		ProductID: productID, PvzID: pvzID, EmployeeID: "employee-1", ConfirmationCode: "4821",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "4821", issued.ConfirmationCode)
+
+	stock, err := s.GetStock(ctx, model.Pvz{ID: pvzID})
+	require.NoError(t, err)
+	assert.Empty(t, stock)
+
+	returned, err := s.ReturnProduct(ctx, ret)
+	require.NoError(t, err)
+	assert.Equal(t, "брак", returned.Reason)
+
+	stock, err = s.GetStock(ctx, model.Pvz{ID: pvzID})
+	require.NoError(t, err)
+	require.Len(t, stock, 1)
+	assert.Equal(t, productID, stock[0].ID)
+
+	_, err = s.GetStock(ctx, model.Pvz{ID: uuid.New()})
+	assert.ErrorIs(t, err, service.ErrNotFound)
+}
+
+// slowIssuances задерживает ответ на чтение выдач: между проверкой и записью выдачи остается окно
+type slowIssuances struct {
+	*repository.MemoryRepository
+}
+
+func (r slowIssuances) GetLastIssuance(ctx context.Context, productID uuid.UUID) (*model.Issuance, error) {
+	issuance, err := r.MemoryRepository.GetLastIssuance(ctx, productID)
+	time.Sleep(5 * time.Millisecond)
+	return issuance, err
+}
+
+// Одновременные выдачи одного товара: проходит ровно одна
+func TestIssuanceService_ConcurrentIssue(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	s := service.NewIssuanceService(slowIssuances{repo}, repo, repo, repo)
+	s.Tx = repo
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	receptionID, err := repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+	productID, err := repo.CreateProduct(ctx, model.Product{TypeProduct: "обувь", ReceptionID: receptionID})
+	require.NoError(t, err)
+	require.NoError(t, repo.CloseReception(ctx, receptionID, model.CloseReasonManual))
+
+	const workers = 8
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.IssueProduct(ctx, model.Issuance{ProductID: productID, PvzID: pvzID, EmployeeID: "employee-1"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	issued := 0
+	for err := range errs {
+		if err == nil {
+			issued++
+			continue
+		}
+		assertServiceCode(t, err, service.CodeProductAlreadyIssued)
+	}
+	assert.Equal(t, 1, issued)
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/service"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	policy := service.DefaultPasswordPolicy()
+	policy.Breached = map[string]struct{}{"password1": {}}
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  string
+	}{
+		{"короткий", "Ab1", "at least 8 characters"},
+		{"короткий в байтах длиннее", "Пароль1", "at least 8 characters"},
+		{"один класс символов", "abcdefghij", "at least 2 of"},
+		{"утекший в другом регистре", "PASSWORD1", service.PasswordBreached},
+		{"длиннее 72 байт", strings.Repeat("пароль1", 11), service.PasswordTooLong},
+		{"буквы и цифры", "pvz2026scanner", ""},
+		{"кириллица и символы", "пароль-склада", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, service.ErrValidation)
+			assert.ErrorContains(t, err, tt.wantErr)
+			var serviceErr *service.Error
+			require.ErrorAs(t, err, &serviceErr)
+			assert.Equal(t, service.CodeWeakPassword, serviceErr.Code)
+		})
+	}
+}
+
+func TestReadBreachList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# частые пароли\nQwerty123\n\n  letmein1  \n"), 0o600))
+
+	list, err := service.ReadBreachList(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"qwerty123": {}, "letmein1": {}}, list)
+
+	_, err = service.ReadBreachList(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+// Список из configs должен читаться и содержать самые частые пароли
+func TestReadBreachList_Shipped(t *testing.T) {
+	list, err := service.ReadBreachList("../../../configs/breached_passwords.txt")
+	require.NoError(t, err)
+	assert.Contains(t, list, "password1")
+	assert.Contains(t, list, "qwerty123")
+}
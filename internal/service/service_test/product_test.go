@@ -23,6 +23,21 @@ import (
 
 const electrType = "электроника"
 
+// emptyCells - ПВЗ без ячеек хранения, товар остается неразмещенным
+func emptyCells(t *testing.T) *mocks.StorageCellRepository {
+	cellRepo := mocks.NewStorageCellRepository(t)
+	cellRepo.On("GetStorageCells", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	return cellRepo
+}
+
+// activePvz - любой запрошенный ПВЗ существует и работает
+func activePvz(t *testing.T) *mocks.PvzRepository {
+	pvzRepo := mocks.NewPvzRepository(t)
+	pvzRepo.On("GetPvzByID", mock.Anything, mock.Anything).
+		Return(&model.Pvz{Status: model.PvzStatusActive}, nil).Maybe()
+	return pvzRepo
+}
+
 func TestProductService_AddProduct(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -54,7 +69,7 @@ func TestProductService_AddProduct(t *testing.T) {
 				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(&model.Reception{IsClosed: false, ID: uuid.New()}, nil)
 			},
 			mockCreateProduct: func(mockRepo *mocks.ProductRepository) {
-				mockRepo.On("CreateProduct", mock.Anything, mock.Anything, mock.Anything).Return(uuid.New(), nil)
+				mockRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(uuid.New(), nil)
 			},
 			mockGetProductByID: func(mockRepo *mocks.ProductRepository) {
 				mockRepo.On("GetProductByID", mock.Anything, mock.Anything).Return(&model.Product{ID: uuid.New(), TypeProduct: electrType}, nil)
@@ -67,13 +82,25 @@ func TestProductService_AddProduct(t *testing.T) {
 			pvzID:       uuid.New(),
 			typeProduct: electrType,
 			mockGetLastReception: func(mockRepo *mocks.ReceptionRepository) {
-				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, errors.New(service.PvzOrReceptionsNotExist))
+				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			mockCreateProduct:  func(mockRepo *mocks.ProductRepository) {},
 			mockGetProductByID: func(mockRepo *mocks.ProductRepository) {},
 			expectedError:      errors.New(service.PvzOrReceptionsNotExist),
 			expectedProduct:    nil,
 		},
+		{
+			name:        "Storage failure is not reported as not found",
+			pvzID:       uuid.New(),
+			typeProduct: electrType,
+			mockGetLastReception: func(mockRepo *mocks.ReceptionRepository) {
+				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			mockCreateProduct:  func(mockRepo *mocks.ProductRepository) {},
+			mockGetProductByID: func(mockRepo *mocks.ProductRepository) {},
+			expectedError:      errors.New(service.FailedReceptionGet + ": connection refused"),
+			expectedProduct:    nil,
+		},
 		{
 			name:        "Error when product creation fails",
 			pvzID:       uuid.New(),
@@ -82,10 +109,10 @@ func TestProductService_AddProduct(t *testing.T) {
 				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(&model.Reception{IsClosed: false, ID: uuid.New()}, nil)
 			},
 			mockCreateProduct: func(mockRepo *mocks.ProductRepository) {
-				mockRepo.On("CreateProduct", mock.Anything, mock.Anything, mock.Anything).Return(uuid.UUID{}, errors.New("product creation failed"))
+				mockRepo.On("CreateProduct", mock.Anything, mock.Anything).Return(uuid.UUID{}, errors.New("product creation failed"))
 			},
 			mockGetProductByID: func(mockRepo *mocks.ProductRepository) {},
-			expectedError:      errors.New("failed to create product"),
+			expectedError:      errors.New("failed to create product: product creation failed"),
 			expectedProduct:    nil,
 		},
 	}
@@ -94,7 +121,7 @@ func TestProductService_AddProduct(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockProductRepo := mocks.NewProductRepository(t)
 			mockReceptionRepo := mocks.NewReceptionRepository(t)
-			service := service.NewProductService(mockProductRepo, mockReceptionRepo)
+			service := service.NewProductService(mockProductRepo, mockReceptionRepo, emptyCells(t), activePvz(t))
 
 			// Настроим моки
 			tt.mockGetLastReception(mockReceptionRepo)
@@ -134,7 +161,7 @@ func TestProductService_DeleteProduct(t *testing.T) {
 			name:  "Delete Product when no reception found",
 			pvzID: uuid.New(),
 			mockGetLastReception: func(mockRepo *mocks.ReceptionRepository) {
-				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, errors.New(service.PvzOrReceptionsNotExist))
+				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			mockGetLastProduct:    func(mockRepo *mocks.ProductRepository) {},
 			mockDeleteProductByID: func(mockRepo *mocks.ProductRepository) {},
@@ -196,7 +223,7 @@ func TestProductService_DeleteProduct(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockProductRepo := mocks.NewProductRepository(t)
 			mockReceptionRepo := mocks.NewReceptionRepository(t)
-			service := service.NewProductService(mockProductRepo, mockReceptionRepo)
+			service := service.NewProductService(mockProductRepo, mockReceptionRepo, emptyCells(t), activePvz(t))
 
 			// Настроим моки
 			tt.mockGetLastReception(mockReceptionRepo)
@@ -220,3 +247,95 @@ func TestProductService_DeleteProduct(t *testing.T) {
 		})
 	}
 }
+
+func TestProductService_AddProductDuplicateBarcode(t *testing.T) {
+	mockProductRepo := mocks.NewProductRepository(t)
+	mockReceptionRepo := mocks.NewReceptionRepository(t)
+	s := service.NewProductService(mockProductRepo, mockReceptionRepo, emptyCells(t), activePvz(t))
+
+	barcode := "4006381333931"
+	reception := &model.Reception{ID: uuid.New()}
+
+	mockReceptionRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(reception, nil)
+	mockProductRepo.On("GetProductsByBarcode", mock.Anything, barcode).
+		Return([]model.Product{{ID: uuid.New(), ReceptionID: reception.ID, Barcode: barcode}}, nil)
+
+	product, err := s.AddProduct(context.Background(), model.Product{TypeProduct: electrType, Barcode: barcode},
+		model.Pvz{ID: uuid.New()})
+
+	assert.Nil(t, product)
+	assert.ErrorIs(t, err, service.ErrConflict)
+	assert.Equal(t, service.DuplicateBarcode, err.Error())
+}
+
+func TestProductService_AddProductInactivePvz(t *testing.T) {
+	for _, status := range []string{model.PvzStatusSuspended, model.PvzStatusDecommissioned} {
+		t.Run(status, func(t *testing.T) {
+			pvzRepo := mocks.NewPvzRepository(t)
+			pvzRepo.On("GetPvzByID", mock.Anything, mock.Anything).Return(&model.Pvz{Status: status}, nil)
+
+			// До приемки и ячеек дело не доходит
+			s := service.NewProductService(mocks.NewProductRepository(t), mocks.NewReceptionRepository(t),
+				mocks.NewStorageCellRepository(t), pvzRepo)
+
+			product, err := s.AddProduct(context.Background(), model.Product{TypeProduct: electrType}, model.Pvz{ID: uuid.New()})
+			assert.Nil(t, product)
+			assertServiceCode(t, err, service.CodePvzNotActive)
+		})
+	}
+}
+
+func TestProductService_GetProductByBarcode(t *testing.T) {
+	barcode := "4006381333931"
+	latest := model.Product{ID: uuid.New(), Barcode: barcode}
+
+	tests := []struct {
+		name          string
+		mockSetup     func(mockRepo *mocks.ProductRepository)
+		expectedError error
+		expected      *model.Product
+	}{
+		{
+			name: "возвращается последний принятый товар",
+			mockSetup: func(mockRepo *mocks.ProductRepository) {
+				mockRepo.On("GetProductsByBarcode", mock.Anything, barcode).
+					Return([]model.Product{latest, {ID: uuid.New(), Barcode: barcode}}, nil)
+			},
+			expected: &latest,
+		},
+		{
+			name: "товар не найден",
+			mockSetup: func(mockRepo *mocks.ProductRepository) {
+				mockRepo.On("GetProductsByBarcode", mock.Anything, barcode).Return(nil, nil)
+			},
+			expectedError: service.ErrNotFound,
+		},
+		{
+			name: "ошибка хранилища",
+			mockSetup: func(mockRepo *mocks.ProductRepository) {
+				mockRepo.On("GetProductsByBarcode", mock.Anything, barcode).Return(nil, errors.New("db down"))
+			},
+			expectedError: service.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProductRepo := mocks.NewProductRepository(t)
+			tt.mockSetup(mockProductRepo)
+
+			s := service.NewProductService(mockProductRepo, mocks.NewReceptionRepository(t), mocks.NewStorageCellRepository(t),
+				mocks.NewPvzRepository(t))
+			product, err := s.GetProductByBarcode(context.Background(), barcode)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, product)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, product)
+		})
+	}
+}
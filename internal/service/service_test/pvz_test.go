@@ -3,14 +3,20 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
 	service2 "pvz-service/internal/service"
 	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
 )
 
 func TestPvzService_AddNewPvz(t *testing.T) {
@@ -48,7 +54,7 @@ func TestPvzService_AddNewPvz(t *testing.T) {
 			},
 			mockGetPvzByID: func(mockRepo *mocks.PvzRepository) {},
 			expectedPvz:    nil,
-			expectedError:  errors.New("creation failed"),
+			expectedError:  errors.New(service2.FailedPvzCreate + ": creation failed"),
 		},
 		{
 			name: "Error Getting Pvz by ID",
@@ -60,7 +66,7 @@ func TestPvzService_AddNewPvz(t *testing.T) {
 				mockRepo.On("GetPvzByID", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
 			},
 			expectedPvz:   nil,
-			expectedError: errors.New("not found"),
+			expectedError: errors.New(service2.FailedPvzCreate + ": not found"),
 		},
 	}
 
@@ -90,3 +96,312 @@ func TestPvzService_AddNewPvz(t *testing.T) {
 		})
 	}
 }
+
+func TestPvzService_UpdatePvz(t *testing.T) {
+	pvzID := uuid.New()
+	address := "ул. Тверская, 1"
+	badTimezone := "Mars/Olympus"
+	limit := 3
+	negative := -1
+
+	tests := []struct {
+		name          string
+		patch         model.PvzPatch
+		mockSetup     func(mockRepo *mocks.PvzRepository)
+		expectedError error
+		expectedCode  string
+	}{
+		{
+			name:  "ПВЗ не найден",
+			patch: model.PvzPatch{ID: pvzID, Address: &address},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(nil, fmt.Errorf("pvz not found: %w", model.ErrNotFound))
+			},
+			expectedError: service2.ErrNotFound,
+			expectedCode:  service2.CodePvzNotFound,
+		},
+		{
+			name:  "неизвестный часовой пояс",
+			patch: model.PvzPatch{ID: pvzID, Timezone: &badTimezone},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+			},
+			expectedError: service2.ErrValidation,
+			expectedCode:  service2.CodeInvalidPvzProfile,
+		},
+		{
+			name:  "отрицательный лимит",
+			patch: model.PvzPatch{ID: pvzID, MaxDailyReceptions: &negative},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+			},
+			expectedError: service2.ErrValidation,
+			expectedCode:  service2.CodeInvalidPvzProfile,
+		},
+		{
+			name: "закрытие раньше открытия",
+			patch: model.PvzPatch{ID: pvzID, WorkingHours: &model.WorkingHours{
+				Weekly: map[time.Weekday]model.DayHours{time.Monday: {Open: 21 * 60, Close: 9 * 60}},
+			}},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+			},
+			expectedError: service2.ErrValidation,
+			expectedCode:  service2.CodeInvalidPvzProfile,
+		},
+		{
+			name: "два исключения на одну дату",
+			patch: model.PvzPatch{ID: pvzID, WorkingHours: &model.WorkingHours{
+				Exceptions: []model.HoursException{
+					{Date: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), Closed: true},
+					{Date: time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), Hours: model.DayHours{Open: 600, Close: 900}},
+				},
+			}},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+			},
+			expectedError: service2.ErrValidation,
+			expectedCode:  service2.CodeInvalidPvzProfile,
+		},
+		{
+			name:  "меняются только переданные поля",
+			patch: model.PvzPatch{ID: pvzID, Address: &address, MaxDailyReceptions: &limit},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).
+					Return(&model.Pvz{ID: pvzID, City: "Москва", Timezone: model.DefaultTimezone}, nil).Once()
+				mockRepo.On("UpdatePvzProfile", mock.Anything, model.Pvz{
+					ID: pvzID, City: "Москва", Timezone: model.DefaultTimezone, Address: address, MaxDailyReceptions: limit,
+				}).Return(nil)
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).
+					Return(&model.Pvz{ID: pvzID, Address: address, MaxDailyReceptions: limit}, nil).Once()
+			},
+		},
+		{
+			name:  "ошибка сохранения",
+			patch: model.PvzPatch{ID: pvzID, Address: &address},
+			mockSetup: func(mockRepo *mocks.PvzRepository) {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+				mockRepo.On("UpdatePvzProfile", mock.Anything, mock.Anything).Return(errors.New("failed to update Pvz"))
+			},
+			expectedError: service2.ErrInternal,
+			expectedCode:  service2.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewPvzRepository(t)
+			tt.mockSetup(mockRepo)
+
+			pvz, err := service2.NewPvzService(mockRepo).UpdatePvz(context.Background(), tt.patch)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				var serviceErr *service2.Error
+				require.ErrorAs(t, err, &serviceErr)
+				assert.Equal(t, tt.expectedCode, serviceErr.Code)
+				assert.Nil(t, pvz)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, address, pvz.Address)
+		})
+	}
+}
+
+func TestPvzService_GetNearbyPvz(t *testing.T) {
+	query := model.NearbyQuery{Point: model.GeoPoint{Lat: 55.75, Lng: 37.62}, RadiusMeters: 1000, Limit: 10}
+
+	// Выходной вчера, сегодня и завтра по UTC закрывает ПВЗ в любом поясе
+	now := time.Now().UTC()
+	closed := model.WorkingHours{}
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now, now.AddDate(0, 0, 1)} {
+		closed.Exceptions = append(closed.Exceptions, model.HoursException{
+			Date:   time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+			Closed: true,
+		})
+	}
+
+	openPvz := model.Pvz{ID: uuid.New(), Timezone: model.DefaultTimezone}
+	closedPvz := model.Pvz{ID: uuid.New(), Timezone: "Asia/Vladivostok", WorkingHours: closed}
+
+	t.Run("статус по графику", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetNearbyPvz", mock.Anything, query).Return([]model.NearbyPvz{
+			{Pvz: openPvz, DistanceMeters: 120},
+			{Pvz: closedPvz, DistanceMeters: 800},
+		}, nil)
+
+		list, err := service2.NewPvzService(mockRepo).GetNearbyPvz(context.Background(), query)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.True(t, list[0].IsOpen)
+		assert.False(t, list[1].IsOpen)
+		assert.Equal(t, 800.0, list[1].DistanceMeters)
+	})
+
+	t.Run("ошибка репозитория", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetNearbyPvz", mock.Anything, query).Return(nil, errors.New("db down"))
+
+		_, err := service2.NewPvzService(mockRepo).GetNearbyPvz(context.Background(), query)
+		assertServiceCode(t, err, service2.CodeInternal)
+	})
+}
+
+func TestPvzService_GetPvzChangeStamp(t *testing.T) {
+	pvz := model.Pvz{ID: uuid.New()}
+	stamp := model.ChangeStamp{ChangedAt: time.Now().UTC(), Count: 1}
+
+	t.Run("отметка ПВЗ", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetPvzChangeStamp", mock.Anything, pvz.ID).Return(stamp, nil)
+
+		got, err := service2.NewPvzService(mockRepo).GetPvzChangeStamp(context.Background(), pvz)
+		require.NoError(t, err)
+		assert.Equal(t, stamp, got)
+	})
+
+	t.Run("ПВЗ не найден", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetPvzChangeStamp", mock.Anything, pvz.ID).Return(model.ChangeStamp{}, fmt.Errorf("pvz not found: %w", model.ErrNotFound))
+
+		_, err := service2.NewPvzService(mockRepo).GetPvzChangeStamp(context.Background(), pvz)
+		assertServiceCode(t, err, service2.CodePvzNotFound)
+	})
+
+	t.Run("сбой хранилища - не 404", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetPvzChangeStamp", mock.Anything, pvz.ID).Return(model.ChangeStamp{}, errors.New("connection refused"))
+
+		_, err := service2.NewPvzService(mockRepo).GetPvzChangeStamp(context.Background(), pvz)
+		assert.ErrorIs(t, err, service2.ErrInternal)
+	})
+}
+
+func TestPvzService_ChangePvzStatus(t *testing.T) {
+	pvzID := uuid.New()
+
+	tests := []struct {
+		name         string
+		current      string
+		target       string
+		updateErr    error
+		expectedCode string
+	}{
+		{name: "приостановка", current: model.PvzStatusActive, target: model.PvzStatusSuspended},
+		{name: "возобновление", current: model.PvzStatusSuspended, target: model.PvzStatusActive},
+		{name: "вывод приостановленного", current: model.PvzStatusSuspended, target: model.PvzStatusDecommissioned},
+		{
+			name:         "повторная приостановка",
+			current:      model.PvzStatusSuspended,
+			target:       model.PvzStatusSuspended,
+			expectedCode: service2.CodeInvalidPvzStatus,
+		},
+		{
+			name:         "выведенный ПВЗ не возвращается",
+			current:      model.PvzStatusDecommissioned,
+			target:       model.PvzStatusActive,
+			expectedCode: service2.CodeInvalidPvzStatus,
+		},
+		{
+			name:         "статус сменили параллельно",
+			current:      model.PvzStatusActive,
+			target:       model.PvzStatusSuspended,
+			updateErr:    errors.New("no rows affected"),
+			expectedCode: service2.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewPvzRepository(t)
+			mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: tt.current}, nil).Once()
+			if tt.expectedCode == "" || tt.updateErr != nil {
+				mockRepo.On("UpdatePvzStatus", mock.Anything, pvzID, tt.current, tt.target).Return(tt.updateErr)
+			}
+			if tt.expectedCode == "" {
+				mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: tt.target}, nil).Once()
+			}
+
+			pvz, err := service2.NewPvzService(mockRepo).ChangePvzStatus(context.Background(), model.Pvz{ID: pvzID}, tt.target)
+
+			if tt.expectedCode != "" {
+				assertServiceCode(t, err, tt.expectedCode)
+				assert.Nil(t, pvz)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.target, pvz.Status)
+		})
+	}
+
+	t.Run("ПВЗ не найден", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(nil, fmt.Errorf("pvz not found: %w", model.ErrNotFound))
+
+		_, err := service2.NewPvzService(mockRepo).ChangePvzStatus(context.Background(), model.Pvz{ID: pvzID}, model.PvzStatusSuspended)
+		assertServiceCode(t, err, service2.CodePvzNotFound)
+	})
+
+	t.Run("сбой хранилища - не 404", func(t *testing.T) {
+		mockRepo := mocks.NewPvzRepository(t)
+		mockRepo.On("GetPvzByID", mock.Anything, pvzID).Return(nil, errors.New("connection refused"))
+
+		_, err := service2.NewPvzService(mockRepo).ChangePvzStatus(context.Background(), model.Pvz{ID: pvzID}, model.PvzStatusSuspended)
+		assert.ErrorIs(t, err, service2.ErrInternal)
+	})
+}
+
+func TestPvzService_Lifecycle(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	pvzService := service2.NewPvzService(repo)
+	receptions := service2.NewReceptionService(repo, repo)
+	info := service2.NewInfoService(repo, repo, repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	pvz := model.Pvz{ID: pvzID}
+
+	first, err := receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+	_, err = receptions.CloseReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+
+	// Приостановленный ПВЗ не открывает приемки ни через API, ни из журнала сканера
+	_, err = pvzService.ChangePvzStatus(ctx, pvz, model.PvzStatusSuspended)
+	require.NoError(t, err)
+
+	_, err = receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	assertServiceCode(t, err, service2.CodePvzNotActive)
+
+	synced, err := service2.NewSyncService(repo, repo, repo, repo, repo).Sync(ctx, pvz, []model.SyncOperation{
+		{ID: uuid.New(), Type: model.SyncOpenReception, DateTime: time.Now().UTC().Add(-time.Minute)},
+	})
+	require.NoError(t, err)
+	require.Len(t, synced.Rejected, 1)
+	assert.Equal(t, service2.CodePvzNotActive, synced.Rejected[0].Code)
+
+	_, err = pvzService.ChangePvzStatus(ctx, pvz, model.PvzStatusActive)
+	require.NoError(t, err)
+	_, err = receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+
+	// Выведенный ПВЗ скрыт из списка, но его приемки сохранены
+	_, err = pvzService.ChangePvzStatus(ctx, pvz, model.PvzStatusDecommissioned)
+	require.NoError(t, err)
+
+	list, err := info.GetInfoPvz(ctx, &model.PvzInfoQuery{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	list, err = info.GetInfoPvz(ctx, &model.PvzInfoQuery{Page: 1, Limit: 10, IncludeInactive: true})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, model.PvzStatusDecommissioned, list[0].Status)
+	assert.Len(t, list[0].Receptions, 2)
+	assert.Equal(t, first.ID, list[0].Receptions[0].ID)
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
 	"pvz-service/internal/service"
 	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
 )
 
 func TestReceptionService_CreateReception(t *testing.T) {
@@ -59,7 +64,7 @@ func TestReceptionService_CreateReception(t *testing.T) {
 				mockRepo.On("CreateReception", mock.Anything, mock.Anything).Return(uuid.UUID{}, errors.New("creation failed"))
 			},
 			mockGetReceptionByID: func(mockRepo *mocks.ReceptionRepository) {},
-			expectedError:        errors.New("creation failed"),
+			expectedError:        errors.New(service.FailedReceptionCreate + ": creation failed"),
 			expectedReception:    nil,
 		},
 	}
@@ -67,9 +72,11 @@ func TestReceptionService_CreateReception(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewReceptionRepository(t)
-			service := service.NewReceptionService(mockRepo)
+			pvzRepo := mocks.NewPvzRepository(t)
+			service := service.NewReceptionService(mockRepo, pvzRepo)
 
-			// Настроим моки
+			// Настроим моки, у ПВЗ без графика и лимита проверки всегда проходят
+			pvzRepo.On("GetPvzByID", mock.Anything, tt.pvzID).Return(&model.Pvz{ID: tt.pvzID, Status: model.PvzStatusActive}, nil)
 			tt.mockGetLastReception(mockRepo)
 			tt.mockCreateReception(mockRepo)
 			tt.mockGetReceptionByID(mockRepo)
@@ -118,7 +125,7 @@ func TestReceptionService_CloseReception(t *testing.T) {
 				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(&model.Reception{IsClosed: false, ID: uuid.New()}, nil)
 			},
 			mockCloseReception: func(mockRepo *mocks.ReceptionRepository) {
-				mockRepo.On("CloseReception", mock.Anything, mock.Anything).Return(nil)
+				mockRepo.On("CloseReception", mock.Anything, mock.Anything, model.CloseReasonManual).Return(nil)
 			},
 			expectedError:     nil,
 			expectedReception: &model.Reception{ID: uuid.New(), IsClosed: true},
@@ -127,18 +134,28 @@ func TestReceptionService_CloseReception(t *testing.T) {
 			name:  "Error when no reception found",
 			pvzID: uuid.New(),
 			mockGetLastReception: func(mockRepo *mocks.ReceptionRepository) {
-				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, errors.New(service.PvzOrReceptionsNotExist))
+				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, model.ErrNotFound)
 			},
 			mockCloseReception: func(mockRepo *mocks.ReceptionRepository) {},
 			expectedError:      errors.New(service.PvzOrReceptionsNotExist),
 			expectedReception:  nil,
 		},
+		{
+			name:  "Storage failure is not reported as not found",
+			pvzID: uuid.New(),
+			mockGetLastReception: func(mockRepo *mocks.ReceptionRepository) {
+				mockRepo.On("GetLastReception", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+			},
+			mockCloseReception: func(mockRepo *mocks.ReceptionRepository) {},
+			expectedError:      errors.New(service.FailedReceptionGet + ": connection refused"),
+			expectedReception:  nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mocks.NewReceptionRepository(t)
-			service := service.NewReceptionService(mockRepo)
+			service := service.NewReceptionService(mockRepo, mocks.NewPvzRepository(t))
 
 			// Настроим моки
 			tt.mockGetLastReception(mockRepo)
@@ -154,6 +171,7 @@ func TestReceptionService_CloseReception(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedReception.IsClosed, reception.IsClosed)
+				assert.Equal(t, model.CloseReasonManual, reception.CloseReason)
 			}
 
 			// Проверка, что все ожидания мока были выполнены
@@ -161,3 +179,67 @@ func TestReceptionService_CloseReception(t *testing.T) {
 		})
 	}
 }
+
+func TestReceptionService_CreateReceptionSchedule(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	receptions := service.NewReceptionService(repo, repo)
+	pvzs := service.NewPvzService(repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+
+	loc, err := time.LoadLocation(model.DefaultTimezone)
+	require.NoError(t, err)
+	today := time.Now().In(loc)
+
+	// Круглосуточно по будням и выходным, но сегодня праздник
+	allDay := model.DayHours{Open: 0, Close: model.MinutesInDay}
+	weekly := make(map[time.Weekday]model.DayHours, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		weekly[day] = allDay
+	}
+	holiday := model.WorkingHours{
+		Weekly:     weekly,
+		Exceptions: []model.HoursException{{Date: time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC), Closed: true}},
+	}
+	_, err = pvzs.UpdatePvz(ctx, model.PvzPatch{ID: pvzID, WorkingHours: &holiday})
+	require.NoError(t, err)
+
+	_, err = receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	assertServiceCode(t, err, service.CodePvzClosed)
+
+	// Лимит одна приемка в день
+	open := model.WorkingHours{Weekly: weekly}
+	limit := 1
+	_, err = pvzs.UpdatePvz(ctx, model.PvzPatch{ID: pvzID, WorkingHours: &open, MaxDailyReceptions: &limit})
+	require.NoError(t, err)
+
+	first, err := receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+	_, err = receptions.CloseReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+
+	_, err = receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	assertServiceCode(t, err, service.CodeReceptionLimitReached)
+
+	// Разрешение модератора снимает и лимит, и график
+	until := time.Now().Add(time.Hour)
+	_, err = pvzs.UpdatePvz(ctx, model.PvzPatch{ID: pvzID, WorkingHours: &holiday, ReceptionOverrideUntil: &until})
+	require.NoError(t, err)
+
+	second, err := receptions.CreateReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	_, err = receptions.CreateReception(ctx, model.Reception{PvzID: uuid.New()})
+	assertServiceCode(t, err, service.CodePvzNotFound)
+}
+
+func assertServiceCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var serviceErr *service.Error
+	require.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, code, serviceErr.Code)
+}
@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
+)
+
+type timeoutConfig struct {
+	maxOpen     time.Duration
+	remindAfter time.Duration
+	action      string
+}
+
+func (c timeoutConfig) GetReceptionTimeout() (time.Duration, time.Duration, string) {
+	return c.maxOpen, c.remindAfter, c.action
+}
+
+// insertOpenReceptions создает ПВЗ с открытыми приемками заданного возраста
+func insertOpenReceptions(t *testing.T, repo *repository.MemoryRepository, ages ...time.Duration) []uuid.UUID {
+	t.Helper()
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	ids := make([]uuid.UUID, len(ages))
+	for i, age := range ages {
+		pvzID, err := repo.CreatePvz(ctx, "Москва")
+		require.NoError(t, err)
+
+		ids[i] = uuid.New()
+		require.NoError(t, repo.InsertReception(ctx, model.Reception{ID: ids[i], DateTime: time.Now().UTC().Add(-age), PvzID: pvzID}))
+	}
+
+	return ids
+}
+
+func eventFor(eventType string, id uuid.UUID) any {
+	return mock.MatchedBy(func(event model.ReceptionEvent) bool {
+		return event.Type == eventType && event.Reception.ID == id
+	})
+}
+
+func TestReceptionTimeoutService_SweepClose(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	notifier := mocks.NewReceptionNotifier(t)
+	s := service.NewReceptionTimeoutService(repo, timeoutConfig{
+		maxOpen:     24 * time.Hour,
+		remindAfter: 12 * time.Hour,
+		action:      service.ReceptionTimeoutClose,
+	}, notifier)
+
+	ids := insertOpenReceptions(t, repo, 48*time.Hour, 14*time.Hour, time.Hour)
+	stale, old, fresh := ids[0], ids[1], ids[2]
+
+	notifier.On("NotifyReception", mock.Anything, eventFor(model.ReceptionEventAutoClosed, stale)).Return(nil).Once()
+	notifier.On("NotifyReception", mock.Anything, eventFor(model.ReceptionEventReminder, old)).Return(nil).Once()
+
+	result, err := s.SweepReceptions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, service.SweepResult{Reminded: 1, Closed: 1}, result)
+
+	closed, err := repo.GetReceptionByID(ctx, stale)
+	require.NoError(t, err)
+	assert.True(t, closed.IsClosed)
+	assert.Equal(t, model.CloseReasonTimeout, closed.CloseReason)
+
+	reception, err := repo.GetReceptionByID(ctx, fresh)
+	require.NoError(t, err)
+	assert.False(t, reception.IsClosed)
+
+	// Повторный проход не шлет напоминание второй раз
+	result, err = s.SweepReceptions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, service.SweepResult{}, result)
+}
+
+func TestReceptionTimeoutService_SweepFlag(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	notifier := mocks.NewReceptionNotifier(t)
+	s := service.NewReceptionTimeoutService(repo, timeoutConfig{
+		maxOpen: 24 * time.Hour,
+		action:  service.ReceptionTimeoutFlag,
+	}, notifier)
+
+	stale := insertOpenReceptions(t, repo, 48*time.Hour)[0]
+
+	notifier.On("NotifyReception", mock.Anything, eventFor(model.ReceptionEventFlagged, stale)).Return(nil).Once()
+
+	result, err := s.SweepReceptions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, service.SweepResult{Flagged: 1}, result)
+
+	result, err = s.SweepReceptions(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, service.SweepResult{}, result)
+
+	// Помеченная приемка остается открытой
+	reception, err := repo.GetReceptionByID(ctx, stale)
+	require.NoError(t, err)
+	assert.False(t, reception.IsClosed)
+	assert.False(t, reception.FlaggedAt.IsZero())
+}
+
+func TestReceptionTimeoutService_SweepErrors(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+
+	t.Run("пороги выключены", func(t *testing.T) {
+		s := service.NewReceptionTimeoutService(mocks.NewReceptionRepository(t), timeoutConfig{action: service.ReceptionTimeoutClose}, mocks.NewReceptionNotifier(t))
+
+		result, err := s.SweepReceptions(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, service.SweepResult{}, result)
+	})
+
+	t.Run("ошибка выборки", func(t *testing.T) {
+		repo := mocks.NewReceptionRepository(t)
+		repo.On("GetOpenReceptionsBefore", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+		s := service.NewReceptionTimeoutService(repo, timeoutConfig{maxOpen: time.Hour, action: service.ReceptionTimeoutClose}, mocks.NewReceptionNotifier(t))
+
+		_, err := s.SweepReceptions(ctx)
+		assert.ErrorIs(t, err, service.ErrInternal)
+	})
+
+	t.Run("ошибка доставки не отменяет закрытие и не мешает остальным", func(t *testing.T) {
+		repo := repository.NewMemoryRepository(memdb.NewStorage())
+		notifier := mocks.NewReceptionNotifier(t)
+		s := service.NewReceptionTimeoutService(repo, timeoutConfig{maxOpen: time.Hour, action: service.ReceptionTimeoutClose}, notifier)
+
+		ids := insertOpenReceptions(t, repo, 3*time.Hour, 2*time.Hour)
+		notifier.On("NotifyReception", mock.Anything, eventFor(model.ReceptionEventAutoClosed, ids[0])).Return(errors.New("smtp down")).Once()
+		notifier.On("NotifyReception", mock.Anything, eventFor(model.ReceptionEventAutoClosed, ids[1])).Return(nil).Once()
+
+		result, err := s.SweepReceptions(ctx)
+		assert.ErrorIs(t, err, service.ErrInternal)
+		assert.Equal(t, service.SweepResult{Closed: 2}, result)
+
+		reception, err := repo.GetReceptionByID(ctx, ids[0])
+		require.NoError(t, err)
+		assert.True(t, reception.IsClosed)
+	})
+}
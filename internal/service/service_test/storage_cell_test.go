@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
+)
+
+func TestStorageCellService_DeleteStorageCell(t *testing.T) {
+	pvzID := uuid.New()
+	cellID := uuid.New()
+
+	tests := []struct {
+		name          string
+		mockSetup     func(cellRepo *mocks.StorageCellRepository, pvzRepo *mocks.PvzRepository)
+		expectedError error
+		expectedCode  string
+	}{
+		{
+			name: "ПВЗ не найден",
+			mockSetup: func(cellRepo *mocks.StorageCellRepository, pvzRepo *mocks.PvzRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(nil, fmt.Errorf("pvz not found: %w", model.ErrNotFound))
+			},
+			expectedError: service.ErrNotFound,
+			expectedCode:  service.CodePvzNotFound,
+		},
+		{
+			name: "ячейка не найдена",
+			mockSetup: func(cellRepo *mocks.StorageCellRepository, pvzRepo *mocks.PvzRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+				cellRepo.On("GetStorageCells", mock.Anything, pvzID).Return([]model.StorageCell{{ID: uuid.New()}}, nil)
+			},
+			expectedError: service.ErrNotFound,
+			expectedCode:  service.CodeStorageCellNotFound,
+		},
+		{
+			name: "в ячейке есть товары",
+			mockSetup: func(cellRepo *mocks.StorageCellRepository, pvzRepo *mocks.PvzRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+				cellRepo.On("GetStorageCells", mock.Anything, pvzID).
+					Return([]model.StorageCell{{ID: cellID, Capacity: 3, Occupied: 1}}, nil)
+			},
+			expectedError: service.ErrConflict,
+			expectedCode:  service.CodeStorageCellNotEmpty,
+		},
+		{
+			name: "ошибка удаления",
+			mockSetup: func(cellRepo *mocks.StorageCellRepository, pvzRepo *mocks.PvzRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+				cellRepo.On("GetStorageCells", mock.Anything, pvzID).Return([]model.StorageCell{{ID: cellID, Capacity: 3}}, nil)
+				cellRepo.On("DeleteStorageCell", mock.Anything, cellID).Return(errors.New("no rows affected"))
+			},
+			expectedError: service.ErrInternal,
+			expectedCode:  service.CodeInternal,
+		},
+		{
+			name: "успешное удаление",
+			mockSetup: func(cellRepo *mocks.StorageCellRepository, pvzRepo *mocks.PvzRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID}, nil)
+				cellRepo.On("GetStorageCells", mock.Anything, pvzID).Return([]model.StorageCell{{ID: cellID, Capacity: 3}}, nil)
+				cellRepo.On("DeleteStorageCell", mock.Anything, cellID).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cellRepo := mocks.NewStorageCellRepository(t)
+			pvzRepo := mocks.NewPvzRepository(t)
+			tt.mockSetup(cellRepo, pvzRepo)
+
+			s := service.NewStorageCellService(cellRepo, pvzRepo)
+			err := s.DeleteStorageCell(context.Background(), model.Pvz{ID: pvzID}, cellID)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				var serviceErr *service.Error
+				require.ErrorAs(t, err, &serviceErr)
+				assert.Equal(t, tt.expectedCode, serviceErr.Code)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestStorageCellService_Placement(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	cells := service.NewStorageCellService(repo, repo)
+	products := service.NewProductService(repo, repo, repo, repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	pvz := model.Pvz{ID: pvzID}
+
+	small, err := cells.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 1, SizeClass: model.SizeSmall})
+	require.NoError(t, err)
+	mediumA, err := cells.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "B-01", Capacity: 2, SizeClass: model.SizeMedium})
+	require.NoError(t, err)
+	mediumB, err := cells.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "B-02", Capacity: 4, SizeClass: model.SizeMedium})
+	require.NoError(t, err)
+
+	_, err = cells.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A-01", Capacity: 1, SizeClass: model.SizeSmall})
+	assert.ErrorIs(t, err, service.ErrConflict)
+
+	_, err = repo.CreateReception(ctx, pvzID)
+	require.NoError(t, err)
+
+	box := model.Dimensions{LengthMM: 500, WidthMM: 300, HeightMM: 200}
+	add := func(cellID uuid.UUID, d model.Dimensions) (*model.Product, error) {
+		return products.AddProduct(ctx, model.Product{TypeProduct: "обувь", Dimensions: d, CellID: cellID}, pvz)
+	}
+
+	// Без габаритов подходит самая маленькая ячейка
+	first, err := add(uuid.Nil, model.Dimensions{})
+	require.NoError(t, err)
+	assert.Equal(t, small.ID, first.CellID)
+
+	// Маленькая заполнена, средние пусты - берется первая по коду
+	second, err := add(uuid.Nil, box)
+	require.NoError(t, err)
+	assert.Equal(t, mediumA.ID, second.CellID)
+
+	// B-01 заполнена наполовину, B-02 пуста
+	third, err := add(uuid.Nil, box)
+	require.NoError(t, err)
+	assert.Equal(t, mediumB.ID, third.CellID)
+
+	_, err = add(small.ID, model.Dimensions{})
+	assert.ErrorIs(t, err, service.ErrConflict)
+	_, err = add(mediumA.ID, model.Dimensions{LengthMM: 1200})
+	assert.ErrorIs(t, err, service.ErrConflict)
+	_, err = add(uuid.New(), model.Dimensions{})
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	// Крупной посылке ячейки нет - товар принимается без ячейки
+	large, err := add(uuid.Nil, model.Dimensions{LengthMM: 1200})
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, large.CellID)
+
+	list, err := cells.GetStorageCells(ctx, pvz)
+	require.NoError(t, err)
+	occupied := make([]int, 0, len(list))
+	for _, cell := range list {
+		occupied = append(occupied, cell.Occupied)
+	}
+	assert.Equal(t, []int{1, 1, 1}, occupied)
+
+	assert.ErrorIs(t, cells.DeleteStorageCell(ctx, pvz, small.ID), service.ErrConflict)
+}
@@ -0,0 +1,327 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
+)
+
+func TestSyncService_Sync(t *testing.T) {
+	pvzID := uuid.New()
+	opID := uuid.New()
+	clientTime := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	openOp := model.SyncOperation{ID: opID, Type: model.SyncOpenReception, DateTime: clientTime}
+	current := &model.Reception{ID: uuid.New(), DateTime: clientTime.Add(-time.Hour), PvzID: pvzID}
+
+	tests := []struct {
+		name          string
+		ops           []model.SyncOperation
+		mockSetup     func(syncRepo *mocks.SyncRepository, pvzRepo *mocks.PvzRepository, recRepo *mocks.ReceptionRepository)
+		expectedError error
+		expected      *model.SyncResult
+	}{
+		{
+			name: "ПВЗ не найден",
+			ops:  []model.SyncOperation{openOp},
+			mockSetup: func(syncRepo *mocks.SyncRepository, pvzRepo *mocks.PvzRepository, recRepo *mocks.ReceptionRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(nil, fmt.Errorf("pvz not found: %w", model.ErrNotFound))
+			},
+			expectedError: service.ErrNotFound,
+		},
+		{
+			name: "повтор операции возвращает сохраненный результат",
+			ops:  []model.SyncOperation{openOp},
+			mockSetup: func(syncRepo *mocks.SyncRepository, pvzRepo *mocks.PvzRepository, recRepo *mocks.ReceptionRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: model.PvzStatusActive}, nil)
+				syncRepo.On("GetSyncOperation", mock.Anything, pvzID, opID).Return(&model.SyncOperation{
+					ID: opID, PvzID: pvzID, Type: model.SyncOpenReception, DateTime: clientTime,
+					Code: service.CodeReceptionNotClosed, Message: service.ReceptionWasNotClosed,
+				}, nil)
+				recRepo.On("GetLastReception", mock.Anything, pvzID).Return(nil, model.ErrNotFound)
+			},
+			expected: &model.SyncResult{
+				PvzID: pvzID,
+				Rejected: []model.SyncOperation{{
+					ID: opID, PvzID: pvzID, Type: model.SyncOpenReception, DateTime: clientTime,
+					Code: service.CodeReceptionNotClosed, Message: service.ReceptionWasNotClosed,
+				}},
+			},
+		},
+		{
+			name: "открытие при незакрытой приемке отклоняется",
+			ops:  []model.SyncOperation{openOp},
+			mockSetup: func(syncRepo *mocks.SyncRepository, pvzRepo *mocks.PvzRepository, recRepo *mocks.ReceptionRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: model.PvzStatusActive}, nil)
+				syncRepo.On("GetSyncOperation", mock.Anything, pvzID, opID).Return(nil, model.ErrNotFound)
+				recRepo.On("GetLastReception", mock.Anything, pvzID).Return(current, nil)
+				syncRepo.On("SaveSyncOperation", mock.Anything, model.SyncOperation{
+					ID: opID, PvzID: pvzID, Type: model.SyncOpenReception, DateTime: clientTime,
+					Code: service.CodeReceptionNotClosed, Message: service.ReceptionWasNotClosed,
+				}).Return(nil)
+				syncRepo.On("GetReceptionProducts", mock.Anything, current.ID).Return(nil, nil)
+			},
+			expected: &model.SyncResult{
+				PvzID:     pvzID,
+				Reception: current,
+				Rejected: []model.SyncOperation{{
+					ID: opID, PvzID: pvzID, Type: model.SyncOpenReception, DateTime: clientTime,
+					Code: service.CodeReceptionNotClosed, Message: service.ReceptionWasNotClosed,
+				}},
+			},
+		},
+		{
+			name: "сбой чтения журнала",
+			ops:  []model.SyncOperation{openOp},
+			mockSetup: func(syncRepo *mocks.SyncRepository, pvzRepo *mocks.PvzRepository, recRepo *mocks.ReceptionRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: model.PvzStatusActive}, nil)
+				syncRepo.On("GetSyncOperation", mock.Anything, pvzID, opID).Return(nil, errors.New("connection refused"))
+			},
+			expectedError: service.ErrInternal,
+		},
+		{
+			name: "ошибка записи журнала",
+			ops:  []model.SyncOperation{openOp},
+			mockSetup: func(syncRepo *mocks.SyncRepository, pvzRepo *mocks.PvzRepository, recRepo *mocks.ReceptionRepository) {
+				pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: model.PvzStatusActive}, nil)
+				syncRepo.On("GetSyncOperation", mock.Anything, pvzID, opID).Return(nil, model.ErrNotFound)
+				recRepo.On("GetLastReception", mock.Anything, pvzID).Return(nil, model.ErrNotFound)
+				recRepo.On("InsertReception", mock.Anything, model.Reception{ID: opID, DateTime: clientTime, PvzID: pvzID}).Return(nil)
+				syncRepo.On("SaveSyncOperation", mock.Anything, mock.Anything).Return(errors.New("failed to save sync operation"))
+			},
+			expectedError: service.ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			syncRepo := mocks.NewSyncRepository(t)
+			pvzRepo := mocks.NewPvzRepository(t)
+			recRepo := mocks.NewReceptionRepository(t)
+			productRepo := mocks.NewProductRepository(t)
+			tt.mockSetup(syncRepo, pvzRepo, recRepo)
+
+			s := service.NewSyncService(syncRepo, pvzRepo, recRepo, productRepo, mocks.NewStorageCellRepository(t))
+			result, err := s.Sync(context.Background(), model.Pvz{ID: pvzID}, tt.ops)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+type txMarker struct{}
+
+// markingTx отмечает контекст транзакции, по отметке видно, какие запросы выполнены в ней
+type markingTx struct {
+	calls int
+}
+
+func (tx *markingTx) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx.calls++
+	return fn(context.WithValue(ctx, txMarker{}, tx.calls))
+}
+
+// Операция и ее запись в журнал идут в одной транзакции, у каждой операции своя
+func TestSyncService_SyncTransaction(t *testing.T) {
+	pvzID := uuid.New()
+	clientTime := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	openOp := model.SyncOperation{ID: uuid.New(), Type: model.SyncOpenReception, DateTime: clientTime}
+	closeOp := model.SyncOperation{ID: uuid.New(), Type: model.SyncCloseReception, DateTime: clientTime.Add(time.Minute)}
+	opened := &model.Reception{ID: openOp.ID, DateTime: clientTime, PvzID: pvzID}
+
+	inTx := func(n int) any {
+		return mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(txMarker{}) == n })
+	}
+
+	syncRepo := mocks.NewSyncRepository(t)
+	pvzRepo := mocks.NewPvzRepository(t)
+	recRepo := mocks.NewReceptionRepository(t)
+
+	pvzRepo.On("GetPvzByID", mock.Anything, pvzID).Return(&model.Pvz{ID: pvzID, Status: model.PvzStatusActive}, nil)
+
+	syncRepo.On("GetSyncOperation", inTx(1), pvzID, openOp.ID).Return(nil, model.ErrNotFound).Once()
+	recRepo.On("GetLastReception", inTx(1), pvzID).Return(nil, model.ErrNotFound).Once()
+	recRepo.On("InsertReception", inTx(1), *opened).Return(nil).Once()
+	syncRepo.On("SaveSyncOperation", inTx(1), mock.MatchedBy(func(op model.SyncOperation) bool {
+		return op.ID == openOp.ID && op.Applied
+	})).Return(nil).Once()
+
+	// Сбой записи журнала откатывает закрытие вместе с ним
+	syncRepo.On("GetSyncOperation", inTx(2), pvzID, closeOp.ID).Return(nil, model.ErrNotFound).Once()
+	recRepo.On("GetLastReception", inTx(2), pvzID).Return(opened, nil).Once()
+	recRepo.On("CloseReception", inTx(2), opened.ID, model.CloseReasonSync).Return(nil).Once()
+	syncRepo.On("SaveSyncOperation", inTx(2), mock.Anything).Return(errors.New("connection reset")).Once()
+
+	tx := &markingTx{}
+	s := service.NewSyncService(syncRepo, pvzRepo, recRepo, mocks.NewProductRepository(t), mocks.NewStorageCellRepository(t))
+	s.Tx = tx
+
+	_, err := s.Sync(context.Background(), model.Pvz{ID: pvzID}, []model.SyncOperation{openOp, closeOp})
+
+	assert.ErrorIs(t, err, service.ErrInternal)
+	assert.Equal(t, 2, tx.calls)
+}
+
+func TestSyncService_SyncReplay(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	s := service.NewSyncService(repo, repo, repo, repo, repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+	pvz := model.Pvz{ID: pvzID}
+
+	start := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Microsecond)
+	ops := []model.SyncOperation{
+		{ID: uuid.New(), Type: model.SyncOpenReception, DateTime: start},
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: start.Add(time.Minute), TypeProduct: "обувь"},
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: start.Add(2 * time.Minute), TypeProduct: "одежда"},
+		{ID: uuid.New(), Type: model.SyncDeleteProduct, DateTime: start.Add(3 * time.Minute)},
+		{ID: uuid.New(), Type: model.SyncCloseReception, DateTime: start.Add(4 * time.Minute)},
+		// Товар после закрытия приемки - конфликт с состоянием
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: start.Add(5 * time.Minute), TypeProduct: "обувь"},
+	}
+
+	first, err := s.Sync(ctx, pvz, ops)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uuid.UUID{ops[0].ID, ops[1].ID, ops[2].ID, ops[3].ID, ops[4].ID}, first.Applied)
+	require.Len(t, first.Rejected, 1)
+	assert.Equal(t, ops[5].ID, first.Rejected[0].ID)
+	assert.Equal(t, service.CodeReceptionAlreadyClosed, first.Rejected[0].Code)
+
+	// Приемка и товар сохранены с ID и временем клиента
+	require.NotNil(t, first.Reception)
+	assert.Equal(t, ops[0].ID, first.Reception.ID)
+	assert.True(t, start.Equal(first.Reception.DateTime))
+	assert.True(t, first.Reception.IsClosed)
+	assert.Equal(t, model.CloseReasonSync, first.Reception.CloseReason)
+	require.Len(t, first.Reception.Products, 1)
+	assert.Equal(t, ops[1].ID, first.Reception.Products[0].ID)
+	assert.True(t, ops[1].DateTime.Equal(first.Reception.Products[0].DateTime))
+
+	// Повторная отправка журнала ничего не меняет
+	second, err := s.Sync(ctx, pvz, ops)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	products, err := repo.GetProductSliceByReceptionID(ctx, ops[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+}
+
+func TestSyncService_SyncConflicts(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	s := service.NewSyncService(repo, repo, repo, repo, repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	pvz := model.Pvz{ID: pvzID}
+
+	// Пока сканер был без связи, приемку открыли через API
+	online, err := service.NewReceptionService(repo, repo).CreateReception(ctx, model.Reception{PvzID: pvzID})
+	require.NoError(t, err)
+
+	before := online.DateTime.Add(-time.Hour)
+	ops := []model.SyncOperation{
+		{ID: uuid.New(), Type: model.SyncOpenReception, DateTime: before},
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: before, TypeProduct: "обувь"},
+		{ID: uuid.New(), Type: model.SyncDeleteProduct, DateTime: online.DateTime.Add(time.Minute)},
+		{ID: uuid.New(), Type: model.SyncCloseReception, DateTime: time.Now().Add(time.Hour)},
+		{ID: uuid.New(), Type: "reopen", DateTime: online.DateTime.Add(time.Minute)},
+	}
+
+	result, err := s.Sync(ctx, pvz, ops)
+	require.NoError(t, err)
+
+	assert.Empty(t, result.Applied)
+	codes := make([]string, 0, len(result.Rejected))
+	for _, op := range result.Rejected {
+		codes = append(codes, op.Code)
+	}
+	assert.Equal(t, []string{
+		service.CodeReceptionNotClosed,
+		service.CodeSyncOutOfOrder,
+		service.CodeProductNotFound,
+		service.CodeSyncTimeInFuture,
+		service.CodeInvalidSyncOperation,
+	}, codes)
+
+	require.NotNil(t, result.Reception)
+	assert.Equal(t, online.ID, result.Reception.ID)
+	assert.False(t, result.Reception.IsClosed)
+	assert.Empty(t, result.Reception.Products)
+}
+
+func TestSyncService_SyncServiceChecks(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	s := service.NewSyncService(repo, repo, repo, repo, repo)
+
+	pvzID, err := repo.CreatePvz(ctx, "Москва")
+	require.NoError(t, err)
+	pvz := model.Pvz{ID: pvzID}
+
+	limit := 1
+	_, err = service.NewPvzService(repo).UpdatePvz(ctx, model.PvzPatch{ID: pvzID, MaxDailyReceptions: &limit})
+	require.NoError(t, err)
+
+	cellID, err := repo.CreateStorageCell(ctx, model.StorageCell{PvzID: pvzID, Code: "A1", Capacity: 5, SizeClass: model.SizeSmall})
+	require.NoError(t, err)
+
+	start := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	small := model.Dimensions{LengthMM: 100, WidthMM: 100, HeightMM: 100}
+	large := model.Dimensions{LengthMM: 700, WidthMM: 100, HeightMM: 100}
+	ops := []model.SyncOperation{
+		{ID: uuid.New(), Type: model.SyncOpenReception, DateTime: start},
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: start.Add(time.Minute), TypeProduct: "обувь",
+			Barcode: "4006381333931", Dimensions: small},
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: start.Add(2 * time.Minute), TypeProduct: "обувь",
+			Barcode: "4006381333931"},
+		{ID: uuid.New(), Type: model.SyncAddProduct, DateTime: start.Add(3 * time.Minute), TypeProduct: "одежда",
+			Dimensions: large, CellID: cellID},
+		{ID: uuid.New(), Type: model.SyncCloseReception, DateTime: start.Add(4 * time.Minute)},
+		// Вторая приемка за день превышает лимит ПВЗ
+		{ID: uuid.New(), Type: model.SyncOpenReception, DateTime: start.Add(5 * time.Minute)},
+	}
+
+	result, err := s.Sync(ctx, pvz, ops)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uuid.UUID{ops[0].ID, ops[1].ID, ops[4].ID}, result.Applied)
+	codes := make([]string, 0, len(result.Rejected))
+	for _, op := range result.Rejected {
+		codes = append(codes, op.Code)
+	}
+	assert.Equal(t, []string{
+		service.CodeDuplicateBarcode,
+		service.CodeStorageCellTooSmall,
+		service.CodeReceptionLimitReached,
+	}, codes)
+
+	// Товар из журнала размещен в ячейку так же, как через POST /products
+	require.NotNil(t, result.Reception)
+	require.Len(t, result.Reception.Products, 1)
+	assert.Equal(t, cellID, result.Reception.Products[0].CellID)
+	assert.Equal(t, "4006381333931", result.Reception.Products[0].Barcode)
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
+)
+
+func TestTenantService_CreateTenant(t *testing.T) {
+	existing := model.Tenant{ID: tenant.DefaultID, Name: "default"}
+
+	tests := []struct {
+		name          string
+		tenantName    string
+		mockSetup     func(repo *mocks.TenantRepository)
+		expectedError error
+		expectedCode  string
+	}{
+		{
+			name:          "пустое имя",
+			tenantName:    "   ",
+			mockSetup:     func(repo *mocks.TenantRepository) {},
+			expectedError: service.ErrValidation,
+			expectedCode:  service.CodeInvalidTenantName,
+		},
+		{
+			name:       "имя занято",
+			tenantName: "default",
+			mockSetup: func(repo *mocks.TenantRepository) {
+				repo.On("GetTenants", mock.Anything).Return([]model.Tenant{existing}, nil)
+			},
+			expectedError: service.ErrConflict,
+			expectedCode:  service.CodeTenantAlreadyExists,
+		},
+		{
+			name:       "ошибка создания",
+			tenantName: "acme",
+			mockSetup: func(repo *mocks.TenantRepository) {
+				repo.On("GetTenants", mock.Anything).Return([]model.Tenant{existing}, nil)
+				repo.On("CreateTenant", mock.Anything, "acme").Return(nil, errors.New("insert error"))
+			},
+			expectedError: service.ErrInternal,
+			expectedCode:  service.CodeInternal,
+		},
+		{
+			name:       "успешное создание, пробелы по краям отбрасываются",
+			tenantName: " acme ",
+			mockSetup: func(repo *mocks.TenantRepository) {
+				repo.On("GetTenants", mock.Anything).Return([]model.Tenant{existing}, nil)
+				repo.On("CreateTenant", mock.Anything, "acme").Return(&model.Tenant{ID: uuid.New(), Name: "acme"}, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewTenantRepository(t)
+			tt.mockSetup(repo)
+
+			created, err := service.NewTenantService(repo).CreateTenant(context.Background(), model.Tenant{Name: tt.tenantName})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				var serviceErr *service.Error
+				require.ErrorAs(t, err, &serviceErr)
+				assert.Equal(t, tt.expectedCode, serviceErr.Code)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "acme", created.Name)
+		})
+	}
+}
+
+// Задача запускается для каждого оператора с его контекстом, ошибка одного не останавливает обход
+func TestTenantService_ForEachTenant(t *testing.T) {
+	other := uuid.New()
+	repo := mocks.NewTenantRepository(t)
+	repo.On("GetTenants", mock.Anything).Return([]model.Tenant{{ID: tenant.DefaultID}, {ID: other}}, nil)
+
+	var visited []uuid.UUID
+	failed := errors.New("job failed")
+	err := service.NewTenantService(repo).ForEachTenant(context.Background(), func(ctx context.Context) error {
+		id, ok := tenant.FromContext(ctx)
+		require.True(t, ok)
+		visited = append(visited, id)
+		if id == tenant.DefaultID {
+			return failed
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, failed)
+	assert.Equal(t, []uuid.UUID{tenant.DefaultID, other}, visited)
+}
@@ -0,0 +1,504 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/model"
+	"pvz-service/internal/repository"
+	"pvz-service/internal/repository/memdb"
+	"pvz-service/internal/service"
+	"pvz-service/internal/service/mocks"
+	"pvz-service/internal/tenant"
+	"pvz-service/pkg/netguard"
+	"pvz-service/pkg/webhooksig"
+)
+
+// webhookConfig - по умолчанию без защиты от адресов во внутренней сети: получатели в тестах на httptest
+type webhookConfig struct {
+	maxAttempts int
+	backoff     time.Duration
+	backoffMax  time.Duration
+	denyPrivate bool
+	workers     int
+	passTimeout time.Duration
+}
+
+func (c webhookConfig) GetDeliveryTimeout() time.Duration { return time.Second }
+
+func (c webhookConfig) GetDeliveryWorkers() int { return max(c.workers, 1) }
+
+func (c webhookConfig) GetDeliveryPassTimeout() time.Duration {
+	if c.passTimeout == 0 {
+		return time.Minute
+	}
+	return c.passTimeout
+}
+
+func (c webhookConfig) GetMaxAttempts() int { return c.maxAttempts }
+
+func (c webhookConfig) GetRetryBackoff() (time.Duration, time.Duration) {
+	return c.backoff, c.backoffMax
+}
+
+func (c webhookConfig) GetAllowPrivateTargets() bool { return !c.denyPrivate }
+
+var defaultWebhookConfig = webhookConfig{maxAttempts: 3, backoff: time.Minute, backoffMax: time.Hour}
+
+// receivedHook - запрос, который получил тестовый сервер партнера
+type receivedHook struct {
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// receiver - сервер партнера: проверяет подпись и отвечает status
+type receiver struct {
+	*httptest.Server
+	secret string
+
+	mu     sync.Mutex
+	status int
+	hooks  []receivedHook
+}
+
+func newReceiver(t *testing.T, secret string, status int) *receiver {
+	rcv := &receiver{secret: secret, status: status}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := webhooksig.Verify(rcv.secret, r.Header.Get(webhooksig.SignatureHeader), r.Header.Get(webhooksig.TimestampHeader),
+			body, time.Now(), webhooksig.DefaultTolerance)
+
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		rcv.hooks = append(rcv.hooks, receivedHook{header: r.Header.Clone(), body: body, err: err})
+		w.WriteHeader(rcv.status)
+	}))
+	t.Cleanup(rcv.Close)
+	return rcv
+}
+
+func (r *receiver) received() []receivedHook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedHook(nil), r.hooks...)
+}
+
+func (r *receiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func newWebhookService(t *testing.T, cfg webhookConfig) (*service.WebhookService, *repository.MemoryRepository, uuid.UUID) {
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	pvzID, err := repo.CreatePvz(tenant.WithID(context.Background(), tenant.DefaultID), "Москва")
+	require.NoError(t, err)
+	return service.NewWebhookService(repo, repo, cfg), repo, pvzID
+}
+
+func subscribe(t *testing.T, s *service.WebhookService, url string, pvzIDs []uuid.UUID, events ...string) *model.WebhookSubscription {
+	sub, err := s.CreateWebhook(tenant.WithID(context.Background(), tenant.DefaultID), model.WebhookSubscription{
+		URL: url, EventTypes: events, PvzIDs: pvzIDs, Secret: "partner-secret-0123",
+	})
+	require.NoError(t, err)
+	return sub
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	s, _, pvzID := newWebhookService(t, defaultWebhookConfig)
+
+	t.Run("секрет генерируется, события без повторов", func(t *testing.T) {
+		sub, err := s.CreateWebhook(ctx, model.WebhookSubscription{
+			URL:        "https://partner.example/hooks",
+			EventTypes: []string{model.WebhookEventReceptionClosed, model.WebhookEventReceptionCreated, model.WebhookEventReceptionClosed},
+			PvzIDs:     []uuid.UUID{pvzID},
+		})
+		require.NoError(t, err)
+		assert.Len(t, sub.Secret, 64)
+		assert.Equal(t, []string{model.WebhookEventReceptionClosed, model.WebhookEventReceptionCreated}, sub.EventTypes)
+		assert.Equal(t, []uuid.UUID{pvzID}, sub.PvzIDs)
+	})
+
+	tests := []struct {
+		name string
+		sub  model.WebhookSubscription
+		kind error
+		code string
+	}{
+		{
+			name: "не http адрес",
+			sub:  model.WebhookSubscription{URL: "ftp://partner.example", EventTypes: []string{model.WebhookEventReceptionClosed}},
+			kind: service.ErrValidation,
+			code: service.CodeInvalidWebhookURL,
+		},
+		{
+			name: "неизвестное событие",
+			sub:  model.WebhookSubscription{URL: "https://partner.example", EventTypes: []string{"pvz.deleted"}},
+			kind: service.ErrValidation,
+			code: service.CodeInvalidWebhookEvent,
+		},
+		{
+			name: "на тестовое событие не подписываются",
+			sub:  model.WebhookSubscription{URL: "https://partner.example", EventTypes: []string{model.WebhookEventTest}},
+			kind: service.ErrValidation,
+			code: service.CodeInvalidWebhookEvent,
+		},
+		{
+			name: "неизвестный ПВЗ",
+			sub: model.WebhookSubscription{
+				URL: "https://partner.example", EventTypes: []string{model.WebhookEventReceptionClosed}, PvzIDs: []uuid.UUID{uuid.New()},
+			},
+			kind: service.ErrNotFound,
+			code: service.CodePvzNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateWebhook(ctx, tt.sub)
+			assert.ErrorIs(t, err, tt.kind)
+
+			var serviceErr *service.Error
+			require.ErrorAs(t, err, &serviceErr)
+			assert.Equal(t, tt.code, serviceErr.Code)
+		})
+	}
+}
+
+func TestWebhookService_PublishAndDeliver(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	s, repo, pvzID := newWebhookService(t, defaultWebhookConfig)
+	otherPvzID, err := repo.CreatePvz(ctx, "Казань")
+	require.NoError(t, err)
+
+	partner := newReceiver(t, "partner-secret-0123", http.StatusNoContent)
+	sub := subscribe(t, s, partner.URL, []uuid.UUID{pvzID}, model.WebhookEventReceptionClosed)
+	everything := newReceiver(t, "partner-secret-0123", http.StatusOK)
+	subscribe(t, s, everything.URL, nil, model.WebhookEventTypes...)
+
+	receptionID := uuid.New()
+	s.Publish(ctx, model.WebhookEvent{
+		Type:  model.WebhookEventReceptionClosed,
+		PvzID: pvzID,
+		Data:  map[string]string{"id": receptionID.String()},
+	})
+	// Чужой ПВЗ и чужое событие до первого получателя не доходят
+	s.Publish(ctx, model.WebhookEvent{Type: model.WebhookEventReceptionClosed, PvzID: otherPvzID})
+	s.Publish(ctx, model.WebhookEvent{Type: model.WebhookEventProductReceived, PvzID: pvzID})
+
+	require.NoError(t, s.DeliverWebhooks(ctx))
+
+	hooks := partner.received()
+	require.Len(t, hooks, 1)
+	assert.NoError(t, hooks[0].err)
+	assert.Equal(t, model.WebhookEventReceptionClosed, hooks[0].header.Get(webhooksig.EventHeader))
+	assert.Equal(t, "application/json", hooks[0].header.Get("Content-Type"))
+
+	var payload struct {
+		ID    uuid.UUID         `json:"id"`
+		Type  string            `json:"type"`
+		PvzID uuid.UUID         `json:"pvzId"`
+		Data  map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(hooks[0].body, &payload))
+	assert.Equal(t, model.WebhookEventReceptionClosed, payload.Type)
+	assert.Equal(t, pvzID, payload.PvzID)
+	assert.Equal(t, receptionID.String(), payload.Data["id"])
+	assert.Equal(t, payload.ID.String(), hooks[0].header.Get(webhooksig.IDHeader))
+
+	assert.Len(t, everything.received(), 3)
+
+	deliveries, err := s.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{SubscriptionID: sub.ID, Page: 1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].ResponseStatus)
+	assert.False(t, deliveries[0].DeliveredAt.IsZero())
+
+	// Доставленное повторно не отправляется
+	require.NoError(t, s.DeliverWebhooks(ctx))
+	assert.Len(t, partner.received(), 1)
+}
+
+func TestWebhookService_RetryBackoffAndDeadLetter(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	cfg := webhookConfig{maxAttempts: 4, backoff: 20 * time.Millisecond, backoffMax: 50 * time.Millisecond}
+	s, _, pvzID := newWebhookService(t, cfg)
+
+	partner := newReceiver(t, "partner-secret-0123", http.StatusServiceUnavailable)
+	sub := subscribe(t, s, partner.URL, nil, model.WebhookEventReceptionCreated)
+	s.Publish(ctx, model.WebhookEvent{Type: model.WebhookEventReceptionCreated, PvzID: pvzID})
+
+	deliveryOf := func() model.WebhookDelivery {
+		deliveries, err := s.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{SubscriptionID: sub.ID, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		return deliveries[0]
+	}
+
+	// Задержки растут вдвое и упираются в потолок: 20ms, 40ms, 50ms
+	for attempt, delay := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond} {
+		time.Sleep(time.Until(deliveryOf().NextAttemptAt))
+
+		before := time.Now()
+		require.NoError(t, s.DeliverWebhooks(ctx))
+		after := time.Now()
+
+		delivery := deliveryOf()
+		assert.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, attempt+1, delivery.Attempts)
+		assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+		assert.Equal(t, "unexpected response status 503", delivery.LastError)
+		assert.WithinRange(t, delivery.NextAttemptAt, before.Add(delay-time.Millisecond), after.Add(delay))
+
+		// До срока следующая попытка не делается
+		require.NoError(t, s.DeliverWebhooks(ctx))
+		assert.Len(t, partner.received(), attempt+1)
+	}
+
+	time.Sleep(time.Until(deliveryOf().NextAttemptAt))
+	require.NoError(t, s.DeliverWebhooks(ctx))
+
+	dead := deliveryOf()
+	assert.Equal(t, model.WebhookDeliveryDead, dead.Status)
+	assert.Equal(t, 4, dead.Attempts)
+	for _, hook := range partner.received() {
+		assert.NoError(t, hook.err)
+	}
+
+	// Ручной повтор возвращает доставку в очередь
+	_, err := s.RetryDelivery(ctx, uuid.New(), dead.ID)
+	assert.ErrorIs(t, err, service.ErrNotFound)
+
+	partner.respond(http.StatusOK)
+	retried, err := s.RetryDelivery(ctx, sub.ID, dead.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryPending, retried.Status)
+	assert.Zero(t, retried.Attempts)
+
+	_, err = s.RetryDelivery(ctx, sub.ID, dead.ID)
+	assert.ErrorIs(t, err, service.ErrConflict)
+
+	require.NoError(t, s.DeliverWebhooks(ctx))
+	delivered := deliveryOf()
+	assert.Equal(t, model.WebhookDeliveryDelivered, delivered.Status)
+	assert.Equal(t, 1, delivered.Attempts)
+	assert.Empty(t, delivered.LastError)
+}
+
+func TestWebhookService_SendTestEvent(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	s, _, _ := newWebhookService(t, defaultWebhookConfig)
+
+	t.Run("получатель принял", func(t *testing.T) {
+		partner := newReceiver(t, "partner-secret-0123", http.StatusOK)
+		sub := subscribe(t, s, partner.URL, nil, model.WebhookEventProductReceived)
+
+		delivery, err := s.SendTestEvent(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryDelivered, delivery.Status)
+		assert.Equal(t, model.WebhookEventTest, delivery.EventType)
+		assert.NotEqual(t, uuid.Nil, delivery.ID)
+
+		hooks := partner.received()
+		require.Len(t, hooks, 1)
+		assert.NoError(t, hooks[0].err)
+		assert.JSONEq(t, `{"webhookId":"`+sub.ID.String()+`"}`, string(mustField(t, hooks[0].body, "data")))
+	})
+
+	t.Run("чужой секрет не проходит проверку подписи", func(t *testing.T) {
+		partner := newReceiver(t, "another-secret-4567", http.StatusOK)
+		sub := subscribe(t, s, partner.URL, nil, model.WebhookEventProductReceived)
+
+		_, err := s.SendTestEvent(ctx, sub.ID)
+		require.NoError(t, err)
+		require.Len(t, partner.received(), 1)
+		assert.ErrorIs(t, partner.received()[0].err, webhooksig.ErrMismatch)
+	})
+
+	t.Run("получатель недоступен, доставка ждет повтора", func(t *testing.T) {
+		partner := newReceiver(t, "partner-secret-0123", http.StatusOK)
+		sub := subscribe(t, s, partner.URL, nil, model.WebhookEventProductReceived)
+		partner.Close()
+
+		delivery, err := s.SendTestEvent(ctx, sub.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Zero(t, delivery.ResponseStatus)
+		assert.NotEmpty(t, delivery.LastError)
+	})
+
+	t.Run("неизвестная подписка", func(t *testing.T) {
+		_, err := s.SendTestEvent(ctx, uuid.New())
+		assert.ErrorIs(t, err, service.ErrNotFound)
+	})
+}
+
+func TestWebhookService_PrivateTargets(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	cfg := defaultWebhookConfig
+	cfg.denyPrivate = true
+
+	t.Run("адрес во внутренней сети не сохраняется", func(t *testing.T) {
+		s, _, _ := newWebhookService(t, cfg)
+
+		for _, url := range []string{
+			"http://127.0.0.1:8080/hooks",
+			"http://[::1]/hooks",
+			"http://169.254.169.254/latest/meta-data",
+			"https://10.0.0.5/hooks",
+			"http://localhost:8080/hooks",
+		} {
+			_, err := s.CreateWebhook(ctx, model.WebhookSubscription{URL: url, EventTypes: []string{model.WebhookEventReceptionClosed}})
+			assertServiceCode(t, err, service.CodeInvalidWebhookURL)
+			assert.ErrorContains(t, err, service.PrivateWebhookURL, url)
+		}
+	})
+
+	// Подписка сохранилась, а имя потом стало указывать внутрь (DNS rebinding):
+	// соединение отклоняется при подключении, запрос до получателя не доходит
+	t.Run("подключение во внутреннюю сеть отклоняется", func(t *testing.T) {
+		s, repo, pvzID := newWebhookService(t, cfg)
+		partner := newReceiver(t, "partner-secret-0123", http.StatusOK)
+
+		subID, err := repo.CreateWebhook(ctx, model.WebhookSubscription{
+			URL: partner.URL, EventTypes: []string{model.WebhookEventReceptionCreated}, Secret: "partner-secret-0123",
+		})
+		require.NoError(t, err)
+
+		s.Publish(ctx, model.WebhookEvent{Type: model.WebhookEventReceptionCreated, PvzID: pvzID})
+		require.NoError(t, s.DeliverWebhooks(ctx))
+
+		assert.Empty(t, partner.received())
+		deliveries, err := s.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{SubscriptionID: subID, Page: 1, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, model.WebhookDeliveryPending, deliveries[0].Status)
+		assert.Contains(t, deliveries[0].LastError, netguard.ErrForbiddenAddress.Error())
+	})
+}
+
+// Проход пишет получателям не больше workers запросов за раз и после passTimeout новых попыток
+// не начинает: оставшиеся доставки уходят на следующем проходе
+func TestWebhookService_DeliverWorkersAndPassTimeout(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	cfg := defaultWebhookConfig
+	cfg.workers, cfg.passTimeout = 2, 300*time.Millisecond
+	s, _, pvzID := newWebhookService(t, cfg)
+
+	var (
+		mu                  sync.Mutex
+		inFlight, maxFlight int
+		received            int
+	)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxFlight = max(maxFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(200 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		received++
+		mu.Unlock()
+	}))
+	t.Cleanup(slow.Close)
+
+	sub := subscribe(t, s, slow.URL, nil, model.WebhookEventReceptionCreated)
+	for range 6 {
+		s.Publish(ctx, model.WebhookEvent{Type: model.WebhookEventReceptionCreated, PvzID: pvzID})
+	}
+
+	// Две волны по два запроса укладываются в проход, третья начиналась бы уже после него
+	require.NoError(t, s.DeliverWebhooks(ctx))
+	mu.Lock()
+	assert.Equal(t, 2, maxFlight)
+	assert.Equal(t, 4, received)
+	mu.Unlock()
+
+	deliveries, err := s.GetWebhookDeliveries(ctx, model.WebhookDeliveryQuery{SubscriptionID: sub.ID, Page: 1, Limit: 10})
+	require.NoError(t, err)
+	var pending int
+	for _, delivery := range deliveries {
+		if delivery.Status == model.WebhookDeliveryPending {
+			assert.Zero(t, delivery.Attempts)
+			pending++
+		}
+	}
+	assert.Equal(t, 2, pending)
+
+	require.NoError(t, s.DeliverWebhooks(ctx))
+	mu.Lock()
+	assert.Equal(t, 6, received)
+	mu.Unlock()
+}
+
+func TestWebhookService_DeliverErrors(t *testing.T) {
+	ctx := context.Background()
+
+	repo := mocks.NewWebhookRepository(t)
+	repo.On("GetDueWebhookDeliveries", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+	s := service.NewWebhookService(repo, mocks.NewPvzRepository(t), defaultWebhookConfig)
+
+	assert.ErrorIs(t, s.DeliverWebhooks(ctx), service.ErrInternal)
+
+	// Ошибка хранилища при публикации не доходит до вызывающего
+	repo.On("GetWebhooks", mock.Anything).Return(nil, errors.New("db down")).Once()
+	s.Publish(ctx, model.WebhookEvent{Type: model.WebhookEventReceptionCreated})
+}
+
+// Сервисы приемок и товаров публикуют события, если подключены к вебхукам
+func TestService_PublishesDomainEvents(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), tenant.DefaultID)
+	repo := repository.NewMemoryRepository(memdb.NewStorage())
+	s := service.NewService(repo, "jwt-secret", defaultWebhookConfig)
+
+	pvz, err := s.AddNewPvz(ctx, model.Pvz{City: "Москва"})
+	require.NoError(t, err)
+
+	partner := newReceiver(t, "partner-secret-0123", http.StatusOK)
+	subscribe(t, s.WebhookService, partner.URL, []uuid.UUID{pvz.ID}, model.WebhookEventTypes...)
+
+	_, err = s.CreateReception(ctx, model.Reception{PvzID: pvz.ID})
+	require.NoError(t, err)
+	_, err = s.AddProduct(ctx, model.Product{TypeProduct: "обувь"}, *pvz)
+	require.NoError(t, err)
+	_, err = s.CloseReception(ctx, model.Reception{PvzID: pvz.ID})
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeliverWebhooks(ctx))
+
+	var events []string
+	for _, hook := range partner.received() {
+		assert.NoError(t, hook.err)
+		events = append(events, hook.header.Get(webhooksig.EventHeader))
+	}
+	assert.ElementsMatch(t, []string{
+		model.WebhookEventReceptionCreated, model.WebhookEventProductReceived, model.WebhookEventReceptionClosed,
+	}, events)
+}
+
+func mustField(t *testing.T, body []byte, field string) json.RawMessage {
+	t.Helper()
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &fields))
+	return fields[field]
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	StorageCellExists       = "storage cell with this code already exists in this pvz"
+	StorageCellNotEmpty     = "storage cell still holds products"
+	FailedStorageCellCreate = "failed to create storage cell"
+	FailedStorageCellDelete = "failed to delete storage cell"
+	FailedStorageCellList   = "failed to get storage cells"
+)
+
+type StorageCellRepository interface {
+	CreateStorageCell(ctx context.Context, cell model.StorageCell) (uuid.UUID, error)
+	DeleteStorageCell(ctx context.Context, id uuid.UUID) error
+	GetStorageCells(ctx context.Context, pvzID uuid.UUID) ([]model.StorageCell, error)
+}
+
+type StorageCellService struct {
+	cellRepository StorageCellRepository
+	pvzRepository  PvzRepository
+}
+
+func NewStorageCellService(cellRepo StorageCellRepository, pvzRepo PvzRepository) *StorageCellService {
+	return &StorageCellService{
+		cellRepository: cellRepo,
+		pvzRepository:  pvzRepo,
+	}
+}
+
+func (s *StorageCellService) CreateStorageCell(ctx context.Context, cell model.StorageCell) (*model.StorageCell, error) {
+	cells, err := s.GetStorageCells(ctx, model.Pvz{ID: cell.PvzID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range cells {
+		if c.Code == cell.Code {
+			return nil, NewConflictError(CodeStorageCellExists, StorageCellExists)
+		}
+	}
+
+	cell.ID, err = s.cellRepository.CreateStorageCell(ctx, cell)
+	if err != nil {
+		return nil, NewInternalError(FailedStorageCellCreate, err)
+	}
+
+	return &cell, nil
+}
+
+// DeleteStorageCell удаляет пустую ячейку ПВЗ
+func (s *StorageCellService) DeleteStorageCell(ctx context.Context, pvz model.Pvz, cellID uuid.UUID) error {
+	cells, err := s.GetStorageCells(ctx, pvz)
+	if err != nil {
+		return err
+	}
+
+	for _, cell := range cells {
+		if cell.ID != cellID {
+			continue
+		}
+		if cell.Occupied > 0 {
+			return NewConflictError(CodeStorageCellNotEmpty, StorageCellNotEmpty)
+		}
+		if err = s.cellRepository.DeleteStorageCell(ctx, cellID); err != nil {
+			return NewInternalError(FailedStorageCellDelete, err)
+		}
+		return nil
+	}
+
+	return NewNotFoundError(CodeStorageCellNotFound, StorageCellNotFound)
+}
+
+// GetStorageCells возвращает ячейки ПВЗ с текущей заполненностью
+func (s *StorageCellService) GetStorageCells(ctx context.Context, pvz model.Pvz) ([]model.StorageCell, error) {
+	if _, err := s.pvzRepository.GetPvzByID(ctx, pvz.ID); err != nil {
+		return nil, pvzError(err)
+	}
+
+	cells, err := s.cellRepository.GetStorageCells(ctx, pvz.ID)
+	if err != nil {
+		return nil, NewInternalError(FailedStorageCellList, err)
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+)
+
+const (
+	PvzNotFound          = "pvz not found"
+	SyncOutOfOrder       = "operation is older than the current state of the reception"
+	SyncTimeInFuture     = "operation time is ahead of the server clock"
+	InvalidSyncOperation = "unknown operation type"
+	FailedSync           = "failed to apply sync operations"
+)
+
+// MaxSyncClockSkew - насколько время операции может опережать часы сервера.
+// Операции из будущего сломали бы порядок приемок и товаров по date_time
+const MaxSyncClockSkew = 5 * time.Minute
+
+type SyncRepository interface {
+	GetSyncOperation(ctx context.Context, pvzID, id uuid.UUID) (*model.SyncOperation, error)
+	SaveSyncOperation(ctx context.Context, op model.SyncOperation) error
+	GetReceptionProducts(ctx context.Context, receptionID uuid.UUID) ([]model.Product, error)
+}
+
+type SyncService struct {
+	syncRepository      SyncRepository
+	pvzRepository       PvzRepository
+	receptionRepository ReceptionRepository
+	productRepository   ProductRepository
+	// Проверки графика, штрихкодов и ячеек общие с онлайн-API
+	receptions *ReceptionService
+	products   *ProductService
+	// Events получает события приемок и товаров, примененных из журнала
+	Events EventPublisher
+	// Tx - транзакция, в которой операция применяется вместе с записью в журнал
+	Tx Transactor
+}
+
+func NewSyncService(syncRepo SyncRepository, pvzRepo PvzRepository, receptionRepo ReceptionRepository,
+	productRepo ProductRepository, cellRepo StorageCellRepository) *SyncService {
+	return &SyncService{
+		syncRepository:      syncRepo,
+		pvzRepository:       pvzRepo,
+		receptionRepository: receptionRepo,
+		productRepository:   productRepo,
+		receptions:          NewReceptionService(receptionRepo, pvzRepo),
+		products:            NewProductService(productRepo, receptionRepo, cellRepo, pvzRepo),
+		Events:              nopPublisher{},
+		Tx:                  nopTransactor{},
+	}
+}
+
+// Sync применяет журнал операций сканера по порядку.
+// Каждая операция применяется и записывается в журнал сервера с результатом в одной транзакции,
+// поэтому повторная отправка того же журнала ничего не меняет.
+// Конфликты с состоянием сервера не прерывают синхронизацию, операция попадает в Rejected
+func (s *SyncService) Sync(ctx context.Context, pvz model.Pvz, ops []model.SyncOperation) (*model.SyncResult, error) {
+	stored, err := s.pvzRepository.GetPvzByID(ctx, pvz.ID)
+	if err != nil {
+		return nil, pvzError(err)
+	}
+
+	result := &model.SyncResult{PvzID: pvz.ID}
+
+	for _, op := range ops {
+		op.PvzID = pvz.ID
+		// Точность TIMESTAMP в Postgres - микросекунды
+		op.DateTime = op.DateTime.UTC().Truncate(time.Microsecond)
+
+		err = s.Tx.InTx(ctx, func(ctx context.Context) error {
+			return s.syncOperation(ctx, stored, &op)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		addSyncResult(result, op)
+	}
+
+	reception, err := s.receptionRepository.GetLastReception(ctx, pvz.ID)
+	switch {
+	case err == nil:
+		reception.Products, err = s.syncRepository.GetReceptionProducts(ctx, reception.ID)
+		if err != nil {
+			return nil, NewInternalError(FailedSync, err)
+		}
+		result.Reception = reception
+	case !errors.Is(err, model.ErrNotFound):
+		return nil, NewInternalError(FailedSync, err)
+	}
+
+	return result, nil
+}
+
+// syncOperation применяет операцию и записывает ее в журнал. Если операция уже есть в журнале ПВЗ,
+// op заменяется сохраненной. Сбой записи откатывает и саму операцию, повтор применит ее заново
+func (s *SyncService) syncOperation(ctx context.Context, pvz *model.Pvz, op *model.SyncOperation) error {
+	saved, err := s.syncRepository.GetSyncOperation(ctx, op.PvzID, op.ID)
+	switch {
+	case err == nil:
+		*op = *saved
+		return nil
+	case !errors.Is(err, model.ErrNotFound):
+		return NewInternalError(FailedSync, err)
+	}
+
+	err = s.apply(ctx, pvz, *op)
+
+	var serviceErr *Error
+	switch {
+	case err == nil:
+		op.Applied = true
+	case errors.As(err, &serviceErr) && !errors.Is(err, ErrInternal):
+		op.Code = serviceErr.Code
+		op.Message = serviceErr.Message
+	default:
+		return err
+	}
+
+	if err = s.syncRepository.SaveSyncOperation(ctx, *op); err != nil {
+		return NewInternalError(FailedSync, err)
+	}
+
+	return nil
+}
+
+func (s *SyncService) apply(ctx context.Context, pvz *model.Pvz, op model.SyncOperation) error {
+	if op.DateTime.After(time.Now().Add(MaxSyncClockSkew)) {
+		return NewConflictError(CodeSyncTimeInFuture, SyncTimeInFuture)
+	}
+
+	// Приостановленный ПВЗ не принимает новые приемки и товары, даже из офлайн-журнала
+	if (op.Type == model.SyncOpenReception || op.Type == model.SyncAddProduct) && !pvz.IsActive() {
+		return NewConflictError(CodePvzNotActive, PvzNotActive)
+	}
+
+	switch op.Type {
+	case model.SyncOpenReception:
+		return s.openReception(ctx, pvz, op)
+	case model.SyncAddProduct:
+		return s.addProduct(ctx, op)
+	case model.SyncDeleteProduct:
+		return s.deleteProduct(ctx, op)
+	case model.SyncCloseReception:
+		return s.closeReception(ctx, op)
+	}
+
+	return NewValidationError(CodeInvalidSyncOperation, InvalidSyncOperation)
+}
+
+func (s *SyncService) openReception(ctx context.Context, pvz *model.Pvz, op model.SyncOperation) error {
+	last, err := s.receptionRepository.GetLastReception(ctx, op.PvzID)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return NewInternalError(FailedReceptionGet, err)
+	}
+	if err == nil {
+		if !last.IsClosed {
+			return NewConflictError(CodeReceptionNotClosed, ReceptionWasNotClosed)
+		}
+		if !op.DateTime.After(last.DateTime) {
+			return NewConflictError(CodeSyncOutOfOrder, SyncOutOfOrder)
+		}
+	}
+
+	// График и дневной лимит проверяются на момент операции, а не синхронизации
+	if err = s.receptions.checkSchedule(ctx, pvz, op.DateTime); err != nil {
+		return err
+	}
+
+	reception := model.Reception{ID: op.ID, DateTime: op.DateTime, PvzID: op.PvzID}
+	if err = s.receptionRepository.InsertReception(ctx, reception); err != nil {
+		return NewInternalError(FailedReceptionCreate, err)
+	}
+
+	s.Events.Publish(ctx, receptionEvent(model.WebhookEventReceptionCreated, reception))
+
+	return nil
+}
+
+func (s *SyncService) addProduct(ctx context.Context, op model.SyncOperation) error {
+	reception, err := s.currentReception(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	if last, err := s.productRepository.GetLastProduct(ctx, reception.ID); err == nil && !op.DateTime.After(last.DateTime) {
+		return NewConflictError(CodeSyncOutOfOrder, SyncOutOfOrder)
+	}
+
+	if err = s.products.checkBarcode(ctx, op.Barcode, reception.ID); err != nil {
+		return err
+	}
+
+	product := model.Product{
+		ID:          op.ID,
+		DateTime:    op.DateTime,
+		TypeProduct: op.TypeProduct,
+		ReceptionID: reception.ID,
+		Barcode:     op.Barcode,
+		SKU:         op.SKU,
+		WeightGrams: op.WeightGrams,
+		Dimensions:  op.Dimensions,
+		CellID:      op.CellID,
+	}
+
+	product.CellID, err = s.products.placeProduct(ctx, product, op.PvzID)
+	if err != nil {
+		return err
+	}
+
+	if err = s.productRepository.InsertProduct(ctx, product); err != nil {
+		return NewInternalError(FailedProductCreate, err)
+	}
+
+	s.Events.Publish(ctx, productEvent(product, op.PvzID))
+
+	return nil
+}
+
+func (s *SyncService) deleteProduct(ctx context.Context, op model.SyncOperation) error {
+	reception, err := s.currentReception(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	product, err := s.productRepository.GetLastProduct(ctx, reception.ID)
+	if err != nil {
+		return NewNotFoundError(CodeProductNotFound, ProductNotFound)
+	}
+
+	if op.DateTime.Before(product.DateTime) {
+		return NewConflictError(CodeSyncOutOfOrder, SyncOutOfOrder)
+	}
+
+	if err = s.productRepository.DeleteProductByID(ctx, product.ID); err != nil {
+		return NewInternalError(FailedProductDelete, err)
+	}
+
+	return nil
+}
+
+func (s *SyncService) closeReception(ctx context.Context, op model.SyncOperation) error {
+	reception, err := s.currentReception(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	if err = s.receptionRepository.CloseReception(ctx, reception.ID, model.CloseReasonSync); err != nil {
+		return NewInternalError(FailedReceptionClose, err)
+	}
+
+	reception.IsClosed, reception.CloseReason = true, model.CloseReasonSync
+	s.Events.Publish(ctx, receptionEvent(model.WebhookEventReceptionClosed, *reception))
+
+	return nil
+}
+
+// currentReception возвращает открытую приемку ПВЗ, начатую не позже операции
+func (s *SyncService) currentReception(ctx context.Context, op model.SyncOperation) (*model.Reception, error) {
+	reception, err := s.receptionRepository.GetLastReception(ctx, op.PvzID)
+	if err != nil {
+		return nil, lastReceptionError(err)
+	}
+
+	if reception.IsClosed {
+		return nil, NewConflictError(CodeReceptionAlreadyClosed, ReceptionAlreadyClosed)
+	}
+
+	if op.DateTime.Before(reception.DateTime) {
+		return nil, NewConflictError(CodeSyncOutOfOrder, SyncOutOfOrder)
+	}
+
+	return reception, nil
+}
+
+func addSyncResult(result *model.SyncResult, op model.SyncOperation) {
+	if op.Applied {
+		result.Applied = append(result.Applied, op.ID)
+		return
+	}
+	result.Rejected = append(result.Rejected, op)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/internal/tenant"
+)
+
+const (
+	TenantNotFound      = "tenant not found"
+	TenantAlreadyExists = "tenant with this name already exists"
+	InvalidTenantName   = "tenant name must not be empty"
+	FailedTenantCreate  = "failed to create tenant"
+	FailedTenantList    = "failed to get tenants"
+	FailedTenantUpdate  = "failed to update tenant"
+)
+
+type TenantRepository interface {
+	CreateTenant(ctx context.Context, name string) (*model.Tenant, error)
+	GetTenantByID(ctx context.Context, id uuid.UUID) (*model.Tenant, error)
+	GetTenants(ctx context.Context) ([]model.Tenant, error)
+	SetTenantRegistration(ctx context.Context, id uuid.UUID, open bool) error
+}
+
+// TenantService управляет операторами маркетплейсов. Операторов заводит администратор сервиса
+type TenantService struct {
+	tenantRepository TenantRepository
+}
+
+func NewTenantService(repo TenantRepository) *TenantService {
+	return &TenantService{
+		tenantRepository: repo,
+	}
+}
+
+func (s *TenantService) CreateTenant(ctx context.Context, t model.Tenant) (*model.Tenant, error) {
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		return nil, NewValidationError(CodeInvalidTenantName, InvalidTenantName)
+	}
+
+	tenants, err := s.tenantRepository.GetTenants(ctx)
+	if err != nil {
+		return nil, NewInternalError(FailedTenantCreate, err)
+	}
+	for _, existing := range tenants {
+		if existing.Name == name {
+			return nil, NewConflictError(CodeTenantAlreadyExists, TenantAlreadyExists)
+		}
+	}
+
+	created, err := s.tenantRepository.CreateTenant(ctx, name)
+	if err != nil {
+		return nil, NewInternalError(FailedTenantCreate, err)
+	}
+
+	return created, nil
+}
+
+func (s *TenantService) GetTenants(ctx context.Context) ([]model.Tenant, error) {
+	tenants, err := s.tenantRepository.GetTenants(ctx)
+	if err != nil {
+		return nil, NewInternalError(FailedTenantList, err)
+	}
+
+	return tenants, nil
+}
+
+// SetRegistration открывает или закрывает самостоятельную регистрацию пользователей у оператора
+func (s *TenantService) SetRegistration(ctx context.Context, id uuid.UUID, open bool) (*model.Tenant, error) {
+	if err := s.tenantRepository.SetTenantRegistration(ctx, id, open); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, NewNotFoundError(CodeTenantNotFound, TenantNotFound)
+		}
+		return nil, NewInternalError(FailedTenantUpdate, err)
+	}
+
+	updated, err := s.tenantRepository.GetTenantByID(ctx, id)
+	if err != nil {
+		return nil, NewInternalError(FailedTenantUpdate, err)
+	}
+
+	return updated, nil
+}
+
+// ForEachTenant запускает run с контекстом каждого оператора по очереди. Так фоновые задачи
+// обходят всех операторов, а хранилище по-прежнему видит данные только одного из них.
+// Ошибка одного оператора не мешает остальным
+func (s *TenantService) ForEachTenant(ctx context.Context, run func(ctx context.Context) error) error {
+	tenants, err := s.tenantRepository.GetTenants(ctx)
+	if err != nil {
+		return NewInternalError(FailedTenantList, err)
+	}
+
+	var errs []error
+	for _, t := range tenants {
+		if err := run(tenant.WithID(ctx, t.ID)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
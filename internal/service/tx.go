@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+// Transactor выполняет fn в одной транзакции хранилища: репозитории, вызванные с ctx из fn,
+// работают в ней. Ошибка fn откатывает транзакцию
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// nopTransactor - транзакции по умолчанию, пока хранилище не подключено: fn выполняется как есть
+type nopTransactor struct{}
+
+func (nopTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,546 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	log "log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"pvz-service/internal/model"
+	"pvz-service/pkg/netguard"
+	"pvz-service/pkg/webhooksig"
+)
+
+const (
+	WebhookNotFound         = "webhook subscription not found"
+	WebhookDeliveryNotFound = "webhook delivery not found"
+	WebhookDeliveryNotDead  = "only dead deliveries can be retried"
+	InvalidWebhookURL       = "webhook url must be an absolute http or https url"
+	PrivateWebhookURL       = "webhook url must point to a public address"
+	UnresolvedWebhookURL    = "webhook url host cannot be resolved"
+	InvalidWebhookEventType = "unknown webhook event type"
+	FailedWebhookCreate     = "failed to create webhook subscription"
+	FailedWebhookGet        = "failed to get webhook subscription"
+	FailedWebhookList       = "failed to get webhook subscriptions"
+	FailedWebhookDelete     = "failed to delete webhook subscription"
+	FailedWebhookDeliveries = "failed to get webhook deliveries"
+	FailedWebhookDeliver    = "failed to deliver webhooks"
+	FailedWebhookTestEvent  = "failed to send test event"
+	FailedWebhookRetry      = "failed to retry webhook delivery"
+)
+
+const (
+	webhookUserAgent         = "pvz-service-webhooks"
+	webhookSecretBytes       = 32
+	webhookDeliveryBatch     = 100
+	webhookResponseBodyLimit = 64 << 10
+	webhookLastErrorLimit    = 512
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, sub model.WebhookSubscription) (uuid.UUID, error)
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.WebhookSubscription, error)
+	GetWebhooks(ctx context.Context) ([]model.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	CreateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) (uuid.UUID, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+	GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error)
+	GetDueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, query model.WebhookDeliveryQuery) ([]model.WebhookDelivery, error)
+}
+
+// WebhookConfig - таймаут запроса к получателю, параллельность и длительность прохода доставки,
+// расписание повторов и доступ к адресам во внутренней сети
+type WebhookConfig interface {
+	GetDeliveryTimeout() time.Duration
+	GetDeliveryWorkers() int
+	GetDeliveryPassTimeout() time.Duration
+	GetMaxAttempts() int
+	GetRetryBackoff() (base time.Duration, max time.Duration)
+	GetAllowPrivateTargets() bool
+}
+
+// EventPublisher принимает доменные события для исходящих вебхуков.
+// Публикация не должна ломать операцию, которая породила событие, поэтому ошибок не возвращает
+type EventPublisher interface {
+	Publish(ctx context.Context, event model.WebhookEvent)
+}
+
+// nopPublisher - публикатор по умолчанию, пока вебхуки не подключены
+type nopPublisher struct{}
+
+func (nopPublisher) Publish(context.Context, model.WebhookEvent) {}
+
+// webhookPayload - тело запроса к получателю
+type webhookPayload struct {
+	ID         uuid.UUID `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurredAt"`
+	PvzID      string    `json:"pvzId,omitempty"`
+	Data       any       `json:"data"`
+}
+
+type receptionEventData struct {
+	ID          uuid.UUID `json:"id"`
+	DateTime    time.Time `json:"dateTime"`
+	PvzID       uuid.UUID `json:"pvzId"`
+	Status      string    `json:"status"`
+	CloseReason string    `json:"closeReason,omitempty"`
+}
+
+type productEventData struct {
+	ID          uuid.UUID `json:"id"`
+	DateTime    time.Time `json:"dateTime"`
+	Type        string    `json:"type"`
+	ReceptionID uuid.UUID `json:"receptionId"`
+	Barcode     string    `json:"barcode,omitempty"`
+}
+
+type testEventData struct {
+	WebhookID uuid.UUID `json:"webhookId"`
+}
+
+func receptionEvent(eventType string, reception model.Reception) model.WebhookEvent {
+	return model.WebhookEvent{
+		Type:  eventType,
+		PvzID: reception.PvzID,
+		Data: receptionEventData{
+			ID:          reception.ID,
+			DateTime:    reception.DateTime,
+			PvzID:       reception.PvzID,
+			Status:      reception.Status(),
+			CloseReason: reception.CloseReason,
+		},
+	}
+}
+
+func productEvent(product model.Product, pvzID uuid.UUID) model.WebhookEvent {
+	return model.WebhookEvent{
+		Type:  model.WebhookEventProductReceived,
+		PvzID: pvzID,
+		Data: productEventData{
+			ID:          product.ID,
+			DateTime:    product.DateTime,
+			Type:        product.TypeProduct,
+			ReceptionID: product.ReceptionID,
+			Barcode:     product.Barcode,
+		},
+	}
+}
+
+type WebhookService struct {
+	webhookRepository WebhookRepository
+	pvzRepository     PvzRepository
+	config            WebhookConfig
+	client            *http.Client
+}
+
+func NewWebhookService(repo WebhookRepository, pvzRepo PvzRepository, cfg WebhookConfig) *WebhookService {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.GetAllowPrivateTargets() {
+		// Адрес проверяется после резолва при каждом подключении, поэтому имя, которое
+		// после создания подписки стало указывать во внутреннюю сеть, не пройдет.
+		// Прокси из окружения отключен: иначе проверялся бы адрес прокси, а не получателя
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   netguard.Control,
+		}).DialContext
+	}
+
+	return &WebhookService{
+		webhookRepository: repo,
+		pvzRepository:     pvzRepo,
+		config:            cfg,
+		client: &http.Client{
+			Timeout:   cfg.GetDeliveryTimeout(),
+			Transport: transport,
+			// Редирект считается неудачной попыткой: подпись выдана конкретному адресу
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// CreateWebhook создает подписку. Без секрета в запросе генерируется случайный,
+// клиент видит его только в ответе на создание
+func (s *WebhookService) CreateWebhook(ctx context.Context, sub model.WebhookSubscription) (*model.WebhookSubscription, error) {
+	target, err := url.Parse(sub.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, NewValidationError(CodeInvalidWebhookURL, InvalidWebhookURL)
+	}
+
+	if !s.config.GetAllowPrivateTargets() {
+		if err = netguard.CheckHost(ctx, net.DefaultResolver, target.Hostname()); err != nil {
+			if errors.Is(err, netguard.ErrForbiddenAddress) {
+				return nil, NewValidationError(CodeInvalidWebhookURL, PrivateWebhookURL)
+			}
+			return nil, NewValidationError(CodeInvalidWebhookURL, UnresolvedWebhookURL)
+		}
+	}
+
+	for _, eventType := range sub.EventTypes {
+		if !slices.Contains(model.WebhookEventTypes, eventType) {
+			return nil, NewValidationError(CodeInvalidWebhookEvent, InvalidWebhookEventType)
+		}
+	}
+	slices.Sort(sub.EventTypes)
+	sub.EventTypes = slices.Compact(sub.EventTypes)
+
+	for _, pvzID := range sub.PvzIDs {
+		if _, err = s.pvzRepository.GetPvzByID(ctx, pvzID); err != nil {
+			return nil, pvzError(err)
+		}
+	}
+
+	if sub.Secret == "" {
+		if sub.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, NewInternalError(FailedWebhookCreate, err)
+		}
+	}
+
+	id, err := s.webhookRepository.CreateWebhook(ctx, sub)
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookCreate, err)
+	}
+
+	created, err := s.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookCreate, err)
+	}
+
+	return created, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]model.WebhookSubscription, error) {
+	subs, err := s.webhookRepository.GetWebhooks(ctx)
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookList, err)
+	}
+
+	return subs, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом доставок
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.getWebhook(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.webhookRepository.DeleteWebhook(ctx, id); err != nil {
+		return NewInternalError(FailedWebhookDelete, err)
+	}
+
+	return nil
+}
+
+// GetWebhookDeliveries возвращает журнал доставок подписки, новые первыми
+func (s *WebhookService) GetWebhookDeliveries(ctx context.Context, query model.WebhookDeliveryQuery) ([]model.WebhookDelivery, error) {
+	if _, err := s.getWebhook(ctx, query.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepository.GetWebhookDeliveries(ctx, query)
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookDeliveries, err)
+	}
+
+	return deliveries, nil
+}
+
+// SendTestEvent сразу отправляет подписке событие webhook.test и возвращает результат попытки.
+// Доставка попадает в журнал и при неудаче повторяется по общему расписанию
+func (s *WebhookService) SendTestEvent(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	sub, err := s.getWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := newDelivery(sub.ID, model.WebhookEvent{
+		ID:         uuid.New(),
+		Type:       model.WebhookEventTest,
+		OccurredAt: time.Now().UTC(),
+		Data:       testEventData{WebhookID: sub.ID},
+	})
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookTestEvent, err)
+	}
+
+	// Запись создается уже с результатом попытки, чтобы планировщик не отправил ее параллельно
+	s.attempt(ctx, sub, &delivery)
+
+	deliveryID, err := s.webhookRepository.CreateWebhookDelivery(ctx, delivery)
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookTestEvent, err)
+	}
+
+	stored, err := s.webhookRepository.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, NewInternalError(FailedWebhookTestEvent, err)
+	}
+
+	return stored, nil
+}
+
+// RetryDelivery возвращает доставку из dead в очередь с обнуленным счетчиком попыток
+func (s *WebhookService) RetryDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	delivery, err := s.webhookRepository.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, NewNotFoundError(CodeWebhookDeliveryNotFound, WebhookDeliveryNotFound)
+		}
+		return nil, NewInternalError(FailedWebhookRetry, err)
+	}
+	if delivery.SubscriptionID != webhookID {
+		return nil, NewNotFoundError(CodeWebhookDeliveryNotFound, WebhookDeliveryNotFound)
+	}
+
+	if delivery.Status != model.WebhookDeliveryDead {
+		return nil, NewConflictError(CodeWebhookDeliveryNotDead, WebhookDeliveryNotDead)
+	}
+
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+
+	if err = s.webhookRepository.UpdateWebhookDelivery(ctx, *delivery); err != nil {
+		return nil, NewInternalError(FailedWebhookRetry, err)
+	}
+
+	return delivery, nil
+}
+
+// Publish ставит событие в очередь доставки всем подходящим подпискам.
+// Отправляет их DeliverWebhooks на ближайшем проходе планировщика
+func (s *WebhookService) Publish(ctx context.Context, event model.WebhookEvent) {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	subs, err := s.webhookRepository.GetWebhooks(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to publish webhook event", "event", event.Type, log.Any("err", err))
+		return
+	}
+
+	for _, sub := range subs {
+		if !sub.Matches(event.Type, event.PvzID) {
+			continue
+		}
+
+		delivery, err := newDelivery(sub.ID, event)
+		if err == nil {
+			_, err = s.webhookRepository.CreateWebhookDelivery(ctx, delivery)
+		}
+		if err != nil {
+			log.ErrorContext(ctx, "Failed to enqueue webhook delivery",
+				"event", event.Type, "webhook_id", sub.ID.String(), log.Any("err", err))
+		}
+	}
+}
+
+// DeliverWebhooks делает очередную попытку для доставок, которым пора.
+// Получателям пишут GetDeliveryWorkers воркеров. После GetDeliveryPassTimeout новые попытки
+// не начинаются, начатые дожидаются ответа: медленные получатели не держат остальные задачи
+// планировщика, а оставшиеся доставки уйдут на следующем проходе.
+// Неответ получателя - не ошибка прохода, он записывается в доставку.
+// Ошибкой считается только сбой хранилища
+func (s *WebhookService) DeliverWebhooks(ctx context.Context) error {
+	due, err := s.webhookRepository.GetDueWebhookDeliveries(ctx, time.Now().UTC(), webhookDeliveryBatch)
+	if err != nil {
+		return NewInternalError(FailedWebhookDeliver, err)
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	// Подписки читаем заранее, воркеры их только читают
+	subs := make(map[uuid.UUID]*model.WebhookSubscription)
+	for _, delivery := range due {
+		if _, ok := subs[delivery.SubscriptionID]; ok {
+			continue
+		}
+		sub, err := s.webhookRepository.GetWebhookByID(ctx, delivery.SubscriptionID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		subs[delivery.SubscriptionID] = sub
+	}
+
+	pass, cancel := context.WithTimeout(ctx, s.config.GetDeliveryPassTimeout())
+	defer cancel()
+
+	queue := make(chan *model.WebhookDelivery)
+	var wg sync.WaitGroup
+	for range min(s.config.GetDeliveryWorkers(), len(due)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for delivery := range queue {
+				s.attempt(ctx, subs[delivery.SubscriptionID], delivery)
+
+				if err := s.webhookRepository.UpdateWebhookDelivery(ctx, *delivery); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range due {
+		if subs[due[i].SubscriptionID] == nil {
+			continue
+		}
+		select {
+		case queue <- &due[i]:
+		case <-pass.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return NewInternalError(FailedWebhookDeliver, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (s *WebhookService) getWebhook(ctx context.Context, id uuid.UUID) (*model.WebhookSubscription, error) {
+	sub, err := s.webhookRepository.GetWebhookByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return nil, NewNotFoundError(CodeWebhookNotFound, WebhookNotFound)
+		}
+		return nil, NewInternalError(FailedWebhookGet, err)
+	}
+	return sub, nil
+}
+
+// attempt отправляет доставку и записывает в нее результат:
+// 2xx - delivered, иначе следующая попытка через backoff или dead, если попытки кончились
+func (s *WebhookService) attempt(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery) {
+	now := time.Now().UTC()
+
+	delivery.Attempts++
+	delivery.ResponseStatus, delivery.LastError = 0, ""
+
+	status, err := s.post(ctx, sub, delivery, now)
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		delivery.Status = model.WebhookDeliveryDelivered
+		delivery.DeliveredAt = now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > webhookLastErrorLimit {
+		delivery.LastError = delivery.LastError[:webhookLastErrorLimit]
+	}
+
+	if delivery.Attempts >= s.config.GetMaxAttempts() {
+		delivery.Status = model.WebhookDeliveryDead
+		return
+	}
+
+	delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+}
+
+func (s *WebhookService) post(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhooksig.SignatureHeader, webhooksig.Sign(sub.Secret, now, delivery.Payload))
+	req.Header.Set(webhooksig.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(webhooksig.EventHeader, delivery.EventType)
+	req.Header.Set(webhooksig.IDHeader, delivery.EventID.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Дочитываем тело, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseBodyLimit))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff - задержка перед попыткой attempts+1: base * 2^(attempts-1), не больше max
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	base, ceiling := s.config.GetRetryBackoff()
+
+	delay := base
+	for i := 1; i < attempts && delay < ceiling; i++ {
+		delay *= 2
+	}
+
+	return min(delay, ceiling)
+}
+
+func newDelivery(subscriptionID uuid.UUID, event model.WebhookEvent) (model.WebhookDelivery, error) {
+	payload := webhookPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	}
+	if event.PvzID != uuid.Nil {
+		payload.PvzID = event.PvzID.String()
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return model.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        body,
+		Status:         model.WebhookDeliveryPending,
+		NextAttemptAt:  event.OccurredAt.UTC(),
+	}, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,26 @@
+// Package tenant передает оператора маркетплейса, чьи данные обрабатывает запрос,
+// от авторизации до хранилища
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DefaultID - оператор, к которому миграция отнесла данные, созданные до разделения,
+// и которого получают токены без tenantId
+var DefaultID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type ctxKey struct{}
+
+// WithID возвращает контекст с оператором id
+func WithID(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает оператора из контекста. Хранилища без него не работают
+func FromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(ctxKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
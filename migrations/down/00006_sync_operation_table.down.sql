@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS sync_operation;
+
+DELETE FROM schema_version WHERE version = 6;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_product_barcode;
+DROP INDEX IF EXISTS uq_product_reception_id_barcode;
+
+ALTER TABLE product
+    DROP COLUMN IF EXISTS barcode,
+    DROP COLUMN IF EXISTS sku,
+    DROP COLUMN IF EXISTS weight_grams,
+    DROP COLUMN IF EXISTS length_mm,
+    DROP COLUMN IF EXISTS width_mm,
+    DROP COLUMN IF EXISTS height_mm;
+
+DELETE FROM schema_version WHERE version = 7;
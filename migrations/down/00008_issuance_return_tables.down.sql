@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS product_return;
+DROP TABLE IF EXISTS issuance;
+
+DELETE FROM schema_version WHERE version = 8;
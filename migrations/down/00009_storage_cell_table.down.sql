@@ -0,0 +1,5 @@
+ALTER TABLE product DROP COLUMN IF EXISTS cell_id;
+
+DROP TABLE IF EXISTS storage_cell;
+
+DELETE FROM schema_version WHERE version = 9;
@@ -0,0 +1,11 @@
+ALTER TABLE pvz
+    DROP CONSTRAINT IF EXISTS chk_pvz_coordinates,
+    DROP COLUMN IF EXISTS reception_override_until,
+    DROP COLUMN IF EXISTS max_daily_receptions,
+    DROP COLUMN IF EXISTS working_hours,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS address;
+
+DELETE FROM schema_version WHERE version = 10;
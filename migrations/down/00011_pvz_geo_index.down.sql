@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pvz_latitude_longitude;
+
+DELETE FROM schema_version WHERE version = 11;
@@ -0,0 +1,24 @@
+ALTER TABLE storage_cell
+    DROP CONSTRAINT IF EXISTS fk_storage_cell_pvz_id,
+    ADD CONSTRAINT fk_storage_cell_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE;
+
+ALTER TABLE product_return
+    DROP CONSTRAINT IF EXISTS fk_return_pvz_id,
+    ADD CONSTRAINT fk_return_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE;
+
+ALTER TABLE issuance
+    DROP CONSTRAINT IF EXISTS fk_issuance_pvz_id,
+    ADD CONSTRAINT fk_issuance_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE;
+
+ALTER TABLE sync_operation
+    DROP CONSTRAINT IF EXISTS fk_sync_pvz_id,
+    ADD CONSTRAINT fk_sync_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE;
+
+ALTER TABLE reception
+    DROP CONSTRAINT IF EXISTS fk_pvz_id,
+    ADD CONSTRAINT fk_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE;
+
+ALTER TABLE pvz
+    DROP COLUMN IF EXISTS status;
+
+DELETE FROM schema_version WHERE version = 12;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_reception_open_date_time;
+
+ALTER TABLE reception
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS flagged_at,
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS close_reason;
+
+DELETE FROM schema_version WHERE version = 13;
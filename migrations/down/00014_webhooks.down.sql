@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook_subscription;
+
+DELETE FROM schema_version WHERE version = 14;
@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS trg_product_touch_pvz ON product;
+DROP TRIGGER IF EXISTS trg_storage_cell_touch_pvz ON storage_cell;
+DROP TRIGGER IF EXISTS trg_product_return_touch_pvz ON product_return;
+DROP TRIGGER IF EXISTS trg_issuance_touch_pvz ON issuance;
+DROP TRIGGER IF EXISTS trg_reception_touch_pvz ON reception;
+DROP TRIGGER IF EXISTS trg_pvz_changed_at ON pvz;
+
+DROP FUNCTION IF EXISTS pvz_touch_by_reception_id();
+DROP FUNCTION IF EXISTS pvz_touch_by_pvz_id();
+DROP FUNCTION IF EXISTS pvz_set_changed_at();
+
+ALTER TABLE pvz
+    DROP COLUMN IF EXISTS changed_at;
+
+DELETE FROM schema_version WHERE version = 15;
@@ -0,0 +1,24 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'pvz', 'reception', 'product', 'sync_operation', 'issuance', 'product_return',
+        'storage_cell', 'webhook_subscription', 'webhook_delivery'
+    ] LOOP
+        EXECUTE format('REVOKE ALL ON %I FROM pvz_tenant', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS tenant_id', t);
+    END LOOP;
+END
+$$;
+
+DROP ROLE IF EXISTS pvz_tenant;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenant;
+
+DELETE FROM schema_version WHERE version = 16;
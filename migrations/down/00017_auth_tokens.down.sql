@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS auth_token;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS email_verified_at;
+
+DELETE FROM schema_version WHERE version = 17;
@@ -0,0 +1,2 @@
+-- Прежние пароли не восстанавливаются: /dummyLogin в них не нуждается
+DELETE FROM schema_version WHERE version = 18;
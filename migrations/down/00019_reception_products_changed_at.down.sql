@@ -0,0 +1,31 @@
+DROP TRIGGER IF EXISTS trg_reception_update_touch_pvz ON reception;
+DROP TRIGGER IF EXISTS trg_reception_touch_pvz ON reception;
+CREATE TRIGGER trg_reception_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON reception
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_pvz_id();
+
+DROP TRIGGER IF EXISTS trg_product_touch_reception ON product;
+DROP FUNCTION IF EXISTS reception_touch_products();
+
+CREATE OR REPLACE FUNCTION pvz_touch_by_reception_id() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE pvz SET changed_at = clock_timestamp()
+        WHERE id = (SELECT pvz_id FROM reception WHERE id = OLD.reception_id);
+    ELSE
+        UPDATE pvz SET changed_at = clock_timestamp()
+        WHERE id = (SELECT pvz_id FROM reception WHERE id = NEW.reception_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_product_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON product
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_reception_id();
+
+DROP INDEX IF EXISTS idx_reception_products_changed_at;
+DROP INDEX IF EXISTS idx_reception_pvz_id_products_changed_at;
+
+ALTER TABLE reception
+    DROP COLUMN IF EXISTS products_changed_at;
+
+DELETE FROM schema_version WHERE version = 19;
@@ -0,0 +1,4 @@
+ALTER TABLE tenant
+    DROP COLUMN IF EXISTS open_registration;
+
+DELETE FROM schema_version WHERE version = 20;
@@ -0,0 +1,13 @@
+CREATE INDEX IF NOT EXISTS idx_sync_operation_pvz_id ON sync_operation(pvz_id);
+
+-- Из операций с одинаковым UUID в разных ПВЗ остается самая ранняя
+DELETE FROM sync_operation a
+    USING sync_operation b
+WHERE a.id = b.id
+  AND (a.synced_at, a.pvz_id) > (b.synced_at, b.pvz_id);
+
+ALTER TABLE sync_operation
+    DROP CONSTRAINT IF EXISTS sync_operation_pkey,
+    ADD CONSTRAINT sync_operation_pkey PRIMARY KEY (id);
+
+DELETE FROM schema_version WHERE version = 21;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS sync_operation (
+                                              id UUID PRIMARY KEY,
+                                              pvz_id UUID NOT NULL,
+                                              type VARCHAR(32) NOT NULL,
+                                              client_time TIMESTAMP NOT NULL,
+                                              type_product VARCHAR(255) NOT NULL DEFAULT '',
+                                              applied BOOLEAN NOT NULL,
+                                              code VARCHAR(64) NOT NULL DEFAULT '',
+                                              message TEXT NOT NULL DEFAULT '',
+                                              synced_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                              CONSTRAINT fk_sync_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_operation_pvz_id ON sync_operation(pvz_id);
+
+INSERT INTO schema_version (version) VALUES (6) ON CONFLICT DO NOTHING;
@@ -0,0 +1,14 @@
+ALTER TABLE product
+    ADD COLUMN IF NOT EXISTS barcode VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sku VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS weight_grams INTEGER NOT NULL DEFAULT 0 CHECK (weight_grams >= 0),
+    ADD COLUMN IF NOT EXISTS length_mm INTEGER NOT NULL DEFAULT 0 CHECK (length_mm >= 0),
+    ADD COLUMN IF NOT EXISTS width_mm INTEGER NOT NULL DEFAULT 0 CHECK (width_mm >= 0),
+    ADD COLUMN IF NOT EXISTS height_mm INTEGER NOT NULL DEFAULT 0 CHECK (height_mm >= 0);
+
+-- Один и тот же штрихкод нельзя отсканировать в приемку дважды
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_reception_id_barcode
+    ON product (reception_id, barcode) WHERE barcode <> '';
+CREATE INDEX IF NOT EXISTS idx_product_barcode ON product(barcode) WHERE barcode <> '';
+
+INSERT INTO schema_version (version) VALUES (7) ON CONFLICT DO NOTHING;
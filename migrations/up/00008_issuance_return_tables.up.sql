@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS issuance (
+                                        id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                        product_id UUID NOT NULL,
+                                        pvz_id UUID NOT NULL,
+                                        issued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                        employee_id VARCHAR(255) NOT NULL,
+                                        confirmation_code VARCHAR(16) NOT NULL,
+                                        CONSTRAINT fk_issuance_product_id FOREIGN KEY (product_id) REFERENCES product(id) ON DELETE CASCADE,
+                                        CONSTRAINT fk_issuance_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_issuance_product_id_issued_at ON issuance(product_id, issued_at);
+
+CREATE TABLE IF NOT EXISTS product_return (
+                                              id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                              product_id UUID NOT NULL,
+                                              pvz_id UUID NOT NULL,
+                                              returned_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                                              employee_id VARCHAR(255) NOT NULL,
+                                              reason TEXT NOT NULL,
+                                              CONSTRAINT fk_return_product_id FOREIGN KEY (product_id) REFERENCES product(id) ON DELETE CASCADE,
+                                              CONSTRAINT fk_return_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_return_product_id_returned_at ON product_return(product_id, returned_at);
+
+INSERT INTO schema_version (version) VALUES (8) ON CONFLICT DO NOTHING;
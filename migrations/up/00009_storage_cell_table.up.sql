@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS storage_cell (
+                                            id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+                                            pvz_id UUID NOT NULL,
+                                            code VARCHAR(32) NOT NULL,
+                                            capacity INTEGER NOT NULL CHECK (capacity > 0),
+                                            size_class VARCHAR(16) NOT NULL CHECK (size_class IN ('small', 'medium', 'large')),
+                                            CONSTRAINT fk_storage_cell_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE CASCADE,
+                                            CONSTRAINT uq_storage_cell_pvz_id_code UNIQUE (pvz_id, code)
+);
+
+ALTER TABLE product
+    ADD COLUMN IF NOT EXISTS cell_id UUID NULL,
+    ADD CONSTRAINT fk_product_cell_id FOREIGN KEY (cell_id) REFERENCES storage_cell(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_product_cell_id ON product(cell_id) WHERE cell_id IS NOT NULL;
+
+INSERT INTO schema_version (version) VALUES (9) ON CONFLICT DO NOTHING;
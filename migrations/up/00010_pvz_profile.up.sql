@@ -0,0 +1,11 @@
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION NULL CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION NULL CHECK (longitude BETWEEN -180 AND 180),
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'Europe/Moscow',
+    ADD COLUMN IF NOT EXISTS working_hours JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS max_daily_receptions INTEGER NOT NULL DEFAULT 0 CHECK (max_daily_receptions >= 0),
+    ADD COLUMN IF NOT EXISTS reception_override_until TIMESTAMP NULL,
+    ADD CONSTRAINT chk_pvz_coordinates CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+INSERT INTO schema_version (version) VALUES (10) ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+-- Поиск ближайших ПВЗ сначала отсекает ПВЗ вне ограничивающего прямоугольника
+CREATE INDEX IF NOT EXISTS idx_pvz_latitude_longitude ON pvz(latitude, longitude) WHERE latitude IS NOT NULL;
+
+INSERT INTO schema_version (version) VALUES (11) ON CONFLICT DO NOTHING;
@@ -0,0 +1,26 @@
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'suspended', 'decommissioned'));
+
+-- ПВЗ выводятся из эксплуатации сменой статуса, удаление ПВЗ не должно стирать его историю
+ALTER TABLE reception
+    DROP CONSTRAINT IF EXISTS fk_pvz_id,
+    ADD CONSTRAINT fk_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT;
+
+ALTER TABLE sync_operation
+    DROP CONSTRAINT IF EXISTS fk_sync_pvz_id,
+    ADD CONSTRAINT fk_sync_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT;
+
+ALTER TABLE issuance
+    DROP CONSTRAINT IF EXISTS fk_issuance_pvz_id,
+    ADD CONSTRAINT fk_issuance_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT;
+
+ALTER TABLE product_return
+    DROP CONSTRAINT IF EXISTS fk_return_pvz_id,
+    ADD CONSTRAINT fk_return_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT;
+
+ALTER TABLE storage_cell
+    DROP CONSTRAINT IF EXISTS fk_storage_cell_pvz_id,
+    ADD CONSTRAINT fk_storage_cell_pvz_id FOREIGN KEY (pvz_id) REFERENCES pvz(id) ON DELETE RESTRICT;
+
+INSERT INTO schema_version (version) VALUES (12) ON CONFLICT DO NOTHING;
@@ -0,0 +1,14 @@
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS close_reason TEXT
+        CHECK (close_reason IN ('manual', 'sync', 'timeout')),
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMP;
+
+-- Закрытые до миграции приемки закрывались вручную или синхронизацией, причину уже не восстановить
+UPDATE reception SET close_reason = 'manual' WHERE is_closed AND close_reason IS NULL;
+
+-- Планировщик ищет открытые приемки старше порога
+CREATE INDEX IF NOT EXISTS idx_reception_open_date_time ON reception(date_time) WHERE NOT is_closed;
+
+INSERT INTO schema_version (version) VALUES (13) ON CONFLICT DO NOTHING;
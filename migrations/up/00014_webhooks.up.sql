@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS webhook_subscription (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    -- Пустой массив - события всех ПВЗ
+    pvz_ids UUID[] NOT NULL DEFAULT '{}',
+    secret TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT NOT NULL DEFAULT '',
+    response_status INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP,
+    CONSTRAINT fk_delivery_subscription_id FOREIGN KEY (subscription_id)
+        REFERENCES webhook_subscription(id) ON DELETE CASCADE
+);
+
+-- Очередь на отправку
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_pending
+    ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+-- Журнал доставок подписки
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_subscription_created
+    ON webhook_delivery (subscription_id, created_at);
+
+INSERT INTO schema_version (version) VALUES (14) ON CONFLICT DO NOTHING;
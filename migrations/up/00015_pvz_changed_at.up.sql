@@ -0,0 +1,53 @@
+-- Время последнего изменения ПВЗ или его приемок, товаров, выдач, возвратов и ячеек.
+-- По нему строятся ETag ручек чтения, не собирая ответ целиком
+ALTER TABLE pvz
+    ADD COLUMN IF NOT EXISTS changed_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE OR REPLACE FUNCTION pvz_set_changed_at() RETURNS trigger AS $$
+BEGIN
+    NEW.changed_at := clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Строки с pvz_id: reception, issuance, product_return, storage_cell
+CREATE OR REPLACE FUNCTION pvz_touch_by_pvz_id() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE pvz SET changed_at = clock_timestamp() WHERE id = OLD.pvz_id;
+    ELSE
+        UPDATE pvz SET changed_at = clock_timestamp() WHERE id = NEW.pvz_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Товар ссылается на ПВЗ через приемку
+CREATE OR REPLACE FUNCTION pvz_touch_by_reception_id() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE pvz SET changed_at = clock_timestamp()
+        WHERE id = (SELECT pvz_id FROM reception WHERE id = OLD.reception_id);
+    ELSE
+        UPDATE pvz SET changed_at = clock_timestamp()
+        WHERE id = (SELECT pvz_id FROM reception WHERE id = NEW.reception_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_pvz_changed_at BEFORE UPDATE ON pvz
+    FOR EACH ROW EXECUTE FUNCTION pvz_set_changed_at();
+
+CREATE TRIGGER trg_reception_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON reception
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_pvz_id();
+CREATE TRIGGER trg_issuance_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON issuance
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_pvz_id();
+CREATE TRIGGER trg_product_return_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON product_return
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_pvz_id();
+CREATE TRIGGER trg_storage_cell_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON storage_cell
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_pvz_id();
+CREATE TRIGGER trg_product_touch_pvz AFTER INSERT OR UPDATE OR DELETE ON product
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_reception_id();
+
+INSERT INTO schema_version (version) VALUES (15) ON CONFLICT DO NOTHING;
@@ -0,0 +1,61 @@
+-- Операторы маркетплейсов, которые делят один экземпляр сервиса
+CREATE TABLE IF NOT EXISTS tenant (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Существующие данные переходят к оператору по умолчанию
+INSERT INTO tenant (id, name) VALUES ('00000000-0000-0000-0000-000000000001', 'default')
+ON CONFLICT DO NOTHING;
+
+-- email остается уникальным глобально: вход не спрашивает оператора
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS tenant_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000001'
+        REFERENCES tenant(id) ON DELETE RESTRICT;
+ALTER TABLE users
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- Запросы с данными операторов выполняются под ролью pvz_tenant (см. pgdb.TenantDB).
+-- Владелец таблиц политики не проверяет, под ним работают пользователи и сами операторы
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'pvz_tenant') THEN
+        CREATE ROLE pvz_tenant NOLOGIN;
+    END IF;
+END
+$$;
+GRANT pvz_tenant TO CURRENT_USER;
+
+-- Оператор строки берется из app.tenant_id, который TenantDB ставит на время запроса.
+-- Без него вставка падает на NOT NULL, а выборки ничего не видят
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'pvz', 'reception', 'product', 'sync_operation', 'issuance', 'product_return',
+        'storage_cell', 'webhook_subscription', 'webhook_delivery'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id UUID', t);
+        EXECUTE format('UPDATE %I SET tenant_id = %L WHERE tenant_id IS NULL',
+            t, '00000000-0000-0000-0000-000000000001');
+        EXECUTE format('ALTER TABLE %I
+            ALTER COLUMN tenant_id SET DEFAULT NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid,
+            ALTER COLUMN tenant_id SET NOT NULL', t);
+        EXECUTE format('ALTER TABLE %I DROP CONSTRAINT IF EXISTS fk_%s_tenant_id', t, t);
+        EXECUTE format('ALTER TABLE %I ADD CONSTRAINT fk_%s_tenant_id
+            FOREIGN KEY (tenant_id) REFERENCES tenant(id) ON DELETE RESTRICT', t, t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS idx_%s_tenant_id ON %I (tenant_id)', t, t);
+
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I
+            USING (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid)
+            WITH CHECK (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid)', t);
+        EXECUTE format('GRANT SELECT, INSERT, UPDATE, DELETE ON %I TO pvz_tenant', t);
+    END LOOP;
+END
+$$;
+
+INSERT INTO schema_version (version) VALUES (16) ON CONFLICT DO NOTHING;
@@ -0,0 +1,22 @@
+-- Подтверждение почты. Уже зарегистрированные пользователи считаются подтвердившими,
+-- чтобы включение auth.require_verified_email не закрыло им вход
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+UPDATE users SET email_verified_at = NOW() WHERE email_verified_at IS NULL;
+
+-- Одноразовые токены из писем: подтверждение почты и сброс пароля.
+-- Хранится SHA-256 токена, сам токен есть только в письме
+CREATE TABLE IF NOT EXISTS auth_token (
+    token_hash TEXT PRIMARY KEY,
+    user_id UUID NOT NULL,
+    purpose TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_auth_token_user_id FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_auth_token_purpose CHECK (purpose IN ('verify_email', 'reset_password'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_token_user_id ON auth_token (user_id);
+
+INSERT INTO schema_version (version) VALUES (17) ON CONFLICT DO NOTHING;
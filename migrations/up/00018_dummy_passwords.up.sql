@@ -0,0 +1,6 @@
+-- Тестовые пользователи /dummyLogin заводились с паролем, равным имени роли.
+-- Токены им теперь выдаются без пароля, поэтому старый пароль делаем непригодным для входа:
+-- строка не похожа ни на один поддерживаемый хеш
+UPDATE users SET password = '!' WHERE email IN ('employee@test.com', 'moderator@test.com');
+
+INSERT INTO schema_version (version) VALUES (18) ON CONFLICT DO NOTHING;
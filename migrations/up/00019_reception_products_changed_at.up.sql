@@ -0,0 +1,38 @@
+-- Товар больше не обновляет строку pvz: при потоке сканов транзакции всех приемок ПВЗ
+-- ждали блокировку одной строки. Отметка ставится на приемку, а отметка ПВЗ собирается
+-- как наибольшее из pvz.changed_at и reception.products_changed_at его приемок.
+-- 'epoch' по умолчанию: уже добавленные товары учтены в pvz.changed_at
+ALTER TABLE reception
+    ADD COLUMN IF NOT EXISTS products_changed_at TIMESTAMP NOT NULL DEFAULT 'epoch';
+
+CREATE INDEX IF NOT EXISTS idx_reception_pvz_id_products_changed_at
+    ON reception (pvz_id, products_changed_at);
+CREATE INDEX IF NOT EXISTS idx_reception_products_changed_at
+    ON reception (products_changed_at);
+
+CREATE OR REPLACE FUNCTION reception_touch_products() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE reception SET products_changed_at = clock_timestamp() WHERE id = OLD.reception_id;
+    ELSE
+        UPDATE reception SET products_changed_at = clock_timestamp() WHERE id = NEW.reception_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_product_touch_pvz ON product;
+DROP FUNCTION IF EXISTS pvz_touch_by_reception_id();
+
+CREATE TRIGGER trg_product_touch_reception AFTER INSERT OR UPDATE OR DELETE ON product
+    FOR EACH ROW EXECUTE FUNCTION reception_touch_products();
+
+-- Отметка товаров не меняет саму приемку, поэтому строку pvz не трогает
+DROP TRIGGER IF EXISTS trg_reception_touch_pvz ON reception;
+CREATE TRIGGER trg_reception_touch_pvz AFTER INSERT OR DELETE ON reception
+    FOR EACH ROW EXECUTE FUNCTION pvz_touch_by_pvz_id();
+CREATE TRIGGER trg_reception_update_touch_pvz AFTER UPDATE ON reception
+    FOR EACH ROW WHEN (OLD.products_changed_at IS NOT DISTINCT FROM NEW.products_changed_at)
+    EXECUTE FUNCTION pvz_touch_by_pvz_id();
+
+INSERT INTO schema_version (version) VALUES (19) ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+-- Самостоятельная регистрация через POST /register разрешается оператором явно.
+-- Оператор по умолчанию остается открытым, как было до разделения
+ALTER TABLE tenant
+    ADD COLUMN IF NOT EXISTS open_registration BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE tenant SET open_registration = true WHERE id = '00000000-0000-0000-0000-000000000001';
+
+INSERT INTO schema_version (version) VALUES (20) ON CONFLICT DO NOTHING;
@@ -0,0 +1,10 @@
+-- Журнал синхронизации ведется по ПВЗ: UUID операции уникален только внутри своего ПВЗ,
+-- такой же UUID от сканера другого ПВЗ (или другого оператора, скрытого RLS) - другая операция
+ALTER TABLE sync_operation
+    DROP CONSTRAINT IF EXISTS sync_operation_pkey,
+    ADD CONSTRAINT sync_operation_pkey PRIMARY KEY (pvz_id, id);
+
+-- Первичный ключ начинается с pvz_id и заменяет отдельный индекс
+DROP INDEX IF EXISTS idx_sync_operation_pvz_id;
+
+INSERT INTO schema_version (version) VALUES (21) ON CONFLICT DO NOTHING;
@@ -0,0 +1,129 @@
+// Package barcode проверяет штрихкоды, которые присылают сканеры ПВЗ.
+//
+// Поддерживаются два формата:
+//   - EAN-13: 13 цифр, последняя - контрольная по модулю 10 (GS1);
+//   - Code128: печатные ASCII-символы набора B, последний символ - контрольный
+//     по модулю 103. Сканер должен передавать контрольный символ вместе с данными.
+package barcode
+
+import (
+	"errors"
+)
+
+var (
+	ErrEmpty       = errors.New("barcode is empty")
+	ErrTooLong     = errors.New("barcode is too long")
+	ErrInvalidChar = errors.New("barcode contains unsupported characters")
+	ErrChecksum    = errors.New("barcode checksum mismatch")
+)
+
+// MaxLength - максимальная длина штрихкода вместе с контрольным символом
+const MaxLength = 64
+
+type Format string
+
+const (
+	EAN13   Format = "EAN-13"
+	Code128 Format = "Code128"
+)
+
+const (
+	ean13Length = 13
+	// Код старта набора B участвует в контрольной сумме Code128
+	code128StartB = 104
+	code128Mod    = 103
+	// Набор B кодирует ASCII 32..127 значениями 0..95
+	code128FirstChar = 32
+	code128LastChar  = 127
+)
+
+// Detect определяет формат по содержимому: 13 цифр - EAN-13, остальное - Code128
+func Detect(code string) Format {
+	if len(code) == ean13Length && isDigits(code) {
+		return EAN13
+	}
+	return Code128
+}
+
+// Validate проверяет штрихкод и возвращает его формат
+func Validate(code string) (Format, error) {
+	if code == "" {
+		return "", ErrEmpty
+	}
+	if len(code) > MaxLength {
+		return "", ErrTooLong
+	}
+
+	format := Detect(code)
+	if format == EAN13 {
+		return format, ValidateEAN13(code)
+	}
+	return format, ValidateCode128(code)
+}
+
+// ValidateEAN13 проверяет контрольную цифру EAN-13
+func ValidateEAN13(code string) error {
+	if len(code) != ean13Length || !isDigits(code) {
+		return ErrInvalidChar
+	}
+
+	if ean13CheckDigit(code[:ean13Length-1]) != code[ean13Length-1] {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// ValidateCode128 проверяет контрольный символ Code128 (набор B) в конце кода
+func ValidateCode128(code string) error {
+	if len(code) < 2 {
+		return ErrEmpty
+	}
+	if len(code) > MaxLength {
+		return ErrTooLong
+	}
+
+	for i := 0; i < len(code); i++ {
+		if code[i] < code128FirstChar || code[i] > code128LastChar {
+			return ErrInvalidChar
+		}
+	}
+
+	data, check := code[:len(code)-1], code[len(code)-1]
+	if code128Checksum(data) != int(check-code128FirstChar) {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// ean13CheckDigit считает контрольную цифру по 12 цифрам данных:
+// цифры на четных позициях (с единицы) умножаются на 3
+func ean13CheckDigit(data string) byte {
+	sum := 0
+	for i := 0; i < len(data); i++ {
+		digit := int(data[i] - '0')
+		if i%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return byte((10-sum%10)%10) + '0'
+}
+
+// code128Checksum возвращает значение контрольного символа: старт B плюс
+// взвешенная позицией сумма значений символов, по модулю 103
+func code128Checksum(data string) int {
+	sum := code128StartB
+	for i := 0; i < len(data); i++ {
+		sum += (i + 1) * int(data[i]-code128FirstChar)
+	}
+	return sum % code128Mod
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package barcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withCode128Check дописывает к данным контрольный символ
+func withCode128Check(data string) string {
+	return data + string(rune(code128Checksum(data)+code128FirstChar))
+}
+
+func TestCode128Checksum(t *testing.T) {
+	// 104 + 48*1 + 42*2 + 42*3 + 17*4 + 18*5 + 19*6 + 35*7 = 879, 879 % 103 = 55 ('W')
+	assert.Equal(t, 55, code128Checksum("PJJ123C"))
+	assert.NoError(t, ValidateCode128("PJJ123CW"))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		wantFormat Format
+		wantErr    error
+	}{
+		{name: "EAN-13", code: "4006381333931", wantFormat: EAN13},
+		{name: "EAN-13 с нулевой контрольной цифрой", code: "1300000000000", wantFormat: EAN13},
+		{name: "EAN-13 с неверной контрольной цифрой", code: "4006381333932", wantFormat: EAN13, wantErr: ErrChecksum},
+		{name: "Code128", code: withCode128Check("RU-PVZ-000123"), wantFormat: Code128},
+		{name: "Code128 из цифр", code: withCode128Check("00046000123456789"), wantFormat: Code128},
+		{name: "Code128 с неверным контрольным символом", code: "RU-PVZ-000123X", wantFormat: Code128, wantErr: ErrChecksum},
+		{name: "Code128 с кириллицей", code: "ПВЗ-1", wantFormat: Code128, wantErr: ErrInvalidChar},
+		{name: "Code128 с управляющим символом", code: "AB\tC", wantFormat: Code128, wantErr: ErrInvalidChar},
+		{name: "один символ", code: "A", wantFormat: Code128, wantErr: ErrEmpty},
+		{name: "пустой", code: "", wantErr: ErrEmpty},
+		{name: "слишком длинный", code: withCode128Check(strings.Repeat("A", MaxLength)), wantErr: ErrTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := Validate(tt.code)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantFormat, format)
+		})
+	}
+}
+
+func TestValidateEAN13(t *testing.T) {
+	assert.NoError(t, ValidateEAN13("5901234123457"))
+	assert.ErrorIs(t, ValidateEAN13("590123412345"), ErrInvalidChar)
+	assert.ErrorIs(t, ValidateEAN13("59012341234A7"), ErrInvalidChar)
+	assert.ErrorIs(t, ValidateEAN13("5901234123458"), ErrChecksum)
+}
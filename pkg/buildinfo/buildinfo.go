@@ -0,0 +1,24 @@
+package buildinfo
+
+import "runtime"
+
+// Значения подставляются при сборке:
+// go build -ldflags "-X pvz-service/pkg/buildinfo.Commit=... -X pvz-service/pkg/buildinfo.BuildTime=..."
+var (
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+type Info struct {
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+func Get() Info {
+	return Info{
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
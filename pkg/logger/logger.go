@@ -25,6 +25,11 @@ func (h *Handler) Enabled(ctx context.Context, rec slog.Level) bool {
 }
 
 func (h *Handler) Handle(ctx context.Context, rec slog.Record) error {
+	// Добавляем requestId, если он есть
+	if requestID, ok := ctx.Value(middleware.RequestIDKey).(string); ok && requestID != "" {
+		rec.Add(middleware.RequestIDKey, slog.StringValue(requestID))
+	}
+
 	// Добавляем userID, если он есть
 	if userID, ok := ctx.Value(middleware.UserIDKey).(string); ok && userID != "" {
 		rec.Add(middleware.UserIDKey, slog.StringValue(userID))
@@ -46,8 +51,9 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 	return &Handler{next: h.next.WithGroup(name)}
 }
 
-func InitLogger() *slog.Logger {
-	baseHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})
+// InitLogger создает JSON-логгер. level может меняться во время работы (slog.LevelVar)
+func InitLogger(level slog.Leveler) *slog.Logger {
+	baseHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level})
 	wrappedHandler := NewHandlerLogger(baseHandler)
 	logger := slog.New(wrappedHandler)
 	slog.SetDefault(logger)
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pvz-service/internal/middleware"
+)
+
+func TestHandler_AddsContextKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandlerLogger(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+	ctx = context.WithValue(ctx, middleware.UserIDKey, "user-1")
+	ctx = context.WithValue(ctx, middleware.RoleKey, "moderator")
+
+	logger.InfoContext(ctx, "test")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record[middleware.RequestIDKey])
+	assert.Equal(t, "user-1", record[middleware.UserIDKey])
+	assert.Equal(t, "moderator", record[middleware.RoleKey])
+}
@@ -0,0 +1,93 @@
+// Package netguard не пускает исходящие запросы сервиса во внутреннюю сеть (SSRF).
+//
+// Адрес получателя проверяется дважды: при сохранении - по ответу DNS, и при каждом
+// подключении - в хуке Control у net.Dialer, уже после резолва. Вторая проверка
+// закрывает DNS rebinding, когда имя после первой проверки начинает указывать внутрь.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// ErrForbiddenAddress - адрес не публичный
+var ErrForbiddenAddress = errors.New("address is not public")
+
+// reserved - непубличные диапазоны, которые не покрывают методы netip.Addr
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "этот" хост
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),   // бенчмарки
+	netip.MustParsePrefix("240.0.0.0/4"),     // зарезервировано и broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, может вести во внутреннюю IPv4-сеть
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // локальный NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // документация
+	netip.MustParsePrefix("2002::/16"),       // 6to4, внутри может быть любой IPv4
+	netip.MustParsePrefix("fec0::/10"),       // устаревшие site-local
+	netip.MustParsePrefix("100::/64"),        // discard
+	netip.MustParsePrefix("2001::/32"),       // Teredo
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay
+	netip.MustParsePrefix("192.0.2.0/24"),    // документация
+	netip.MustParsePrefix("198.51.100.0/24"), // документация
+	netip.MustParsePrefix("203.0.113.0/24"),  // документация
+}
+
+// IsPublic сообщает, можно ли отправлять запрос на адрес
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range reserved {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckHost резолвит хост и возвращает ErrForbiddenAddress, если хотя бы один из его адресов не публичный
+func CheckHost(ctx context.Context, resolver *net.Resolver, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkAddr(addr)
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if err = checkAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Control - хук net.Dialer.Control: проверяет адрес, к которому клиент подключается на самом деле
+func Control(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	return checkAddr(addrPort.Addr())
+}
+
+func checkAddr(addr netip.Addr) error {
+	if !IsPublic(addr) {
+		return fmt.Errorf("%s: %w", addr, ErrForbiddenAddress)
+	}
+	return nil
+}
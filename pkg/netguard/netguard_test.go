@@ -0,0 +1,55 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.public, IsPublic(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, CheckHost(ctx, net.DefaultResolver, "93.184.216.34"))
+	assert.ErrorIs(t, CheckHost(ctx, net.DefaultResolver, "169.254.169.254"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckHost(ctx, net.DefaultResolver, "localhost"), ErrForbiddenAddress)
+}
+
+func TestControl(t *testing.T) {
+	assert.NoError(t, Control("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, Control("tcp4", "10.0.0.1:80", nil), ErrForbiddenAddress)
+	assert.ErrorIs(t, Control("tcp6", "[::1]:8080", nil), ErrForbiddenAddress)
+	assert.Error(t, Control("tcp", "not-an-address", nil))
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	tryAdvisoryLockQuery = "SELECT pg_try_advisory_lock($1)"
+	lockAliveQuery       = "SELECT 1"
+)
+
+// LockConn - отдельное соединение, на котором держится сессионная advisory-блокировка
+type LockConn interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Close(ctx context.Context) error
+}
+
+// ConnFactory открывает соединение под блокировку
+type ConnFactory func(ctx context.Context) (LockConn, error)
+
+// PoolConnFactory забирает соединение из пула насовсем: блокировка живет до закрытия
+// соединения и не должна вернуться в пул вместе с ним
+func PoolConnFactory(pool *pgxpool.Pool) ConnFactory {
+	return func(ctx context.Context) (LockConn, error) {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return conn.Hijack(), nil
+	}
+}
+
+// AdvisoryLock - выбор лидера через pg_try_advisory_lock.
+// Лидер держит блокировку на своем соединении, при обрыве соединения Postgres
+// снимает ее сам, и лидером становится другой экземпляр
+type AdvisoryLock struct {
+	connect ConnFactory
+	key     int64
+
+	mu   sync.Mutex
+	conn LockConn
+}
+
+func NewAdvisoryLock(connect ConnFactory, key int64) *AdvisoryLock {
+	return &AdvisoryLock{
+		connect: connect,
+		key:     key,
+	}
+}
+
+// TryAcquire возвращает true, если блокировка у этого экземпляра.
+// Уже взятая блокировка проверяется запросом на ее соединении
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if _, err := l.conn.Exec(ctx, lockAliveQuery); err == nil {
+			return true, nil
+		}
+		// Соединение потеряно, вместе с ним и блокировка
+		_ = l.closeConn(ctx)
+	}
+
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrConnectionFailed, err)
+	}
+
+	var locked bool
+	if err = conn.QueryRow(ctx, tryAdvisoryLockQuery, l.key).Scan(&locked); err != nil {
+		_ = conn.Close(ctx)
+		return false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+
+	if !locked {
+		_ = conn.Close(ctx)
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release отдает лидерство. Закрытие соединения снимает сессионную блокировку
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	return l.closeConn(ctx)
+}
+
+func (l *AdvisoryLock) closeConn(ctx context.Context) error {
+	err := l.conn.Close(ctx)
+	l.conn = nil
+	return err
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockKey int64 = 42
+
+// newLockConns возвращает фабрику, которая по очереди отдает моки соединений
+func newLockConns(t *testing.T, count int) (ConnFactory, []pgxmock.PgxConnIface) {
+	conns := make([]pgxmock.PgxConnIface, count)
+	for i := range conns {
+		conn, err := pgxmock.NewConn()
+		require.NoError(t, err)
+		conns[i] = conn
+	}
+
+	t.Cleanup(func() {
+		for _, conn := range conns {
+			assert.NoError(t, conn.ExpectationsWereMet())
+		}
+	})
+
+	next := 0
+	return func(context.Context) (LockConn, error) {
+		if next == len(conns) {
+			return nil, errors.New("connection refused")
+		}
+		next++
+		return conns[next-1], nil
+	}, conns
+}
+
+func expectTryLock(conn pgxmock.PgxConnIface, locked bool) {
+	conn.ExpectQuery(regexp.QuoteMeta(tryAdvisoryLockQuery)).
+		WithArgs(testLockKey).
+		WillReturnRows(pgxmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(locked))
+}
+
+func TestAdvisoryLock_TryAcquire(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("блокировка взята и удерживается на том же соединении", func(t *testing.T) {
+		connect, conns := newLockConns(t, 1)
+		lock := NewAdvisoryLock(connect, testLockKey)
+
+		expectTryLock(conns[0], true)
+		conns[0].ExpectExec(regexp.QuoteMeta(lockAliveQuery)).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		conns[0].ExpectClose()
+
+		leader, err := lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, leader)
+
+		leader, err = lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, leader)
+
+		assert.NoError(t, lock.Release(ctx))
+		assert.NoError(t, lock.Release(ctx))
+	})
+
+	t.Run("блокировка у другого экземпляра", func(t *testing.T) {
+		connect, conns := newLockConns(t, 1)
+		lock := NewAdvisoryLock(connect, testLockKey)
+
+		expectTryLock(conns[0], false)
+		conns[0].ExpectClose()
+
+		leader, err := lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.False(t, leader)
+
+		assert.NoError(t, lock.Release(ctx))
+	})
+
+	t.Run("соединение потеряно, блокировка берется заново", func(t *testing.T) {
+		connect, conns := newLockConns(t, 2)
+		lock := NewAdvisoryLock(connect, testLockKey)
+
+		expectTryLock(conns[0], true)
+		conns[0].ExpectExec(regexp.QuoteMeta(lockAliveQuery)).WillReturnError(errors.New("connection reset"))
+		conns[0].ExpectClose()
+		expectTryLock(conns[1], false)
+		conns[1].ExpectClose()
+
+		leader, err := lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.True(t, leader)
+
+		leader, err = lock.TryAcquire(ctx)
+		require.NoError(t, err)
+		assert.False(t, leader)
+	})
+
+	t.Run("база недоступна", func(t *testing.T) {
+		connect, _ := newLockConns(t, 0)
+		lock := NewAdvisoryLock(connect, testLockKey)
+
+		leader, err := lock.TryAcquire(ctx)
+		assert.ErrorIs(t, err, ErrConnectionFailed)
+		assert.False(t, leader)
+	})
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+)
+
+var (
+	ErrSchemaOutdated = errors.New("postgres: schema version is outdated")
+)
+
+const schemaVersionQuery = "SELECT COALESCE(MAX(version), 0) FROM schema_version"
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// HealthChecker проверяет, что база доступна и миграции применены
+// не ниже версии, которую ожидает код.
+type HealthChecker struct {
+	db            Pinger
+	schemaVersion int
+}
+
+func NewHealthChecker(db Pinger, schemaVersion int) *HealthChecker {
+	return &HealthChecker{
+		db:            db,
+		schemaVersion: schemaVersion,
+	}
+}
+
+func (c *HealthChecker) CheckReadiness(ctx context.Context) error {
+	if err := c.db.Ping(ctx); err != nil {
+		return fmt.Errorf("%w: %s", ErrConnectionFailed, err)
+	}
+
+	var version int
+	if err := c.db.QueryRow(ctx, schemaVersionQuery).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if version < c.schemaVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrSchemaOutdated, version, c.schemaVersion)
+	}
+
+	return nil
+}
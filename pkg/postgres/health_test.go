@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthChecker_CheckReadiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(mock pgxmock.PgxPoolIface)
+		expectedErr error
+		wantErr     bool
+	}{
+		{
+			name: "ready",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectPing()
+				mock.ExpectQuery("SELECT COALESCE").WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(5))
+			},
+		},
+		{
+			name: "ping failed",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+			},
+			expectedErr: ErrConnectionFailed,
+			wantErr:     true,
+		},
+		{
+			name: "schema outdated",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectPing()
+				mock.ExpectQuery("SELECT COALESCE").WillReturnRows(pgxmock.NewRows([]string{"version"}).AddRow(4))
+			},
+			expectedErr: ErrSchemaOutdated,
+			wantErr:     true,
+		},
+		{
+			name: "version table missing",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectPing()
+				mock.ExpectQuery("SELECT COALESCE").WillReturnError(errors.New(`relation "schema_version" does not exist`))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool(pgxmock.MonitorPingsOption(true))
+			require.NoError(t, err)
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			err = NewHealthChecker(mock, 5).CheckReadiness(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
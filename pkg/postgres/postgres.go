@@ -15,11 +15,16 @@ var (
 )
 
 func InitDBPool(ctx context.Context, cfg config.PGConfig) (*pgxpool.Pool, error) {
-	dsn := cfg.GetDSN()
+	poolCfg, err := pgxpool.ParseConfig(cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConnectionFailed, err)
+	}
+	poolCfg.MaxConns = cfg.GetMaxConns()
+
 	bgCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	pool, err := pgxpool.Connect(bgCtx, dsn)
+	pool, err := pgxpool.ConnectConfig(bgCtx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrConnectionFailed, err)
 	}
@@ -32,6 +37,28 @@ func InitDBPool(ctx context.Context, cfg config.PGConfig) (*pgxpool.Pool, error)
 	return pool, nil
 }
 
+// InitReplicaPool создает пул реплики, если она задана в конфиге.
+// Пул подключается лениво: недоступная реплика не мешает старту, запросы уйдут на primary
+func InitReplicaPool(ctx context.Context, cfg config.PGConfig) (*pgxpool.Pool, error) {
+	if cfg.GetReplicaDSN() == "" {
+		return nil, nil
+	}
+
+	poolCfg, err := pgxpool.ParseConfig(cfg.GetReplicaDSN())
+	if err != nil {
+		return nil, fmt.Errorf("invalid replica dsn: %w", err)
+	}
+	poolCfg.MaxConns = cfg.GetReplicaMaxConns()
+	poolCfg.LazyConnect = true
+
+	pool, err := pgxpool.ConnectConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConnectionFailed, err)
+	}
+
+	return pool, nil
+}
+
 func pingDBPool(ctx context.Context, pool *pgxpool.Pool) error {
 	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
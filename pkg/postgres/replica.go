@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// DefaultReplicaRetryInterval - через сколько после сбоя снова пробуем реплику
+const DefaultReplicaRetryInterval = 10 * time.Second
+
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// ReplicaDB отправляет чтение на реплику, а при ее недоступности - на primary.
+// После сбоя реплика не используется retryInterval, чтобы не платить таймаутом на каждом запросе.
+// Exec всегда идет на primary.
+type ReplicaDB struct {
+	primary       Querier
+	replica       Querier
+	retryInterval time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+func NewReplicaDB(primary, replica Querier, retryInterval time.Duration) *ReplicaDB {
+	return &ReplicaDB{
+		primary:       primary,
+		replica:       replica,
+		retryInterval: retryInterval,
+		now:           time.Now,
+	}
+}
+
+func (db *ReplicaDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if !db.replicaAvailable() {
+		return db.primary.QueryRow(ctx, sql, args...)
+	}
+
+	return &fallbackRow{
+		row: db.replica.QueryRow(ctx, sql, args...),
+		retry: func() pgx.Row {
+			return db.primary.QueryRow(ctx, sql, args...)
+		},
+		db: db,
+	}
+}
+
+func (db *ReplicaDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if !db.replicaAvailable() {
+		return db.primary.Query(ctx, sql, args...)
+	}
+
+	rows, err := db.replica.Query(ctx, sql, args...)
+	if isConnectionError(err) {
+		db.markDown()
+		return db.primary.Query(ctx, sql, args...)
+	}
+
+	return rows, err
+}
+
+func (db *ReplicaDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return db.primary.Exec(ctx, sql, args...)
+}
+
+func (db *ReplicaDB) replicaAvailable() bool {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return !db.now().Before(db.downUntil)
+}
+
+func (db *ReplicaDB) markDown() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.downUntil = db.now().Add(db.retryInterval)
+}
+
+// fallbackRow повторяет запрос на primary, если реплика не ответила.
+// pgx возвращает ошибку QueryRow только в Scan, поэтому решение принимается здесь
+type fallbackRow struct {
+	row   pgx.Row
+	retry func() pgx.Row
+	db    *ReplicaDB
+}
+
+func (r *fallbackRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	if isConnectionError(err) {
+		r.db.markDown()
+		return r.retry().Scan(dest...)
+	}
+
+	return err
+}
+
+// isConnectionError отделяет сбой соединения с репликой от остальных ошибок. Сбой - это
+// ошибка сети (pgconn оборачивает в нее и неудачное подключение) или оборванное соединение.
+// Отсутствие строк, ошибка Postgres, отмена контекста и ошибки до отправки запроса
+// (например, не задан оператор) на primary не лечатся
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/pashagolub/pgxmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errReplicaDown - так pgconn сообщает, что до реплики не достучаться
+var errReplicaDown = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func newReplicaMocks(t *testing.T) (pgxmock.PgxPoolIface, pgxmock.PgxPoolIface) {
+	primary, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	replica, err := pgxmock.NewPool()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, primary.ExpectationsWereMet())
+		assert.NoError(t, replica.ExpectationsWereMet())
+	})
+
+	return primary, replica
+}
+
+func TestReplicaDB_Query(t *testing.T) {
+	t.Run("чтение идет на реплику", func(t *testing.T) {
+		primary, replica := newReplicaMocks(t)
+		db := NewReplicaDB(primary, replica, time.Minute)
+
+		replica.ExpectQuery("SELECT id FROM pvz").WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+
+		rows, err := db.Query(context.Background(), "SELECT id FROM pvz")
+		require.NoError(t, err)
+		rows.Close()
+	})
+
+	t.Run("ошибка запроса не переключает на primary", func(t *testing.T) {
+		primary, replica := newReplicaMocks(t)
+		db := NewReplicaDB(primary, replica, time.Minute)
+
+		replica.ExpectQuery("SELECT id FROM pvz").WillReturnError(&pgconn.PgError{Code: "42P01"})
+
+		_, err := db.Query(context.Background(), "SELECT id FROM pvz")
+		assert.Error(t, err)
+	})
+
+	t.Run("реплика недоступна - primary, затем повтор через интервал", func(t *testing.T) {
+		primary, replica := newReplicaMocks(t)
+		db := NewReplicaDB(primary, replica, time.Minute)
+
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		db.now = func() time.Time { return now }
+
+		replica.ExpectQuery("SELECT id FROM pvz").WillReturnError(errReplicaDown)
+		primary.ExpectQuery("SELECT id FROM pvz").WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		primary.ExpectQuery("SELECT id FROM pvz").WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+		replica.ExpectQuery("SELECT id FROM pvz").WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(1))
+
+		rows, err := db.Query(context.Background(), "SELECT id FROM pvz")
+		require.NoError(t, err)
+		rows.Close()
+
+		// реплика помечена недоступной и не опрашивается
+		rows, err = db.Query(context.Background(), "SELECT id FROM pvz")
+		require.NoError(t, err)
+		rows.Close()
+
+		now = now.Add(time.Minute)
+		rows, err = db.Query(context.Background(), "SELECT id FROM pvz")
+		require.NoError(t, err)
+		rows.Close()
+	})
+}
+
+func TestReplicaDB_QueryRow(t *testing.T) {
+	t.Run("реплика недоступна - повтор на primary", func(t *testing.T) {
+		primary, replica := newReplicaMocks(t)
+		db := NewReplicaDB(primary, replica, time.Minute)
+
+		replica.ExpectQuery("SELECT city").WillReturnError(errReplicaDown)
+		primary.ExpectQuery("SELECT city").WillReturnRows(pgxmock.NewRows([]string{"city"}).AddRow("Москва"))
+
+		var city string
+		require.NoError(t, db.QueryRow(context.Background(), "SELECT city FROM pvz").Scan(&city))
+		assert.Equal(t, "Москва", city)
+	})
+
+	t.Run("ошибка запроса - без повтора", func(t *testing.T) {
+		primary, replica := newReplicaMocks(t)
+		db := NewReplicaDB(primary, replica, time.Minute)
+
+		replica.ExpectQuery("SELECT city").WillReturnError(&pgconn.PgError{Code: "42P01"})
+
+		var city string
+		err := db.QueryRow(context.Background(), "SELECT city FROM pvz").Scan(&city)
+		var pgErr *pgconn.PgError
+		assert.ErrorAs(t, err, &pgErr)
+	})
+}
+
+func TestIsConnectionError(t *testing.T) {
+	assert.False(t, isConnectionError(nil))
+	assert.False(t, isConnectionError(pgx.ErrNoRows))
+	assert.False(t, isConnectionError(context.Canceled))
+	assert.False(t, isConnectionError(fmt.Errorf("timeout: %w", context.DeadlineExceeded)))
+	assert.False(t, isConnectionError(&pgconn.PgError{Code: "23505"}))
+	// Ошибка до отправки запроса, как pgdb.ErrTenantNotSet
+	assert.False(t, isConnectionError(errors.New("tenant is not set in context")))
+
+	assert.True(t, isConnectionError(errReplicaDown))
+	assert.True(t, isConnectionError(fmt.Errorf("failed to connect: %w", &net.DNSError{Err: "no such host", Name: "replica"})))
+	assert.True(t, isConnectionError(fmt.Errorf("read: %w", io.ErrUnexpectedEOF)))
+}
+
+func TestReplicaDB_ExecUsesPrimary(t *testing.T) {
+	primary, replica := newReplicaMocks(t)
+	db := NewReplicaDB(primary, replica, time.Minute)
+
+	primary.ExpectExec("UPDATE reception").WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	_, err := db.Exec(context.Background(), "UPDATE reception SET is_closed = true")
+	assert.NoError(t, err)
+}
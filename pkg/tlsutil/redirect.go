@@ -0,0 +1,23 @@
+package tlsutil
+
+import (
+	"net"
+	"net/http"
+)
+
+// RedirectHandler отправляет клиента на тот же адрес по HTTPS.
+// 308 сохраняет метод и тело, поэтому POST со сканера не превращается в GET
+func RedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if httpsPort != "" && httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,158 @@
+// Package tlsutil - TLS для HTTP-сервера: сертификаты с перечитыванием
+// при изменении файлов, необязательный mTLS и редирект с HTTP на HTTPS.
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCheckInterval - как часто проверяем, не изменились ли файлы
+const DefaultCheckInterval = 10 * time.Second
+
+var ErrNoClientCA = errors.New("tls: no certificates found in client CA bundle")
+
+// Reloader отдает актуальный сертификат и пул CA клиентов.
+// Файлы проверяются по времени изменения не чаще checkInterval прямо во время handshake,
+// при ошибке чтения продолжаем работать со старыми
+type Reloader struct {
+	certFile      string
+	keyFile       string
+	clientCAFile  string
+	checkInterval time.Duration
+	now           func() time.Time
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+// NewReloader сразу читает файлы, чтобы ошибка конфигурации была видна при старте.
+// clientCAFile пустой - mTLS выключен
+func NewReloader(certFile, keyFile, clientCAFile string, checkInterval time.Duration) (*Reloader, error) {
+	r := &Reloader{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		clientCAFile:  clientCAFile,
+		checkInterval: checkInterval,
+		now:           time.Now,
+		modTimes:      make(map[string]time.Time),
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.lastCheck = r.now()
+
+	return r, nil
+}
+
+// TLSConfig возвращает конфиг сервера. Клиентский сертификат необязателен:
+// устройства без него авторизуются по JWT
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			return cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, clientCAs := r.current()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if clientCAs != nil {
+				cfg.ClientCAs = clientCAs
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+			}
+
+			return cfg, nil
+		},
+	}
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := r.now(); now.Sub(r.lastCheck) >= r.checkInterval {
+		r.lastCheck = now
+		if r.changed() {
+			if err := r.load(); err != nil {
+				slog.Error("TLS reload failed, keeping current certificates", slog.String("error", err.Error()))
+			} else {
+				slog.Info("TLS certificates reloaded")
+			}
+		}
+	}
+
+	return r.cert, r.clientCAs
+}
+
+func (r *Reloader) changed() bool {
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+// load читает все файлы и подменяет состояние только если все прочитано без ошибок
+func (r *Reloader) load() error {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("tls: load key pair: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("tls: read client CA: %w", err)
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return ErrNoClientCA
+		}
+	}
+
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+
+	return nil
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
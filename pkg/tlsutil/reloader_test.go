@@ -0,0 +1,193 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// issue выпускает сертификат, подписанный parent (или самоподписанный, если parent == nil)
+func issue(t *testing.T, serial int64, subject pkix.Name, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               subject,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (c *testCert) keyPEM(t *testing.T) []byte {
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	cert, err := tls.X509KeyPair(c.pem, c.keyPEM(t))
+	require.NoError(t, err)
+	return cert
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	ca := issue(t, 1, pkix.Name{CommonName: "pvz ca"}, nil, true)
+	server := issue(t, 2, pkix.Name{CommonName: "pvz server"}, ca, false)
+	device := issue(t, 3, pkix.Name{CommonName: "scanner-1", OrganizationalUnit: []string{"employee"}}, ca, false)
+
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, certFile, server.pem, modTime)
+	writeFile(t, keyFile, server.keyPEM(t), modTime)
+	writeFile(t, caFile, ca.pem, modTime)
+
+	reloader, err := NewReloader(certFile, keyFile, caFile, time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	reloader.now = func() time.Time { return now }
+
+	var subject string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = ""
+		if len(r.TLS.VerifiedChains) > 0 {
+			subject = r.TLS.VerifiedChains[0][0].Subject.CommonName
+		}
+	}))
+	srv.TLS = reloader.TLSConfig()
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	get := func(clientCerts ...tls.Certificate) *x509.Certificate {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: clientCerts,
+		}}}
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.TLS.PeerCertificates[0]
+	}
+
+	t.Run("сертификат устройства проверяется, без него запрос тоже проходит", func(t *testing.T) {
+		assert.Equal(t, int64(2), get(device.tlsCertificate(t)).SerialNumber.Int64())
+		assert.Equal(t, "scanner-1", subject)
+
+		get()
+		assert.Empty(t, subject)
+	})
+
+	t.Run("новый сертификат подхватывается после интервала проверки", func(t *testing.T) {
+		renewed := issue(t, 4, pkix.Name{CommonName: "pvz server"}, ca, false)
+		writeFile(t, certFile, renewed.pem, modTime.Add(time.Second))
+		writeFile(t, keyFile, renewed.keyPEM(t), modTime.Add(time.Second))
+
+		assert.Equal(t, int64(2), get().SerialNumber.Int64())
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, int64(4), get().SerialNumber.Int64())
+	})
+
+	t.Run("битый файл не ломает текущий сертификат", func(t *testing.T) {
+		writeFile(t, certFile, []byte("garbage"), modTime.Add(2*time.Second))
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, int64(4), get().SerialNumber.Int64())
+	})
+}
+
+func TestNewReloader_InvalidClientCA(t *testing.T) {
+	dir := t.TempDir()
+	ca := issue(t, 1, pkix.Name{CommonName: "pvz ca"}, nil, true)
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, ca.pem, time.Now())
+	writeFile(t, keyFile, ca.keyPEM(t), time.Now())
+	writeFile(t, caFile, []byte("not a pem"), time.Now())
+
+	_, err := NewReloader(certFile, keyFile, caFile, time.Minute)
+	assert.ErrorIs(t, err, ErrNoClientCA)
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		target    string
+		expected  string
+	}{
+		{"нестандартный порт", "8443", "http://pvz.local:8080/pvz?page=2", "https://pvz.local:8443/pvz?page=2"},
+		{"порт 443 не указывается", "443", "http://pvz.local/receptions", "https://pvz.local/receptions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			w := httptest.NewRecorder()
+
+			RedirectHandler(tt.httpsPort).ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+			assert.Equal(t, tt.expected, w.Header().Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package webhooksig подписывает исходящие вебхуки и проверяет подпись на стороне получателя.
+//
+// Подпись - HMAC-SHA256 от строки "<timestamp>.<тело запроса>" на секрете подписки,
+// в заголовке передается как "sha256=<hex>". Timestamp - unix-время в секундах,
+// получатель отклоняет запросы старше допустимого окна, чтобы их нельзя было повторить.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	// IDHeader - id события. Повторные попытки приходят с тем же id, по нему получатель убирает дубли
+	IDHeader = "X-Webhook-Id"
+
+	signaturePrefix = "sha256="
+)
+
+// DefaultTolerance - насколько timestamp может отставать от часов получателя
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("webhook signature is missing")
+	ErrInvalidTimestamp = errors.New("webhook timestamp is invalid")
+	ErrExpired          = errors.New("webhook timestamp is outside the tolerance window")
+	ErrMismatch         = errors.New("webhook signature mismatch")
+)
+
+// Sign возвращает значение заголовка подписи для тела body, отправленного в момент timestamp
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет заголовки подписи и timestamp относительно now
+func Verify(secret, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	sent := time.Unix(unix, 0)
+	if now.Sub(sent) > tolerance || sent.Sub(now) > tolerance {
+		return ErrExpired
+	}
+
+	expected := Sign(secret, sent, body)
+	if !strings.HasPrefix(signature, signaturePrefix) || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return ErrMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package webhooksig
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+
+	// echo -n '1700000000.{"ok":true}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t,
+		"sha256=c1afc7c2df3db0690d7d75954610ed1a1d959ce96355ccb8c0a8bc09fd0cfc27",
+		Sign("secret", timestamp, []byte(`{"ok":true}`)),
+	)
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"webhook.test"}`)
+	signature := Sign("secret", now, body)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		timestamp string
+		body      []byte
+		now       time.Time
+		wantErr   error
+	}{
+		{name: "верная подпись", secret: "secret", signature: signature, timestamp: timestamp, body: body, now: now},
+		{name: "часы получателя немного впереди", secret: "secret", signature: signature, timestamp: timestamp, body: body, now: now.Add(time.Minute)},
+		{name: "без подписи", secret: "secret", timestamp: timestamp, body: body, now: now, wantErr: ErrMissingSignature},
+		{name: "timestamp не число", secret: "secret", signature: signature, timestamp: "yesterday", body: body, now: now, wantErr: ErrInvalidTimestamp},
+		{name: "старый запрос", secret: "secret", signature: signature, timestamp: timestamp, body: body, now: now.Add(time.Hour), wantErr: ErrExpired},
+		{name: "другой секрет", secret: "other", signature: signature, timestamp: timestamp, body: body, now: now, wantErr: ErrMismatch},
+		{name: "тело изменено", secret: "secret", signature: signature, timestamp: timestamp, body: []byte(`{}`), now: now, wantErr: ErrMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.timestamp, tt.body, tt.now, DefaultTolerance)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}